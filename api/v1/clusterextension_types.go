@@ -26,6 +26,16 @@ var ClusterExtensionKind = "ClusterExtension"
 type (
 	UpgradeConstraintPolicy     string
 	CRDUpgradeSafetyEnforcement string
+	ChannelDeprecationPolicy    string
+	VersionPolicyType           string
+	TieBreakPolicy              string
+	RollbackConstraintPolicy    string
+	OnFailurePolicy             string
+	UpgradeApprovalPolicy       string
+	UpgradePreviewAction        string
+	PatchType                   string
+	RolloutStrategy             string
+	RevisionOutcome             string
 
 	ClusterExtensionConfigType string
 )
@@ -43,7 +53,131 @@ const (
 	// disastrous results such as data loss.
 	UpgradeConstraintPolicySelfCertified UpgradeConstraintPolicy = "SelfCertified"
 
-	ClusterExtensionConfigTypeInline ClusterExtensionConfigType = "Inline"
+	// Deprecated channels are reported in status conditions but otherwise
+	// have no effect on resolution.
+	ChannelDeprecationPolicyIgnore ChannelDeprecationPolicy = "Ignore"
+
+	// A requested channel that is deprecated in favor of a successor channel
+	// is transparently replaced by its successor during resolution.
+	ChannelDeprecationPolicyMigrate ChannelDeprecationPolicy = "Migrate"
+
+	// Upgrades of the installed bundle are blocked while a requested channel is
+	// deprecated in favor of a successor channel, until the deprecation is
+	// acknowledged or the requested channels are updated.
+	ChannelDeprecationPolicyBlock ChannelDeprecationPolicy = "Block"
+
+	// Only bundles in the same major.minor version stream as the installed bundle are allowed.
+	VersionPolicyTypePatchOnly VersionPolicyType = "PatchOnly"
+
+	// Only bundles in the same major version stream as the installed bundle are allowed.
+	VersionPolicyTypeMinorOnly VersionPolicyType = "MinorOnly"
+
+	// Only the version of the installed bundle is allowed.
+	VersionPolicyTypePinned VersionPolicyType = "Pinned"
+
+	// Only bundles a given number of minor versions behind the newest available minor version are allowed.
+	VersionPolicyTypeLagBy VersionPolicyType = "LagBy"
+
+	// Resolution fails when equally preferred bundles are found in several catalogs with the same priority.
+	TieBreakPolicyFail TieBreakPolicy = "Fail"
+
+	// The bundle with the highest version wins among catalogs with the same priority.
+	TieBreakPolicyHighestVersion TieBreakPolicy = "HighestVersion"
+
+	// The catalog that provided the installed bundle wins among catalogs with the same priority.
+	TieBreakPolicyStickyCatalog TieBreakPolicy = "StickyCatalog"
+
+	// Rollbacks honor the version range and upgrade constraints of the ClusterExtension.
+	RollbackConstraintPolicyEnforce RollbackConstraintPolicy = "Enforce"
+
+	// Rollbacks proceed regardless of the version range and upgrade constraints of the ClusterExtension.
+	RollbackConstraintPolicyIgnore RollbackConstraintPolicy = "Ignore"
+
+	// Failed upgrades stay in place and require manual intervention.
+	OnFailurePolicyNone OnFailurePolicy = "None"
+
+	// Failed upgrades are rolled back to the previously installed revision.
+	OnFailurePolicyRollback OnFailurePolicy = "Rollback"
+
+	// Upgrades are applied as soon as they are resolved.
+	UpgradeApprovalPolicyAutomatic UpgradeApprovalPolicy = "Automatic"
+
+	// Upgrades are applied once the upgrade preview is approved.
+	UpgradeApprovalPolicyManual UpgradeApprovalPolicy = "Manual"
+
+	// The object is created by the upgrade.
+	UpgradePreviewActionAdded UpgradePreviewAction = "Added"
+
+	// The object is deleted by the upgrade.
+	UpgradePreviewActionRemoved UpgradePreviewAction = "Removed"
+
+	// The object is changed by the upgrade.
+	UpgradePreviewActionChanged UpgradePreviewAction = "Changed"
+
+	// The patch is a partial object that is merged into the target objects. Lists are merged
+	// according to the patch strategy of built-in Kubernetes types, and replaced otherwise.
+	PatchTypeStrategicMerge PatchType = "StrategicMerge"
+
+	// The patch is a list of RFC 6902 JSON patch operations applied to the target objects.
+	PatchTypeJSON6902 PatchType = "JSON6902"
+
+	// All objects of a revision are rolled out as soon as the objects of the previous phases are available.
+	RolloutStrategyAllAtOnce RolloutStrategy = "AllAtOnce"
+
+	// The Deployments of a revision must pass a soak before the revision completes.
+	RolloutStrategyCanary RolloutStrategy = "Canary"
+
+	// The revision is rolling out.
+	RevisionOutcomeRollingOut RevisionOutcome = "RollingOut"
+
+	// The revision rolled out.
+	RevisionOutcomeSucceeded RevisionOutcome = "Succeeded"
+
+	// The revision failed to roll out.
+	RevisionOutcomeFailed RevisionOutcome = "Failed"
+
+	// The revision was superseded by a newer revision before it rolled out.
+	RevisionOutcomeAbandoned RevisionOutcome = "Abandoned"
+
+	// AnnotationAcknowledgedDeprecatedChannels is a ClusterExtension annotation
+	// holding a comma-separated list of deprecated channels that a user has
+	// acknowledged. It lifts the block imposed by the "Block" channel
+	// deprecation policy for the listed channels.
+	AnnotationAcknowledgedDeprecatedChannels = "olm.operatorframework.io/acknowledged-deprecated-channels"
+
+	// AnnotationAdoptHelmRelease is a ClusterExtension annotation naming a Helm 3
+	// release installed outside of OLM in the installation namespace. The release
+	// history is imported into OLM's release storage and the release is managed by
+	// the ClusterExtension from then on. The ClusterExtension must have the same name
+	// as the release.
+	AnnotationAdoptHelmRelease = "olm.operatorframework.io/adopt-helm-release"
+
+	// AnnotationMigrateOLMv0Subscription is a ClusterExtension annotation naming an
+	// OLM v0 Subscription in the installation namespace. The objects installed for
+	// the CSV of the Subscription are adopted by the first ClusterExtensionRevision
+	// of the ClusterExtension, without being deleted or recreated, once the
+	// Subscription and the CSV are removed.
+	AnnotationMigrateOLMv0Subscription = "olm.operatorframework.io/migrate-olmv0-subscription"
+
+	// AnnotationRetryFailedRevision is a ClusterExtension annotation holding the
+	// number of a revision that failed to roll out and was rolled back by the
+	// "Rollback" onFailure policy. It lifts the block on installing the bundle of
+	// that revision again.
+	AnnotationRetryFailedRevision = "olm.operatorframework.io/retry-failed-revision"
+
+	// AnnotationApprovedUpgrade is a ClusterExtension annotation holding the digest
+	// of an approved upgrade preview, see status.upgradePreview. It lifts the block
+	// imposed by the "Manual" upgrade approval policy for the previewed upgrade.
+	AnnotationApprovedUpgrade = "olm.operatorframework.io/approved-upgrade"
+
+	// LabelExtensionConfig is the label that Secrets and ConfigMaps referenced by a
+	// ClusterExtension configuration must carry. Only labeled objects are watched by the
+	// controller and read as configuration. The label value is ignored.
+	LabelExtensionConfig = "olm.operatorframework.io/extension-config"
+
+	ClusterExtensionConfigTypeInline       ClusterExtensionConfigType = "Inline"
+	ClusterExtensionConfigTypeSecretRef    ClusterExtensionConfigType = "SecretRef"
+	ClusterExtensionConfigTypeConfigMapRef ClusterExtensionConfigType = "ConfigMapRef"
 )
 
 // ClusterExtensionSpec defines the desired state of ClusterExtension
@@ -78,8 +212,14 @@ type ClusterExtensionSpec struct {
 	// source is required and selects the installation source of content for this ClusterExtension.
 	// Set the sourceType field to perform the selection.
 	//
+	// <opcon:standard:description>
 	// Catalog is currently the only implemented sourceType.
 	// Setting sourceType to "Catalog" requires the catalog field to also be defined.
+	// </opcon:standard:description>
+	// <opcon:experimental:description>
+	// Setting sourceType to "Catalog" requires the catalog field to also be defined.
+	// Setting sourceType to "Helm" requires the helm field to also be defined.
+	// </opcon:experimental:description>
 	//
 	// Below is a minimal example of a source definition (in yaml):
 	//
@@ -89,12 +229,15 @@ type ClusterExtensionSpec struct {
 	//     packageName: example-package
 	//
 	// +required
+	// <opcon:experimental:validation:XValidation:rule="self.sourceType == 'Helm' ? has(self.helm) : !has(self.helm)",message="helm is required when sourceType is Helm, and forbidden otherwise">
 	Source SourceConfig `json:"source"`
 
 	// install is optional and configures installation options for the ClusterExtension,
 	// such as the pre-flight check configuration.
 	//
 	// +optional
+	// <opcon:standard:validation:XValidation:rule="has(self.preflight)",message="at least one of [preflight] are required when install is specified">
+	// <opcon:experimental:validation:XValidation:rule="has(self.preflight) || has(self.historyLimit) || has(self.rollback) || has(self.upgradeApproval) || has(self.patches) || has(self.namespace) || has(self.probes) || has(self.rollout) || has(self.collisionProtection) || has(self.uninstallPolicy) || has(self.ignoreDifferences)",message="at least one of [preflight, historyLimit, rollback, upgradeApproval, patches, namespace, probes, rollout, collisionProtection, uninstallPolicy, ignoreDifferences] are required when install is specified">
 	Install *ClusterExtensionInstallConfig `json:"install,omitempty"`
 
 	// config is optional and specifies bundle-specific configuration.
@@ -106,6 +249,10 @@ type ClusterExtensionSpec struct {
 	// to configure bundles can be found in the OLM documentation associated with your current OLM version.
 	//
 	// +optional
+	// <opcon:standard:validation:XValidation:rule="has(self.configType) && self.configType == 'Inline' ?has(self.inline) : !has(self.inline)",message="inline is required when configType is Inline, and forbidden otherwise">
+	// <opcon:experimental:validation:XValidation:rule="has(self.configType) && self.configType == 'Inline' ? has(self.inline) : true",message="inline is required when configType is Inline">
+	// <opcon:experimental:validation:XValidation:rule="self.configType == 'SecretRef' ? has(self.secretRef) : !has(self.secretRef)",message="secretRef is required when configType is SecretRef, and forbidden otherwise">
+	// <opcon:experimental:validation:XValidation:rule="self.configType == 'ConfigMapRef' ? has(self.configMapRef) : !has(self.configMapRef)",message="configMapRef is required when configType is ConfigMapRef, and forbidden otherwise">
 	Config *ClusterExtensionConfig `json:"config,omitempty"`
 
 	// progressDeadlineMinutes is an optional field that defines the maximum period
@@ -118,9 +265,45 @@ type ClusterExtensionSpec struct {
 	// +optional
 	// <opcon:experimental>
 	ProgressDeadlineMinutes int32 `json:"progressDeadlineMinutes,omitempty"`
+
+	// onFailure is optional and configures what happens when an upgrade fails to roll out:
+	// when a new revision does not become available within progressDeadlineMinutes, or when
+	// the upgrade of the Helm release fails. Helm releases are not checked for availability,
+	// so progressDeadlineMinutes does not trigger rollbacks of Helm releases.
+	//
+	// Allowed values are "None" or "Rollback". The default value is "None".
+	//
+	// When set to "None", the failed revision stays in place and requires manual intervention.
+	//
+	// When set to "Rollback", the content of the previously installed revision is restored, and
+	// the bundle that failed is not installed again until the ClusterExtension is annotated with
+	// olm.operatorframework.io/retry-failed-revision set to the number of the failed revision.
+	// Newer bundles are still installed.
+	//
+	// +kubebuilder:validation:Enum:="None";"Rollback"
+	// +optional
+	// <opcon:experimental>
+	OnFailure OnFailurePolicy `json:"onFailure,omitempty"`
+
+	// paused is optional and stops the reconciliation of the ClusterExtension when set to true.
+	//
+	// While paused, no bundle is resolved or installed, and changes to the installed objects are
+	// not reverted, so that they can be changed by hand, for example to mitigate an incident.
+	// The Progressing condition reports the reason "Paused". Deleting a paused ClusterExtension
+	// still uninstalls it.
+	//
+	// When set back to false, the installed objects are reconciled with the bundle again, and
+	// changes made while paused are reverted.
+	//
+	// +optional
+	// <opcon:experimental>
+	Paused bool `json:"paused,omitempty"`
 }
 
-const SourceTypeCatalog = "Catalog"
+const (
+	SourceTypeCatalog = "Catalog"
+	SourceTypeHelm    = "Helm"
+)
 
 // SourceConfig is a discriminated union which selects the installation source.
 //
@@ -129,14 +312,25 @@ const SourceTypeCatalog = "Catalog"
 type SourceConfig struct {
 	// sourceType is required and specifies the type of install source.
 	//
+	// <opcon:standard:description>
 	// The only allowed value is "Catalog".
+	// </opcon:standard:description>
+	// <opcon:experimental:description>
+	// Allowed values are "Catalog" and "Helm".
+	// </opcon:experimental:description>
 	//
 	// When set to "Catalog", information for determining the appropriate bundle of content to install
 	// is fetched from ClusterCatalog resources on the cluster.
 	// When using the Catalog sourceType, the catalog field must also be set.
+	// <opcon:experimental:description>
+	//
+	// When set to "Helm", a Helm chart is installed directly from an HTTP chart repository or an OCI registry,
+	// without a ClusterCatalog. When using the Helm sourceType, the helm field must also be set.
+	// </opcon:experimental:description>
 	//
 	// +unionDiscriminator
 	// +kubebuilder:validation:Enum:="Catalog"
+	// <opcon:experimental:validation:Enum=Catalog;Helm>
 	// +required
 	SourceType string `json:"sourceType"`
 
@@ -145,12 +339,58 @@ type SourceConfig struct {
 	//
 	// +optional
 	Catalog *CatalogFilter `json:"catalog,omitempty"`
+
+	// helm configures the Helm chart to install and the repository it is sourced from.
+	// It is required when sourceType is "Helm", and forbidden otherwise.
+	//
+	// +optional
+	// <opcon:experimental>
+	Helm *HelmSource `json:"helm,omitempty"`
+}
+
+// HelmSource identifies a Helm chart in an HTTP chart repository or an OCI registry.
+type HelmSource struct {
+	// repository is required and specifies the chart repository that the chart is sourced from.
+	//
+	// For an HTTP chart repository, it is the http:// or https:// URL of the repository. The chart
+	// version is resolved from the index.yaml file of the repository.
+	//
+	// For an OCI registry, it is the oci:// reference of the repository that contains the chart,
+	// without the chart name, for example "oci://ghcr.io/example/charts". The chart version is
+	// resolved from the tags of the chart repository.
+	//
+	// +kubebuilder:validation:MaxLength:=1024
+	// +kubebuilder:validation:XValidation:rule="self.startsWith('http://') || self.startsWith('https://') || self.startsWith('oci://')",message="repository must be an http://, https:// or oci:// URL"
+	// +required
+	Repository string `json:"repository"`
+
+	// chart is required and specifies the name of the chart to install.
+	//
+	// It must contain only lowercase alphanumeric characters or hyphens (-), start and end with
+	// an alphanumeric character, and be no longer than 253 characters.
+	//
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:XValidation:rule="self.matches(\"^[a-z0-9]([-a-z0-9]*[a-z0-9])?$\")",message="chart must contain only lowercase alphanumeric characters or hyphens (-), and start and end with an alphanumeric character"
+	// +required
+	Chart string `json:"chart"`
+
+	// version is an optional semver constraint (a specific version or range of versions) of the chart.
+	// It uses the same syntax as the version field of a catalog source.
+	//
+	// When unspecified, the highest version of the chart that is not a pre-release is installed.
+	// When specified, the highest version of the chart that satisfies the constraint is installed.
+	// There is no upgrade graph for Helm charts, so any version that satisfies the constraint may
+	// be installed, including a downgrade.
+	//
+	// +kubebuilder:validation:MaxLength:=64
+	// +kubebuilder:validation:XValidation:rule="self.matches(\"^(\\\\s*(=||!=|>|<|>=|=>|<=|=<|~|~>|\\\\^)\\\\s*(v?(0|[1-9]\\\\d*|[x|X|\\\\*])(\\\\.(0|[1-9]\\\\d*|x|X|\\\\*]))?(\\\\.(0|[1-9]\\\\d*|x|X|\\\\*))?(-([0-9A-Za-z\\\\-]+(\\\\.[0-9A-Za-z\\\\-]+)*))?(\\\\+([0-9A-Za-z\\\\-]+(\\\\.[0-9A-Za-z\\\\-]+)*))?)\\\\s*)((?:\\\\s+|,\\\\s*|\\\\s*\\\\|\\\\|\\\\s*)(=||!=|>|<|>=|=>|<=|=<|~|~>|\\\\^)\\\\s*(v?(0|[1-9]\\\\d*|x|X|\\\\*])(\\\\.(0|[1-9]\\\\d*|x|X|\\\\*))?(\\\\.(0|[1-9]\\\\d*|x|X|\\\\*]))?(-([0-9A-Za-z\\\\-]+(\\\\.[0-9A-Za-z\\\\-]+)*))?(\\\\+([0-9A-Za-z\\\\-]+(\\\\.[0-9A-Za-z\\\\-]+)*))?)\\\\s*)*$\")",message="invalid version expression"
+	// +optional
+	Version string `json:"version,omitempty"`
 }
 
 // ClusterExtensionInstallConfig is a union which selects the clusterExtension installation config.
 // ClusterExtensionInstallConfig requires the namespace and serviceAccount which should be used for the installation of packages.
 //
-// +kubebuilder:validation:XValidation:rule="has(self.preflight)",message="at least one of [preflight] are required when install is specified"
 // +union
 type ClusterExtensionInstallConfig struct {
 	// preflight is optional and configures the checks that run before installation or upgrade
@@ -161,22 +401,480 @@ type ClusterExtensionInstallConfig struct {
 	//
 	// +optional
 	Preflight *PreflightConfig `json:"preflight,omitempty"`
+
+	// historyLimit is optional and sets the number of revisions of the installed content
+	// that are retained and available for rollback.
+	//
+	// When not specified, the 10 most recent Helm release revisions, or the 5 most recent
+	// ClusterExtensionRevisions, are retained. Active ClusterExtensionRevisions are never
+	// deleted, only archived ones are. The minimum value is 1, and the maximum is 100.
+	//
+	// A summary of the revisions is kept in status.history after they are deleted.
+	//
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=100
+	// +optional
+	// <opcon:experimental>
+	HistoryLimit int32 `json:"historyLimit,omitempty"`
+
+	// rollback is optional and requests a rollback of the installed content to a previous revision.
+	//
+	// While rollback is specified, the content of the requested revision is installed and
+	// no new bundles are resolved. Remove rollback to resume resolution.
+	//
+	// +optional
+	// <opcon:experimental>
+	Rollback *ClusterExtensionRollback `json:"rollback,omitempty"`
+
+	// upgradeApproval is optional and configures whether upgrades of the installed content
+	// require approval.
+	//
+	// Allowed values are "Automatic" or "Manual". The default value is "Automatic".
+	//
+	// When set to "Automatic", upgrades are applied as soon as they are resolved.
+	//
+	// When set to "Manual", upgrades that change the installed objects are previewed in
+	// status.upgradePreview and are not applied until the ClusterExtension is annotated with
+	// olm.operatorframework.io/approved-upgrade set to the digest of the preview.
+	//
+	// +kubebuilder:validation:Enum:="Automatic";"Manual"
+	// +optional
+	// <opcon:experimental>
+	UpgradeApproval UpgradeApprovalPolicy `json:"upgradeApproval,omitempty"`
+
+	// patches is optional and lists patches that are applied, in order, to the objects rendered
+	// from the bundle before they are installed. Patched objects are subject to the same
+	// permission and preflight checks as unpatched ones.
+	//
+	// The labels that OLM sets on installed objects can't be changed by patches.
+	//
+	// +kubebuilder:validation:MaxItems:=32
+	// +listType=atomic
+	// +optional
+	// <opcon:experimental>
+	Patches []ClusterExtensionPatch `json:"patches,omitempty"`
+
+	// namespace is optional and configures the creation of the namespace specified in
+	// spec.namespace.
+	//
+	// When specified, OLM applies the namespace with the configured labels and annotations,
+	// using the ServiceAccount specified in spec.serviceAccount, and keeps them up to date. An
+	// existing namespace is taken over unless it is controlled by another owner, in which
+	// case it is neither changed nor deleted. The namespace is deleted when the
+	// ClusterExtension is deleted.
+	//
+	// When not specified, the namespace must exist before the ClusterExtension is installed.
+	//
+	// +optional
+	// <opcon:experimental>
+	Namespace *ClusterExtensionInstallNamespace `json:"namespace,omitempty"`
+
+	// probes is optional and lists readiness probes of the installed objects. A phase of the
+	// rollout of a ClusterExtensionRevision completes once its objects pass the built-in
+	// probes, such as the availability of Deployments, and the probes selecting them.
+	//
+	// Probes are only used when the content is installed with ClusterExtensionRevisions.
+	//
+	// +kubebuilder:validation:MaxItems:=32
+	// +listType=atomic
+	// +optional
+	// <opcon:experimental>
+	Probes []ClusterExtensionProbe `json:"probes,omitempty"`
+
+	// rollout is optional and configures how the Deployments of the installed content are
+	// rolled out.
+	//
+	// Rollout strategies are only used when the content is installed with
+	// ClusterExtensionRevisions.
+	//
+	// +optional
+	// <opcon:experimental>
+	Rollout *ClusterExtensionRollout `json:"rollout,omitempty"`
+
+	// collisionProtection is optional and configures whether objects that exist on the cluster
+	// before they are installed, and were not installed by this ClusterExtension, are adopted.
+	// Adopted objects are managed by the ClusterExtension from then on, and are reported in
+	// status.activeRevisions.
+	//
+	// When not specified, existing objects are never adopted, and their installation is
+	// retried until they are removed.
+	//
+	// Collision protection is only used when the content is installed with
+	// ClusterExtensionRevisions.
+	//
+	// +optional
+	// <opcon:experimental>
+	CollisionProtection *ClusterExtensionCollisionProtection `json:"collisionProtection,omitempty"`
+
+	// uninstallPolicy is optional and configures which installed objects are kept when the
+	// ClusterExtension is deleted.
+	//
+	// Allowed values are "Delete", "OrphanCRDs" or "OrphanAll". The default value is "Delete".
+	//
+	// When set to "Delete", all installed objects are deleted, including CustomResourceDefinitions
+	// and, with them, all custom resources.
+	//
+	// When set to "OrphanCRDs", the CustomResourceDefinitions, and the custom resources, are kept,
+	// along with the installed namespaces, including the namespace created for spec.install.namespace,
+	// as deleting them deletes the custom resources they hold. All other installed objects are deleted.
+	//
+	// When set to "OrphanAll", all installed objects are kept.
+	//
+	// Kept objects are no longer owned by, or labeled as managed by, the ClusterExtension, so that
+	// they can be adopted by another ClusterExtension later. The policy is only applied when the
+	// ClusterExtension is deleted with background cascading deletion, which is the default.
+	//
+	// +kubebuilder:validation:Enum:="Delete";"OrphanCRDs";"OrphanAll"
+	// +optional
+	// <opcon:experimental>
+	UninstallPolicy UninstallPolicy `json:"uninstallPolicy,omitempty"`
+
+	// ignoreDifferences is optional and lists fields of the installed objects that OLM does not
+	// revert when they are changed on the cluster, for example the replicas of a Deployment
+	// that are scaled by a HorizontalPodAutoscaler, or annotations injected by another controller.
+	//
+	// The fields are left out of the objects applied to existing objects, so that they keep the
+	// value they have on the cluster. Objects that don't exist are created with all of their
+	// fields. Changes to all other fields are reverted, and reported in status.driftDetected.
+	// When the content is installed with Helm releases, upgrades set the fields to the values
+	// of the bundle.
+	//
+	// +kubebuilder:validation:MaxItems:=32
+	// +listType=atomic
+	// +optional
+	// <opcon:experimental>
+	IgnoreDifferences []IgnoreDifference `json:"ignoreDifferences,omitempty"`
+}
+
+// IgnoreDifference lists fields of the installed objects that it selects whose changes on the
+// cluster are not reverted.
+type IgnoreDifference struct {
+	// selector is required and selects the objects whose fields are ignored.
+	//
+	// +required
+	Selector ProbeSelector `json:"selector"`
+
+	// jsonPointers is required and lists the ignored fields as JSON pointers (RFC 6901), for
+	// example "/spec/replicas", or "/metadata/annotations/example.com~1injected" for the
+	// annotation "example.com/injected". All values below an ignored field are ignored.
+	//
+	// The apiVersion, kind, name and namespace of the objects, and the labels that OLM sets on
+	// them, can't be ignored.
+	//
+	// +kubebuilder:validation:MinItems:=1
+	// +kubebuilder:validation:MaxItems:=16
+	// +kubebuilder:validation:items:MaxLength:=256
+	// +kubebuilder:validation:XValidation:rule="self.all(p, p.startsWith('/'))",message="jsonPointers must start with /"
+	// +kubebuilder:validation:XValidation:rule="self.all(p, !(p in ['/apiVersion', '/kind', '/metadata', '/metadata/name', '/metadata/namespace', '/metadata/labels']) && !p.startsWith('/metadata/labels/olm.operatorframework.io~1'))",message="jsonPointers must not select the apiVersion, kind, name, namespace or OLM labels of objects"
+	// +listType=atomic
+	// +required
+	JSONPointers []string `json:"jsonPointers"`
+}
+
+// UninstallPolicy specifies which installed objects are kept when a ClusterExtension is deleted.
+type UninstallPolicy string
+
+const (
+	// UninstallPolicyDelete deletes all installed objects.
+	UninstallPolicyDelete UninstallPolicy = "Delete"
+	// UninstallPolicyOrphanCRDs keeps the installed CustomResourceDefinitions and namespaces,
+	// and deletes all other installed objects.
+	UninstallPolicyOrphanCRDs UninstallPolicy = "OrphanCRDs"
+	// UninstallPolicyOrphanAll keeps all installed objects.
+	UninstallPolicyOrphanAll UninstallPolicy = "OrphanAll"
+)
+
+// ClusterExtensionCollisionProtection configures the adoption of existing objects by a ClusterExtension.
+type ClusterExtensionCollisionProtection struct {
+	// policy is optional and is the collision protection of the objects that are not selected
+	// by an override.
+	//
+	// Allowed values are "Prevent", "IfNoController" or "None". The default value is "Prevent".
+	//
+	// When set to "Prevent", existing objects are not adopted.
+	//
+	// When set to "IfNoController", existing objects are adopted unless they are controlled by
+	// another owner, such as an OLM v0 ClusterServiceVersion.
+	//
+	// When set to "None", existing objects are adopted even if they are controlled by another
+	// owner. Use this setting with extreme caution, as the other owner may keep changing the
+	// objects.
+	//
+	// +kubebuilder:validation:Enum:="Prevent";"IfNoController";"None"
+	// +optional
+	Policy CollisionProtection `json:"policy,omitempty"`
+
+	// overrides is optional and lists the collision protection of the objects that they select.
+	// The first override selecting an object applies.
+	//
+	// +kubebuilder:validation:MaxItems:=32
+	// +listType=atomic
+	// +optional
+	Overrides []CollisionProtectionOverride `json:"overrides,omitempty"`
+}
+
+// CollisionProtectionOverride configures the collision protection of the installed objects that it selects.
+type CollisionProtectionOverride struct {
+	// selector is required and selects the objects that the override applies to.
+	//
+	// +required
+	Selector ProbeSelector `json:"selector"`
+
+	// policy is required and is the collision protection of the selected objects.
+	//
+	// Allowed values are "Prevent", "IfNoController" or "None", see the policy of
+	// collisionProtection.
+	//
+	// +kubebuilder:validation:Enum:="Prevent";"IfNoController";"None"
+	// +required
+	Policy CollisionProtection `json:"policy"`
+}
+
+// ClusterExtensionRollout configures the rollout strategy of a ClusterExtension.
+//
+// +kubebuilder:validation:XValidation:rule="self.strategy == 'Canary' ? has(self.canary) : !has(self.canary)",message="canary is required when strategy is Canary, and forbidden otherwise"
+// +union
+type ClusterExtensionRollout struct {
+	// strategy is required and specifies the rollout strategy.
+	//
+	// Allowed values are "AllAtOnce" or "Canary".
+	//
+	// When set to "AllAtOnce", a revision completes as soon as all of its objects are
+	// available.
+	//
+	// When set to "Canary", the Deployments of a revision first roll out a single canary pod,
+	// which must run for a soak period configured in the canary field, while the other pods
+	// keep running the previous version. Only once the canary soaked do the rest of the pods,
+	// and then the objects of later phases, such as webhook configurations and APIServices,
+	// roll out. A revision whose Deployments fail the soak is blocked, and is reported with the
+	// SoakFailed reason.
+	//
+	// +unionDiscriminator
+	// +kubebuilder:validation:Enum:="AllAtOnce";"Canary"
+	// +required
+	Strategy RolloutStrategy `json:"strategy"`
+
+	// canary configures the Canary strategy.
+	//
+	// +optional
+	Canary *CanaryRollout `json:"canary,omitempty"`
+}
+
+// CanaryRollout configures the soak of the Deployments of a revision.
+type CanaryRollout struct {
+	// soakSeconds is required and is the time, in seconds, that the canary pods of the
+	// Deployments of a revision must be ready before the rest of their pods roll out. The soak
+	// ends no earlier than soakSeconds after the creation of the revision.
+	//
+	// The minimum value is 10, and the maximum is 86400 (24 hours).
+	//
+	// +kubebuilder:validation:Minimum:=10
+	// +kubebuilder:validation:Maximum:=86400
+	// +required
+	SoakSeconds int32 `json:"soakSeconds"`
+
+	// maxContainerRestarts is optional and is the number of restarts of the containers of
+	// the pods of the new ReplicaSet of a Deployment that is tolerated during the soak. When the
+	// containers restart more often, the soak fails.
+	//
+	// The default value is 0, and the maximum is 1000.
+	//
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=1000
+	// +optional
+	MaxContainerRestarts int32 `json:"maxContainerRestarts,omitempty"`
+}
+
+// ClusterExtensionProbe applies readiness probes to the installed objects that it selects.
+type ClusterExtensionProbe struct {
+	// selector is required and selects the objects that the probes apply to.
+	//
+	// +required
+	Selector ProbeSelector `json:"selector"`
+
+	// assertions is required and lists the probes that the selected objects must pass.
+	//
+	// +kubebuilder:validation:MinItems:=1
+	// +kubebuilder:validation:MaxItems:=16
+	// +kubebuilder:validation:XValidation:rule="self.all(p, p.type == 'Condition' ? has(p.condition) : !has(p.condition))",message="condition is required when type is Condition, and forbidden otherwise"
+	// +kubebuilder:validation:XValidation:rule="self.all(p, p.type == 'FieldsEqual' ? has(p.fieldsEqual) : !has(p.fieldsEqual))",message="fieldsEqual is required when type is FieldsEqual, and forbidden otherwise"
+	// +kubebuilder:validation:XValidation:rule="self.all(p, p.type == 'FieldValue' ? has(p.fieldValue) : !has(p.fieldValue))",message="fieldValue is required when type is FieldValue, and forbidden otherwise"
+	// +kubebuilder:validation:XValidation:rule="self.all(p, p.type == 'CEL' ? has(p.cel) : !has(p.cel))",message="cel is required when type is CEL, and forbidden otherwise"
+	// +listType=atomic
+	// +required
+	Assertions []ObjectProbe `json:"assertions"`
+}
+
+// ProbeSelector selects installed objects by their group and kind, and optionally by their
+// namespace and name, for probes, collision protection overrides and ignored differences. All
+// specified fields must match.
+type ProbeSelector struct {
+	// group is optional and is the API group of the objects, for example "batch".
+	// When not specified, objects of the core API group are selected.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// kind is required and is the kind of the objects, for example "Job".
+	//
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	Kind string `json:"kind"`
+
+	// namespace is optional and selects objects of the namespace.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// name is optional and selects the object with the name.
+	//
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// ClusterExtensionInstallNamespace configures the namespace that OLM manages for a ClusterExtension.
+type ClusterExtensionInstallNamespace struct {
+	// labels is optional and sets labels on the created namespace, for example
+	// pod-security.kubernetes.io/enforce to set the Pod Security Standard of the namespace.
+	//
+	// Labels that OLM sets to track the ClusterExtension that created the namespace
+	// can't be set.
+	//
+	// +kubebuilder:validation:MaxProperties:=64
+	// +kubebuilder:validation:XValidation:rule="self.all(k, !k.startsWith('olm.operatorframework.io/'))",message="labels with the olm.operatorframework.io/ prefix are reserved"
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// annotations is optional and sets annotations on the created namespace.
+	//
+	// +kubebuilder:validation:MaxProperties:=64
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ClusterExtensionPatch is a patch that is applied to the objects rendered from a bundle.
+type ClusterExtensionPatch struct {
+	// target is required and selects the objects that the patch applies to.
+	//
+	// +required
+	Target PatchTarget `json:"target"`
+
+	// type is required and is the type of the patch.
+	//
+	// Allowed values are "StrategicMerge" or "JSON6902".
+	//
+	// When set to "StrategicMerge", patch is a partial object, in YAML or JSON, that is merged into
+	// the target objects. Lists of built-in Kubernetes types, such as the containers of a Deployment,
+	// are merged by key. Lists of other types are replaced.
+	//
+	// When set to "JSON6902", patch is a list of RFC 6902 JSON patch operations, in YAML or JSON.
+	//
+	// +kubebuilder:validation:Enum:="StrategicMerge";"JSON6902"
+	// +required
+	Type PatchType `json:"type"`
+
+	// patch is required and is the content of the patch, in YAML or JSON.
+	//
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=65536
+	// +required
+	Patch string `json:"patch"`
+}
+
+// PatchTarget selects the rendered objects that a patch applies to. All specified fields must
+// match; unspecified fields match any object.
+type PatchTarget struct {
+	// group is optional and selects objects of the API group, for example "apps".
+	// When not specified, objects of all API groups, including the core API group, are selected.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// version is optional and selects objects of the API version, for example "v1".
+	//
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// kind is optional and selects objects of the kind, for example "Deployment".
+	//
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// namespace is optional and selects objects in the namespace.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// name is optional and selects the object with the name.
+	//
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// labelSelector is optional and selects objects with matching labels.
+	//
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+// ClusterExtensionRollback configures a rollback to a previous revision of the installed content.
+type ClusterExtensionRollback struct {
+	// revision is required and is the number of the revision to roll back to: the Helm release
+	// revision, or the ClusterExtensionRevision revision when revisions are managed with
+	// ClusterExtensionRevisions. Only retained revisions are available, see historyLimit.
+	//
+	// +kubebuilder:validation:Minimum:=1
+	// +required
+	Revision int64 `json:"revision"`
+
+	// constraintPolicy is optional and configures whether the version range and the upgrade
+	// constraints of the ClusterExtension apply to the rollback.
+	//
+	// Allowed values are "Enforce" or "Ignore". The default value is "Enforce".
+	//
+	// When set to "Enforce", the rollback is blocked when the bundle of the requested revision
+	// is outside of the version range of the ClusterExtension, or when it is an older version
+	// of a catalog bundle and the upgrade constraint policy is not "SelfCertified".
+	//
+	// When set to "Ignore", the rollback proceeds regardless of these constraints.
+	// Use this option with caution, as the content of older versions may not handle data
+	// written by newer versions.
+	//
+	// +kubebuilder:validation:Enum:="Enforce";"Ignore"
+	// +optional
+	ConstraintPolicy RollbackConstraintPolicy `json:"constraintPolicy,omitempty"`
 }
 
 // ClusterExtensionConfig is a discriminated union which selects the source configuration values to be merged into
 // the ClusterExtension's rendered manifests.
 //
-// +kubebuilder:validation:XValidation:rule="has(self.configType) && self.configType == 'Inline' ?has(self.inline) : !has(self.inline)",message="inline is required when configType is Inline, and forbidden otherwise"
 // +union
 type ClusterExtensionConfig struct {
 	// configType is required and specifies the type of configuration source.
 	//
+	// <opcon:standard:description>
 	// The only allowed value is "Inline".
+	// </opcon:standard:description>
+	// <opcon:experimental:description>
+	// Allowed values are "Inline", "SecretRef" and "ConfigMapRef".
+	// </opcon:experimental:description>
 	//
 	// When set to "Inline", the cluster extension configuration is defined inline within the ClusterExtension resource.
+	// <opcon:experimental:description>
+	//
+	// When set to "SecretRef", the cluster extension configuration is read from a key of a Secret in the
+	// installation namespace, specified in the secretRef field.
+	//
+	// When set to "ConfigMapRef", the cluster extension configuration is read from a key of a ConfigMap in the
+	// installation namespace, specified in the configMapRef field.
+	//
+	// When the configuration is read from a Secret or ConfigMap, inline may also be set. The inline values are
+	// merged on top of the referenced values: objects are merged recursively and all other values from inline
+	// replace the referenced ones.
+	// </opcon:experimental:description>
 	//
 	// +unionDiscriminator
 	// +kubebuilder:validation:Enum:="Inline"
+	// <opcon:experimental:validation:Enum=Inline;SecretRef;ConfigMapRef>
 	// +required
 	ConfigType ClusterExtensionConfigType `json:"configType"`
 
@@ -191,6 +889,52 @@ type ClusterExtensionConfig struct {
 	// +optional
 	// +unionMember
 	Inline *apiextensionsv1.JSON `json:"inline,omitempty"`
+
+	// secretRef references a key of a Secret in the installation namespace whose value is a JSON or YAML
+	// object containing configuration values for the ClusterExtension.
+	//
+	// It is required when configType is "SecretRef", and forbidden otherwise.
+	// The referenced Secret must carry the "olm.operatorframework.io/extension-config" label, and is read with
+	// the ServiceAccount of the ClusterExtension.
+	//
+	// +optional
+	// +unionMember
+	// <opcon:experimental>
+	SecretRef *ConfigSourceReference `json:"secretRef,omitempty"`
+
+	// configMapRef references a key of a ConfigMap in the installation namespace whose value is a JSON or YAML
+	// object containing configuration values for the ClusterExtension.
+	//
+	// It is required when configType is "ConfigMapRef", and forbidden otherwise.
+	// The referenced ConfigMap must carry the "olm.operatorframework.io/extension-config" label, and is read with
+	// the ServiceAccount of the ClusterExtension.
+	//
+	// +optional
+	// +unionMember
+	// <opcon:experimental>
+	ConfigMapRef *ConfigSourceReference `json:"configMapRef,omitempty"`
+}
+
+// ConfigSourceReference identifies a key of a Secret or ConfigMap in the installation namespace.
+type ConfigSourceReference struct {
+	// name is required and specifies the name of the referenced object in the installation namespace.
+	//
+	// It must be a valid DNS1123 subdomain and be no longer than 253 characters.
+	//
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:XValidation:rule="self.matches(\"^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$\")",message="name must be a valid DNS1123 subdomain. It must contain only lowercase alphanumeric characters, hyphens (-) or periods (.), start and end with an alphanumeric character, and be no longer than 253 characters"
+	// +required
+	Name string `json:"name"`
+
+	// key is required and specifies the key of the referenced object whose value holds the configuration.
+	//
+	// It must consist of alphanumeric characters, hyphens (-), underscores (_) or periods (.),
+	// and be no longer than 253 characters.
+	//
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=`^[-._a-zA-Z0-9]+$`
+	// +required
+	Key string `json:"key"`
 }
 
 // CatalogFilter defines the attributes used to identify and filter content from a catalog.
@@ -321,6 +1065,8 @@ type CatalogFilter struct {
 	//   - Automatic upgrades are constrained to upgrade edges defined by the selected channel
 	//
 	// When unspecified, upgrade edges across all channels are used to identify valid automatic upgrade paths.
+	// Bundles that belong to at least one channel that is not deprecated are preferred over bundles
+	// that only belong to deprecated channels.
 	//
 	// Some examples of valid values are:
 	//   - 1.1.x
@@ -374,6 +1120,109 @@ type CatalogFilter struct {
 	// +kubebuilder:default:=CatalogProvided
 	// +optional
 	UpgradeConstraintPolicy UpgradeConstraintPolicy `json:"upgradeConstraintPolicy,omitempty"`
+
+	// channelDeprecationPolicy is optional and controls how the ClusterExtension reacts when a channel
+	// listed in the channels field is deprecated by the package author and the deprecated channel names
+	// a successor channel using the "olm.channel.successor" channel property.
+	//
+	// Allowed values are "Ignore", "Migrate", "Block", or omitted.
+	//
+	// When set to "Ignore", channel deprecations are only reported in the ChannelDeprecated condition.
+	//
+	// When set to "Migrate", each deprecated channel is replaced by its successor channel during
+	// resolution, so that automatic upgrades continue along the stream of updates recommended by the
+	// package author. The ChannelDeprecated condition reports the successor channels in use.
+	//
+	// When set to "Block", the installed bundle is not upgraded while a requested channel is deprecated
+	// in favor of a successor channel. Progressing is set to False with the Blocked reason until
+	// the channels field is updated or the deprecated channel is listed in the
+	// "olm.operatorframework.io/acknowledged-deprecated-channels" annotation. Initial installs
+	// are not blocked.
+	//
+	// When omitted, the default value is "Ignore".
+	//
+	// +kubebuilder:validation:Enum:=Ignore;Migrate;Block
+	// +optional
+	// <opcon:experimental>
+	ChannelDeprecationPolicy ChannelDeprecationPolicy `json:"channelDeprecationPolicy,omitempty"`
+
+	// versionPolicy is optional and restricts the versions that can be installed relative to the
+	// installed bundle or to the newest version available in the catalog, without writing a version
+	// range by hand.
+	//
+	// This constraint is an AND operation with the version and channels fields.
+	//
+	// When unspecified, only the version, channels and upgradeConstraintPolicy fields constrain the
+	// installable bundles.
+	//
+	// +optional
+	// <opcon:experimental>
+	VersionPolicy *VersionPolicy `json:"versionPolicy,omitempty"`
+
+	// tieBreakPolicy is optional and controls how a bundle is selected when the package is found
+	// in several ClusterCatalogs that share the highest priority, for example when an upstream
+	// catalog is mirrored in-cluster.
+	//
+	// Allowed values are "Fail", "HighestVersion", "StickyCatalog", or omitted.
+	//
+	// When set to "Fail", resolution fails and reports the ambiguous catalogs.
+	//
+	// When set to "HighestVersion", the bundle with the highest version is selected. When the
+	// highest version is offered by several catalogs, the catalog whose name sorts first is selected.
+	//
+	// When set to "StickyCatalog", the bundle from the catalog that provided the installed bundle is
+	// selected, as recorded by the installed revision. When nothing is installed, or that catalog does
+	// not offer a matching bundle, the bundle is selected as with "HighestVersion".
+	//
+	// When omitted, the default value is "Fail".
+	//
+	// +kubebuilder:validation:Enum:=Fail;HighestVersion;StickyCatalog
+	// +optional
+	// <opcon:experimental>
+	TieBreakPolicy TieBreakPolicy `json:"tieBreakPolicy,omitempty"`
+}
+
+// VersionPolicy is a discriminated union of symbolic version constraints that are translated
+// into version predicates at resolution time.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.type) && self.type == 'LagBy' ? has(self.lagBy) : !has(self.lagBy)",message="lagBy is required when type is LagBy, and forbidden otherwise"
+// +union
+type VersionPolicy struct {
+	// type is required and specifies the kind of version policy.
+	//
+	// Allowed values are "PatchOnly", "MinorOnly", "Pinned" and "LagBy".
+	//
+	// When set to "PatchOnly", only bundles with the same major and minor version as the installed
+	// bundle are installable, so that only patch (z-stream) upgrades are performed.
+	//
+	// When set to "MinorOnly", only bundles with the same major version as the installed bundle are
+	// installable, so that minor and patch upgrades are performed but major upgrades are not.
+	//
+	// When set to "Pinned", only the version of the installed bundle is installable, freezing the
+	// ClusterExtension at its current version.
+	//
+	// PatchOnly, MinorOnly and Pinned are relative to the installed bundle and do not constrain the
+	// initial installation.
+	//
+	// When set to "LagBy", only bundles whose minor version is at least the number of minor versions
+	// given in the lagBy field behind the newest minor version available in the requested channels
+	// are installable. For example, when 1.4, 1.5 and 2.0 are available and lagBy is 1, the newest
+	// installable version is the newest 1.5.z release. When fewer minor versions are available, only
+	// the oldest one is installable. The installed bundle always remains installable.
+	//
+	// +unionDiscriminator
+	// +kubebuilder:validation:Enum:=PatchOnly;MinorOnly;Pinned;LagBy
+	// +required
+	Type VersionPolicyType `json:"type"`
+
+	// lagBy is the number of minor versions to stay behind the newest minor version available.
+	// It is required when type is "LagBy", and forbidden otherwise.
+	//
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=10
+	// +unionMember
+	// +optional
+	LagBy int32 `json:"lagBy,omitempty"`
 }
 
 // ServiceAccountReference identifies the serviceAccount used fo install a ClusterExtension.
@@ -443,6 +1292,10 @@ const (
 	TypeChannelDeprecated = "ChannelDeprecated"
 	TypeBundleDeprecated  = "BundleDeprecated"
 
+	// TypeIncompatibleBundles is present when bundles were excluded from
+	// resolution because they can't run on the cluster.
+	TypeIncompatibleBundles = "IncompatibleBundles"
+
 	// None will not perform CRD upgrade safety checks.
 	CRDUpgradeSafetyEnforcementNone CRDUpgradeSafetyEnforcement = "None"
 	// Strict will enforce the CRD upgrade safety check and block the upgrade if the CRD would not pass the check.
@@ -480,6 +1333,14 @@ type RevisionStatus struct {
 	// +listMapKey=type
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// adoptedObjects lists the objects that existed before the revision was rolled out and were
+	// adopted by it, see spec.install.collisionProtection.
+	//
+	// +listType=atomic
+	// +optional
+	// <opcon:experimental>
+	AdoptedObjects []AdoptedObject `json:"adoptedObjects,omitempty"`
 }
 
 // ClusterExtensionStatus defines the observed state of a ClusterExtension.
@@ -498,6 +1359,10 @@ type ClusterExtensionStatus struct {
 	// When Progressing is False and the Reason is Blocked, the ClusterExtension has encountered an error that requires manual intervention for recovery.
 	// <opcon:experimental:description>
 	// When Progressing is True and Reason is RollingOut, the ClusterExtension has one or more ClusterExtensionRevisions in active roll out.
+	// When Progressing is True and Reason is AwaitingApproval, the ClusterExtension has an upgrade that awaits approval, see upgradePreview.
+	// When Progressing is False and Reason is SoakFailed, the Deployments of the latest ClusterExtensionRevision failed the soak of the canary rollout.
+	// When Progressing is False and Reason is Paused, the reconciliation of the ClusterExtension is paused, see spec.paused.
+	// When IncompatibleBundles is True and Reason is IncompatibleWithCluster, bundles that can't run on the cluster were excluded from resolution, and the message lists them.
 	// </opcon:experimental:description>
 	//
 	// When the ClusterExtension is sourced from a catalog, it surfaces deprecation conditions based on catalog metadata.
@@ -524,6 +1389,146 @@ type ClusterExtensionStatus struct {
 	// +optional
 	// <opcon:experimental>
 	ActiveRevisions []RevisionStatus `json:"activeRevisions,omitempty"`
+
+	// upgradePreview summarizes the changes to the installed objects that upgrading to the
+	// resolved bundle, or to the current configuration, makes. It is only set while such an
+	// upgrade is pending.
+	//
+	// +optional
+	// <opcon:experimental>
+	UpgradePreview *ClusterExtensionUpgradePreview `json:"upgradePreview,omitempty"`
+
+	// history summarizes the revisions of the installed content, oldest first. Entries are kept
+	// after the revisions are deleted, see spec.install.historyLimit, up to the 100 most recent
+	// revisions.
+	//
+	// +listType=map
+	// +listMapKey=revision
+	// +kubebuilder:validation:MaxItems:=100
+	// +optional
+	// <opcon:experimental>
+	History []RevisionHistoryEntry `json:"history,omitempty"`
+
+	// driftDetected reports the last changes to the installed objects, made on the cluster, that
+	// were reverted. Fields listed in spec.install.ignoreDifferences are not reverted. When the
+	// content is installed with ClusterExtensionRevisions, it reports the changes reverted by the
+	// installed revision.
+	//
+	// +optional
+	// <opcon:experimental>
+	DriftDetected *DriftDetected `json:"driftDetected,omitempty"`
+}
+
+// RevisionHistoryEntry summarizes a revision of the installed content: a Helm release revision,
+// or a ClusterExtensionRevision.
+type RevisionHistoryEntry struct {
+	// revision is the number of the revision.
+	//
+	// +required
+	// +kubebuilder:validation:Minimum:=1
+	Revision int64 `json:"revision"`
+
+	// bundleName is the name of the bundle installed by the revision.
+	//
+	// +kubebuilder:validation:MaxLength:=253
+	// +optional
+	BundleName string `json:"bundleName,omitempty"`
+
+	// bundleVersion is the version of the bundle installed by the revision.
+	//
+	// +kubebuilder:validation:MaxLength:=64
+	// +optional
+	BundleVersion string `json:"bundleVersion,omitempty"`
+
+	// outcome is the outcome of the rollout of the revision.
+	//
+	// Allowed values are "RollingOut", "Succeeded", "Failed" or "Abandoned".
+	//
+	// When set to "RollingOut", the revision is rolling out.
+	//
+	// When set to "Succeeded", the revision rolled out, at completedAt.
+	//
+	// When set to "Failed", the revision failed to roll out, at completedAt.
+	//
+	// When set to "Abandoned", the revision was superseded by a newer revision before it rolled out.
+	//
+	// +kubebuilder:validation:Enum:="RollingOut";"Succeeded";"Failed";"Abandoned"
+	// +required
+	Outcome RevisionOutcome `json:"outcome"`
+
+	// createdAt is the time the revision was created.
+	//
+	// +required
+	CreatedAt metav1.Time `json:"createdAt"`
+
+	// completedAt is the time the revision rolled out, or failed to roll out.
+	//
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// supersededAt is the time a newer revision superseded the revision.
+	//
+	// +optional
+	SupersededAt *metav1.Time `json:"supersededAt,omitempty"`
+}
+
+// ClusterExtensionUpgradePreview summarizes the changes to the installed objects that an upgrade makes.
+type ClusterExtensionUpgradePreview struct {
+	// bundle is required and represents the identifying attributes of the bundle that is upgraded to.
+	//
+	// +required
+	Bundle BundleMetadata `json:"bundle"`
+
+	// digest is required and identifies the previewed upgrade by its bundle and by the configuration
+	// and patches of the ClusterExtension. Annotating the ClusterExtension with
+	// olm.operatorframework.io/approved-upgrade set to the digest approves the upgrade when
+	// spec.install.upgradeApproval is "Manual".
+	//
+	// +required
+	Digest string `json:"digest"`
+
+	// added is the number of objects that the upgrade creates.
+	//
+	// +optional
+	Added int32 `json:"added,omitempty"`
+
+	// removed is the number of objects that the upgrade deletes.
+	//
+	// +optional
+	Removed int32 `json:"removed,omitempty"`
+
+	// changed is the number of objects that the upgrade changes.
+	//
+	// +optional
+	Changed int32 `json:"changed,omitempty"`
+
+	// objects lists the objects that the upgrade adds, removes or changes, sorted by group, kind,
+	// namespace and name. At most 100 objects are listed.
+	//
+	// +kubebuilder:validation:MaxItems:=100
+	// +listType=atomic
+	// +optional
+	Objects []UpgradePreviewObject `json:"objects,omitempty"`
+}
+
+// UpgradePreviewObject describes the change that an upgrade makes to a single object.
+type UpgradePreviewObject struct {
+	// action is required and is one of "Added", "Removed" or "Changed".
+	//
+	// +kubebuilder:validation:Enum:="Added";"Removed";"Changed"
+	// +required
+	Action UpgradePreviewAction `json:"action"`
+
+	ObjectReference `json:",inline"`
+
+	// fields lists the paths of the fields that the upgrade changes, for example
+	// "spec.template.spec.containers[0].image", when the action is "Changed".
+	// At most 20 fields are listed.
+	//
+	// +kubebuilder:validation:MaxItems:=20
+	// +listType=atomic
+	// +optional
+	Fields []string `json:"fields,omitempty"`
 }
 
 // ClusterExtensionInstallStatus is a representation of the status of the identified bundle.
@@ -535,6 +1540,14 @@ type ClusterExtensionInstallStatus struct {
 	//
 	// +required
 	Bundle BundleMetadata `json:"bundle"`
+
+	// catalog is the name of the ClusterCatalog that provided the installed bundle.
+	// It is empty when the catalog is not known, for example for bundles installed
+	// before the catalog was recorded.
+	//
+	// +optional
+	// <opcon:experimental>
+	Catalog string `json:"catalog,omitempty"`
 }
 
 // +kubebuilder:object:root=true
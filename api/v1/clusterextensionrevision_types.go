@@ -36,6 +36,18 @@ const (
 	ClusterExtensionRevisionReasonProbesSucceeded = "ProbesSucceeded"
 	ClusterExtensionRevisionReasonReconciling     = "Reconciling"
 	ClusterExtensionRevisionReasonRetrying        = "Retrying"
+
+	// AnnotationPhase is an annotation of the objects of a bundle naming the phase of
+	// the ClusterExtensionRevision that the object is rolled out in. The phase is either
+	// one of the well-known phases, such as "crds" or "deploy", or a custom phase.
+	AnnotationPhase = "olm.operatorframework.io/phase"
+
+	// AnnotationPhaseWeight is an annotation of the objects of a bundle holding the
+	// integer weight of a custom phase, see AnnotationPhase. Phases are rolled out in
+	// order of increasing weight. The well-known phases have the fixed weights 100
+	// (namespaces) to 800 (publish), in steps of 100, and custom phases default to
+	// the weight of the deploy phase.
+	AnnotationPhaseWeight = "olm.operatorframework.io/phase-weight"
 )
 
 // ClusterExtensionRevisionSpec defines the desired state of ClusterExtensionRevision.
@@ -77,12 +89,16 @@ type ClusterExtensionRevisionSpec struct {
 	//   - deploy: Deployment, StatefulSet, DaemonSet, Service, ConfigMap, Secret objects
 	//   - publish: Ingress, APIService, Route, Webhook objects
 	//
+	// Objects of a bundle may override their phase, and declare custom phases, with the
+	// olm.operatorframework.io/phase and olm.operatorframework.io/phase-weight annotations.
+	//
 	// All objects in a phase are applied in no particular order.
 	// The revision progresses to the next phase only after all objects in the current phase pass their readiness probes.
 	//
-	// Once set, even if empty, the phases field is immutable.
+	// Once set, even if empty, the phases field is immutable. A revision has at most 20 phases.
 	//
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf || oldSelf.size() == 0", message="phases is immutable"
+	// +kubebuilder:validation:MaxItems:=20
 	// +listType=map
 	// +listMapKey=name
 	// +optional
@@ -98,6 +114,44 @@ type ClusterExtensionRevisionSpec struct {
 	// +optional
 	// <opcon:experimental>
 	ProgressDeadlineMinutes int32 `json:"progressDeadlineMinutes,omitempty"`
+
+	// canary is optional and configures the soak of the Deployments of the revision. When set,
+	// the Deployments roll out a single canary pod, which must run for the soak period before
+	// the rest of their pods and the objects of later phases are rolled out and the revision
+	// completes.
+	//
+	// +optional
+	// <opcon:experimental>
+	Canary *CanaryRollout `json:"canary,omitempty"`
+
+	// uninstallPolicy is optional and configures which objects of the revision are kept when
+	// the revision is deleted because its ClusterExtension was deleted. It is kept in sync with
+	// spec.install.uninstallPolicy of the ClusterExtension on the latest revision.
+	//
+	// Allowed values are "Delete", "OrphanCRDs" or "OrphanAll". The default value is "Delete".
+	//
+	// +kubebuilder:validation:Enum:="Delete";"OrphanCRDs";"OrphanAll"
+	// +optional
+	// <opcon:experimental>
+	UninstallPolicy UninstallPolicy `json:"uninstallPolicy,omitempty"`
+
+	// paused is optional and stops the reconciliation of the objects of the revision when set
+	// to true. It is set while the ClusterExtension of the revision is paused.
+	//
+	// +optional
+	// <opcon:experimental>
+	Paused bool `json:"paused,omitempty"`
+
+	// ignoreDifferences is optional and lists fields of the objects of the revision that are left
+	// out when existing objects are applied, so that they keep the value they have on the cluster
+	// instead of being reverted. It is kept in sync with
+	// spec.install.ignoreDifferences of the ClusterExtension on the latest revision.
+	//
+	// +kubebuilder:validation:MaxItems:=32
+	// +listType=atomic
+	// +optional
+	// <opcon:experimental>
+	IgnoreDifferences []IgnoreDifference `json:"ignoreDifferences,omitempty"`
 }
 
 // ClusterExtensionRevisionLifecycleState specifies the lifecycle state of the ClusterExtensionRevision.
@@ -122,6 +176,7 @@ type ClusterExtensionRevisionPhase struct {
 	// start and end with an alphanumeric character, and be no longer than 63 characters.
 	//
 	// Common phase names include: namespaces, policies, rbac, crds, storage, deploy, publish.
+	// Bundles may declare custom phases with the olm.operatorframework.io/phase annotation.
 	//
 	// [RFC 1123]: https://tools.ietf.org/html/rfc1123
 	//
@@ -165,6 +220,151 @@ type ClusterExtensionRevisionObject struct {
 	// +kubebuilder:validation:Enum=Prevent;IfNoController;None
 	// +optional
 	CollisionProtection CollisionProtection `json:"collisionProtection,omitempty"`
+
+	// probes is optional and lists readiness probes that the object must pass, in addition
+	// to the built-in probes, before the phase of the object is complete.
+	//
+	// +kubebuilder:validation:MaxItems=16
+	// +listType=atomic
+	// +optional
+	Probes []ObjectProbe `json:"probes,omitempty"`
+}
+
+// ObjectProbeType specifies the type of an ObjectProbe.
+type ObjectProbeType string
+
+const (
+	// ObjectProbeTypeCondition checks the status of a condition of the object.
+	ObjectProbeTypeCondition ObjectProbeType = "Condition"
+	// ObjectProbeTypeFieldsEqual checks that two fields of the object have the same value.
+	ObjectProbeTypeFieldsEqual ObjectProbeType = "FieldsEqual"
+	// ObjectProbeTypeFieldValue checks that a field of the object has a value.
+	ObjectProbeTypeFieldValue ObjectProbeType = "FieldValue"
+	// ObjectProbeTypeCEL checks that a CEL expression over the object evaluates to true.
+	ObjectProbeTypeCEL ObjectProbeType = "CEL"
+)
+
+// ObjectProbe is a readiness check of an object. It is a discriminated union, and the field
+// matching the type must be set.
+//
+// +union
+type ObjectProbe struct {
+	// type is required and specifies the type of the probe.
+	//
+	// Allowed values are "Condition", "FieldsEqual", "FieldValue" and "CEL".
+	//
+	// When set to "Condition", the probe passes when the condition specified in the condition
+	// field has the expected status, and was observed for the current generation of the object.
+	//
+	// When set to "FieldsEqual", the probe passes when the fields specified in the fieldsEqual
+	// field have the same value.
+	//
+	// When set to "FieldValue", the probe passes when the field specified in the fieldValue
+	// field has the expected value.
+	//
+	// When set to "CEL", the probe passes when the CEL expression specified in the cel field
+	// evaluates to true.
+	//
+	// +unionDiscriminator
+	// +kubebuilder:validation:Enum:="Condition";"FieldsEqual";"FieldValue";"CEL"
+	// +required
+	Type ObjectProbeType `json:"type"`
+
+	// condition configures a probe of type Condition.
+	//
+	// +optional
+	Condition *ConditionProbe `json:"condition,omitempty"`
+
+	// fieldsEqual configures a probe of type FieldsEqual.
+	//
+	// +optional
+	FieldsEqual *FieldsEqualProbe `json:"fieldsEqual,omitempty"`
+
+	// fieldValue configures a probe of type FieldValue.
+	//
+	// +optional
+	FieldValue *FieldValueProbe `json:"fieldValue,omitempty"`
+
+	// cel configures a probe of type CEL.
+	//
+	// +optional
+	CEL *CELProbe `json:"cel,omitempty"`
+}
+
+// ConditionProbe checks the status of a condition in status.conditions of an object.
+type ConditionProbe struct {
+	// type is required and is the type of the condition, for example "Ready".
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=316
+	// +required
+	Type string `json:"type"`
+
+	// status is required and is the expected status of the condition.
+	//
+	// Allowed values are "True", "False" and "Unknown".
+	//
+	// +kubebuilder:validation:Enum:="True";"False";"Unknown"
+	// +required
+	Status string `json:"status"`
+}
+
+// FieldsEqualProbe checks that two fields of an object have the same value. Fields are
+// specified by their path in the object, for example ".status.updatedReplicas".
+type FieldsEqualProbe struct {
+	// fieldA is required and is the path of the first field.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=256
+	// +required
+	FieldA string `json:"fieldA"`
+
+	// fieldB is required and is the path of the second field.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=256
+	// +required
+	FieldB string `json:"fieldB"`
+}
+
+// FieldValueProbe checks that a field of an object has a value.
+type FieldValueProbe struct {
+	// fieldPath is required and is the path of the field in the object, for example
+	// ".status.phase".
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=256
+	// +required
+	FieldPath string `json:"fieldPath"`
+
+	// value is required and is the expected value of the field. Values that are not strings,
+	// such as numbers and booleans, are compared with their JSON representation.
+	//
+	// +kubebuilder:validation:MaxLength=1024
+	// +required
+	Value string `json:"value"`
+}
+
+// CELProbe checks that a CEL expression evaluates to true. The object is available in the
+// expression as the variable "self", for example "self.status.succeeded >= 1".
+type CELProbe struct {
+	// rule is required and is the CEL expression. It must evaluate to a boolean.
+	//
+	// Like the validation rules of CRDs, rules are subject to the cost limits of the API
+	// server. Rules whose estimated cost exceeds the limit are rejected, and evaluations
+	// that exceed the cost limit of a single call don't pass.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=4096
+	// +required
+	Rule string `json:"rule"`
+
+	// message is optional and is reported when the expression does not evaluate to true.
+	// When not specified, the rule is reported.
+	//
+	// +kubebuilder:validation:MaxLength=1024
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // CollisionProtection specifies if and how ownership collisions are prevented.
@@ -195,6 +395,11 @@ type ClusterExtensionRevisionStatus struct {
 	//   - When status is True and reason is Succeeded, the ClusterExtensionRevision has reached the desired state.
 	//   - When status is False and reason is Blocked, the ClusterExtensionRevision has encountered an error that requires manual intervention for recovery.
 	//   - When status is False and reason is Archived, the ClusterExtensionRevision is archived and not being actively reconciled.
+	//   - When status is False and reason is SoakFailed, the Deployments of the ClusterExtensionRevision failed the soak of the canary rollout, and the revision is not rolled out further.
+	//   - When status is False and reason is Paused, the ClusterExtensionRevision is paused and its objects are not being actively reconciled.
+	//
+	// While the revision is rolling out, or retrying, the message names the first object that blocks the rollout, if any.
+	// The results of all objects are reported in the phases field.
 	//
 	// The Available condition represents whether the revision has been successfully rolled out and is available:
 	//   - When status is True and reason is ProbesSucceeded, the ClusterExtensionRevision has been successfully rolled out and all objects pass their readiness probes.
@@ -210,6 +415,133 @@ type ClusterExtensionRevisionStatus struct {
 	// +listMapKey=type
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// phases reports the results of the last reconciliation of the phases of the revision, in
+	// rollout order. Phases that the rollout has not reached yet are not listed.
+	//
+	// +kubebuilder:validation:MaxItems:=20
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	// <opcon:experimental>
+	Phases []ClusterExtensionRevisionPhaseStatus `json:"phases,omitempty"`
+
+	// adoptedObjects lists the objects that existed before the revision was rolled out, were
+	// not installed by the same ClusterExtension, and were adopted by the revision because of
+	// their collisionProtection.
+	//
+	// +listType=atomic
+	// +optional
+	// <opcon:experimental>
+	AdoptedObjects []AdoptedObject `json:"adoptedObjects,omitempty"`
+
+	// driftDetected reports the last changes to the objects of the revision, made on the cluster
+	// after the revision succeeded, that were reverted.
+	//
+	// +optional
+	// <opcon:experimental>
+	DriftDetected *DriftDetected `json:"driftDetected,omitempty"`
+}
+
+// DriftDetected reports changes to installed objects, made on the cluster, that were reverted.
+type DriftDetected struct {
+	// lastDetectedAt is the last time changes were reverted.
+	//
+	// +required
+	LastDetectedAt metav1.Time `json:"lastDetectedAt"`
+
+	// count is the number of times changes were reverted.
+	//
+	// +required
+	Count int64 `json:"count"`
+
+	// objects lists the objects whose changes were last reverted, up to 16 objects.
+	//
+	// +kubebuilder:validation:MaxItems:=16
+	// +listType=atomic
+	// +optional
+	Objects []ObjectReference `json:"objects,omitempty"`
+}
+
+// AdoptedObject identifies an object that was adopted by a ClusterExtensionRevision.
+type AdoptedObject struct {
+	ObjectReference `json:",inline"`
+
+	// previousController identifies the controller of the object before it was adopted, as
+	// kind/name. It is empty when the object was not controlled.
+	//
+	// +optional
+	PreviousController string `json:"previousController,omitempty"`
+
+	// adoptedAt is the time the object was adopted.
+	//
+	// +required
+	AdoptedAt metav1.Time `json:"adoptedAt"`
+}
+
+// ClusterExtensionRevisionPhaseStatus reports the results of the reconciliation of a phase.
+type ClusterExtensionRevisionPhaseStatus struct {
+	// name is the name of the phase.
+	//
+	// +required
+	Name string `json:"name"`
+
+	// validationError is set when the phase failed its preflight validation and was not applied.
+	//
+	// +optional
+	ValidationError string `json:"validationError,omitempty"`
+
+	// objectCount is the number of objects in the phase.
+	//
+	// +optional
+	ObjectCount int32 `json:"objectCount,omitempty"`
+
+	// readyObjectCount is the number of objects of the phase that were applied and pass their
+	// readiness probes.
+	//
+	// +optional
+	ReadyObjectCount int32 `json:"readyObjectCount,omitempty"`
+
+	// objects lists the objects of the phase that are not ready, in rollout order, up to 16 objects.
+	// These are the objects that failed validation, that collide with an object controlled by another
+	// owner, or that fail their readiness probes.
+	//
+	// +kubebuilder:validation:MaxItems:=16
+	// +listType=atomic
+	// +optional
+	Objects []ClusterExtensionRevisionObjectStatus `json:"objects,omitempty"`
+}
+
+// ClusterExtensionRevisionObjectStatus reports the result of the reconciliation of an object that is not ready.
+type ClusterExtensionRevisionObjectStatus struct {
+	ObjectReference `json:",inline"`
+
+	// version is the API version of the object.
+	//
+	// +required
+	Version string `json:"version"`
+
+	// action is the action taken on the object: Created, Updated, Idle, Progressed, Recovered,
+	// or Collision when the object exists, and is controlled by another owner.
+	// It is empty when the object was not applied.
+	//
+	// +optional
+	Action string `json:"action,omitempty"`
+
+	// collisionOwner identifies the controller of the object, as kind/name, when action is Collision.
+	//
+	// +optional
+	CollisionOwner string `json:"collisionOwner,omitempty"`
+
+	// probeMessage is set when the object fails its readiness probes, and describes the failure.
+	//
+	// +optional
+	ProbeMessage string `json:"probeMessage,omitempty"`
+
+	// validationError is set when the object failed its preflight validation.
+	//
+	// +optional
+	ValidationError string `json:"validationError,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -28,6 +28,12 @@ const (
 	ReasonRetrying             = "Retrying"
 	ReasonBlocked              = "Blocked"
 	ReasonInvalidConfiguration = "InvalidConfiguration"
+	ReasonHookFailed           = "HookFailed"
+	ReasonRolledBack           = "RolledBack"
+	ReasonAwaitingApproval     = "AwaitingApproval"
+
+	// IncompatibleBundles reasons
+	ReasonIncompatibleWithCluster = "IncompatibleWithCluster"
 
 	// Deprecation reasons
 	ReasonDeprecated               = "Deprecated"
@@ -38,4 +44,29 @@ const (
 	ReasonSucceeded                = "Succeeded"
 	ReasonFailed                   = "Failed"
 	ReasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+	ReasonSoakFailed               = "SoakFailed"
+	ReasonPaused                   = "Paused"
 )
+
+// ObjectReference identifies a Kubernetes object by its API group, kind, namespace and name.
+type ObjectReference struct {
+	// group is the API group of the object. It is empty for the core API group.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// kind is the kind of the object.
+	//
+	// +required
+	Kind string `json:"kind"`
+
+	// namespace is the namespace of the object. It is empty for cluster-scoped objects.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// name is the name of the object.
+	//
+	// +required
+	Name string `json:"name"`
+}
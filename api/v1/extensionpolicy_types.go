@@ -0,0 +1,179 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ExtensionPolicyKind = "ExtensionPolicy"
+)
+
+// SelfCertifiedUpgradesPolicy specifies whether ClusterExtensions may opt out of
+// catalog-provided upgrade constraints.
+type SelfCertifiedUpgradesPolicy string
+
+const (
+	// SelfCertifiedUpgradesAllow permits ClusterExtensions to set an upgradeConstraintPolicy of SelfCertified.
+	SelfCertifiedUpgradesAllow SelfCertifiedUpgradesPolicy = "Allow"
+	// SelfCertifiedUpgradesForbid rejects ClusterExtensions that set an upgradeConstraintPolicy of SelfCertified.
+	SelfCertifiedUpgradesForbid SelfCertifiedUpgradesPolicy = "Forbid"
+)
+
+// ExtensionPolicySpec defines the guardrails that every ClusterExtension on the cluster must satisfy.
+type ExtensionPolicySpec struct {
+	// allowedPackages is an optional list of package names that ClusterExtensions are permitted to install.
+	//
+	// When omitted or empty, ClusterExtensions may install any package.
+	// When specified, a ClusterExtension whose spec.source.catalog.packageName or spec.source.helm.chart
+	// is not in this list is rejected.
+	//
+	// Each entry must follow the DNS subdomain standard as defined in [RFC 1123].
+	//
+	// [RFC 1123]: https://tools.ietf.org/html/rfc1123
+	//
+	// +kubebuilder:validation:MaxItems=256
+	// +kubebuilder:validation:items:MaxLength=253
+	// +kubebuilder:validation:items:XValidation:rule="self.matches(\"^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$\")",message="allowedPackages entries must be valid DNS1123 subdomains"
+	// +listType=set
+	// +optional
+	AllowedPackages []string `json:"allowedPackages,omitempty"`
+
+	// allowedCatalogs is an optional list of ClusterCatalog names that ClusterExtensions are permitted to
+	// resolve content from.
+	//
+	// When omitted or empty, ClusterExtensions may resolve content from any ClusterCatalog.
+	// When specified, a ClusterExtension must set spec.source.catalog.selector so that it can only match
+	// ClusterCatalogs in this list. This is done by selecting on the "olm.operatorframework.io/metadata.name"
+	// label, either with matchLabels or with a matchExpressions entry using the "In" operator.
+	//
+	// +kubebuilder:validation:MaxItems=256
+	// +kubebuilder:validation:items:MaxLength=253
+	// +listType=set
+	// +optional
+	AllowedCatalogs []string `json:"allowedCatalogs,omitempty"`
+
+	// allowedHelmRepositories is an optional list of chart repositories that ClusterExtensions are permitted
+	// to install Helm charts from.
+	//
+	// When specified, a ClusterExtension with a Helm source must set spec.source.helm.repository to one of
+	// the entries in this list. Entries are compared exactly, ignoring a trailing slash.
+	//
+	// When omitted or empty, Helm sources are allowed only if this policy does not restrict where content
+	// comes from, that is, if allowedPackages and allowedCatalogs are also omitted. A policy that restricts
+	// catalog content therefore cannot be bypassed by installing a Helm chart instead.
+	//
+	// +kubebuilder:validation:MaxItems=256
+	// +kubebuilder:validation:items:MaxLength=1024
+	// +listType=set
+	// +optional
+	AllowedHelmRepositories []string `json:"allowedHelmRepositories,omitempty"`
+
+	// allowedInstallNamespaces is an optional list of namespaces that ClusterExtensions are permitted to
+	// use as their spec.namespace.
+	//
+	// When omitted or empty, ClusterExtensions may be installed into any namespace.
+	//
+	// +kubebuilder:validation:MaxItems=256
+	// +kubebuilder:validation:items:MaxLength=63
+	// +listType=set
+	// +optional
+	AllowedInstallNamespaces []string `json:"allowedInstallNamespaces,omitempty"`
+
+	// minimumCRDUpgradeSafetyEnforcement is an optional field that sets the weakest CRD upgrade safety
+	// enforcement a ClusterExtension may request.
+	//
+	// Allowed values are "None" and "Strict". When omitted, the default value is "None".
+	//
+	// When set to "None", ClusterExtensions may disable the CRD upgrade safety preflight check.
+	//
+	// When set to "Strict", ClusterExtensions that set spec.install.preflight.crdUpgradeSafety.enforcement
+	// to "None" are rejected.
+	//
+	// +kubebuilder:validation:Enum:=None;Strict
+	// +kubebuilder:default:=None
+	// +optional
+	MinimumCRDUpgradeSafetyEnforcement CRDUpgradeSafetyEnforcement `json:"minimumCRDUpgradeSafetyEnforcement,omitempty"`
+
+	// selfCertifiedUpgrades is an optional field that controls whether ClusterExtensions may set
+	// spec.source.catalog.upgradeConstraintPolicy to "SelfCertified".
+	//
+	// Allowed values are "Allow" and "Forbid". When omitted, the default value is "Allow".
+	//
+	// +kubebuilder:validation:Enum:=Allow;Forbid
+	// +kubebuilder:default:=Allow
+	// +optional
+	SelfCertifiedUpgrades SelfCertifiedUpgradesPolicy `json:"selfCertifiedUpgrades,omitempty"`
+
+	// maxProgressDeadlineMinutes is an optional field that sets the largest spec.progressDeadlineMinutes
+	// a ClusterExtension may request.
+	//
+	// When omitted, no upper bound is enforced beyond the one defined by the ClusterExtension API.
+	// When specified, ClusterExtensions that set spec.progressDeadlineMinutes to a larger value are rejected.
+	// ClusterExtensions that do not set spec.progressDeadlineMinutes are not affected.
+	// The minimum value is 10 minutes, and the maximum is 720 minutes (12 hours).
+	//
+	// +kubebuilder:validation:Minimum:=10
+	// +kubebuilder:validation:Maximum:=720
+	// +optional
+	MaxProgressDeadlineMinutes int32 `json:"maxProgressDeadlineMinutes,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name=Age,type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ExtensionPolicy defines cluster-wide guardrails for ClusterExtensions.
+//
+// Every ExtensionPolicy on the cluster applies to every ClusterExtension: a ClusterExtension
+// must satisfy all of them to be admitted and reconciled. Violations are rejected by a
+// validating webhook when ClusterExtensions are created or their spec is updated and, for
+// ClusterExtensions that existed before a policy was created or changed, reported by the
+// ClusterExtension controller, which stops reconciling the offending ClusterExtension until
+// it complies. Updates that don't change the spec of a ClusterExtension are always admitted.
+type ExtensionPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is the standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// spec defines the guardrails enforced by this ExtensionPolicy.
+	// +optional
+	Spec ExtensionPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ExtensionPolicyList contains a list of ExtensionPolicy
+type ExtensionPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// items is a required list of ExtensionPolicy objects.
+	//
+	// +required
+	Items []ExtensionPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ExtensionPolicy{}, &ExtensionPolicyList{})
+}
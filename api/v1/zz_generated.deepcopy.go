@@ -25,6 +25,23 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdoptedObject) DeepCopyInto(out *AdoptedObject) {
+	*out = *in
+	out.ObjectReference = in.ObjectReference
+	in.AdoptedAt.DeepCopyInto(&out.AdoptedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdoptedObject.
+func (in *AdoptedObject) DeepCopy() *AdoptedObject {
+	if in == nil {
+		return nil
+	}
+	out := new(AdoptedObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BundleMetadata) DeepCopyInto(out *BundleMetadata) {
 	*out = *in
@@ -40,6 +57,21 @@ func (in *BundleMetadata) DeepCopy() *BundleMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CELProbe) DeepCopyInto(out *CELProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CELProbe.
+func (in *CELProbe) DeepCopy() *CELProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(CELProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CRDUpgradeSafetyPreflightConfig) DeepCopyInto(out *CRDUpgradeSafetyPreflightConfig) {
 	*out = *in
@@ -55,6 +87,21 @@ func (in *CRDUpgradeSafetyPreflightConfig) DeepCopy() *CRDUpgradeSafetyPreflight
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryRollout) DeepCopyInto(out *CanaryRollout) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryRollout.
+func (in *CanaryRollout) DeepCopy() *CanaryRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CatalogFilter) DeepCopyInto(out *CatalogFilter) {
 	*out = *in
@@ -67,6 +114,11 @@ func (in *CatalogFilter) DeepCopyInto(out *CatalogFilter) {
 		in, out := &in.Selector, &out.Selector
 		*out = (*in).DeepCopy()
 	}
+	if in.VersionPolicy != nil {
+		in, out := &in.VersionPolicy, &out.VersionPolicy
+		*out = new(VersionPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogFilter.
@@ -252,6 +304,26 @@ func (in *ClusterExtension) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterExtensionCollisionProtection) DeepCopyInto(out *ClusterExtensionCollisionProtection) {
+	*out = *in
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]CollisionProtectionOverride, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExtensionCollisionProtection.
+func (in *ClusterExtensionCollisionProtection) DeepCopy() *ClusterExtensionCollisionProtection {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterExtensionCollisionProtection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterExtensionConfig) DeepCopyInto(out *ClusterExtensionConfig) {
 	*out = *in
@@ -259,6 +331,16 @@ func (in *ClusterExtensionConfig) DeepCopyInto(out *ClusterExtensionConfig) {
 		in, out := &in.Inline, &out.Inline
 		*out = (*in).DeepCopy()
 	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(ConfigSourceReference)
+		**out = **in
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(ConfigSourceReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExtensionConfig.
@@ -279,6 +361,47 @@ func (in *ClusterExtensionInstallConfig) DeepCopyInto(out *ClusterExtensionInsta
 		*out = new(PreflightConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(ClusterExtensionRollback)
+		**out = **in
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]ClusterExtensionPatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(ClusterExtensionInstallNamespace)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = make([]ClusterExtensionProbe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(ClusterExtensionRollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CollisionProtection != nil {
+		in, out := &in.CollisionProtection, &out.CollisionProtection
+		*out = new(ClusterExtensionCollisionProtection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IgnoreDifferences != nil {
+		in, out := &in.IgnoreDifferences, &out.IgnoreDifferences
+		*out = make([]IgnoreDifference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExtensionInstallConfig.
@@ -291,6 +414,35 @@ func (in *ClusterExtensionInstallConfig) DeepCopy() *ClusterExtensionInstallConf
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterExtensionInstallNamespace) DeepCopyInto(out *ClusterExtensionInstallNamespace) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExtensionInstallNamespace.
+func (in *ClusterExtensionInstallNamespace) DeepCopy() *ClusterExtensionInstallNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterExtensionInstallNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterExtensionInstallStatus) DeepCopyInto(out *ClusterExtensionInstallStatus) {
 	*out = *in
@@ -339,6 +491,45 @@ func (in *ClusterExtensionList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterExtensionPatch) DeepCopyInto(out *ClusterExtensionPatch) {
+	*out = *in
+	in.Target.DeepCopyInto(&out.Target)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExtensionPatch.
+func (in *ClusterExtensionPatch) DeepCopy() *ClusterExtensionPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterExtensionPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterExtensionProbe) DeepCopyInto(out *ClusterExtensionProbe) {
+	*out = *in
+	out.Selector = in.Selector
+	if in.Assertions != nil {
+		in, out := &in.Assertions, &out.Assertions
+		*out = make([]ObjectProbe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExtensionProbe.
+func (in *ClusterExtensionProbe) DeepCopy() *ClusterExtensionProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterExtensionProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterExtensionRevision) DeepCopyInto(out *ClusterExtensionRevision) {
 	*out = *in
@@ -402,6 +593,13 @@ func (in *ClusterExtensionRevisionList) DeepCopyObject() runtime.Object {
 func (in *ClusterExtensionRevisionObject) DeepCopyInto(out *ClusterExtensionRevisionObject) {
 	*out = *in
 	in.Object.DeepCopyInto(&out.Object)
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = make([]ObjectProbe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExtensionRevisionObject.
@@ -414,6 +612,22 @@ func (in *ClusterExtensionRevisionObject) DeepCopy() *ClusterExtensionRevisionOb
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterExtensionRevisionObjectStatus) DeepCopyInto(out *ClusterExtensionRevisionObjectStatus) {
+	*out = *in
+	out.ObjectReference = in.ObjectReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExtensionRevisionObjectStatus.
+func (in *ClusterExtensionRevisionObjectStatus) DeepCopy() *ClusterExtensionRevisionObjectStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterExtensionRevisionObjectStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterExtensionRevisionPhase) DeepCopyInto(out *ClusterExtensionRevisionPhase) {
 	*out = *in
@@ -436,6 +650,26 @@ func (in *ClusterExtensionRevisionPhase) DeepCopy() *ClusterExtensionRevisionPha
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterExtensionRevisionPhaseStatus) DeepCopyInto(out *ClusterExtensionRevisionPhaseStatus) {
+	*out = *in
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]ClusterExtensionRevisionObjectStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExtensionRevisionPhaseStatus.
+func (in *ClusterExtensionRevisionPhaseStatus) DeepCopy() *ClusterExtensionRevisionPhaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterExtensionRevisionPhaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterExtensionRevisionSpec) DeepCopyInto(out *ClusterExtensionRevisionSpec) {
 	*out = *in
@@ -446,6 +680,18 @@ func (in *ClusterExtensionRevisionSpec) DeepCopyInto(out *ClusterExtensionRevisi
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryRollout)
+		**out = **in
+	}
+	if in.IgnoreDifferences != nil {
+		in, out := &in.IgnoreDifferences, &out.IgnoreDifferences
+		*out = make([]IgnoreDifference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExtensionRevisionSpec.
@@ -468,6 +714,25 @@ func (in *ClusterExtensionRevisionStatus) DeepCopyInto(out *ClusterExtensionRevi
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]ClusterExtensionRevisionPhaseStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdoptedObjects != nil {
+		in, out := &in.AdoptedObjects, &out.AdoptedObjects
+		*out = make([]AdoptedObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DriftDetected != nil {
+		in, out := &in.DriftDetected, &out.DriftDetected
+		*out = new(DriftDetected)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExtensionRevisionStatus.
@@ -480,6 +745,41 @@ func (in *ClusterExtensionRevisionStatus) DeepCopy() *ClusterExtensionRevisionSt
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterExtensionRollback) DeepCopyInto(out *ClusterExtensionRollback) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExtensionRollback.
+func (in *ClusterExtensionRollback) DeepCopy() *ClusterExtensionRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterExtensionRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterExtensionRollout) DeepCopyInto(out *ClusterExtensionRollout) {
+	*out = *in
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryRollout)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExtensionRollout.
+func (in *ClusterExtensionRollout) DeepCopy() *ClusterExtensionRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterExtensionRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterExtensionSpec) DeepCopyInto(out *ClusterExtensionSpec) {
 	*out = *in
@@ -529,6 +829,23 @@ func (in *ClusterExtensionStatus) DeepCopyInto(out *ClusterExtensionStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.UpgradePreview != nil {
+		in, out := &in.UpgradePreview, &out.UpgradePreview
+		*out = new(ClusterExtensionUpgradePreview)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]RevisionHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DriftDetected != nil {
+		in, out := &in.DriftDetected, &out.DriftDetected
+		*out = new(DriftDetected)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExtensionStatus.
@@ -541,6 +858,255 @@ func (in *ClusterExtensionStatus) DeepCopy() *ClusterExtensionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterExtensionUpgradePreview) DeepCopyInto(out *ClusterExtensionUpgradePreview) {
+	*out = *in
+	out.Bundle = in.Bundle
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]UpgradePreviewObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExtensionUpgradePreview.
+func (in *ClusterExtensionUpgradePreview) DeepCopy() *ClusterExtensionUpgradePreview {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterExtensionUpgradePreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CollisionProtectionOverride) DeepCopyInto(out *CollisionProtectionOverride) {
+	*out = *in
+	out.Selector = in.Selector
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CollisionProtectionOverride.
+func (in *CollisionProtectionOverride) DeepCopy() *CollisionProtectionOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(CollisionProtectionOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConditionProbe) DeepCopyInto(out *ConditionProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionProbe.
+func (in *ConditionProbe) DeepCopy() *ConditionProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ConditionProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSourceReference) DeepCopyInto(out *ConfigSourceReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSourceReference.
+func (in *ConfigSourceReference) DeepCopy() *ConfigSourceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSourceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftDetected) DeepCopyInto(out *DriftDetected) {
+	*out = *in
+	in.LastDetectedAt.DeepCopyInto(&out.LastDetectedAt)
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftDetected.
+func (in *DriftDetected) DeepCopy() *DriftDetected {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftDetected)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionPolicy) DeepCopyInto(out *ExtensionPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionPolicy.
+func (in *ExtensionPolicy) DeepCopy() *ExtensionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExtensionPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionPolicyList) DeepCopyInto(out *ExtensionPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ExtensionPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionPolicyList.
+func (in *ExtensionPolicyList) DeepCopy() *ExtensionPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExtensionPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionPolicySpec) DeepCopyInto(out *ExtensionPolicySpec) {
+	*out = *in
+	if in.AllowedPackages != nil {
+		in, out := &in.AllowedPackages, &out.AllowedPackages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedCatalogs != nil {
+		in, out := &in.AllowedCatalogs, &out.AllowedCatalogs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedHelmRepositories != nil {
+		in, out := &in.AllowedHelmRepositories, &out.AllowedHelmRepositories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedInstallNamespaces != nil {
+		in, out := &in.AllowedInstallNamespaces, &out.AllowedInstallNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionPolicySpec.
+func (in *ExtensionPolicySpec) DeepCopy() *ExtensionPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldValueProbe) DeepCopyInto(out *FieldValueProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldValueProbe.
+func (in *FieldValueProbe) DeepCopy() *FieldValueProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldValueProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldsEqualProbe) DeepCopyInto(out *FieldsEqualProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldsEqualProbe.
+func (in *FieldsEqualProbe) DeepCopy() *FieldsEqualProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldsEqualProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmSource) DeepCopyInto(out *HelmSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmSource.
+func (in *HelmSource) DeepCopy() *HelmSource {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IgnoreDifference) DeepCopyInto(out *IgnoreDifference) {
+	*out = *in
+	out.Selector = in.Selector
+	if in.JSONPointers != nil {
+		in, out := &in.JSONPointers, &out.JSONPointers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IgnoreDifference.
+func (in *IgnoreDifference) DeepCopy() *IgnoreDifference {
+	if in == nil {
+		return nil
+	}
+	out := new(IgnoreDifference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageSource) DeepCopyInto(out *ImageSource) {
 	*out = *in
@@ -561,6 +1127,75 @@ func (in *ImageSource) DeepCopy() *ImageSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectProbe) DeepCopyInto(out *ObjectProbe) {
+	*out = *in
+	if in.Condition != nil {
+		in, out := &in.Condition, &out.Condition
+		*out = new(ConditionProbe)
+		**out = **in
+	}
+	if in.FieldsEqual != nil {
+		in, out := &in.FieldsEqual, &out.FieldsEqual
+		*out = new(FieldsEqualProbe)
+		**out = **in
+	}
+	if in.FieldValue != nil {
+		in, out := &in.FieldValue, &out.FieldValue
+		*out = new(FieldValueProbe)
+		**out = **in
+	}
+	if in.CEL != nil {
+		in, out := &in.CEL, &out.CEL
+		*out = new(CELProbe)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectProbe.
+func (in *ObjectProbe) DeepCopy() *ObjectProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectReference.
+func (in *ObjectReference) DeepCopy() *ObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchTarget) DeepCopyInto(out *PatchTarget) {
+	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchTarget.
+func (in *PatchTarget) DeepCopy() *PatchTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(PatchTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PreflightConfig) DeepCopyInto(out *PreflightConfig) {
 	*out = *in
@@ -581,6 +1216,21 @@ func (in *PreflightConfig) DeepCopy() *PreflightConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeSelector) DeepCopyInto(out *ProbeSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeSelector.
+func (in *ProbeSelector) DeepCopy() *ProbeSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResolvedCatalogSource) DeepCopyInto(out *ResolvedCatalogSource) {
 	*out = *in
@@ -616,6 +1266,30 @@ func (in *ResolvedImageSource) DeepCopy() *ResolvedImageSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevisionHistoryEntry) DeepCopyInto(out *RevisionHistoryEntry) {
+	*out = *in
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.SupersededAt != nil {
+		in, out := &in.SupersededAt, &out.SupersededAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionHistoryEntry.
+func (in *RevisionHistoryEntry) DeepCopy() *RevisionHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(RevisionHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RevisionStatus) DeepCopyInto(out *RevisionStatus) {
 	*out = *in
@@ -626,6 +1300,13 @@ func (in *RevisionStatus) DeepCopyInto(out *RevisionStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AdoptedObjects != nil {
+		in, out := &in.AdoptedObjects, &out.AdoptedObjects
+		*out = make([]AdoptedObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionStatus.
@@ -661,6 +1342,11 @@ func (in *SourceConfig) DeepCopyInto(out *SourceConfig) {
 		*out = new(CatalogFilter)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Helm != nil {
+		in, out := &in.Helm, &out.Helm
+		*out = new(HelmSource)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceConfig.
@@ -672,3 +1358,39 @@ func (in *SourceConfig) DeepCopy() *SourceConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePreviewObject) DeepCopyInto(out *UpgradePreviewObject) {
+	*out = *in
+	out.ObjectReference = in.ObjectReference
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePreviewObject.
+func (in *UpgradePreviewObject) DeepCopy() *UpgradePreviewObject {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePreviewObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionPolicy) DeepCopyInto(out *VersionPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionPolicy.
+func (in *VersionPolicy) DeepCopy() *VersionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
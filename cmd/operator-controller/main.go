@@ -30,9 +30,11 @@ import (
 
 	"github.com/spf13/cobra"
 	"go.podman.io/image/v5/types"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8slabels "k8s.io/apimachinery/pkg/labels"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	apimachineryrand "k8s.io/apimachinery/pkg/util/rand"
@@ -53,7 +55,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	crwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
 	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
 
 	ocv1 "github.com/operator-framework/operator-controller/api/v1"
@@ -63,9 +67,11 @@ import (
 	"github.com/operator-framework/operator-controller/internal/operator-controller/authorization"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/catalogmetadata/cache"
 	catalogclient "github.com/operator-framework/operator-controller/internal/operator-controller/catalogmetadata/client"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/clustercapabilities"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/contentmanager"
 	cmcache "github.com/operator-framework/operator-controller/internal/operator-controller/contentmanager/cache"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/controllers"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/extensionpolicy"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/finalizers"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/resolve"
@@ -74,6 +80,7 @@ import (
 	"github.com/operator-framework/operator-controller/internal/operator-controller/rukpak/render/certproviders"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/rukpak/render/registryv1"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/scheme"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/webhook"
 	sharedcontrollers "github.com/operator-framework/operator-controller/internal/shared/controllers"
 	cacheutil "github.com/operator-framework/operator-controller/internal/shared/util/cache"
 	fsutil "github.com/operator-framework/operator-controller/internal/shared/util/fs"
@@ -104,6 +111,7 @@ type config struct {
 	catalogdCasDir       string
 	pullCasDir           string
 	globalPullSecret     string
+	webhookPort          int
 }
 
 type reconcilerConfigurator interface {
@@ -118,6 +126,7 @@ type boxcutterReconcilerConfigurator struct {
 	imageCache            imageutil.Cache
 	imagePuller           imageutil.Puller
 	finalizers            crfinalizer.Finalizers
+	policyChecker         *extensionpolicy.Checker
 }
 
 type helmReconcilerConfigurator struct {
@@ -129,11 +138,20 @@ type helmReconcilerConfigurator struct {
 	imagePuller           imageutil.Puller
 	finalizers            crfinalizer.Finalizers
 	watcher               cmcache.Watcher
+	policyChecker         *extensionpolicy.Checker
 }
 
 const (
 	authFilePrefix   = "operator-controller-global-pull-secrets"
 	fieldOwnerPrefix = "olm.operatorframework.io"
+
+	// chartSourcePollInterval is how often ClusterExtensions with the Helm sourceType
+	// re-resolve their chart, since chart repositories can't be watched.
+	chartSourcePollInterval = 10 * time.Minute
+
+	// helmHookPollInterval is how often ClusterExtensions check whether the hooks of
+	// their Helm release, which run in the background, have completed.
+	helmHookPollInterval = 10 * time.Second
 )
 
 // podNamespace checks whether the controller is running in a Pod vs.
@@ -183,6 +201,7 @@ func init() {
 	flags.StringVar(&cfg.cachePath, "cache-path", "/var/cache", "The local directory path used for filesystem based caching")
 	flags.StringVar(&cfg.systemNamespace, "system-namespace", "", "Configures the namespace that gets used to deploy system resources.")
 	flags.StringVar(&cfg.globalPullSecret, "global-pull-secret", "", "The <namespace>/<name> of the global pull secret that is going to be used to pull bundle images.")
+	flags.IntVar(&cfg.webhookPort, "webhook-server-port", 9443, "Webhook server port. Only used when the ExtensionPolicy feature gate is enabled. Requires tls-cert and tls-key.")
 
 	//adds version sub command
 	operatorControllerCmd.AddCommand(versionCommand)
@@ -265,6 +284,12 @@ func run() error {
 		}
 	}
 
+	if features.OperatorControllerFeatureGate.Enabled(features.ExtensionPolicy) {
+		cacheOptions.ByObject[&ocv1.ExtensionPolicy{}] = crcache.ByObject{
+			Label: k8slabels.Everything(),
+		}
+	}
+
 	saKey, err := sautil.GetServiceAccount()
 	if err != nil {
 		setupLog.Error(err, "Failed to extract serviceaccount from JWT")
@@ -279,6 +304,13 @@ func run() error {
 		return err
 	}
 
+	if features.OperatorControllerFeatureGate.Enabled(features.ConfigSourceReferences) {
+		if err := setupConfigSourceCache(&cacheOptions, cfg.systemNamespace); err != nil {
+			setupLog.Error(err, "Unable to setup config source cache")
+			return err
+		}
+	}
+
 	metricsServerOptions := server.Options{}
 	if len(cfg.certFile) > 0 && len(cfg.keyFile) > 0 {
 		setupLog.Info("Starting metrics server with TLS enabled", "addr", cfg.metricsAddr, "tls-cert", cfg.certFile, "tls-key", cfg.keyFile)
@@ -324,6 +356,33 @@ func run() error {
 			"Metrics will not be served since the TLS certificate and key file are not provided.")
 	}
 
+	// The webhook server is only needed to enforce ExtensionPolicies at admission time. It shares
+	// the serving certificate with the metrics server.
+	var webhookServer crwebhook.Server
+	if features.OperatorControllerFeatureGate.Enabled(features.ExtensionPolicy) {
+		if certWatcher == nil {
+			err := errors.New("tls-cert and tls-key are required")
+			setupLog.Error(err, "unable to configure webhook server for ExtensionPolicy feature")
+			return err
+		}
+		tlsProfile, err := tlsprofiles.GetTLSConfigFunc()
+		if err != nil {
+			setupLog.Error(err, "failed to get TLS profile")
+			return err
+		}
+		webhookServer = crwebhook.NewServer(crwebhook.Options{
+			Port: cfg.webhookPort,
+			TLSOpts: []func(*tls.Config){
+				func(config *tls.Config) {
+					config.GetCertificate = certWatcher.GetCertificate
+					// Disable http/2 for the same reasons as the metrics server above.
+					config.NextProtos = []string{"http/1.1"}
+				},
+				tlsProfile,
+			},
+		})
+	}
+
 	restConfig := ctrl.GetConfigOrDie()
 	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                        scheme.Scheme,
@@ -339,7 +398,8 @@ func run() error {
 		RenewDeadline: ptr.To(107 * time.Second),
 		RetryPeriod:   ptr.To(26 * time.Second),
 
-		Cache: cacheOptions,
+		WebhookServer: webhookServer,
+		Cache:         cacheOptions,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -448,6 +508,35 @@ func run() error {
 			resolve.NoDependencyValidation,
 		},
 	}
+	if features.OperatorControllerFeatureGate.Enabled(features.ClusterCapabilities) {
+		capabilitiesDiscoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create discovery client for cluster capabilities")
+			return err
+		}
+		resolver.CapabilitiesProvider = &clustercapabilities.DiscoveryProvider{
+			Discovery: capabilitiesDiscoveryClient,
+			Reader:    mgr.GetAPIReader(),
+		}
+	}
+
+	var (
+		sourceResolver                  = &resolve.SourceResolver{Catalog: resolver}
+		bundlePuller   imageutil.Puller = imagePuller
+	)
+	if features.OperatorControllerFeatureGate.Enabled(features.HelmChartSupport) {
+		pullHTTPClientFunc := func() (*http.Client, error) {
+			return httputil.BuildHTTPClient(cpwPull)
+		}
+		sourceResolver.Helm = &resolve.HelmRepositoryResolver{
+			HTTPClientFunc: pullHTTPClientFunc,
+			ListTags:       imagePuller.ListTags,
+		}
+		bundlePuller = &imageutil.SchemePuller{
+			Image: imagePuller,
+			HTTP:  &imageutil.HTTPChartPuller{HTTPClientFunc: pullHTTPClientFunc},
+		}
+	}
 
 	aeClient, err := apiextensionsv1client.NewForConfig(mgr.GetConfig())
 	if err != nil {
@@ -464,6 +553,24 @@ func run() error {
 		ctrlBuilderOpts = append(ctrlBuilderOpts, controllers.WithOwns(&ocv1.ClusterExtensionRevision{}))
 	}
 
+	var policyChecker *extensionpolicy.Checker
+	if features.OperatorControllerFeatureGate.Enabled(features.ExtensionPolicy) {
+		policyChecker = &extensionpolicy.Checker{Reader: cl}
+		ctrlBuilderOpts = append(ctrlBuilderOpts, controllers.WithExtensionPolicyWatch(cl, mgr.GetLogger()))
+		if err := (&webhook.ClusterExtension{PolicyChecker: policyChecker}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterExtension")
+			return err
+		}
+	}
+
+	if features.OperatorControllerFeatureGate.Enabled(features.ConfigSourceReferences) {
+		if err := controllers.SetupConfigSourceIndex(context.Background(), mgr.GetFieldIndexer()); err != nil {
+			setupLog.Error(err, "unable to create field index", "index", controllers.ConfigSourceIndexKey)
+			return err
+		}
+		ctrlBuilderOpts = append(ctrlBuilderOpts, controllers.WithConfigSourceWatch(cl, mgr.GetLogger()))
+	}
+
 	ceReconciler := &controllers.ClusterExtensionReconciler{
 		Client: cl,
 	}
@@ -486,21 +593,23 @@ func run() error {
 			mgr:                   mgr,
 			preflights:            preflights,
 			regv1ManifestProvider: regv1ManifestProvider,
-			resolver:              resolver,
+			resolver:              sourceResolver,
 			imageCache:            imageCache,
-			imagePuller:           imagePuller,
+			imagePuller:           bundlePuller,
 			finalizers:            clusterExtensionFinalizers,
+			policyChecker:         policyChecker,
 		}
 	} else {
 		cerCfg = &helmReconcilerConfigurator{
 			mgr:                   mgr,
 			preflights:            preflights,
 			regv1ManifestProvider: regv1ManifestProvider,
-			resolver:              resolver,
+			resolver:              sourceResolver,
 			imageCache:            imageCache,
-			imagePuller:           imagePuller,
+			imagePuller:           bundlePuller,
 			finalizers:            clusterExtensionFinalizers,
 			watcher:               ceController,
+			policyChecker:         policyChecker,
 		}
 	}
 	if err := cerCfg.Configure(ceReconciler); err != nil {
@@ -617,6 +726,7 @@ func (c *boxcutterReconcilerConfigurator) Configure(ceReconciler *controllers.Cl
 		Preflights:        c.preflights,
 		PreAuthorizer:     preAuth,
 		FieldOwner:        fmt.Sprintf("%s/clusterextension-controller", fieldOwnerPrefix),
+		Reader:            c.mgr.GetAPIReader(),
 	}
 	revisionStatesGetter := &controllers.BoxcutterRevisionStatesGetter{Reader: c.mgr.GetClient()}
 	storageMigrator := &applier.BoxcutterStorageMigrator{
@@ -627,11 +737,78 @@ func (c *boxcutterReconcilerConfigurator) Configure(ceReconciler *controllers.Cl
 	}
 	ceReconciler.ReconcileSteps = []controllers.ReconcileStepFunc{
 		controllers.HandleFinalizers(c.finalizers),
+	}
+	if features.OperatorControllerFeatureGate.Enabled(features.OLMv0Migration) {
+		// OLM v0 objects are read with the ServiceAccount of the ClusterExtension. The migration
+		// runs before the reconciliation is paused or resumed, as it keeps the migrated revision
+		// paused until OLM v0 is removed.
+		_ = v1alpha1.AddToScheme(c.mgr.GetScheme())
+		tokenGetter := authentication.NewTokenGetter(coreClient, authentication.WithExpirationDuration(1*time.Hour))
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.MigrateOLMv0Subscription(&applier.OLMv0Migrator{
+			ClientFor: action.ClientFor(c.mgr.GetConfig(), action.ServiceAccountRestConfigMapper(tokenGetter), client.Options{
+				Scheme: c.mgr.GetScheme(),
+				Mapper: c.mgr.GetRESTMapper(),
+			}),
+			RevisionGenerator: rg,
+			Client:            c.mgr.GetClient(),
+			Scheme:            c.mgr.GetScheme(),
+		}))
+	}
+	if features.OperatorControllerFeatureGate.Enabled(features.PauseReconciliation) {
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.PauseReconciliation(appl))
+	}
+	if features.OperatorControllerFeatureGate.Enabled(features.HelmReleaseAdoption) {
+		// Adopted releases are imported into Helm storage first, and migrated to
+		// ClusterExtensionRevisions by the storage migrator like any release installed by OLM.
+		// Releases installed outside of OLM are accessed with the ServiceAccount of the ClusterExtension.
+		tokenGetter := authentication.NewTokenGetter(coreClient, authentication.WithExpirationDuration(1*time.Hour))
+		adoptionCfgGetter, err := helmclient.NewActionConfigGetter(c.mgr.GetConfig(), c.mgr.GetRESTMapper(),
+			helmclient.StorageDriverMapper(action.ChunkedStorageDriverMapper(coreClient, c.mgr.GetAPIReader(), cfg.systemNamespace)),
+			helmclient.ClientNamespaceMapper(func(obj client.Object) (string, error) {
+				ext := obj.(*ocv1.ClusterExtension)
+				return ext.Spec.Namespace, nil
+			}),
+			helmclient.ClientRestConfigMapper(action.ServiceAccountRestConfigMapper(tokenGetter)),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to create helm action config getter for release adoption: %w", err)
+		}
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.AdoptHelmRelease(&action.ReleaseAdopter{
+			ActionConfigGetter: adoptionCfgGetter,
+		}))
+	}
+	ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps,
 		controllers.MigrateStorage(storageMigrator),
 		controllers.RetrieveRevisionStates(revisionStatesGetter),
-		controllers.ResolveBundle(c.resolver, c.mgr.GetClient()),
-		controllers.UnpackBundle(c.imagePuller, c.imageCache),
-		controllers.ApplyBundleWithBoxcutter(appl.Apply),
+	)
+	if features.OperatorControllerFeatureGate.Enabled(features.RevisionHistory) {
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.RecordRevisionHistory())
+	}
+	if c.policyChecker != nil {
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.EnforceExtensionPolicies(c.policyChecker))
+	}
+	if features.OperatorControllerFeatureGate.Enabled(features.ReleaseRollback) {
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps,
+			controllers.RollBack(appl),
+			controllers.RollBackFailedRollouts(appl),
+		)
+	}
+	ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.ResolveBundle(c.resolver, c.mgr.GetClient()))
+	ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.UnpackBundle(c.imagePuller, c.imageCache))
+	if features.OperatorControllerFeatureGate.Enabled(features.ConfigSourceReferences) {
+		// Referenced Secrets and ConfigMaps are read with the ServiceAccount of the ClusterExtension.
+		tokenGetter := authentication.NewTokenGetter(coreClient, authentication.WithExpirationDuration(1*time.Hour))
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.ResolveConfigReferences(action.ClientFor(c.mgr.GetConfig(), action.ServiceAccountRestConfigMapper(tokenGetter), client.Options{
+			Scheme: c.mgr.GetScheme(),
+			Mapper: c.mgr.GetRESTMapper(),
+		})))
+	}
+	if features.OperatorControllerFeatureGate.Enabled(features.UpgradePreview) {
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.PreviewUpgrade(appl))
+	}
+	ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.ApplyBundleWithBoxcutter(appl.Apply))
+	if features.OperatorControllerFeatureGate.Enabled(features.HelmChartSupport) {
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.RequeueChartSources(chartSourcePollInterval))
 	}
 
 	baseDiscoveryClient, err := discovery.NewDiscoveryClientForConfig(c.mgr.GetConfig())
@@ -679,6 +856,8 @@ func (c *boxcutterReconcilerConfigurator) Configure(ceReconciler *controllers.Cl
 		Client:                c.mgr.GetClient(),
 		RevisionEngineFactory: revisionEngineFactory,
 		TrackingCache:         trackingCache,
+		PodReader:             c.mgr.GetAPIReader(),
+		ReportDrift:           features.OperatorControllerFeatureGate.Enabled(features.DriftPolicy),
 	}).SetupWithManager(c.mgr); err != nil {
 		return fmt.Errorf("unable to setup ClusterExtensionRevision controller: %w", err)
 	}
@@ -722,19 +901,11 @@ func (c *helmReconcilerConfigurator) Configure(ceReconciler *controllers.Cluster
 	}
 
 	cm := contentmanager.NewManager(clientRestConfigMapper, c.mgr.GetConfig(), c.mgr.GetRESTMapper())
-	err = c.finalizers.Register(controllers.ClusterExtensionCleanupContentManagerCacheFinalizer, finalizers.FinalizerFunc(func(ctx context.Context, obj client.Object) (crfinalizer.Result, error) {
-		ext := obj.(*ocv1.ClusterExtension)
-		err := cm.Delete(ext)
-		return crfinalizer.Result{}, err
-	}))
-	if err != nil {
-		setupLog.Error(err, "unable to register content manager cleanup finalizer")
-		return err
-	}
 
 	// now initialize the helmApplier, assigning the potentially nil preAuth
 	appl := &applier.Helm{
 		ActionClientGetter: acg,
+		ActionConfigGetter: cfgGetter,
 		Preflights:         c.preflights,
 		HelmChartProvider: &applier.RegistryV1HelmChartProvider{
 			ManifestProvider: c.regv1ManifestProvider,
@@ -743,14 +914,87 @@ func (c *helmReconcilerConfigurator) Configure(ceReconciler *controllers.Cluster
 		PreAuthorizer:                 preAuth,
 		Watcher:                       c.watcher,
 		Manager:                       cm,
+		ClientFor: action.ClientFor(c.mgr.GetConfig(), clientRestConfigMapper, client.Options{
+			Scheme: c.mgr.GetScheme(),
+			Mapper: c.mgr.GetRESTMapper(),
+		}),
+		ReportDrift: features.OperatorControllerFeatureGate.Enabled(features.DriftPolicy),
+	}
+	// The hooks of releases run in the background for as long as the manager runs.
+	if err := c.mgr.Add(appl); err != nil {
+		return fmt.Errorf("unable to add helm applier to the manager: %w", err)
+	}
+
+	err = c.finalizers.Register(controllers.ClusterExtensionCleanupContentManagerCacheFinalizer, finalizers.FinalizerFunc(func(ctx context.Context, obj client.Object) (crfinalizer.Result, error) {
+		ext := obj.(*ocv1.ClusterExtension)
+		appl.Forget(ext)
+		err := cm.Delete(ext)
+		return crfinalizer.Result{}, err
+	}))
+	if err != nil {
+		setupLog.Error(err, "unable to register content manager cleanup finalizer")
+		return err
+	}
+	if features.OperatorControllerFeatureGate.Enabled(features.UninstallPolicy) {
+		// Release objects are owned by the ClusterExtension, so the objects kept by its
+		// uninstall policy are orphaned before it is deleted.
+		err = c.finalizers.Register(controllers.ClusterExtensionUninstallPolicyFinalizer, &applier.HelmReleaseOrphaner{
+			ActionClientGetter:            acg,
+			HelmReleaseToObjectsConverter: &applier.HelmReleaseToObjectsConverter{},
+			ClientFor: action.ClientFor(c.mgr.GetConfig(), clientRestConfigMapper, client.Options{
+				Scheme: c.mgr.GetScheme(),
+				Mapper: c.mgr.GetRESTMapper(),
+			}),
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to register uninstall policy finalizer")
+			return err
+		}
 	}
+
 	revisionStatesGetter := &controllers.HelmRevisionStatesGetter{ActionClientGetter: acg}
 	ceReconciler.ReconcileSteps = []controllers.ReconcileStepFunc{
 		controllers.HandleFinalizers(c.finalizers),
-		controllers.RetrieveRevisionStates(revisionStatesGetter),
-		controllers.ResolveBundle(c.resolver, c.mgr.GetClient()),
-		controllers.UnpackBundle(c.imagePuller, c.imageCache),
-		controllers.ApplyBundle(appl),
+	}
+	if features.OperatorControllerFeatureGate.Enabled(features.PauseReconciliation) {
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.PauseReconciliation(appl))
+	}
+	if features.OperatorControllerFeatureGate.Enabled(features.HelmReleaseAdoption) {
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.AdoptHelmRelease(&action.ReleaseAdopter{
+			ActionConfigGetter: cfgGetter,
+		}))
+	}
+	ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.RetrieveRevisionStates(revisionStatesGetter))
+	if features.OperatorControllerFeatureGate.Enabled(features.RevisionHistory) {
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.RecordRevisionHistory())
+	}
+	if c.policyChecker != nil {
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.EnforceExtensionPolicies(c.policyChecker))
+	}
+	if features.OperatorControllerFeatureGate.Enabled(features.ReleaseRollback) {
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps,
+			controllers.RollBack(appl),
+			controllers.RollBackFailedRollouts(appl),
+		)
+	}
+	ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.ResolveBundle(c.resolver, c.mgr.GetClient()))
+	ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.UnpackBundle(c.imagePuller, c.imageCache))
+	if features.OperatorControllerFeatureGate.Enabled(features.ConfigSourceReferences) {
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.ResolveConfigReferences(action.ClientFor(c.mgr.GetConfig(), clientRestConfigMapper, client.Options{
+			Scheme: c.mgr.GetScheme(),
+			Mapper: c.mgr.GetRESTMapper(),
+		})))
+	}
+	if features.OperatorControllerFeatureGate.Enabled(features.UpgradePreview) {
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.PreviewUpgrade(appl))
+	}
+	ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.ApplyBundle(appl))
+	if features.OperatorControllerFeatureGate.Enabled(features.DriftPolicy) {
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.RecordDrift(appl))
+	}
+	ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.RequeueRunningHooks(appl, helmHookPollInterval))
+	if features.OperatorControllerFeatureGate.Enabled(features.HelmChartSupport) {
+		ceReconciler.ReconcileSteps = append(ceReconciler.ReconcileSteps, controllers.RequeueChartSources(chartSourcePollInterval))
 	}
 
 	return nil
@@ -762,3 +1006,41 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// setupConfigSourceCache extends the cache to the Secrets and ConfigMaps that ClusterExtensions
+// may read their configuration from. Only objects labeled with ocv1.LabelExtensionConfig are
+// cached outside of the system namespace, so that the controller does not hold every Secret of
+// the cluster in memory. The Secret cache configured for pull secrets is extended in place, since
+// the cache accepts a single configuration per type.
+func setupConfigSourceCache(cacheOptions *crcache.Options, systemNamespace string) error {
+	configSourceSelector, err := k8slabels.Parse(ocv1.LabelExtensionConfig)
+	if err != nil {
+		return err
+	}
+
+	secretConfigured := false
+	for obj, byObject := range cacheOptions.ByObject {
+		if _, ok := obj.(*corev1.Secret); ok {
+			if byObject.Namespaces == nil {
+				byObject.Namespaces = map[string]crcache.Config{}
+			}
+			byObject.Namespaces[metav1.NamespaceAll] = crcache.Config{LabelSelector: configSourceSelector}
+			cacheOptions.ByObject[obj] = byObject
+			secretConfigured = true
+		}
+	}
+	if !secretConfigured {
+		cacheOptions.ByObject[&corev1.Secret{}] = crcache.ByObject{
+			Namespaces: map[string]crcache.Config{
+				metav1.NamespaceAll: {LabelSelector: configSourceSelector},
+			},
+		}
+	}
+	cacheOptions.ByObject[&corev1.ConfigMap{}] = crcache.ByObject{
+		Namespaces: map[string]crcache.Config{
+			systemNamespace:     {LabelSelector: k8slabels.Everything()},
+			metav1.NamespaceAll: {LabelSelector: configSourceSelector},
+		},
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	crcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/shared/util/pullsecretcache"
+)
+
+func Test_setupConfigSourceCache(t *testing.T) {
+	const systemNamespace = "olmv1-system"
+
+	for _, tc := range []struct {
+		name               string
+		setupPullSecrets   bool
+		expectedNamespaces []string
+	}{
+		{
+			name:               "extends the Secret cache configured for pull secrets",
+			setupPullSecrets:   true,
+			expectedNamespaces: []string{systemNamespace, "global-pull-secret-ns", metav1.NamespaceAll},
+		},
+		{
+			name:               "configures the Secret cache when no pull secrets are cached",
+			expectedNamespaces: []string{metav1.NamespaceAll},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cacheOptions := crcache.Options{ByObject: map[client.Object]crcache.ByObject{}}
+			if tc.setupPullSecrets {
+				require.NoError(t, pullsecretcache.SetupPullSecretCache(&cacheOptions,
+					&types.NamespacedName{Namespace: "global-pull-secret-ns", Name: "global-pull-secret"},
+					types.NamespacedName{Namespace: systemNamespace, Name: "operator-controller-controller-manager"}))
+			}
+			require.NoError(t, setupConfigSourceCache(&cacheOptions, systemNamespace))
+
+			secrets := byObjectOfType[*corev1.Secret](t, cacheOptions)
+			assert.ElementsMatch(t, tc.expectedNamespaces, mapKeys(secrets.Namespaces))
+			assert.Equal(t, ocv1.LabelExtensionConfig, secrets.Namespaces[metav1.NamespaceAll].LabelSelector.String())
+			if tc.setupPullSecrets {
+				assert.True(t, secrets.Namespaces[systemNamespace].LabelSelector.Empty(), "pull secrets of the system namespace must stay cached")
+			}
+
+			configMaps := byObjectOfType[*corev1.ConfigMap](t, cacheOptions)
+			assert.ElementsMatch(t, []string{systemNamespace, metav1.NamespaceAll}, mapKeys(configMaps.Namespaces))
+			assert.True(t, configMaps.Namespaces[systemNamespace].LabelSelector.Empty())
+			assert.Equal(t, ocv1.LabelExtensionConfig, configMaps.Namespaces[metav1.NamespaceAll].LabelSelector.String())
+		})
+	}
+}
+
+// byObjectOfType returns the only cache configuration of the objects of type T.
+func byObjectOfType[T client.Object](t *testing.T, cacheOptions crcache.Options) crcache.ByObject {
+	t.Helper()
+	var found []crcache.ByObject
+	for obj, byObject := range cacheOptions.ByObject {
+		if _, ok := obj.(T); ok {
+			found = append(found, byObject)
+		}
+	}
+	require.Len(t, found, 1)
+	return found[0]
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
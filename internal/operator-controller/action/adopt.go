@@ -0,0 +1,176 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
+)
+
+// ReleaseAdoptionError is returned when a Helm release can't be adopted without user
+// intervention, for example because OLM already manages a release with the same name.
+type ReleaseAdoptionError struct {
+	Release string
+	Reason  string
+}
+
+func (e *ReleaseAdoptionError) Error() string {
+	return fmt.Sprintf("cannot adopt Helm release %q: %s", e.Release, e.Reason)
+}
+
+// ReleaseAdopter imports Helm releases installed outside of OLM, stored as standard
+// sh.helm.release.v1 Secrets in the installation namespace, into the release storage
+// of OLM, for ClusterExtensions carrying the ocv1.AnnotationAdoptHelmRelease annotation.
+type ReleaseAdopter struct {
+	// ActionConfigGetter provides the release storage of OLM, and the client used
+	// to access the releases installed outside of OLM.
+	ActionConfigGetter helmclient.ActionConfigGetter
+	// ExternalStorageFor returns the storage holding the releases installed outside
+	// of OLM in the given namespace. It defaults to HelmSecretsStorage.
+	ExternalStorageFor func(cfg *action.Configuration, namespace string) (*storage.Storage, error)
+}
+
+// HelmSecretsStorage returns the standard Helm Secrets storage of namespace, accessed
+// with the Kubernetes client of cfg. With the action config getter of the Helm applier,
+// this is the client of the ClusterExtension's ServiceAccount.
+func HelmSecretsStorage(cfg *action.Configuration, namespace string) (*storage.Storage, error) {
+	kc, ok := cfg.KubeClient.(*kube.Client)
+	if !ok {
+		return nil, fmt.Errorf("unsupported Kubernetes client %T", cfg.KubeClient)
+	}
+	cs, err := kc.Factory.KubernetesClientSet()
+	if err != nil {
+		return nil, err
+	}
+	return storage.Init(driver.NewSecrets(cs.CoreV1().Secrets(namespace))), nil
+}
+
+// Adopt imports the history of the release named by the ocv1.AnnotationAdoptHelmRelease
+// annotation of ext into the storage of OLM and deletes the original release Secrets, so
+// that the release is managed by the ClusterExtension only. It does nothing when the
+// annotation is missing or the release was already adopted.
+//
+// Releases with a pending install, upgrade or rollback are not adopted, since the Helm
+// client performing the operation still owns them. Adoption is retried until the
+// operation has finished.
+//
+// The imported releases are labeled as the chart they were installed from, so the
+// installed chart version is reported until the ClusterExtension upgrades the release.
+func (a *ReleaseAdopter) Adopt(ctx context.Context, ext *ocv1.ClusterExtension) error {
+	name, ok := ext.GetAnnotations()[ocv1.AnnotationAdoptHelmRelease]
+	if !ok {
+		return nil
+	}
+	if name != ext.GetName() {
+		return &ReleaseAdoptionError{Release: name, Reason: fmt.Sprintf("the ClusterExtension must be named %q after the release, since chart templates may depend on the release name", name)}
+	}
+
+	cfg, err := a.ActionConfigGetter.ActionConfigFor(ctx, ext)
+	if err != nil {
+		return err
+	}
+	adopted, err := cfg.Releases.History(name)
+	if err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+		return fmt.Errorf("error getting release history: %w", err)
+	}
+	// Once an adoption is complete, the releases installed outside of OLM are
+	// gone and there is no need to look them up again.
+	if slices.ContainsFunc(adopted, func(rel *release.Release) bool {
+		return rel.Labels[labels.AdoptedHelmReleaseKey] == labels.AdoptedHelmReleaseComplete
+	}) {
+		return nil
+	}
+
+	externalStorageFor := a.ExternalStorageFor
+	if externalStorageFor == nil {
+		externalStorageFor = HelmSecretsStorage
+	}
+	external, err := externalStorageFor(cfg, ext.Spec.Namespace)
+	if err != nil {
+		return fmt.Errorf("error accessing Helm releases in namespace %q: %w", ext.Spec.Namespace, err)
+	}
+	history, err := external.History(name)
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		if len(adopted) > 0 {
+			return completeAdoption(cfg.Releases, adopted)
+		}
+		return fmt.Errorf("cannot adopt Helm release %q: %w in namespace %q", name, err, ext.Spec.Namespace)
+	}
+	if err != nil {
+		return fmt.Errorf("error getting history of Helm release %q: %w", name, err)
+	}
+
+	// An interrupted adoption leaves a subset of the history imported. Any other
+	// release stored by OLM under the same name belongs to a different installation.
+	for _, rel := range adopted {
+		if !slices.ContainsFunc(history, func(h *release.Release) bool { return h.Version == rel.Version }) {
+			return &ReleaseAdoptionError{Release: name, Reason: "the ClusterExtension already manages a release with the same name"}
+		}
+	}
+
+	slices.SortFunc(history, func(a, b *release.Release) int { return a.Version - b.Version })
+	if latest := history[len(history)-1]; latest.Info != nil && latest.Info.Status.IsPending() {
+		return fmt.Errorf("cannot adopt Helm release %q yet: revision %d is %s", name, latest.Version, latest.Info.Status)
+	}
+	imported := make([]*release.Release, 0, len(history))
+	for _, rel := range history {
+		out := adoptedRelease(rel)
+		if err := cfg.Releases.Create(out); err != nil && !errors.Is(err, driver.ErrReleaseExists) {
+			return fmt.Errorf("error importing revision %d of Helm release %q: %w", rel.Version, name, err)
+		}
+		imported = append(imported, out)
+	}
+	for _, rel := range history {
+		if _, err := external.Delete(name, rel.Version); err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+			return fmt.Errorf("error deleting revision %d of adopted Helm release %q: %w", rel.Version, name, err)
+		}
+	}
+	if err := completeAdoption(cfg.Releases, imported); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("adopted Helm release", "release", name, "revisions", len(history))
+	return nil
+}
+
+// completeAdoption marks the imported releases as adopted once the original
+// releases were deleted.
+func completeAdoption(releases *storage.Storage, imported []*release.Release) error {
+	for _, rel := range imported {
+		if rel.Labels[labels.AdoptedHelmReleaseKey] != labels.AdoptedHelmReleasePending {
+			continue
+		}
+		rel.Labels[labels.AdoptedHelmReleaseKey] = labels.AdoptedHelmReleaseComplete
+		if err := releases.Update(rel); err != nil {
+			return fmt.Errorf("error completing adoption of revision %d of Helm release %q: %w", rel.Version, rel.Name, err)
+		}
+	}
+	return nil
+}
+
+func adoptedRelease(rel *release.Release) *release.Release {
+	out := *rel
+	out.Labels = map[string]string{}
+	for k, v := range rel.Labels {
+		out.Labels[k] = v
+	}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		out.Labels[labels.PackageNameKey] = rel.Chart.Metadata.Name
+		out.Labels[labels.BundleNameKey] = fmt.Sprintf("%s.v%s", rel.Chart.Metadata.Name, strings.ReplaceAll(rel.Chart.Metadata.Version, "+", "-"))
+		out.Labels[labels.BundleVersionKey] = rel.Chart.Metadata.Version
+	}
+	out.Labels[labels.AdoptedHelmReleaseKey] = labels.AdoptedHelmReleasePending
+	return &out
+}
@@ -0,0 +1,218 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
+)
+
+type fakeActionConfigGetter struct {
+	releases *storage.Storage
+}
+
+func (f *fakeActionConfigGetter) ActionConfigFor(context.Context, client.Object) (*action.Configuration, error) {
+	return &action.Configuration{Releases: f.releases}, nil
+}
+
+func testRelease(name string, version int, status release.Status, chartVersion string) *release.Release {
+	return &release.Release{
+		Name:      name,
+		Namespace: "test-ns",
+		Version:   version,
+		Info:      &release.Info{Status: status},
+		Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: "testchart", Version: chartVersion}},
+		Labels:    map[string]string{"team": "a"},
+	}
+}
+
+func adoptedTestRelease(version int, adoption string) *release.Release {
+	rel := adoptedRelease(testRelease("myrelease", version, release.StatusDeployed, "1.0.0"))
+	rel.Labels[labels.AdoptedHelmReleaseKey] = adoption
+	return rel
+}
+
+func TestReleaseAdopter_Adopt(t *testing.T) {
+	ext := func(annotations map[string]string) *ocv1.ClusterExtension {
+		return &ocv1.ClusterExtension{
+			ObjectMeta: metav1.ObjectMeta{Name: "myrelease", Annotations: annotations},
+			Spec:       ocv1.ClusterExtensionSpec{Namespace: "test-ns"},
+		}
+	}
+	adopt := map[string]string{ocv1.AnnotationAdoptHelmRelease: "myrelease"}
+
+	for _, tc := range []struct {
+		name             string
+		ext              *ocv1.ClusterExtension
+		external         []*release.Release
+		olm              []*release.Release
+		expectedErr      string
+		expectedTerminal bool
+		expectedVersions []int
+		expectedExternal int
+	}{
+		{
+			name:             "does nothing without the annotation",
+			ext:              ext(nil),
+			external:         []*release.Release{testRelease("myrelease", 1, release.StatusDeployed, "1.0.0")},
+			expectedExternal: 1,
+		},
+		{
+			name: "imports the release history and deletes the external release",
+			ext:  ext(adopt),
+			external: []*release.Release{
+				testRelease("myrelease", 2, release.StatusDeployed, "1.1.0+build.1"),
+				testRelease("myrelease", 1, release.StatusSuperseded, "1.0.0"),
+				testRelease("other", 1, release.StatusDeployed, "1.0.0"),
+			},
+			expectedVersions: []int{1, 2},
+			expectedExternal: 1,
+		},
+		{
+			name:             "completes an interrupted adoption",
+			ext:              ext(adopt),
+			external:         []*release.Release{testRelease("myrelease", 1, release.StatusSuperseded, "1.0.0"), testRelease("myrelease", 2, release.StatusDeployed, "1.1.0")},
+			olm:              []*release.Release{testRelease("myrelease", 1, release.StatusSuperseded, "1.0.0")},
+			expectedVersions: []int{1, 2},
+		},
+		{
+			name:             "does nothing once the release is adopted",
+			ext:              ext(adopt),
+			olm:              []*release.Release{testRelease("myrelease", 1, release.StatusDeployed, "1.0.0")},
+			expectedVersions: []int{1},
+		},
+		{
+			name:             "does not look up external releases once the adoption is complete",
+			ext:              ext(adopt),
+			external:         []*release.Release{testRelease("myrelease", 1, release.StatusDeployed, "1.0.0")},
+			olm:              []*release.Release{adoptedTestRelease(1, labels.AdoptedHelmReleaseComplete)},
+			expectedVersions: []int{1},
+			expectedExternal: 1,
+		},
+		{
+			name: "waits for a pending operation to finish",
+			ext:  ext(adopt),
+			external: []*release.Release{
+				testRelease("myrelease", 1, release.StatusDeployed, "1.0.0"),
+				testRelease("myrelease", 2, release.StatusPendingUpgrade, "1.1.0"),
+			},
+			expectedErr:      `cannot adopt Helm release "myrelease" yet: revision 2 is pending-upgrade`,
+			expectedExternal: 2,
+		},
+		{
+			name:        "reports missing releases",
+			ext:         ext(adopt),
+			expectedErr: `cannot adopt Helm release "myrelease": release: not found in namespace "test-ns"`,
+		},
+		{
+			name:             "refuses releases named differently than the ClusterExtension",
+			ext:              ext(map[string]string{ocv1.AnnotationAdoptHelmRelease: "other"}),
+			external:         []*release.Release{testRelease("other", 1, release.StatusDeployed, "1.0.0")},
+			expectedErr:      `must be named "other" after the release`,
+			expectedTerminal: true,
+			expectedExternal: 1,
+		},
+		{
+			name:             "refuses to overwrite a release managed by OLM",
+			ext:              ext(adopt),
+			external:         []*release.Release{testRelease("myrelease", 1, release.StatusDeployed, "1.0.0")},
+			olm:              []*release.Release{testRelease("myrelease", 1, release.StatusSuperseded, "1.0.0"), testRelease("myrelease", 2, release.StatusDeployed, "2.0.0")},
+			expectedErr:      "already manages a release with the same name",
+			expectedTerminal: true,
+			expectedVersions: []int{1, 2},
+			expectedExternal: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			external := storage.Init(driver.NewMemory())
+			for _, rel := range tc.external {
+				require.NoError(t, external.Create(rel))
+			}
+			olm := storage.Init(driver.NewMemory())
+			for _, rel := range tc.olm {
+				require.NoError(t, olm.Create(rel))
+			}
+			a := &ReleaseAdopter{
+				ActionConfigGetter: &fakeActionConfigGetter{releases: olm},
+				ExternalStorageFor: func(*action.Configuration, string) (*storage.Storage, error) {
+					return external, nil
+				},
+			}
+
+			err := a.Adopt(context.Background(), tc.ext)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				var adoptionErr *ReleaseAdoptionError
+				assert.Equal(t, tc.expectedTerminal, errors.As(err, &adoptionErr))
+			} else {
+				require.NoError(t, err)
+			}
+
+			var versions []int
+			imported, _ := olm.History("myrelease")
+			for _, rel := range imported {
+				versions = append(versions, rel.Version)
+			}
+			assert.ElementsMatch(t, tc.expectedVersions, versions)
+			remaining, _ := external.ListReleases()
+			assert.Len(t, remaining, tc.expectedExternal)
+		})
+	}
+}
+
+func TestReleaseAdopter_AdoptLabelsReleases(t *testing.T) {
+	external := storage.Init(driver.NewMemory())
+	require.NoError(t, external.Create(testRelease("myrelease", 1, release.StatusDeployed, "1.1.0+build.1")))
+	olm := storage.Init(driver.NewMemory())
+	a := &ReleaseAdopter{
+		ActionConfigGetter: &fakeActionConfigGetter{releases: olm},
+		ExternalStorageFor: func(*action.Configuration, string) (*storage.Storage, error) {
+			return external, nil
+		},
+	}
+	require.NoError(t, a.Adopt(context.Background(), &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: "myrelease", Annotations: map[string]string{ocv1.AnnotationAdoptHelmRelease: "myrelease"}},
+		Spec:       ocv1.ClusterExtensionSpec{Namespace: "test-ns"},
+	}))
+
+	rel, err := olm.Deployed("myrelease")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"team":                       "a",
+		labels.PackageNameKey:        "testchart",
+		labels.BundleNameKey:         "testchart.v1.1.0-build.1",
+		labels.BundleVersionKey:      "1.1.0+build.1",
+		labels.AdoptedHelmReleaseKey: labels.AdoptedHelmReleaseComplete,
+	}, rel.Labels)
+}
+
+func TestReleaseAdopter_AdoptCompletesInterruptedAdoption(t *testing.T) {
+	olm := storage.Init(driver.NewMemory())
+	require.NoError(t, olm.Create(adoptedTestRelease(1, labels.AdoptedHelmReleasePending)))
+	a := &ReleaseAdopter{
+		ActionConfigGetter: &fakeActionConfigGetter{releases: olm},
+		ExternalStorageFor: func(*action.Configuration, string) (*storage.Storage, error) {
+			return storage.Init(driver.NewMemory()), nil
+		},
+	}
+	require.NoError(t, a.Adopt(context.Background(), &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: "myrelease", Annotations: map[string]string{ocv1.AnnotationAdoptHelmRelease: "myrelease"}},
+		Spec:       ocv1.ClusterExtensionSpec{Namespace: "test-ns"},
+	}))
+
+	rel, err := olm.Get("myrelease", 1)
+	require.NoError(t, err)
+	assert.Equal(t, labels.AdoptedHelmReleaseComplete, rel.Labels[labels.AdoptedHelmReleaseKey])
+}
@@ -10,6 +10,8 @@ import (
 	"k8s.io/client-go/transport"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+
 	ocv1 "github.com/operator-framework/operator-controller/api/v1"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/authentication"
 )
@@ -72,3 +74,16 @@ func validate(o client.Object, c *rest.Config) (*ocv1.ClusterExtension, error) {
 	}
 	return cExt, nil
 }
+
+// ClientFor returns a function creating a client for a ClusterExtension, using the REST
+// config returned by restConfigMapper, typically the one of the ClusterExtension's
+// ServiceAccount.
+func ClientFor(baseRestConfig *rest.Config, restConfigMapper helmclient.ObjectToRestConfigMapper, opts client.Options) func(context.Context, *ocv1.ClusterExtension) (client.Client, error) {
+	return func(ctx context.Context, ext *ocv1.ClusterExtension) (client.Client, error) {
+		cfg, err := restConfigMapper(ctx, ext, baseRestConfig)
+		if err != nil {
+			return nil, err
+		}
+		return client.New(cfg, opts)
+	}
+}
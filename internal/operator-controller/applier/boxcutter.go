@@ -10,6 +10,7 @@ import (
 	"io/fs"
 	"maps"
 	"slices"
+	"strconv"
 	"strings"
 
 	"helm.sh/helm/v3/pkg/release"
@@ -32,12 +33,17 @@ import (
 
 	ocv1 "github.com/operator-framework/operator-controller/api/v1"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/authorization"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/installnamespace"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/rukpak/bundle/source"
 	"github.com/operator-framework/operator-controller/internal/shared/util/cache"
+	errorutil "github.com/operator-framework/operator-controller/internal/shared/util/error"
 )
 
 const (
+	// ClusterExtensionRevisionRetentionLimit is the default number of ClusterExtensionRevisions
+	// retained, see revisionRetentionLimit.
 	ClusterExtensionRevisionRetentionLimit = 5
 )
 
@@ -80,12 +86,19 @@ func (r *SimpleRevisionGenerator) GenerateRevisionFromHelmRelease(
 		})
 	}
 
-	rev := r.buildClusterExtensionRevision(objs, ext, map[string]string{
+	revisionAnnotations := map[string]string{
 		labels.BundleNameKey:      helmRelease.Labels[labels.BundleNameKey],
 		labels.PackageNameKey:     helmRelease.Labels[labels.PackageNameKey],
 		labels.BundleVersionKey:   helmRelease.Labels[labels.BundleVersionKey],
 		labels.BundleReferenceKey: helmRelease.Labels[labels.BundleReferenceKey],
-	})
+	}
+	if catalogName, ok := helmRelease.Labels[labels.CatalogNameKey]; ok {
+		revisionAnnotations[labels.CatalogNameKey] = catalogName
+	}
+	rev, err := r.buildClusterExtensionRevision(objs, ext, revisionAnnotations)
+	if err != nil {
+		return nil, err
+	}
 	rev.Name = fmt.Sprintf("%s-1", ext.Name)
 	rev.Spec.Revision = 1
 	return rev, nil
@@ -101,6 +114,12 @@ func (r *SimpleRevisionGenerator) GenerateRevision(
 	if err != nil {
 		return nil, err
 	}
+	// The namespace requested by spec.install.namespace is rolled out in the namespaces
+	// phase, before the objects it contains.
+	installNamespace := installnamespace.Object(ext)
+	if installNamespace != nil {
+		plain = append(plain, installNamespace)
+	}
 
 	if revisionAnnotations == nil {
 		revisionAnnotations = map[string]string{}
@@ -123,11 +142,18 @@ func (r *SimpleRevisionGenerator) GenerateRevision(
 		}
 	}
 
-	// objectLabels
+	patcher, err := newObjectPatcher(ext)
+	if err != nil {
+		return nil, err
+	}
+	probeSelector, err := newObjectProbeSelector(ext)
+	if err != nil {
+		return nil, err
+	}
+	collisionProtection := newCollisionProtectionSelector(ext)
+
 	objs := make([]ocv1.ClusterExtensionRevisionObject, 0, len(plain))
 	for _, obj := range plain {
-		obj.SetLabels(mergeLabelMaps(obj.GetLabels(), objectLabels))
-
 		gvk, err := apiutil.GVKForObject(obj, r.Scheme)
 		if err != nil {
 			return nil, err
@@ -140,17 +166,37 @@ func (r *SimpleRevisionGenerator) GenerateRevision(
 		unstr := unstructured.Unstructured{Object: unstrObj}
 		unstr.SetGroupVersionKind(gvk)
 
+		// objectLabels are set after patching, so that patches can't change them.
+		if err := patcher.Patch(&unstr); err != nil {
+			return nil, err
+		}
+		if lbls := mergeLabelMaps(unstr.GetLabels(), objectLabels); len(lbls) > 0 {
+			unstr.SetLabels(lbls)
+		}
+
 		// Memory optimization: strip large annotations
 		if err := cache.ApplyStripAnnotationsTransform(&unstr); err != nil {
 			return nil, err
 		}
 		sanitizedUnstructured(ctx, &unstr)
 
+		probes, err := probeSelector.ProbesFor(&unstr)
+		if err != nil {
+			return nil, err
+		}
+		objCollisionProtection := collisionProtection.CollisionProtectionFor(&unstr)
+		// The requested namespace is never adopted, so that a namespace that existed before is
+		// not deleted with the revision. The Boxcutter applier leaves such namespaces out.
+		if installNamespace != nil && obj == installNamespace {
+			objCollisionProtection = ""
+		}
 		objs = append(objs, ocv1.ClusterExtensionRevisionObject{
-			Object: unstr,
+			Object:              unstr,
+			CollisionProtection: objCollisionProtection,
+			Probes:              probes,
 		})
 	}
-	return r.buildClusterExtensionRevision(objs, ext, revisionAnnotations), nil
+	return r.buildClusterExtensionRevision(objs, ext, revisionAnnotations)
 }
 
 // sanitizedUnstructured takes an unstructured obj, removes status if present, and returns a sanitized copy containing only the allowed metadata entries set below.
@@ -199,12 +245,26 @@ func (r *SimpleRevisionGenerator) buildClusterExtensionRevision(
 	objects []ocv1.ClusterExtensionRevisionObject,
 	ext *ocv1.ClusterExtension,
 	annotations map[string]string,
-) *ocv1.ClusterExtensionRevision {
+) (*ocv1.ClusterExtensionRevision, error) {
+	// Objects with invalid phase annotations can't be installed until the bundle,
+	// or the patches of the ClusterExtension, are fixed.
+	phases, err := PhaseSort(objects)
+	if err != nil {
+		return nil, errorutil.NewTerminalError(ocv1.ReasonBlocked, fmt.Errorf("sorting objects into phases: %w", err))
+	}
+
 	if annotations == nil {
 		annotations = make(map[string]string)
 	}
 	annotations[labels.ServiceAccountNameKey] = ext.Spec.ServiceAccount.Name
 	annotations[labels.ServiceAccountNamespaceKey] = ext.Spec.Namespace
+	digest, err := patchesDigest(ext)
+	if err != nil {
+		return nil, err
+	}
+	if digest != "" {
+		annotations[labels.PatchesDigestKey] = digest
+	}
 
 	cer := &ocv1.ClusterExtensionRevision{
 		ObjectMeta: metav1.ObjectMeta{
@@ -219,13 +279,19 @@ func (r *SimpleRevisionGenerator) buildClusterExtensionRevision(
 			// being explicit here ensures all code paths are clear and doesn't rely
 			// on API server defaulting behavior.
 			LifecycleState: ocv1.ClusterExtensionRevisionLifecycleStateActive,
-			Phases:         PhaseSort(objects),
+			Phases:         phases,
 		},
 	}
 	if p := ext.Spec.ProgressDeadlineMinutes; p > 0 {
 		cer.Spec.ProgressDeadlineMinutes = p
 	}
-	return cer
+	cer.Spec.UninstallPolicy = uninstallPolicy(ext)
+	cer.Spec.IgnoreDifferences = ignoreDifferences(ext)
+	if features.OperatorControllerFeatureGate.Enabled(features.CanaryRollout) && ext.Spec.Install != nil &&
+		ext.Spec.Install.Rollout != nil && ext.Spec.Install.Rollout.Strategy == ocv1.RolloutStrategyCanary {
+		cer.Spec.Canary = ext.Spec.Install.Rollout.Canary.DeepCopy()
+	}
+	return cer, nil
 }
 
 // BoxcutterStorageMigrator migrates ClusterExtensions from Helm-based storage to
@@ -296,17 +362,29 @@ func (m *BoxcutterStorageMigrator) Migrate(ctx context.Context, ext *ocv1.Cluste
 	// normal Boxcutter revisions. This label is critical for ensuring we only
 	// set Succeeded=True status on actually-migrated revisions, not on revision 1
 	// created during normal Boxcutter operation.
+	return createMigratedRevision(ctx, m.Client, m.Scheme, ext, rev, labels.MigratedFromHelmKey, helmMigrationMessage)
+}
+
+// helmMigrationMessage is the message of the Succeeded condition of revisions migrated from Helm.
+const helmMigrationMessage = "Revision succeeded - migrated from Helm release"
+
+// createMigratedRevision creates rev, a revision of ext migrated from an existing installation,
+// marked with the migratedKey label, and marks it as succeeded.
+func createMigratedRevision(
+	ctx context.Context, c boxcutterStorageMigratorClient, scheme *runtime.Scheme,
+	ext *ocv1.ClusterExtension, rev *ocv1.ClusterExtensionRevision, migratedKey, message string,
+) error {
 	if rev.Labels == nil {
 		rev.Labels = make(map[string]string)
 	}
-	rev.Labels[labels.MigratedFromHelmKey] = "true"
+	rev.Labels[migratedKey] = "true"
 
 	// Set ownerReference for proper garbage collection when the ClusterExtension is deleted.
-	if err := controllerutil.SetControllerReference(ext, rev, m.Scheme); err != nil {
+	if err := controllerutil.SetControllerReference(ext, rev, scheme); err != nil {
 		return fmt.Errorf("set ownerref: %w", err)
 	}
 
-	if err := m.Client.Create(ctx, rev); err != nil {
+	if err := c.Create(ctx, rev); err != nil {
 		return err
 	}
 
@@ -324,9 +402,9 @@ func (m *BoxcutterStorageMigrator) Migrate(ctx context.Context, ext *ocv1.Cluste
 	// that creates a timing gap where the ClusterExtension reconciliation happens before the status
 	// is set, causing failures during the OLM upgrade window.
 	//
-	// Since we're creating this revision from a successfully deployed Helm release, we know it
-	// represents a working installation and can safely mark it as succeeded immediately.
-	return m.ensureRevisionStatus(ctx, rev)
+	// Since we're creating this revision from a working installation, we know it can safely be
+	// marked as succeeded immediately.
+	return ensureMigratedRevisionSucceeded(ctx, c, rev, migratedKey, message)
 }
 
 // ensureMigratedRevisionStatus checks if revision 1 exists and needs its status set.
@@ -343,7 +421,7 @@ func (m *BoxcutterStorageMigrator) ensureMigratedRevisionStatus(ctx context.Cont
 		}
 		// Ensure revision 1 status is set correctly, including for previously migrated
 		// revisions that may not carry the MigratedFromHelm label.
-		return m.ensureRevisionStatus(ctx, &revisions[i])
+		return ensureMigratedRevisionSucceeded(ctx, m.Client, &revisions[i], labels.MigratedFromHelmKey, helmMigrationMessage)
 	}
 	// No revision 1 found - migration not applicable (revisions created by normal operation).
 	return nil
@@ -377,19 +455,19 @@ func (m *BoxcutterStorageMigrator) findLatestDeployedRelease(ac helmclient.Actio
 	return latestDeployed, nil
 }
 
-// ensureRevisionStatus ensures the revision has the Succeeded status condition set.
+// ensureMigratedRevisionSucceeded ensures the revision has the Succeeded status condition set.
 // Returns nil if the status is already set or after successfully setting it.
-// Only sets status on revisions that were actually migrated from Helm (marked with MigratedFromHelmKey label).
-func (m *BoxcutterStorageMigrator) ensureRevisionStatus(ctx context.Context, rev *ocv1.ClusterExtensionRevision) error {
+// Only sets status on revisions that were actually migrated, marked with the migratedKey label.
+func ensureMigratedRevisionSucceeded(ctx context.Context, c boxcutterStorageMigratorClient, rev *ocv1.ClusterExtensionRevision, migratedKey, message string) error {
 	// Re-fetch to get latest version before checking status
-	if err := m.Client.Get(ctx, client.ObjectKeyFromObject(rev), rev); err != nil {
+	if err := c.Get(ctx, client.ObjectKeyFromObject(rev), rev); err != nil {
 		return fmt.Errorf("getting existing revision for status check: %w", err)
 	}
 
-	// Only set status if this revision was actually migrated from Helm.
+	// Only set status if this revision was actually migrated.
 	// This prevents us from incorrectly marking normal Boxcutter revision 1 as succeeded
 	// when it's still in progress.
-	if rev.Labels[labels.MigratedFromHelmKey] != "true" {
+	if rev.Labels[migratedKey] != "true" {
 		return nil
 	}
 
@@ -403,11 +481,11 @@ func (m *BoxcutterStorageMigrator) ensureRevisionStatus(ctx context.Context, rev
 		Type:               ocv1.ClusterExtensionRevisionTypeSucceeded,
 		Status:             metav1.ConditionTrue,
 		Reason:             ocv1.ReasonSucceeded,
-		Message:            "Revision succeeded - migrated from Helm release",
+		Message:            message,
 		ObservedGeneration: rev.GetGeneration(),
 	})
 
-	if err := m.Client.Status().Update(ctx, rev); err != nil {
+	if err := c.Status().Update(ctx, rev); err != nil {
 		return fmt.Errorf("updating migrated revision status: %w", err)
 	}
 
@@ -421,6 +499,10 @@ type Boxcutter struct {
 	Preflights        []Preflight
 	PreAuthorizer     authorization.PreAuthorizer
 	FieldOwner        string
+	// Reader reads the installation namespaces requested by ClusterExtensions, to leave those
+	// that existed before out of their revisions. It should not be backed by a cache, so that
+	// OLM doesn't need to watch all namespaces of the cluster.
+	Reader client.Reader
 }
 
 // createOrUpdate creates or updates the revision object. PreAuthorization checks are performed to ensure the
@@ -501,6 +583,20 @@ func (bc *Boxcutter) Apply(ctx context.Context, contentFS fs.FS, ext *ocv1.Clust
 	if err != nil {
 		return false, "", err
 	}
+	if bc.Reader != nil {
+		// A namespace that existed before is only labeled and annotated, so that it's kept when
+		// ext is uninstalled.
+		preexisting, err := (&installnamespace.Ensurer{Reader: bc.Reader, Writer: bc.Client}).EnsurePreexisting(ctx, ext)
+		if installnamespace.IsConfigurationError(err) {
+			return false, "", errorutil.NewTerminalError(ocv1.ReasonInvalidConfiguration, err)
+		}
+		if err != nil {
+			return false, "", err
+		}
+		if preexisting {
+			removeInstallNamespace(desiredRevision, ext)
+		}
+	}
 
 	if err := controllerutil.SetControllerReference(ext, desiredRevision, bc.Scheme); err != nil {
 		return false, "", fmt.Errorf("set ownerref: %w", err)
@@ -561,7 +657,7 @@ func (bc *Boxcutter) Apply(ctx context.Context, contentFS fs.FS, ext *ocv1.Clust
 		desiredRevision.Name = fmt.Sprintf("%s-%d", ext.Name, revisionNumber)
 		desiredRevision.Spec.Revision = revisionNumber
 
-		if err = bc.garbageCollectOldRevisions(ctx, prevRevisions); err != nil {
+		if err = bc.garbageCollectOldRevisions(ctx, ext, prevRevisions); err != nil {
 			return false, "", fmt.Errorf("garbage collecting old revisions: %w", err)
 		}
 
@@ -573,6 +669,178 @@ func (bc *Boxcutter) Apply(ctx context.Context, contentFS fs.FS, ext *ocv1.Clust
 	return true, "", nil
 }
 
+// PreviewUpgrade generates the revision of the content in contentFS, and compares its objects with
+// the objects of the latest ClusterExtensionRevision. It returns nil when there is no revision yet,
+// or when the upgrade doesn't change any object.
+func (bc *Boxcutter) PreviewUpgrade(ctx context.Context, contentFS fs.FS, ext *ocv1.ClusterExtension, objectLabels, revisionAnnotations map[string]string) (*ocv1.ClusterExtensionUpgradePreview, error) {
+	existingRevisions, err := bc.getExistingRevisions(ctx, ext.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if len(existingRevisions) == 0 {
+		return nil, nil
+	}
+
+	desiredRevision, err := bc.RevisionGenerator.GenerateRevision(ctx, contentFS, ext, objectLabels, revisionAnnotations)
+	if err != nil {
+		return nil, err
+	}
+	if bc.Reader != nil {
+		preexisting, err := installnamespace.Preexisting(ctx, bc.Reader, ext)
+		if err != nil {
+			return nil, err
+		}
+		if preexisting {
+			removeInstallNamespace(desiredRevision, ext)
+		}
+	}
+	currentRevision := &existingRevisions[len(existingRevisions)-1]
+	return newUpgradePreview(ext, getObjects(currentRevision), getObjects(desiredRevision), revisionAnnotations)
+}
+
+// removeInstallNamespace removes the installation namespace requested by ext from rev, and the
+// phase that held it when it's left empty.
+func removeInstallNamespace(rev *ocv1.ClusterExtensionRevision, ext *ocv1.ClusterExtension) {
+	phases := rev.Spec.Phases[:0]
+	for _, phase := range rev.Spec.Phases {
+		phase.Objects = slices.DeleteFunc(phase.Objects, func(obj ocv1.ClusterExtensionRevisionObject) bool {
+			return obj.Object.GetAPIVersion() == "v1" && obj.Object.GetKind() == "Namespace" && obj.Object.GetName() == ext.Spec.Namespace
+		})
+		if len(phase.Objects) > 0 {
+			phases = append(phases, phase)
+		}
+	}
+	rev.Spec.Phases = phases
+}
+
+// RevisionBundle returns the annotations, describing the installed bundle, of the
+// ClusterExtensionRevision of ext with the given revision number.
+func (bc *Boxcutter) RevisionBundle(ctx context.Context, ext *ocv1.ClusterExtension, revision int64) (map[string]string, error) {
+	existingRevisions, err := bc.getExistingRevisions(ctx, ext.GetName())
+	if err != nil {
+		return nil, err
+	}
+	target, err := findRevision(existingRevisions, ext, revision)
+	if err != nil {
+		return nil, err
+	}
+	return target.Annotations, nil
+}
+
+// Rollback creates a new ClusterExtensionRevision of ext with the phases of the given
+// revision, patched with the current patches of ext, and annotated with
+// labels.RollbackOfRevisionKey and revisionAnnotations. Nothing is
+// created when the latest revision already rolls back to the given revision.
+func (bc *Boxcutter) Rollback(ctx context.Context, ext *ocv1.ClusterExtension, revision int64, objectLabels, revisionAnnotations map[string]string) (bool, string, error) {
+	existingRevisions, err := bc.getExistingRevisions(ctx, ext.GetName())
+	if err != nil {
+		return false, "", err
+	}
+	target, err := findRevision(existingRevisions, ext, revision)
+	if err != nil {
+		return false, "", err
+	}
+	rollbackOf := strconv.FormatInt(revision, 10)
+	if latest := &existingRevisions[len(existingRevisions)-1]; latest.Annotations[labels.RollbackOfRevisionKey] == rollbackOf {
+		return rollbackStatus(latest, revision)
+	}
+
+	annotations := rollbackStorageLabels(target.Annotations, revisionAnnotations, rollbackOf)
+	annotations[labels.ServiceAccountNameKey] = ext.Spec.ServiceAccount.Name
+	annotations[labels.ServiceAccountNamespaceKey] = ext.Spec.Namespace
+	phases, err := repatchPhases(target, ext, objectLabels)
+	if err != nil {
+		return false, "", err
+	}
+	delete(annotations, labels.PatchesDigestKey)
+	if digest, _ := patchesDigest(ext); digest != "" {
+		annotations[labels.PatchesDigestKey] = digest
+	}
+
+	revisionNumber := latestRevisionNumber(existingRevisions) + 1
+	rev := &ocv1.ClusterExtensionRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-%d", ext.Name, revisionNumber),
+			Annotations: annotations,
+			Labels: map[string]string{
+				labels.OwnerKindKey: ocv1.ClusterExtensionKind,
+				labels.OwnerNameKey: ext.Name,
+			},
+		},
+		Spec: ocv1.ClusterExtensionRevisionSpec{
+			LifecycleState:          ocv1.ClusterExtensionRevisionLifecycleStateActive,
+			Revision:                revisionNumber,
+			Phases:                  phases,
+			ProgressDeadlineMinutes: ext.Spec.ProgressDeadlineMinutes,
+			UninstallPolicy:         uninstallPolicy(ext),
+			IgnoreDifferences:       ignoreDifferences(ext),
+		},
+	}
+	if err := controllerutil.SetControllerReference(ext, rev, bc.Scheme); err != nil {
+		return false, "", fmt.Errorf("set ownerref: %w", err)
+	}
+	if err := runUpgradePreflights(ctx, bc.Preflights, ext, getObjects(rev)); err != nil {
+		return false, "", err
+	}
+	if err := bc.garbageCollectOldRevisions(ctx, ext, existingRevisions); err != nil {
+		return false, "", fmt.Errorf("garbage collecting old revisions: %w", err)
+	}
+	if err := bc.createOrUpdate(ctx, getUserInfo(ext), rev); err != nil {
+		return false, "", fmt.Errorf("creating rollback Revision: %w", err)
+	}
+	return rollbackStatus(rev, revision)
+}
+
+// repatchPhases returns the phases of target with the current patches of ext applied to their
+// objects, unless target was generated with the same patches. Patches that target was generated
+// with can't be undone, since the objects of revisions are only stored patched.
+func repatchPhases(target *ocv1.ClusterExtensionRevision, ext *ocv1.ClusterExtension, objectLabels map[string]string) ([]ocv1.ClusterExtensionRevisionPhase, error) {
+	digest, err := patchesDigest(ext)
+	if err != nil {
+		return nil, err
+	}
+	if digest == target.Annotations[labels.PatchesDigestKey] {
+		return target.Spec.Phases, nil
+	}
+	patcher, err := newObjectPatcher(ext)
+	if err != nil || patcher == nil {
+		return target.Spec.Phases, err
+	}
+	phases := make([]ocv1.ClusterExtensionRevisionPhase, len(target.Spec.Phases))
+	for i := range target.Spec.Phases {
+		target.Spec.Phases[i].DeepCopyInto(&phases[i])
+		for j := range phases[i].Objects {
+			obj := &phases[i].Objects[j].Object
+			if err := patcher.Patch(obj); err != nil {
+				return nil, err
+			}
+			if lbls := mergeLabelMaps(obj.GetLabels(), objectLabels); len(lbls) > 0 {
+				obj.SetLabels(lbls)
+			}
+		}
+	}
+	return phases, nil
+}
+
+// rollbackStatus reports whether rev, rolling back to the given revision, is available.
+// Until then, the rollback is rolling out.
+func rollbackStatus(rev *ocv1.ClusterExtensionRevision, revision int64) (bool, string, error) {
+	if meta.IsStatusConditionTrue(rev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeAvailable) {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("Revision %d rolling back to revision %d is not available yet.", rev.Spec.Revision, revision), nil
+}
+
+// findRevision returns the ClusterExtensionRevision of ext with the given revision number.
+func findRevision(revisions []ocv1.ClusterExtensionRevision, ext *ocv1.ClusterExtension, revision int64) (*ocv1.ClusterExtensionRevision, error) {
+	for i := range revisions {
+		if revisions[i].Spec.Revision == revision {
+			return &revisions[i], nil
+		}
+	}
+	return nil, errorutil.NewTerminalError(ocv1.ReasonBlocked, fmt.Errorf("revision %d of ClusterExtension %q not found; archived revisions beyond the %d most recent ones are deleted", revision, ext.GetName(), revisionRetentionLimit(ext)))
+}
+
 // runPreAuthorizationChecks runs PreAuthorization checks if the PreAuthorizer is set. An error will be returned if
 // the ClusterExtension service account does not have the necessary permissions to manage the revision's resources
 func (bc *Boxcutter) runPreAuthorizationChecks(ctx context.Context, user user.Info, rev *ocv1.ClusterExtensionRevision) error {
@@ -590,12 +858,54 @@ func (bc *Boxcutter) runPreAuthorizationChecks(ctx context.Context, user user.In
 	return formatPreAuthorizerOutput(bc.PreAuthorizer.PreAuthorize(ctx, user, manifestReader, revisionManagementPerms(rev)))
 }
 
-// garbageCollectOldRevisions deletes archived revisions beyond ClusterExtensionRevisionRetentionLimit.
+// revisionRetentionLimit returns the number of ClusterExtensionRevisions retained for ext.
+func revisionRetentionLimit(ext *ocv1.ClusterExtension) int {
+	if ext.Spec.Install != nil && ext.Spec.Install.HistoryLimit > 0 {
+		return int(ext.Spec.Install.HistoryLimit)
+	}
+	return ClusterExtensionRevisionRetentionLimit
+}
+
+// Pause sets spec.paused on the active revisions of ext, which stops the reconciliation of
+// their objects.
+func (bc *Boxcutter) Pause(ctx context.Context, ext *ocv1.ClusterExtension) error {
+	return bc.setRevisionsPaused(ctx, ext, true)
+}
+
+// Resume unsets spec.paused on the revisions of ext, which resumes the reconciliation of
+// their objects.
+func (bc *Boxcutter) Resume(ctx context.Context, ext *ocv1.ClusterExtension) error {
+	return bc.setRevisionsPaused(ctx, ext, false)
+}
+
+func (bc *Boxcutter) setRevisionsPaused(ctx context.Context, ext *ocv1.ClusterExtension, paused bool) error {
+	existingRevisions, err := bc.getExistingRevisions(ctx, ext.GetName())
+	if err != nil {
+		return err
+	}
+	for i := range existingRevisions {
+		rev := &existingRevisions[i]
+		if rev.Spec.Paused == paused || (paused && rev.Spec.LifecycleState == ocv1.ClusterExtensionRevisionLifecycleStateArchived) {
+			continue
+		}
+		// spec.paused is not part of the revisions applied by Apply, so it is patched
+		// without taking over the fields they own.
+		patch := client.MergeFrom(rev.DeepCopy())
+		rev.Spec.Paused = paused
+		if err := bc.Client.Patch(ctx, rev, patch); err != nil {
+			return fmt.Errorf("patching %s Revision: %w", rev.Name, err)
+		}
+	}
+	return nil
+}
+
+// garbageCollectOldRevisions deletes archived revisions of ext beyond its revisionRetentionLimit.
 // Active revisions are never deleted. revisionList must be sorted oldest to newest.
-func (bc *Boxcutter) garbageCollectOldRevisions(ctx context.Context, revisionList []ocv1.ClusterExtensionRevision) error {
+func (bc *Boxcutter) garbageCollectOldRevisions(ctx context.Context, ext *ocv1.ClusterExtension, revisionList []ocv1.ClusterExtensionRevision) error {
+	limit := revisionRetentionLimit(ext)
 	for index, r := range revisionList {
 		// Only delete archived revisions that are beyond the limit
-		if index < len(revisionList)-ClusterExtensionRevisionRetentionLimit && r.Spec.LifecycleState == ocv1.ClusterExtensionRevisionLifecycleStateArchived {
+		if index < len(revisionList)-limit && r.Spec.LifecycleState == ocv1.ClusterExtensionRevisionLifecycleStateArchived {
 			if err := bc.Client.Delete(ctx, &ocv1.ClusterExtensionRevision{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: r.Name,
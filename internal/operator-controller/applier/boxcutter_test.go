@@ -16,6 +16,7 @@ import (
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/storage/driver"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -35,9 +36,11 @@ import (
 	ocv1 "github.com/operator-framework/operator-controller/api/v1"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/applier"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/authorization"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/rukpak/util/testing/bundlefs"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/rukpak/util/testing/clusterserviceversion"
+	errorutil "github.com/operator-framework/operator-controller/internal/shared/util/error"
 )
 
 var (
@@ -425,6 +428,505 @@ func Test_SimpleRevisionGenerator_AppliesObjectLabelsAndRevisionAnnotations(t *t
 	require.Equal(t, revAnnotations, rev.Annotations)
 }
 
+func Test_SimpleRevisionGenerator_AppliesPatches(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.ManifestPatches)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.ManifestPatches)))
+	})
+
+	renderedObjs := func() []client.Object {
+		return []client.Object{
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: "test-namespace"},
+				Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+					{Name: "manager", Image: "manager:v1"},
+					{Name: "proxy", Image: "proxy:v1"},
+				}}}},
+			},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: "test-namespace", Labels: map[string]string{"app": "test"}},
+				Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "https", Port: 443}}},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-configmap", Namespace: "test-namespace", Labels: map[string]string{"app": "test"}},
+				Data:       map[string]string{"level": "info"},
+			},
+			&unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Widget",
+				"metadata":   map[string]interface{}{"name": "test-widget"},
+				"spec":       map[string]interface{}{"sizes": []interface{}{"small", "large"}},
+			}},
+		}
+	}
+	b := applier.SimpleRevisionGenerator{
+		Scheme: k8scheme.Scheme,
+		ManifestProvider: &FakeManifestProvider{
+			GetFn: func(fs.FS, *ocv1.ClusterExtension) ([]client.Object, error) {
+				return renderedObjs(), nil
+			},
+		},
+	}
+	newExt := func(patches ...ocv1.ClusterExtensionPatch) *ocv1.ClusterExtension {
+		return &ocv1.ClusterExtension{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ext"},
+			Spec: ocv1.ClusterExtensionSpec{
+				Namespace:      "test-namespace",
+				ServiceAccount: ocv1.ServiceAccountReference{Name: "test-sa"},
+				Install:        &ocv1.ClusterExtensionInstallConfig{Patches: patches},
+			},
+		}
+	}
+	findObject := func(t *testing.T, rev *ocv1.ClusterExtensionRevision, kind string) unstructured.Unstructured {
+		for _, phase := range rev.Spec.Phases {
+			for _, obj := range phase.Objects {
+				if obj.Object.GetKind() == kind {
+					return obj.Object
+				}
+			}
+		}
+		require.Failf(t, "object not found", "kind %s", kind)
+		return unstructured.Unstructured{}
+	}
+
+	t.Run("applies patches to the targeted objects", func(t *testing.T) {
+		rev, err := b.GenerateRevision(t.Context(), dummyBundle, newExt(
+			ocv1.ClusterExtensionPatch{
+				Target: ocv1.PatchTarget{Group: "apps", Kind: "Deployment", Name: "test-deployment"},
+				Type:   ocv1.PatchTypeStrategicMerge,
+				Patch: `spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        image: manager:v2`,
+			},
+			ocv1.ClusterExtensionPatch{
+				Target: ocv1.PatchTarget{Kind: "Service"},
+				Type:   ocv1.PatchTypeJSON6902,
+				Patch:  `[{"op": "replace", "path": "/spec/ports/0/port", "value": 8443}]`,
+			},
+			ocv1.ClusterExtensionPatch{
+				Target: ocv1.PatchTarget{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}}},
+				Type:   ocv1.PatchTypeStrategicMerge,
+				Patch:  `{"metadata": {"labels": {"owner": "patched", "team": "a"}}}`,
+			},
+			ocv1.ClusterExtensionPatch{
+				Target: ocv1.PatchTarget{Group: "example.com", Kind: "Widget"},
+				Type:   ocv1.PatchTypeStrategicMerge,
+				Patch:  `{"spec": {"sizes": ["medium"]}}`,
+			},
+		), map[string]string{"owner": "test-ext"}, nil)
+		require.NoError(t, err)
+
+		deployment := findObject(t, rev, "Deployment")
+		containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+		require.Len(t, containers, 2, "containers are merged by name")
+		assert.Equal(t, "manager:v2", containers[0].(map[string]interface{})["image"])
+		assert.Equal(t, "proxy:v1", containers[1].(map[string]interface{})["image"])
+
+		service := findObject(t, rev, "Service")
+		port, _, _ := unstructured.NestedSlice(service.Object, "spec", "ports")
+		assert.Equal(t, int64(8443), port[0].(map[string]interface{})["port"])
+		assert.Equal(t, map[string]string{"app": "test", "owner": "test-ext", "team": "a"}, service.GetLabels(), "object labels can't be patched")
+
+		configMap := findObject(t, rev, "ConfigMap")
+		assert.Equal(t, "a", configMap.GetLabels()["team"])
+		assert.Equal(t, map[string]string{"owner": "test-ext"}, deployment.GetLabels())
+
+		widget := findObject(t, rev, "Widget")
+		sizes, _, _ := unstructured.NestedStringSlice(widget.Object, "spec", "sizes")
+		assert.Equal(t, []string{"medium"}, sizes, "lists of custom resources are replaced")
+	})
+
+	for name, patch := range map[string]ocv1.ClusterExtensionPatch{
+		"rejects invalid patch content": {
+			Type:  ocv1.PatchTypeJSON6902,
+			Patch: `{"op": "replace"}`,
+		},
+		"rejects patches that fail to apply": {
+			Target: ocv1.PatchTarget{Kind: "ConfigMap"},
+			Type:   ocv1.PatchTypeJSON6902,
+			Patch:  `[{"op": "remove", "path": "/data/missing"}]`,
+		},
+		"rejects patches that rename objects": {
+			Target: ocv1.PatchTarget{Kind: "ConfigMap"},
+			Type:   ocv1.PatchTypeStrategicMerge,
+			Patch:  `{"metadata": {"name": "renamed"}}`,
+		},
+		"rejects patches that move objects to other namespaces": {
+			Target: ocv1.PatchTarget{Kind: "Service"},
+			Type:   ocv1.PatchTypeJSON6902,
+			Patch:  `[{"op": "replace", "path": "/metadata/namespace", "value": "other"}]`,
+		},
+		"rejects patches that change the kind of objects": {
+			Target: ocv1.PatchTarget{Kind: "ConfigMap"},
+			Type:   ocv1.PatchTypeJSON6902,
+			Patch:  `[{"op": "replace", "path": "/kind", "value": "Secret"}]`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := b.GenerateRevision(t.Context(), dummyBundle, newExt(patch), nil, nil)
+			require.Error(t, err)
+			reason, ok := errorutil.ExtractTerminalReason(err)
+			require.True(t, ok)
+			assert.Equal(t, ocv1.ReasonInvalidConfiguration, reason)
+		})
+	}
+
+	t.Run("ignores patches when the feature is disabled", func(t *testing.T) {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.ManifestPatches)))
+		rev, err := b.GenerateRevision(t.Context(), dummyBundle, newExt(ocv1.ClusterExtensionPatch{
+			Target: ocv1.PatchTarget{Kind: "ConfigMap"},
+			Type:   ocv1.PatchTypeStrategicMerge,
+			Patch:  `{"data": {"level": "debug"}}`,
+		}), nil, nil)
+		require.NoError(t, err)
+		configMap := findObject(t, rev, "ConfigMap")
+		level, _, _ := unstructured.NestedString(configMap.Object, "data", "level")
+		assert.Equal(t, "info", level)
+	})
+}
+
+func Test_SimpleRevisionGenerator_AttachesProbes(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.ObjectProbes)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.ObjectProbes)))
+	})
+
+	b := applier.SimpleRevisionGenerator{
+		Scheme: k8scheme.Scheme,
+		ManifestProvider: &FakeManifestProvider{
+			GetFn: func(fs.FS, *ocv1.ClusterExtension) ([]client.Object, error) {
+				return []client.Object{
+					&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "test-namespace"}},
+					&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "cleanup", Namespace: "test-namespace"}},
+					&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-configmap", Namespace: "test-namespace"}},
+				}, nil
+			},
+		},
+	}
+	newExt := func(probes ...ocv1.ClusterExtensionProbe) *ocv1.ClusterExtension {
+		return &ocv1.ClusterExtension{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ext"},
+			Spec: ocv1.ClusterExtensionSpec{
+				Namespace:      "test-namespace",
+				ServiceAccount: ocv1.ServiceAccountReference{Name: "test-sa"},
+				Install:        &ocv1.ClusterExtensionInstallConfig{Probes: probes},
+			},
+		}
+	}
+	probesOf := func(rev *ocv1.ClusterExtensionRevision) map[string][]ocv1.ObjectProbe {
+		probes := map[string][]ocv1.ObjectProbe{}
+		for _, phase := range rev.Spec.Phases {
+			for _, obj := range phase.Objects {
+				probes[obj.Object.GetName()] = obj.Probes
+			}
+		}
+		return probes
+	}
+	completed := ocv1.ObjectProbe{
+		Type:      ocv1.ObjectProbeTypeCondition,
+		Condition: &ocv1.ConditionProbe{Type: "Complete", Status: "True"},
+	}
+	succeeded := ocv1.ObjectProbe{
+		Type: ocv1.ObjectProbeTypeCEL,
+		CEL:  &ocv1.CELProbe{Rule: "self.status.succeeded >= 1"},
+	}
+
+	t.Run("attaches the probes of the selectors matching each object", func(t *testing.T) {
+		rev, err := b.GenerateRevision(t.Context(), dummyBundle, newExt(
+			ocv1.ClusterExtensionProbe{
+				Selector:   ocv1.ProbeSelector{Group: "batch", Kind: "Job"},
+				Assertions: []ocv1.ObjectProbe{completed},
+			},
+			ocv1.ClusterExtensionProbe{
+				Selector:   ocv1.ProbeSelector{Group: "batch", Kind: "Job", Name: "migrate"},
+				Assertions: []ocv1.ObjectProbe{succeeded},
+			},
+		), nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]ocv1.ObjectProbe{
+			"migrate":        {completed, succeeded},
+			"cleanup":        {completed},
+			"test-configmap": nil,
+		}, probesOf(rev))
+	})
+
+	t.Run("rejects CEL rules that don't compile", func(t *testing.T) {
+		_, err := b.GenerateRevision(t.Context(), dummyBundle, newExt(ocv1.ClusterExtensionProbe{
+			Selector: ocv1.ProbeSelector{Group: "batch", Kind: "Job"},
+			Assertions: []ocv1.ObjectProbe{{
+				Type: ocv1.ObjectProbeTypeCEL,
+				CEL:  &ocv1.CELProbe{Rule: "self.status.succeeded >="},
+			}},
+		}), nil, nil)
+		require.Error(t, err)
+		reason, ok := errorutil.ExtractTerminalReason(err)
+		require.True(t, ok)
+		assert.Equal(t, ocv1.ReasonInvalidConfiguration, reason)
+	})
+
+	t.Run("ignores probes when the feature is disabled", func(t *testing.T) {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.ObjectProbes)))
+		rev, err := b.GenerateRevision(t.Context(), dummyBundle, newExt(ocv1.ClusterExtensionProbe{
+			Selector:   ocv1.ProbeSelector{Group: "batch", Kind: "Job"},
+			Assertions: []ocv1.ObjectProbe{completed},
+		}), nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, probesOf(rev)["migrate"])
+	})
+}
+
+func Test_SimpleRevisionGenerator_PropagatesCanaryRollout(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.CanaryRollout)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.CanaryRollout)))
+	})
+
+	b := applier.SimpleRevisionGenerator{
+		Scheme: k8scheme.Scheme,
+		ManifestProvider: &FakeManifestProvider{
+			GetFn: func(fs.FS, *ocv1.ClusterExtension) ([]client.Object, error) {
+				return []client.Object{}, nil
+			},
+		},
+	}
+	newExt := func(rollout *ocv1.ClusterExtensionRollout) *ocv1.ClusterExtension {
+		return &ocv1.ClusterExtension{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ext"},
+			Spec: ocv1.ClusterExtensionSpec{
+				Namespace:      "test-namespace",
+				ServiceAccount: ocv1.ServiceAccountReference{Name: "test-sa"},
+				Install:        &ocv1.ClusterExtensionInstallConfig{Rollout: rollout},
+			},
+		}
+	}
+	canary := &ocv1.CanaryRollout{SoakSeconds: 300, MaxContainerRestarts: 2}
+
+	rev, err := b.GenerateRevision(t.Context(), dummyBundle, newExt(&ocv1.ClusterExtensionRollout{
+		Strategy: ocv1.RolloutStrategyCanary,
+		Canary:   canary,
+	}), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, canary, rev.Spec.Canary)
+
+	rev, err = b.GenerateRevision(t.Context(), dummyBundle, newExt(&ocv1.ClusterExtensionRollout{
+		Strategy: ocv1.RolloutStrategyAllAtOnce,
+	}), nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, rev.Spec.Canary)
+
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.CanaryRollout)))
+	rev, err = b.GenerateRevision(t.Context(), dummyBundle, newExt(&ocv1.ClusterExtensionRollout{
+		Strategy: ocv1.RolloutStrategyCanary,
+		Canary:   canary,
+	}), nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, rev.Spec.Canary, "the canary rollout is ignored when the feature is disabled")
+}
+
+func Test_SimpleRevisionGenerator_PropagatesUninstallPolicy(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.UninstallPolicy)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.UninstallPolicy)))
+	})
+
+	b := applier.SimpleRevisionGenerator{
+		Scheme: k8scheme.Scheme,
+		ManifestProvider: &FakeManifestProvider{
+			GetFn: func(fs.FS, *ocv1.ClusterExtension) ([]client.Object, error) {
+				return []client.Object{}, nil
+			},
+		},
+	}
+	ext := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ext"},
+		Spec: ocv1.ClusterExtensionSpec{
+			Namespace:      "test-namespace",
+			ServiceAccount: ocv1.ServiceAccountReference{Name: "test-sa"},
+			Install:        &ocv1.ClusterExtensionInstallConfig{UninstallPolicy: ocv1.UninstallPolicyOrphanCRDs},
+		},
+	}
+
+	rev, err := b.GenerateRevision(t.Context(), dummyBundle, ext, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ocv1.UninstallPolicyOrphanCRDs, rev.Spec.UninstallPolicy)
+
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.UninstallPolicy)))
+	rev, err = b.GenerateRevision(t.Context(), dummyBundle, ext, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, rev.Spec.UninstallPolicy, "the uninstall policy is ignored when the feature is disabled")
+}
+
+func Test_SimpleRevisionGenerator_PropagatesIgnoreDifferences(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.DriftPolicy)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.DriftPolicy)))
+	})
+
+	b := applier.SimpleRevisionGenerator{
+		Scheme: k8scheme.Scheme,
+		ManifestProvider: &FakeManifestProvider{
+			GetFn: func(fs.FS, *ocv1.ClusterExtension) ([]client.Object, error) {
+				return []client.Object{}, nil
+			},
+		},
+	}
+	ignored := []ocv1.IgnoreDifference{{
+		Selector:     ocv1.ProbeSelector{Group: "apps", Kind: "Deployment"},
+		JSONPointers: []string{"/spec/replicas"},
+	}}
+	ext := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ext"},
+		Spec: ocv1.ClusterExtensionSpec{
+			Namespace:      "test-namespace",
+			ServiceAccount: ocv1.ServiceAccountReference{Name: "test-sa"},
+			Install:        &ocv1.ClusterExtensionInstallConfig{IgnoreDifferences: ignored},
+		},
+	}
+
+	rev, err := b.GenerateRevision(t.Context(), dummyBundle, ext, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ignored, rev.Spec.IgnoreDifferences)
+
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.DriftPolicy)))
+	rev, err = b.GenerateRevision(t.Context(), dummyBundle, ext, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, rev.Spec.IgnoreDifferences, "ignored differences are dropped when the feature is disabled")
+}
+
+func Test_SimpleRevisionGenerator_CollisionProtection(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.CollisionProtectionPolicy)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.CollisionProtectionPolicy)))
+	})
+
+	b := applier.SimpleRevisionGenerator{
+		Scheme: k8scheme.Scheme,
+		ManifestProvider: &FakeManifestProvider{
+			GetFn: func(fs.FS, *ocv1.ClusterExtension) ([]client.Object, error) {
+				return []client.Object{
+					&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+					&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "test-operator", Namespace: "test-namespace"}},
+					&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "test-namespace"}},
+					&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-config", Namespace: "test-namespace"}},
+				}, nil
+			},
+		},
+	}
+	ext := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ext"},
+		Spec: ocv1.ClusterExtensionSpec{
+			Namespace:      "test-namespace",
+			ServiceAccount: ocv1.ServiceAccountReference{Name: "test-sa"},
+			Install: &ocv1.ClusterExtensionInstallConfig{
+				CollisionProtection: &ocv1.ClusterExtensionCollisionProtection{
+					Policy: ocv1.CollisionProtectionIfNoController,
+					Overrides: []ocv1.CollisionProtectionOverride{
+						{
+							Selector: ocv1.ProbeSelector{Kind: "Namespace"},
+							Policy:   ocv1.CollisionProtectionNone,
+						},
+						{
+							Selector: ocv1.ProbeSelector{Kind: "ServiceAccount", Name: "test-operator"},
+							Policy:   ocv1.CollisionProtectionPrevent,
+						},
+						{
+							Selector: ocv1.ProbeSelector{Kind: "ServiceAccount"},
+							Policy:   ocv1.CollisionProtectionNone,
+						},
+					},
+				},
+			},
+		},
+	}
+	collisionProtectionByName := func(rev *ocv1.ClusterExtensionRevision) map[string]ocv1.CollisionProtection {
+		m := map[string]ocv1.CollisionProtection{}
+		for _, phase := range rev.Spec.Phases {
+			for _, obj := range phase.Objects {
+				m[obj.Object.GetName()] = obj.CollisionProtection
+			}
+		}
+		return m
+	}
+
+	rev, err := b.GenerateRevision(t.Context(), dummyBundle, ext, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]ocv1.CollisionProtection{
+		"test-namespace": ocv1.CollisionProtectionNone,
+		// the first override selecting an object applies
+		"test-operator": "",
+		"other":         ocv1.CollisionProtectionNone,
+		"test-config":   ocv1.CollisionProtectionIfNoController,
+	}, collisionProtectionByName(rev))
+
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.CollisionProtectionPolicy)))
+	rev, err = b.GenerateRevision(t.Context(), dummyBundle, ext, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]ocv1.CollisionProtection{
+		"test-namespace": "",
+		"test-operator":  "",
+		"other":          "",
+		"test-config":    "",
+	}, collisionProtectionByName(rev), "the collision protection is ignored when the feature is disabled")
+}
+
+func Test_SimpleRevisionGenerator_IncludesInstallNamespace(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.InstallNamespaceCreation)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.InstallNamespaceCreation)))
+	})
+
+	b := applier.SimpleRevisionGenerator{
+		Scheme: k8scheme.Scheme,
+		ManifestProvider: &FakeManifestProvider{
+			GetFn: func(fs.FS, *ocv1.ClusterExtension) ([]client.Object, error) {
+				return []client.Object{
+					&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "test-operator", Namespace: "test-namespace"}},
+				}, nil
+			},
+		},
+	}
+	ext := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ext"},
+		Spec: ocv1.ClusterExtensionSpec{
+			Namespace:      "test-namespace",
+			ServiceAccount: ocv1.ServiceAccountReference{Name: "test-sa"},
+			Install: &ocv1.ClusterExtensionInstallConfig{
+				Namespace: &ocv1.ClusterExtensionInstallNamespace{
+					Labels:      map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+					Annotations: map[string]string{"example.com/team": "platform"},
+				},
+			},
+		},
+	}
+
+	rev, err := b.GenerateRevision(t.Context(), dummyBundle, ext, map[string]string{"my-label": "my-value"}, nil)
+	require.NoError(t, err)
+	require.Len(t, rev.Spec.Phases, 2)
+	assert.Equal(t, "namespaces", rev.Spec.Phases[0].Name)
+	require.Len(t, rev.Spec.Phases[0].Objects, 1)
+	ns := rev.Spec.Phases[0].Objects[0]
+	assert.Equal(t, "Namespace", ns.Object.GetKind())
+	assert.Equal(t, "test-namespace", ns.Object.GetName())
+	assert.Equal(t, map[string]string{
+		"pod-security.kubernetes.io/enforce": "restricted",
+		"my-label":                           "my-value",
+	}, ns.Object.GetLabels())
+	assert.Equal(t, map[string]string{"example.com/team": "platform"}, ns.Object.GetAnnotations())
+	assert.Empty(t, ns.CollisionProtection, "existing namespaces are never adopted")
+
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.InstallNamespaceCreation)))
+	rev, err = b.GenerateRevision(t.Context(), dummyBundle, ext, nil, nil)
+	require.NoError(t, err)
+	for _, phase := range rev.Spec.Phases {
+		for _, obj := range phase.Objects {
+			assert.NotEqual(t, "Namespace", obj.Object.GetKind(), "the namespace is not installed when the feature is disabled")
+		}
+	}
+}
+
 func Test_SimpleRevisionGenerator_PropagatesProgressDeadlineMinutes(t *testing.T) {
 	r := &FakeManifestProvider{
 		GetFn: func(b fs.FS, e *ocv1.ClusterExtension) ([]client.Object, error) {
@@ -1109,6 +1611,378 @@ func TestBoxcutter_Apply(t *testing.T) {
 	}
 }
 
+func TestBoxcutter_Apply_HistoryLimit(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	require.NoError(t, ocv1.AddToScheme(testScheme))
+
+	ext := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ext", UID: "test-uid"},
+		Spec: ocv1.ClusterExtensionSpec{
+			Install: &ocv1.ClusterExtensionInstallConfig{HistoryLimit: 2},
+		},
+	}
+	existingObjs := []client.Object{ext}
+	for i := int64(1); i <= 5; i++ {
+		existingObjs = append(existingObjs, &ocv1.ClusterExtensionRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   fmt.Sprintf("rev-%d", i),
+				Labels: map[string]string{labels.OwnerNameKey: ext.Name},
+			},
+			Spec: ocv1.ClusterExtensionRevisionSpec{
+				LifecycleState: ocv1.ClusterExtensionRevisionLifecycleStateArchived,
+				Revision:       i,
+			},
+		})
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(existingObjs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			// Updating the phases of the latest revision fails, so that a new revision is created.
+			Patch: func(ctx context.Context, client client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if cer, ok := obj.(*ocv1.ClusterExtensionRevision); ok && cer.Spec.Revision != 6 {
+					return apierrors.NewInvalid(cer.GroupVersionKind().GroupKind(), cer.GetName(), field.ErrorList{field.Invalid(field.NewPath("spec.phases"), "immutable", "spec.phases is immutable")})
+				}
+				return client.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+
+	boxcutter := &applier.Boxcutter{
+		Client:     fakeClient,
+		Scheme:     testScheme,
+		FieldOwner: "test-owner",
+		RevisionGenerator: &mockBundleRevisionBuilder{
+			makeRevisionFunc: func(ctx context.Context, bundleFS fs.FS, ext *ocv1.ClusterExtension, objectLabels, revisionAnnotations map[string]string) (*ocv1.ClusterExtensionRevision, error) {
+				return &ocv1.ClusterExtensionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: revisionAnnotations,
+						Labels:      map[string]string{labels.OwnerNameKey: ext.Name},
+					},
+				}, nil
+			},
+		},
+	}
+	_, _, err := boxcutter.Apply(t.Context(), fstest.MapFS{}, ext, nil, map[string]string{})
+	require.NoError(t, err)
+
+	revList := &ocv1.ClusterExtensionRevisionList{}
+	require.NoError(t, fakeClient.List(t.Context(), revList))
+	names := make([]string, 0, len(revList.Items))
+	for _, rev := range revList.Items {
+		names = append(names, rev.Name)
+	}
+	assert.ElementsMatch(t, []string{"rev-4", "rev-5", "test-ext-6"}, names,
+		"archived revisions beyond the history limit of the ClusterExtension are deleted")
+}
+
+func TestBoxcutter_Apply_PreexistingInstallNamespace(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.InstallNamespaceCreation)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.InstallNamespaceCreation)))
+	})
+	testScheme := runtime.NewScheme()
+	require.NoError(t, ocv1.AddToScheme(testScheme))
+	require.NoError(t, corev1.AddToScheme(testScheme))
+
+	ext := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ext", UID: "test-uid"},
+		Spec: ocv1.ClusterExtensionSpec{
+			Namespace:      "test-namespace",
+			ServiceAccount: ocv1.ServiceAccountReference{Name: "test-sa"},
+			Install: &ocv1.ClusterExtensionInstallConfig{
+				Namespace: &ocv1.ClusterExtensionInstallNamespace{
+					Labels: map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+				},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).
+		WithObjects(ext, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}}).
+		Build()
+	boxcutter := &applier.Boxcutter{
+		Client:     fakeClient,
+		Reader:     fakeClient,
+		Scheme:     testScheme,
+		FieldOwner: "test-owner",
+		RevisionGenerator: &applier.SimpleRevisionGenerator{
+			Scheme: k8scheme.Scheme,
+			ManifestProvider: &FakeManifestProvider{
+				GetFn: func(fs.FS, *ocv1.ClusterExtension) ([]client.Object, error) {
+					return []client.Object{
+						&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "test-operator", Namespace: "test-namespace"}},
+					}, nil
+				},
+			},
+		},
+	}
+
+	preview, err := boxcutter.PreviewUpgrade(t.Context(), dummyBundle, ext, nil, map[string]string{})
+	require.NoError(t, err)
+	require.Nil(t, preview)
+	_, _, err = boxcutter.Apply(t.Context(), dummyBundle, ext, nil, map[string]string{})
+	require.NoError(t, err)
+
+	revList := &ocv1.ClusterExtensionRevisionList{}
+	require.NoError(t, fakeClient.List(t.Context(), revList))
+	require.Len(t, revList.Items, 1)
+	for _, phase := range revList.Items[0].Spec.Phases {
+		for _, obj := range phase.Objects {
+			assert.NotEqual(t, "Namespace", obj.Object.GetKind(), "a namespace that existed before is not deleted with the revision")
+		}
+	}
+	ns := &corev1.Namespace{}
+	require.NoError(t, fakeClient.Get(t.Context(), client.ObjectKey{Name: "test-namespace"}, ns))
+	assert.Equal(t, map[string]string{"pod-security.kubernetes.io/enforce": "restricted"}, ns.Labels)
+	assert.Empty(t, ns.OwnerReferences)
+}
+
+func TestBoxcutter_Rollback(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	require.NoError(t, ocv1.AddToScheme(testScheme))
+
+	ext := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ext", UID: "test-uid"},
+		Spec: ocv1.ClusterExtensionSpec{
+			Namespace:      "test-ns",
+			ServiceAccount: ocv1.ServiceAccountReference{Name: "test-sa"},
+		},
+	}
+	revision := func(revNum int64, lifecycleState ocv1.ClusterExtensionRevisionLifecycleState, version string) *ocv1.ClusterExtensionRevision {
+		return &ocv1.ClusterExtensionRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        fmt.Sprintf("test-ext-%d", revNum),
+				Labels:      map[string]string{labels.OwnerNameKey: ext.Name},
+				Annotations: map[string]string{labels.BundleVersionKey: version},
+			},
+			Spec: ocv1.ClusterExtensionRevisionSpec{
+				LifecycleState: lifecycleState,
+				Revision:       revNum,
+				Phases: []ocv1.ClusterExtensionRevisionPhase{{
+					Name: string(applier.PhaseDeploy),
+					Objects: []ocv1.ClusterExtensionRevisionObject{{
+						Object: unstructured.Unstructured{Object: map[string]interface{}{
+							"apiVersion": "v1",
+							"kind":       "ConfigMap",
+							"metadata":   map[string]interface{}{"name": "cm-" + version},
+						}},
+					}},
+				}},
+			},
+		}
+	}
+	newBoxcutter := func(objs ...client.Object) (*applier.Boxcutter, client.Client) {
+		c := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(objs...).Build()
+		return &applier.Boxcutter{Client: c, Scheme: testScheme, FieldOwner: "test-owner"}, c
+	}
+
+	t.Run("reports the bundle of a revision", func(t *testing.T) {
+		bc, _ := newBoxcutter(revision(1, ocv1.ClusterExtensionRevisionLifecycleStateArchived, "1.0.0"), revision(2, ocv1.ClusterExtensionRevisionLifecycleStateActive, "2.0.0"))
+		bundleAnnotations, err := bc.RevisionBundle(t.Context(), ext, 1)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{labels.BundleVersionKey: "1.0.0"}, bundleAnnotations)
+
+		_, err = bc.RevisionBundle(t.Context(), ext, 3)
+		require.ErrorContains(t, err, `revision 3 of ClusterExtension "test-ext" not found`)
+	})
+
+	t.Run("creates a revision with the phases of the requested revision", func(t *testing.T) {
+		bc, c := newBoxcutter(revision(1, ocv1.ClusterExtensionRevisionLifecycleStateArchived, "1.0.0"), revision(2, ocv1.ClusterExtensionRevisionLifecycleStateActive, "2.0.0"))
+		succeeded, rolloutStatus, err := bc.Rollback(t.Context(), ext, 1, nil, nil)
+		require.NoError(t, err)
+		require.False(t, succeeded)
+		assert.Equal(t, "Revision 3 rolling back to revision 1 is not available yet.", rolloutStatus)
+
+		rev := &ocv1.ClusterExtensionRevision{}
+		require.NoError(t, c.Get(t.Context(), client.ObjectKey{Name: "test-ext-3"}, rev))
+		assert.Equal(t, int64(3), rev.Spec.Revision)
+		assert.Equal(t, ocv1.ClusterExtensionRevisionLifecycleStateActive, rev.Spec.LifecycleState)
+		assert.Equal(t, "cm-1.0.0", rev.Spec.Phases[0].Objects[0].Object.GetName())
+		assert.Equal(t, "1.0.0", rev.Annotations[labels.BundleVersionKey])
+		assert.Equal(t, "1", rev.Annotations[labels.RollbackOfRevisionKey])
+		assert.Equal(t, "test-sa", rev.Annotations[labels.ServiceAccountNameKey])
+		assert.Equal(t, ext.Name, rev.Labels[labels.OwnerNameKey])
+		require.Len(t, rev.OwnerReferences, 1)
+		assert.Equal(t, ext.UID, rev.OwnerReferences[0].UID)
+
+		t.Log("does not roll back again, and succeeds once the revision is available")
+		succeeded, _, err = bc.Rollback(t.Context(), ext, 1, nil, nil)
+		require.NoError(t, err)
+		require.False(t, succeeded)
+		apimeta.SetStatusCondition(&rev.Status.Conditions, metav1.Condition{
+			Type:   ocv1.ClusterExtensionRevisionTypeAvailable,
+			Status: metav1.ConditionTrue,
+			Reason: ocv1.ClusterExtensionRevisionReasonProbesSucceeded,
+		})
+		require.NoError(t, c.Update(t.Context(), rev))
+		succeeded, _, err = bc.Rollback(t.Context(), ext, 1, nil, nil)
+		require.NoError(t, err)
+		require.True(t, succeeded)
+		revList := &ocv1.ClusterExtensionRevisionList{}
+		require.NoError(t, c.List(t.Context(), revList))
+		assert.Len(t, revList.Items, 3)
+	})
+
+	t.Run("applies the current patches to the objects of the requested revision", func(t *testing.T) {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.ManifestPatches)))
+		t.Cleanup(func() {
+			require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.ManifestPatches)))
+		})
+		patchedExt := ext.DeepCopy()
+		patchedExt.Spec.Install = &ocv1.ClusterExtensionInstallConfig{Patches: []ocv1.ClusterExtensionPatch{{
+			Target: ocv1.PatchTarget{Kind: "ConfigMap"},
+			Type:   ocv1.PatchTypeJSON6902,
+			Patch:  `[{"op": "move", "from": "/data/level", "path": "/data/logLevel"}]`,
+		}}}
+		rev1 := revision(1, ocv1.ClusterExtensionRevisionLifecycleStateArchived, "1.0.0")
+		rev1.Spec.Phases[0].Objects[0].Object.Object["data"] = map[string]interface{}{"level": "debug"}
+		bc, c := newBoxcutter(rev1, revision(2, ocv1.ClusterExtensionRevisionLifecycleStateActive, "2.0.0"))
+
+		_, _, err := bc.Rollback(t.Context(), patchedExt, 1, map[string]string{"owner": "test-ext"}, nil)
+		require.NoError(t, err)
+		rev := &ocv1.ClusterExtensionRevision{}
+		require.NoError(t, c.Get(t.Context(), client.ObjectKey{Name: "test-ext-3"}, rev))
+		obj := rev.Spec.Phases[0].Objects[0].Object
+		data, _, _ := unstructured.NestedStringMap(obj.Object, "data")
+		assert.Equal(t, map[string]string{"logLevel": "debug"}, data)
+		assert.Equal(t, map[string]string{"owner": "test-ext"}, obj.GetLabels())
+		assert.NotEmpty(t, rev.Annotations[labels.PatchesDigestKey])
+
+		t.Log("does not apply the patches again to revisions that were patched with them")
+		_, _, err = bc.Rollback(t.Context(), patchedExt, 3, nil, nil)
+		require.NoError(t, err)
+		require.NoError(t, c.Get(t.Context(), client.ObjectKey{Name: "test-ext-4"}, rev))
+		data, _, _ = unstructured.NestedStringMap(rev.Spec.Phases[0].Objects[0].Object.Object, "data")
+		assert.Equal(t, map[string]string{"logLevel": "debug"}, data)
+	})
+
+	t.Run("runs the upgrade preflights against the phases of the requested revision", func(t *testing.T) {
+		bc, c := newBoxcutter(revision(1, ocv1.ClusterExtensionRevisionLifecycleStateArchived, "1.0.0"), revision(2, ocv1.ClusterExtensionRevisionLifecycleStateActive, "2.0.0"))
+		bc.Preflights = []applier.Preflight{&mockPreflight{upgradeErr: errors.New("unsafe CRD change")}}
+		succeeded, _, err := bc.Rollback(t.Context(), ext, 1, nil, nil)
+		require.False(t, succeeded)
+		require.EqualError(t, err, "unsafe CRD change")
+		revList := &ocv1.ClusterExtensionRevisionList{}
+		require.NoError(t, c.List(t.Context(), revList))
+		assert.Len(t, revList.Items, 2)
+	})
+}
+
+func TestBoxcutter_PauseResume(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	require.NoError(t, ocv1.AddToScheme(testScheme))
+
+	ext := &ocv1.ClusterExtension{ObjectMeta: metav1.ObjectMeta{Name: "test-ext"}}
+	revision := func(revNum int64, lifecycleState ocv1.ClusterExtensionRevisionLifecycleState) *ocv1.ClusterExtensionRevision {
+		return &ocv1.ClusterExtensionRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   fmt.Sprintf("test-ext-%d", revNum),
+				Labels: map[string]string{labels.OwnerNameKey: ext.Name},
+			},
+			Spec: ocv1.ClusterExtensionRevisionSpec{
+				LifecycleState: lifecycleState,
+				Revision:       revNum,
+			},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+		revision(1, ocv1.ClusterExtensionRevisionLifecycleStateArchived),
+		revision(2, ocv1.ClusterExtensionRevisionLifecycleStateActive),
+		revision(3, ocv1.ClusterExtensionRevisionLifecycleStateActive),
+	).Build()
+	bc := &applier.Boxcutter{Client: c, Scheme: testScheme, FieldOwner: "test-owner"}
+	pausedRevisions := func() []string {
+		revList := &ocv1.ClusterExtensionRevisionList{}
+		require.NoError(t, c.List(t.Context(), revList))
+		var paused []string
+		for _, rev := range revList.Items {
+			if rev.Spec.Paused {
+				paused = append(paused, rev.Name)
+			}
+		}
+		return paused
+	}
+
+	require.NoError(t, bc.Pause(t.Context(), ext))
+	assert.ElementsMatch(t, []string{"test-ext-2", "test-ext-3"}, pausedRevisions(), "archived revisions are not paused")
+
+	require.NoError(t, bc.Resume(t.Context(), ext))
+	assert.Empty(t, pausedRevisions())
+}
+
+func TestBoxcutter_PreviewUpgrade(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	require.NoError(t, ocv1.AddToScheme(testScheme))
+
+	ext := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ext", UID: "test-uid"},
+		Spec: ocv1.ClusterExtensionSpec{
+			Namespace:      "test-ns",
+			ServiceAccount: ocv1.ServiceAccountReference{Name: "test-sa"},
+		},
+	}
+	configMap := func(name string, data map[string]interface{}) ocv1.ClusterExtensionRevisionObject {
+		return ocv1.ClusterExtensionRevisionObject{
+			Object: unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": name, "namespace": "test-ns"},
+				"data":       data,
+			}},
+		}
+	}
+	revision := func(objs ...ocv1.ClusterExtensionRevisionObject) *ocv1.ClusterExtensionRevision {
+		return &ocv1.ClusterExtensionRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "test-ext-1",
+				Labels: map[string]string{labels.OwnerNameKey: ext.Name},
+			},
+			Spec: ocv1.ClusterExtensionRevisionSpec{
+				LifecycleState: ocv1.ClusterExtensionRevisionLifecycleStateActive,
+				Revision:       1,
+				Phases:         []ocv1.ClusterExtensionRevisionPhase{{Name: string(applier.PhaseDeploy), Objects: objs}},
+			},
+		}
+	}
+	newBoxcutter := func(desired *ocv1.ClusterExtensionRevision, objs ...client.Object) *applier.Boxcutter {
+		return &applier.Boxcutter{
+			Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(objs...).Build(),
+			Scheme: testScheme,
+			RevisionGenerator: &mockBundleRevisionBuilder{
+				makeRevisionFunc: func(context.Context, fs.FS, *ocv1.ClusterExtension, map[string]string, map[string]string) (*ocv1.ClusterExtensionRevision, error) {
+					return desired, nil
+				},
+			},
+		}
+	}
+	current := revision(configMap("cm-a", map[string]interface{}{"key": "old"}), configMap("cm-b", nil))
+	revisionAnnotations := map[string]string{labels.BundleNameKey: "test-bundle.v2.0.0", labels.BundleVersionKey: "2.0.0"}
+
+	t.Run("reports the changes to the objects of the latest revision", func(t *testing.T) {
+		bc := newBoxcutter(revision(configMap("cm-a", map[string]interface{}{"key": "new"}), configMap("cm-c", nil)), current)
+		preview, err := bc.PreviewUpgrade(t.Context(), fstest.MapFS{}, ext, nil, revisionAnnotations)
+		require.NoError(t, err)
+		require.NotNil(t, preview)
+		assert.Equal(t, "test-bundle.v2.0.0", preview.Bundle.Name)
+		assert.Equal(t, []ocv1.UpgradePreviewObject{
+			{Action: ocv1.UpgradePreviewActionChanged, ObjectReference: ocv1.ObjectReference{Kind: "ConfigMap", Namespace: "test-ns", Name: "cm-a"}, Fields: []string{"data.key"}},
+			{Action: ocv1.UpgradePreviewActionRemoved, ObjectReference: ocv1.ObjectReference{Kind: "ConfigMap", Namespace: "test-ns", Name: "cm-b"}},
+			{Action: ocv1.UpgradePreviewActionAdded, ObjectReference: ocv1.ObjectReference{Kind: "ConfigMap", Namespace: "test-ns", Name: "cm-c"}},
+		}, preview.Objects)
+	})
+
+	t.Run("returns no preview when no object changes", func(t *testing.T) {
+		bc := newBoxcutter(current.DeepCopy(), current)
+		preview, err := bc.PreviewUpgrade(t.Context(), fstest.MapFS{}, ext, nil, revisionAnnotations)
+		require.NoError(t, err)
+		assert.Nil(t, preview)
+	})
+
+	t.Run("returns no preview without revisions", func(t *testing.T) {
+		bc := newBoxcutter(current.DeepCopy())
+		preview, err := bc.PreviewUpgrade(t.Context(), fstest.MapFS{}, ext, nil, revisionAnnotations)
+		require.NoError(t, err)
+		assert.Nil(t, preview)
+	})
+}
+
 func Test_PreAuthorizer_Integration(t *testing.T) {
 	testScheme := runtime.NewScheme()
 	require.NoError(t, ocv1.AddToScheme(testScheme))
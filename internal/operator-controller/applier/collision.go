@@ -0,0 +1,40 @@
+package applier
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
+)
+
+// collisionProtectionSelector selects the collision protection that a ClusterExtension declares
+// for the objects rendered from a bundle. A nil collisionProtectionSelector selects the default
+// collision protection of ClusterExtensionRevision objects, Prevent.
+type collisionProtectionSelector ocv1.ClusterExtensionCollisionProtection
+
+func newCollisionProtectionSelector(ext *ocv1.ClusterExtension) *collisionProtectionSelector {
+	if !features.OperatorControllerFeatureGate.Enabled(features.CollisionProtectionPolicy) || ext.Spec.Install == nil {
+		return nil
+	}
+	return (*collisionProtectionSelector)(ext.Spec.Install.CollisionProtection)
+}
+
+// CollisionProtectionFor returns the collision protection of the first override selecting obj,
+// or the policy of s. It returns an empty collision protection for Prevent, the default of
+// ClusterExtensionRevision objects.
+func (s *collisionProtectionSelector) CollisionProtectionFor(obj *unstructured.Unstructured) ocv1.CollisionProtection {
+	if s == nil {
+		return ""
+	}
+	policy := s.Policy
+	for _, o := range s.Overrides {
+		if matchesProbeSelector(o.Selector, obj) {
+			policy = o.Policy
+			break
+		}
+	}
+	if policy == ocv1.CollisionProtectionPrevent {
+		return ""
+	}
+	return policy
+}
@@ -0,0 +1,255 @@
+package applier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	apimachyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
+)
+
+// ignoreDifferences returns the ignored differences of ext, or nil when they aren't configured
+// or the DriftPolicy feature is disabled.
+func ignoreDifferences(ext *ocv1.ClusterExtension) []ocv1.IgnoreDifference {
+	if !features.OperatorControllerFeatureGate.Enabled(features.DriftPolicy) || ext.Spec.Install == nil {
+		return nil
+	}
+	return ext.Spec.Install.IgnoreDifferences
+}
+
+// IgnoredFields returns the JSON pointers of the fields of obj that are ignored by all
+// differences selecting obj, in order.
+func IgnoredFields(differences []ocv1.IgnoreDifference, obj *unstructured.Unstructured) []string {
+	var pointers []string
+	for _, d := range differences {
+		if matchesProbeSelector(d.Selector, obj) {
+			pointers = append(pointers, d.JSONPointers...)
+		}
+	}
+	return pointers
+}
+
+// RemoveIgnoredFields removes the fields of obj identified by pointers, so that applying obj
+// doesn't change them. Fields below an array element that obj doesn't have are skipped.
+func RemoveIgnoredFields(obj *unstructured.Unstructured, pointers []string) {
+	for _, p := range pointers {
+		if tokens := jsonPointerTokens(p); len(tokens) > 0 {
+			jsonPointerRemove(obj.Object, tokens)
+		}
+	}
+}
+
+// jsonPointerTokens splits the JSON pointer p into its unescaped reference tokens.
+func jsonPointerTokens(p string) []string {
+	if !strings.HasPrefix(p, "/") {
+		return nil
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return tokens
+}
+
+func jsonPointerGet(v any, tokens []string) (any, bool) {
+	for _, t := range tokens {
+		switch c := v.(type) {
+		case map[string]any:
+			var ok bool
+			if v, ok = c[t]; !ok {
+				return nil, false
+			}
+		case []any:
+			i, err := strconv.Atoi(t)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, false
+			}
+			v = c[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// jsonPointerRemove removes the value at tokens from obj. Array elements are not removed, as
+// removing them would shift the elements that follow.
+func jsonPointerRemove(obj map[string]any, tokens []string) {
+	parent, ok := jsonPointerGet(obj, tokens[:len(tokens)-1])
+	if !ok {
+		return
+	}
+	if m, ok := parent.(map[string]any); ok {
+		delete(m, tokens[len(tokens)-1])
+	}
+}
+
+// driftFieldOwner is the field manager of the server-side dry-run applies that detect drift.
+const driftFieldOwner = "olm.operatorframework.io/drift-detection"
+
+// reconcileRelease reconciles the objects of rel with the cluster. The fields of the existing
+// objects that are ignored by ext are removed from the reconciled manifest, so that they are
+// not reverted. When drift is reported, the objects that the reconcile reverts are recorded
+// for TakeRevertedDrift.
+func (h *Helm) reconcileRelease(ctx context.Context, ac helmclient.ActionInterface, ext *ocv1.ClusterExtension, rel *release.Release) error {
+	differences := ignoreDifferences(ext)
+	if len(differences) == 0 && !h.ReportDrift {
+		return ac.Reconcile(rel)
+	}
+	objs, err := releaseManifestObjects(rel)
+	if err != nil {
+		return err
+	}
+	c, err := h.ClientFor(ctx, ext)
+	if err != nil {
+		return err
+	}
+
+	var (
+		manifest bytes.Buffer
+		drifted  []ocv1.ObjectReference
+	)
+	for _, obj := range objs {
+		if err := setReleaseObjectNamespace(c, obj, ext.Spec.Namespace); err != nil {
+			return err
+		}
+		pointers := IgnoredFields(differences, obj)
+		if len(pointers) > 0 || h.ReportDrift {
+			live := &unstructured.Unstructured{}
+			live.SetGroupVersionKind(obj.GroupVersionKind())
+			err := c.Get(ctx, client.ObjectKeyFromObject(obj), live)
+			switch {
+			case apierrors.IsNotFound(err):
+				// Deleted objects are created with all of their fields.
+				live = nil
+			case err != nil:
+				return fmt.Errorf("getting %s %q: %w", obj.GetKind(), obj.GetName(), err)
+			default:
+				RemoveIgnoredFields(obj, pointers)
+			}
+			if h.ReportDrift {
+				changed, err := wouldChange(ctx, c, obj, live)
+				if err != nil {
+					return err
+				}
+				if changed {
+					drifted = append(drifted, ocv1.ObjectReference{
+						Group:     obj.GroupVersionKind().Group,
+						Kind:      obj.GetKind(),
+						Namespace: obj.GetNamespace(),
+						Name:      obj.GetName(),
+					})
+				}
+			}
+		}
+		b, err := obj.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		manifest.Write(b)
+	}
+
+	// The stored release is not changed, so that the ignored fields don't cause upgrades.
+	reconciled := *rel
+	reconciled.Manifest = manifest.String()
+	if err := ac.Reconcile(&reconciled); err != nil {
+		return err
+	}
+	h.storeRevertedDrift(ext, drifted)
+	return nil
+}
+
+// wouldChange reports whether applying obj changes live, its current state on the cluster, or
+// creates it when live is nil. The result of a server-side dry-run apply of obj is compared with
+// live, so that values that the API server normalizes, such as quantities and the stringData of
+// Secrets, don't count as changes.
+func wouldChange(ctx context.Context, c client.Client, obj, live *unstructured.Unstructured) (bool, error) {
+	if live == nil {
+		return true, nil
+	}
+	applied := obj.DeepCopy()
+	if err := c.Apply(ctx, client.ApplyConfigurationFromUnstructured(applied),
+		client.DryRunAll, client.ForceOwnership, client.FieldOwner(driftFieldOwner)); err != nil {
+		return false, fmt.Errorf("dry-run applying %s %q: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	// The dry-run adds the fields of its field manager to the managed fields.
+	applied.SetManagedFields(nil)
+	live = live.DeepCopy()
+	live.SetManagedFields(nil)
+	return !equality.Semantic.DeepEqual(applied.Object, live.Object), nil
+}
+
+// storeRevertedDrift records the objects of the release of ext that were reverted by a
+// reconcile, until they are taken by TakeRevertedDrift.
+func (h *Helm) storeRevertedDrift(ext *ocv1.ClusterExtension, reverted []ocv1.ObjectReference) {
+	if len(reverted) == 0 {
+		return
+	}
+	h.revertedMu.Lock()
+	defer h.revertedMu.Unlock()
+	if h.reverted == nil {
+		h.reverted = map[types.UID][]ocv1.ObjectReference{}
+	}
+	h.reverted[ext.GetUID()] = reverted
+}
+
+// TakeRevertedDrift returns the objects of the release of ext whose changes on the cluster
+// were last reverted, and forgets them. It returns nil when no changes were reverted since
+// the last call.
+func (h *Helm) TakeRevertedDrift(ext *ocv1.ClusterExtension) []ocv1.ObjectReference {
+	h.revertedMu.Lock()
+	defer h.revertedMu.Unlock()
+	reverted := h.reverted[ext.GetUID()]
+	delete(h.reverted, ext.GetUID())
+	return reverted
+}
+
+// releaseManifestObjects decodes the objects of the manifest of rel.
+func releaseManifestObjects(rel *release.Release) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	dec := apimachyaml.NewYAMLOrJSONDecoder(strings.NewReader(rel.Manifest), 1024)
+	for {
+		obj := &unstructured.Unstructured{}
+		err := dec.Decode(&obj.Object)
+		if errors.Is(err, io.EOF) {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing release %q objects: %w", rel.Name, err)
+		}
+		if len(obj.Object) > 0 {
+			objs = append(objs, obj)
+		}
+	}
+}
+
+// setReleaseObjectNamespace sets the namespace of obj, an object of a release installed in
+// namespace, when it is namespaced and doesn't specify its namespace.
+func setReleaseObjectNamespace(c client.Client, obj *unstructured.Unstructured, namespace string) error {
+	if obj.GetNamespace() != "" {
+		return nil
+	}
+	namespaced, err := c.IsObjectNamespaced(obj)
+	if err != nil {
+		return fmt.Errorf("determining scope of %s %q: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	if namespaced {
+		obj.SetNamespace(namespace)
+	}
+	return nil
+}
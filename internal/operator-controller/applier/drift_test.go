@@ -0,0 +1,300 @@
+package applier_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/release"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/applier"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
+)
+
+func TestIgnoredFields(t *testing.T) {
+	deployment := &unstructured.Unstructured{}
+	deployment.SetAPIVersion("apps/v1")
+	deployment.SetKind("Deployment")
+	deployment.SetNamespace("test-namespace")
+	deployment.SetName("test-operator")
+
+	differences := []ocv1.IgnoreDifference{
+		{Selector: ocv1.ProbeSelector{Group: "apps", Kind: "Deployment"}, JSONPointers: []string{"/spec/replicas"}},
+		{Selector: ocv1.ProbeSelector{Group: "apps", Kind: "Deployment", Name: "other"}, JSONPointers: []string{"/spec/paused"}},
+		{Selector: ocv1.ProbeSelector{Kind: "Deployment"}, JSONPointers: []string{"/spec/minReadySeconds"}},
+		{Selector: ocv1.ProbeSelector{Group: "apps", Kind: "Deployment", Namespace: "test-namespace"}, JSONPointers: []string{"/metadata/annotations/example.com~1injected"}},
+	}
+	assert.Equal(t, []string{"/spec/replicas", "/metadata/annotations/example.com~1injected"}, applier.IgnoredFields(differences, deployment))
+	assert.Empty(t, applier.IgnoredFields(nil, deployment))
+}
+
+func TestRemoveIgnoredFields(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		obj      map[string]any
+		pointers []string
+		expected map[string]any
+	}{
+		{
+			name:     "removes fields",
+			obj:      map[string]any{"spec": map[string]any{"replicas": int64(1), "paused": false}},
+			pointers: []string{"/spec/replicas"},
+			expected: map[string]any{"spec": map[string]any{"paused": false}},
+		},
+		{
+			name:     "unescapes pointers and skips missing fields",
+			obj:      map[string]any{"metadata": map[string]any{"annotations": map[string]any{"example.com/injected": "true", "other": "x"}}},
+			pointers: []string{"/metadata/annotations/example.com~1injected", "/metadata/labels/app", "invalid"},
+			expected: map[string]any{"metadata": map[string]any{"annotations": map[string]any{"other": "x"}}},
+		},
+		{
+			name: "removes fields of array elements",
+			obj: map[string]any{"spec": map[string]any{"containers": []any{
+				map[string]any{"name": "manager", "image": "example.com/manager:v1"},
+			}}},
+			pointers: []string{"/spec/containers/0/image", "/spec/containers/1/image"},
+			expected: map[string]any{"spec": map[string]any{"containers": []any{
+				map[string]any{"name": "manager"},
+			}}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: tc.obj}
+			applier.RemoveIgnoredFields(obj, tc.pointers)
+			assert.Equal(t, tc.expected, obj.Object)
+		})
+	}
+}
+
+const driftTestManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-operator
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: test-operator
+  template:
+    metadata:
+      labels:
+        app: test-operator
+    spec:
+      containers:
+      - name: manager
+        image: example.com/manager:v1
+        resources:
+          requests:
+            cpu: 1000m
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: test-operator`
+
+func newDriftTestDeployment(replicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-operator", Namespace: "test-namespace"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(replicas),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test-operator"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test-operator"}},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{
+					{Name: "manager", Image: "example.com/manager:v1", Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					}},
+				}},
+			},
+		},
+	}
+}
+
+func newDriftTestExtension(differences ...ocv1.IgnoreDifference) *ocv1.ClusterExtension {
+	ext := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ext"},
+		Spec: ocv1.ClusterExtensionSpec{
+			Namespace:      "test-namespace",
+			ServiceAccount: ocv1.ServiceAccountReference{Name: "test-sa"},
+		},
+	}
+	if len(differences) > 0 {
+		ext.Spec.Install = &ocv1.ClusterExtensionInstallConfig{IgnoreDifferences: differences}
+	}
+	return ext
+}
+
+var ignoreReplicas = ocv1.IgnoreDifference{
+	Selector:     ocv1.ProbeSelector{Group: "apps", Kind: "Deployment", Name: "test-operator"},
+	JSONPointers: []string{"/spec/replicas"},
+}
+
+// newDriftTestClient returns a client of objs whose server-side dry-run applies return the
+// object on the cluster with the applied fields, normalized by its type like the API server.
+func newDriftTestClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	c, ok := newOLMv0TestClient(t, objs...).(client.WithWatch)
+	require.True(t, ok)
+	return interceptor.NewClient(c, interceptor.Funcs{
+		Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+			b, err := json.Marshal(obj)
+			if err != nil {
+				return err
+			}
+			applied := &unstructured.Unstructured{}
+			if err := applied.UnmarshalJSON(b); err != nil {
+				return err
+			}
+			live := &unstructured.Unstructured{}
+			live.SetGroupVersionKind(applied.GroupVersionKind())
+			if err := c.Get(ctx, client.ObjectKeyFromObject(applied), live); err != nil {
+				return err
+			}
+			merged := mergeJSON(live.Object, applied.Object)
+			typed, err := c.Scheme().New(applied.GroupVersionKind())
+			if err != nil {
+				return err
+			}
+			if b, err = json.Marshal(merged); err != nil {
+				return err
+			}
+			if err := json.Unmarshal(b, typed); err != nil {
+				return err
+			}
+			if b, err = json.Marshal(typed); err != nil {
+				return err
+			}
+			return json.Unmarshal(b, obj)
+		},
+	})
+}
+
+// mergeJSON returns dst with the fields of src set, merging objects and replacing other values.
+func mergeJSON(dst, src map[string]any) map[string]any {
+	for k, v := range src {
+		if sm, ok := v.(map[string]any); ok {
+			if dm, ok := dst[k].(map[string]any); ok {
+				dst[k] = mergeJSON(dm, sm)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+func TestHelm_ReconcileReportsRevertedDrift(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.DriftPolicy)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.DriftPolicy)))
+	})
+
+	newHelm := func(reconcileErr error, objs ...client.Object) *applier.Helm {
+		c := newDriftTestClient(t, objs...)
+		return &applier.Helm{
+			ActionClientGetter: &mockActionGetter{
+				currentRel:   &release.Release{Name: "test-ext", Info: &release.Info{Status: release.StatusDeployed}, Manifest: driftTestManifest},
+				reconcileErr: reconcileErr,
+			},
+			Manager: &mockManagedContentCacheManager{cache: &mockManagedContentCache{}},
+			ClientFor: func(context.Context, *ocv1.ClusterExtension) (client.Client, error) {
+				return c, nil
+			},
+			ReportDrift: true,
+		}
+	}
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "test-operator", Namespace: "test-namespace"}}
+
+	t.Run("reports no drift when the objects match the release", func(t *testing.T) {
+		helmApplier := newHelm(nil, newDriftTestDeployment(1), serviceAccount)
+		_, _, err := helmApplier.Apply(t.Context(), nil, newDriftTestExtension(), nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, helmApplier.TakeRevertedDrift(newDriftTestExtension()))
+	})
+
+	t.Run("reports changed and deleted objects once", func(t *testing.T) {
+		ext := newDriftTestExtension()
+		helmApplier := newHelm(nil, newDriftTestDeployment(3))
+		_, _, err := helmApplier.Apply(t.Context(), nil, ext, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []ocv1.ObjectReference{
+			{Group: "apps", Kind: "Deployment", Namespace: "test-namespace", Name: "test-operator"},
+			{Kind: "ServiceAccount", Namespace: "test-namespace", Name: "test-operator"},
+		}, helmApplier.TakeRevertedDrift(ext))
+		assert.Empty(t, helmApplier.TakeRevertedDrift(ext))
+	})
+
+	t.Run("does not report changes of ignored fields", func(t *testing.T) {
+		helmApplier := newHelm(nil, newDriftTestDeployment(3), serviceAccount)
+		_, _, err := helmApplier.Apply(t.Context(), nil, newDriftTestExtension(ignoreReplicas), nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, helmApplier.TakeRevertedDrift(newDriftTestExtension()))
+	})
+
+	t.Run("does not report drift that was not reverted", func(t *testing.T) {
+		helmApplier := newHelm(errors.New("reconcile failed"), newDriftTestDeployment(3), serviceAccount)
+		_, _, err := helmApplier.Apply(t.Context(), nil, newDriftTestExtension(), nil, nil)
+		require.Error(t, err)
+		assert.Empty(t, helmApplier.TakeRevertedDrift(newDriftTestExtension()))
+	})
+}
+
+func TestHelm_ReconcileRemovesIgnoredFields(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.DriftPolicy)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.DriftPolicy)))
+	})
+
+	c := newOLMv0TestClient(t, newDriftTestDeployment(3))
+	mockAcg := &mockActionGetter{
+		currentRel: &release.Release{Name: "test-ext", Info: &release.Info{Status: release.StatusDeployed}, Manifest: driftTestManifest},
+	}
+	helmApplier := &applier.Helm{
+		ActionClientGetter: mockAcg,
+		Manager:            &mockManagedContentCacheManager{cache: &mockManagedContentCache{}},
+		ClientFor: func(context.Context, *ocv1.ClusterExtension) (client.Client, error) {
+			return c, nil
+		},
+	}
+
+	_, _, err := helmApplier.Apply(t.Context(), nil, newDriftTestExtension(ignoreReplicas), nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, mockAcg.reconciledRel)
+	assert.Equal(t, driftTestManifest, mockAcg.currentRel.Manifest, "the stored release is not changed")
+
+	reconciled := releaseObjectsByKind(t, mockAcg.reconciledRel)
+	_, found, err := unstructured.NestedFieldNoCopy(reconciled["Deployment"].Object, "spec", "replicas")
+	require.NoError(t, err)
+	assert.False(t, found, "the ignored fields of existing objects are not reconciled")
+	_, found, err = unstructured.NestedFieldNoCopy(reconciled["Deployment"].Object, "spec", "selector")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Contains(t, reconciled, "ServiceAccount", "objects that don't exist are reconciled with all of their fields")
+}
+
+func releaseObjectsByKind(t *testing.T, rel *release.Release) map[string]*unstructured.Unstructured {
+	t.Helper()
+	objs, err := applier.HelmReleaseToObjectsConverter{}.GetObjectsFromRelease(rel)
+	require.NoError(t, err)
+	byKind := map[string]*unstructured.Unstructured{}
+	for _, obj := range objs {
+		u, ok := obj.(*unstructured.Unstructured)
+		require.True(t, ok)
+		byKind[u.GetKind()] = u
+	}
+	return byKind
+}
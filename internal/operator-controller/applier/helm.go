@@ -3,12 +3,18 @@ package applier
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
 
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
@@ -18,6 +24,7 @@ import (
 	"helm.sh/helm/v3/pkg/storage/driver"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	apimachyaml "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
@@ -28,10 +35,14 @@ import (
 
 	ocv1 "github.com/operator-framework/operator-controller/api/v1"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/authorization"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/config"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/contentmanager"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/contentmanager/cache"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/installnamespace"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/rukpak/util"
+	errorutil "github.com/operator-framework/operator-controller/internal/shared/util/error"
 	imageutil "github.com/operator-framework/operator-controller/internal/shared/util/image"
 )
 
@@ -68,6 +79,54 @@ type Helm struct {
 
 	Manager contentmanager.Manager
 	Watcher cache.Watcher
+
+	// ClientFor returns the client used to read and dry-run apply the objects of releases, to
+	// detect their drift and leave out the fields listed in spec.install.ignoreDifferences,
+	// typically using the ServiceAccount of the ClusterExtension.
+	ClientFor func(ctx context.Context, ext *ocv1.ClusterExtension) (client.Client, error)
+
+	// ReportDrift records the objects whose changes on the cluster are reverted when releases
+	// are reconciled, for TakeRevertedDrift.
+	ReportDrift bool
+
+	// ActionConfigGetter provides the release storage, used to recover releases left pending
+	// by an install or upgrade that was interrupted, for example by a restart of the controller.
+	ActionConfigGetter helmclient.ActionConfigGetter
+
+	// operations holds the installs and upgrades of releases with hooks, which run in
+	// the background so that reconciles aren't blocked while the hooks run. They run
+	// with operationsCtx, the context of the manager set by Start.
+	operationsMu  sync.Mutex
+	operations    map[types.UID]*helmOperation
+	operationsCtx context.Context
+	operationsWG  sync.WaitGroup
+
+	// dryRuns holds the server-side dry-runs of releases run by PreviewUpgrade, so that
+	// Apply doesn't run them again in the same reconcile.
+	dryRunsMu sync.Mutex
+	dryRuns   map[types.UID]*helmDryRun
+
+	// reverted holds the objects of releases whose changes were reverted by a reconcile,
+	// until they are reported.
+	revertedMu sync.Mutex
+	reverted   map[types.UID][]ocv1.ObjectReference
+}
+
+// helmDryRun is the result of the server-side dry-run of the release of a ClusterExtension.
+// key identifies the inputs of the dry-run.
+type helmDryRun struct {
+	key        string
+	rel        *release.Release
+	desiredRel *release.Release
+	state      string
+}
+
+// helmOperation is an install or upgrade of a release running in the background.
+// done is closed once rel and err are set.
+type helmOperation struct {
+	done chan struct{}
+	rel  *release.Release
+	err  error
 }
 
 // runPreAuthorizationChecks performs pre-authorization checks for a Helm release
@@ -79,8 +138,15 @@ func (h *Helm) runPreAuthorizationChecks(ctx context.Context, ext *ocv1.ClusterE
 		return fmt.Errorf("error rendering content for pre-authorization checks: %w", err)
 	}
 
+	// Hooks are created with the ServiceAccount as well, so the permissions
+	// they require are checked alongside the release manifest.
+	manifest := tmplRel.Manifest
+	for _, hook := range tmplRel.Hooks {
+		manifest += "\n---\n" + hook.Manifest
+	}
+
 	manifestManager := getUserInfo(ext)
-	return formatPreAuthorizerOutput(h.PreAuthorizer.PreAuthorize(ctx, manifestManager, strings.NewReader(tmplRel.Manifest), extManagementPerms(ext)))
+	return formatPreAuthorizerOutput(h.PreAuthorizer.PreAuthorize(ctx, manifestManager, strings.NewReader(manifest), extManagementPerms(ext)))
 }
 
 func (h *Helm) Apply(ctx context.Context, contentFS fs.FS, ext *ocv1.ClusterExtension, objectLabels map[string]string, storageLabels map[string]string) (bool, string, error) {
@@ -94,14 +160,18 @@ func (h *Helm) Apply(ctx context.Context, contentFS fs.FS, ext *ocv1.ClusterExte
 		return h.reconcileExistingRelease(ctx, ac, ext)
 	}
 
-	chrt, err := h.buildHelmChart(contentFS, ext)
+	chrt, values, err := h.buildHelmChart(contentFS, ext)
 	if err != nil {
 		return false, "", err
 	}
-	values := chartutil.Values{}
 
+	patcher, err := newObjectPatcher(ext)
+	if err != nil {
+		return false, "", err
+	}
 	post := &postrenderer{
-		labels: objectLabels,
+		labels:  objectLabels,
+		patcher: patcher,
 	}
 
 	if h.PreAuthorizer != nil {
@@ -117,10 +187,29 @@ func (h *Helm) Apply(ctx context.Context, contentFS fs.FS, ext *ocv1.ClusterExte
 		return false, "", err
 	}
 
-	rel, desiredRel, state, err := h.getReleaseState(ac, ext, chrt, values, post)
+	if running, err := h.checkOperation(ext); running || err != nil {
+		return false, fmt.Sprintf("Waiting for the hooks of release %q to complete.", ext.GetName()), err
+	}
+	if err := h.recoverInterruptedRelease(ctx, ac, ext); err != nil {
+		return false, "", err
+	}
+
+	if err := h.ensureInstallNamespace(ctx, ext); err != nil {
+		return false, "", err
+	}
+
+	rel, desiredRel, state, err := h.takeDryRun(ext, chrt, values, objectLabels)
+	if desiredRel == nil {
+		rel, desiredRel, state, err = h.getReleaseState(ac, ext, chrt, values, post)
+	}
 	if err != nil {
 		return false, "", fmt.Errorf("failed to get release state using server-side dry-run: %w", err)
 	}
+	if state == StateNeedsUpgrade && isBlockedByFailedHook(rel, desiredRel, values) {
+		// Hooks such as database migrations are not safe to run over and over again,
+		// so a release that failed on a hook is only retried once it changes.
+		return false, "", errorutil.NewTerminalError(ocv1.ReasonHookFailed, fmt.Errorf("%w; the release is retried once its chart version or configuration changes", newHookError(rel, nil)))
+	}
 	objs, err := h.HelmReleaseToObjectsConverter.GetObjectsFromRelease(desiredRel)
 	if err != nil {
 		return false, "", err
@@ -146,48 +235,301 @@ func (h *Helm) Apply(ctx context.Context, contentFS fs.FS, ext *ocv1.ClusterExte
 
 	switch state {
 	case StateNeedsInstall:
-		rel, err = ac.Install(ext.GetName(), ext.Spec.Namespace, chrt, values, func(install *action.Install) error {
-			install.CreateNamespace = false
-			install.Labels = storageLabels
-			return nil
-		}, helmclient.AppendInstallPostRenderer(post))
+		install := func() (*release.Release, error) {
+			return ac.Install(ext.GetName(), ext.Spec.Namespace, chrt, values, func(install *action.Install) error {
+				install.CreateNamespace = false
+				install.Labels = storageLabels
+				install.Timeout = helmHookTimeout
+				return nil
+			}, helmclient.AppendInstallPostRenderer(post))
+		}
+		if hasHooks(desiredRel, release.HookPreInstall, release.HookPostInstall) {
+			if err := h.startOperation(ext, install); err != nil {
+				return false, "", err
+			}
+			return false, fmt.Sprintf("Running the hooks of release %q.", ext.GetName()), nil
+		}
+		rel, err = install()
 		if err != nil {
-			return false, "", err
+			return false, "", hookFailureOrError(rel, err)
 		}
 	case StateNeedsUpgrade:
-		rel, err = ac.Upgrade(ext.GetName(), ext.Spec.Namespace, chrt, values, func(upgrade *action.Upgrade) error {
-			upgrade.MaxHistory = maxHelmReleaseHistory
-			upgrade.Labels = storageLabels
-			return nil
-		}, helmclient.AppendUpgradePostRenderer(post))
+		upgrade := func() (*release.Release, error) {
+			return ac.Upgrade(ext.GetName(), ext.Spec.Namespace, chrt, values, func(upgrade *action.Upgrade) error {
+				upgrade.MaxHistory = historyLimit(ext)
+				upgrade.Labels = storageLabels
+				upgrade.Timeout = helmHookTimeout
+				return nil
+			}, helmclient.AppendUpgradePostRenderer(post))
+		}
+		if hasHooks(desiredRel, release.HookPreUpgrade, release.HookPostUpgrade) {
+			if err := h.startOperation(ext, upgrade); err != nil {
+				return false, "", err
+			}
+			return false, fmt.Sprintf("Running the hooks of release %q.", ext.GetName()), nil
+		}
+		rel, err = upgrade()
 		if err != nil {
-			return false, "", err
+			return false, "", hookFailureOrError(rel, err)
 		}
 	case StateUnchanged:
-		if err := ac.Reconcile(rel); err != nil {
+		if err := h.reconcileRelease(ctx, ac, ext, rel); err != nil {
 			return false, "", err
 		}
 	default:
 		return false, "", fmt.Errorf("unexpected release state %q", state)
 	}
 
+	if err := h.watchReleaseObjects(ctx, ext, rel); err != nil {
+		return true, "", err
+	}
+	return true, "", nil
+}
+
+// ensureInstallNamespace applies the namespace requested by spec.install.namespace of ext with
+// the client of ClientFor, so that it is created with the permissions of the ServiceAccount of
+// ext rather than those of OLM.
+func (h *Helm) ensureInstallNamespace(ctx context.Context, ext *ocv1.ClusterExtension) error {
+	if !installnamespace.Requested(ext) {
+		return nil
+	}
+	if h.ClientFor == nil {
+		return errors.New("creating the installation namespace is not supported")
+	}
+	cl, err := h.ClientFor(ctx, ext)
+	if err != nil {
+		return err
+	}
+	err = (&installnamespace.Ensurer{Reader: cl, Writer: cl}).Ensure(ctx, ext)
+	if installnamespace.IsConfigurationError(err) {
+		return errorutil.NewTerminalError(ocv1.ReasonInvalidConfiguration, err)
+	}
+	return err
+}
+
+// Pause stops the drift detection of the objects of the release of ext, so that changes
+// to them are not reverted.
+func (h *Helm) Pause(_ context.Context, ext *ocv1.ClusterExtension) error {
+	if h.Manager == nil {
+		return nil
+	}
+	return h.Manager.Delete(ext)
+}
+
+// Resume does nothing, since Apply sets up the drift detection of the objects of the release
+// of ext again.
+func (h *Helm) Resume(context.Context, *ocv1.ClusterExtension) error {
+	return nil
+}
+
+// PreviewUpgrade renders the release of the content in contentFS with a server-side dry-run, and
+// compares its objects with the objects of the current release. It returns nil when there is no
+// current release, while the hooks of the release run, or when the upgrade doesn't change any
+// object.
+func (h *Helm) PreviewUpgrade(ctx context.Context, contentFS fs.FS, ext *ocv1.ClusterExtension, objectLabels, revisionAnnotations map[string]string) (*ocv1.ClusterExtensionUpgradePreview, error) {
+	chrt, values, err := h.buildHelmChart(contentFS, ext)
+	if err != nil {
+		return nil, err
+	}
+	patcher, err := newObjectPatcher(ext)
+	if err != nil {
+		return nil, err
+	}
+	post := &postrenderer{
+		labels:  objectLabels,
+		patcher: patcher,
+	}
+
+	ac, err := h.ActionClientGetter.ActionClientFor(ctx, ext)
+	if err != nil {
+		return nil, err
+	}
+	// Helm refuses to dry-run an upgrade of a pending release. Leave the release to Apply while
+	// its hooks run, and recover it first when an interrupted operation left it pending.
+	if h.HooksRunning(ext) {
+		return nil, nil
+	}
+	if err := h.recoverInterruptedRelease(ctx, ac, ext); err != nil {
+		return nil, err
+	}
+	rel, desiredRel, state, err := h.getReleaseState(ac, ext, chrt, values, post)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release state using server-side dry-run: %w", err)
+	}
+	if err := h.storeDryRun(ext, chrt, values, objectLabels, &helmDryRun{rel: rel, desiredRel: desiredRel, state: state}); err != nil {
+		return nil, err
+	}
+	if state != StateNeedsUpgrade {
+		return nil, nil
+	}
+
+	currentObjs, err := h.HelmReleaseToObjectsConverter.GetObjectsFromRelease(rel)
+	if err != nil {
+		return nil, err
+	}
+	desiredObjs, err := h.HelmReleaseToObjectsConverter.GetObjectsFromRelease(desiredRel)
+	if err != nil {
+		return nil, err
+	}
+	return newUpgradePreview(ext, currentObjs, desiredObjs, revisionAnnotations)
+}
+
+// watchReleaseObjects sets up watches on the objects of rel to detect drift.
+func (h *Helm) watchReleaseObjects(ctx context.Context, ext *ocv1.ClusterExtension, rel *release.Release) error {
 	relObjects, err := util.ManifestObjects(strings.NewReader(rel.Manifest), fmt.Sprintf("%s-release-manifest", rel.Name))
 	if err != nil {
-		return true, "", err
+		return err
 	}
 	klog.FromContext(ctx).Info("watching managed objects")
 	cache, err := h.Manager.Get(ctx, ext)
 	if err != nil {
-		return true, "", err
+		return err
 	}
+	return cache.Watch(ctx, h.Watcher, relObjects...)
+}
 
-	if err := cache.Watch(ctx, h.Watcher, relObjects...); err != nil {
-		return true, "", err
+// RevisionBundle returns the storage labels, describing the installed bundle, of the given
+// revision of the Helm release of ext.
+func (h *Helm) RevisionBundle(ctx context.Context, ext *ocv1.ClusterExtension, revision int64) (map[string]string, error) {
+	ac, err := h.ActionClientGetter.ActionClientFor(ctx, ext)
+	if err != nil {
+		return nil, err
 	}
+	target, err := releaseRevision(ac, ext, revision)
+	if err != nil {
+		return nil, err
+	}
+	return target.Labels, nil
+}
 
+// Rollback installs the chart and values of the given revision of the Helm release of ext as
+// a new release revision, labeled with labels.RollbackOfRevisionKey and storageLabels. It only
+// reconciles the release once the latest revision is a successful rollback to the given revision.
+func (h *Helm) Rollback(ctx context.Context, ext *ocv1.ClusterExtension, revision int64, objectLabels, storageLabels map[string]string) (bool, string, error) {
+	ac, err := h.ActionClientGetter.ActionClientFor(ctx, ext)
+	if err != nil {
+		return false, "", err
+	}
+	if running, err := h.checkOperation(ext); running || err != nil {
+		return false, fmt.Sprintf("Waiting for the hooks of release %q to complete.", ext.GetName()), err
+	}
+	if err := h.recoverInterruptedRelease(ctx, ac, ext); err != nil {
+		return false, "", err
+	}
+	target, err := releaseRevision(ac, ext, revision)
+	if err != nil {
+		return false, "", err
+	}
+
+	rollbackOf := strconv.FormatInt(revision, 10)
+	rel, err := ac.Get(ext.GetName())
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get current release: %w", err)
+	}
+	if rel.Labels[labels.RollbackOfRevisionKey] == rollbackOf {
+		if rel.Info != nil && rel.Info.Status == release.StatusDeployed {
+			if err := h.reconcileRelease(ctx, ac, ext, rel); err != nil {
+				return false, "", err
+			}
+			if err := h.watchReleaseObjects(ctx, ext, rel); err != nil {
+				return true, "", err
+			}
+			return true, "", nil
+		}
+		if hook, _ := failedHook(rel); hook != nil {
+			return false, "", errorutil.NewTerminalError(ocv1.ReasonHookFailed, fmt.Errorf("%w; remove spec.install.rollback to resume upgrades", newHookError(rel, nil)))
+		}
+	}
+
+	patcher, err := newObjectPatcher(ext)
+	if err != nil {
+		return false, "", err
+	}
+	post := &postrenderer{
+		labels:  objectLabels,
+		patcher: patcher,
+	}
+	if h.PreAuthorizer != nil {
+		if err := h.runPreAuthorizationChecks(ctx, ext, target.Chart, target.Config, post); err != nil {
+			return false, "", err
+		}
+	}
+
+	_, desiredRel, _, err := h.getReleaseState(ac, ext, target.Chart, target.Config, post)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to render revision %d using server-side dry-run: %w", revision, err)
+	}
+	objs, err := h.HelmReleaseToObjectsConverter.GetObjectsFromRelease(desiredRel)
+	if err != nil {
+		return false, "", err
+	}
+	if err := runUpgradePreflights(ctx, h.Preflights, ext, objs); err != nil {
+		return false, "", err
+	}
+
+	releaseLabels := rollbackStorageLabels(target.Labels, storageLabels, rollbackOf)
+	upgrade := func() (*release.Release, error) {
+		return ac.Upgrade(ext.GetName(), ext.Spec.Namespace, target.Chart, target.Config, func(upgrade *action.Upgrade) error {
+			upgrade.MaxHistory = historyLimit(ext)
+			upgrade.Labels = releaseLabels
+			upgrade.Timeout = helmHookTimeout
+			return nil
+		}, helmclient.AppendUpgradePostRenderer(post))
+	}
+	if hasHooks(desiredRel, release.HookPreUpgrade, release.HookPostUpgrade) {
+		if err := h.startOperation(ext, upgrade); err != nil {
+			return false, "", err
+		}
+		return false, fmt.Sprintf("Running the hooks of release %q.", ext.GetName()), nil
+	}
+	rel, err = upgrade()
+	if err != nil {
+		return false, "", hookFailureOrError(rel, err)
+	}
+	if err := h.watchReleaseObjects(ctx, ext, rel); err != nil {
+		return true, "", err
+	}
 	return true, "", nil
 }
 
+// releaseRevision returns the given revision of the Helm release of ext.
+func releaseRevision(ac helmclient.ActionInterface, ext *ocv1.ClusterExtension, revision int64) (*release.Release, error) {
+	history, err := ac.History(ext.GetName())
+	if err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil, fmt.Errorf("failed to get release history: %w", err)
+	}
+	for _, rel := range history {
+		if rel != nil && int64(rel.Version) == revision {
+			return rel, nil
+		}
+	}
+	return nil, errorutil.NewTerminalError(ocv1.ReasonBlocked, fmt.Errorf("revision %d of Helm release %q not found; only the %d most recent revisions are retained", revision, ext.GetName(), historyLimit(ext)))
+}
+
+// rollbackStorageLabels returns the storage labels of a revision rolling back to a revision
+// labeled with targetLabels. Labels describing an earlier rollback are not carried over.
+func rollbackStorageLabels(targetLabels, storageLabels map[string]string, rollbackOf string) map[string]string {
+	out := make(map[string]string, len(targetLabels)+len(storageLabels)+1)
+	for k, v := range targetLabels {
+		switch k {
+		case labels.RollbackOfRevisionKey, labels.FailedRevisionKey, labels.FailedBundleKey:
+			continue
+		}
+		out[k] = v
+	}
+	maps.Copy(out, storageLabels)
+	out[labels.RollbackOfRevisionKey] = rollbackOf
+	return out
+}
+
+// historyLimit returns the number of Helm release revisions retained for ext.
+func historyLimit(ext *ocv1.ClusterExtension) int {
+	if ext.Spec.Install != nil && ext.Spec.Install.HistoryLimit > 0 {
+		return int(ext.Spec.Install.HistoryLimit)
+	}
+	return maxHelmReleaseHistory
+}
+
 // reconcileExistingRelease reconciles an existing Helm release without catalog access.
 // This is used when the catalog is unavailable but we need to maintain the current installation.
 // It reconciles the release to actively maintain resources, and sets up watchers for monitoring/observability.
@@ -201,7 +543,7 @@ func (h *Helm) reconcileExistingRelease(ctx context.Context, ac helmclient.Actio
 	}
 
 	// Reconcile the existing release to ensure resources are maintained
-	if err := ac.Reconcile(rel); err != nil {
+	if err := h.reconcileRelease(ctx, ac, ext, rel); err != nil {
 		// Reconcile failed - resources NOT maintained
 		// Return false (rollout failed) with error
 		return false, "", err
@@ -244,21 +586,90 @@ func (h *Helm) reconcileExistingRelease(ctx context.Context, ac helmclient.Actio
 	return true, "", nil
 }
 
-func (h *Helm) buildHelmChart(bundleFS fs.FS, ext *ocv1.ClusterExtension) (*chart.Chart, error) {
+// buildHelmChart returns the chart to install for bundleFS along with the release values.
+// Registry+v1 bundles consume the ClusterExtension configuration while being converted to
+// a chart, so they are installed with empty values. Helm chart bundles receive the
+// ClusterExtension configuration as release values.
+func (h *Helm) buildHelmChart(bundleFS fs.FS, ext *ocv1.ClusterExtension) (*chart.Chart, chartutil.Values, error) {
 	if h.HelmChartProvider == nil {
-		return nil, errors.New("HelmChartProvider is nil")
+		return nil, nil, errors.New("HelmChartProvider is nil")
 	}
 	if features.OperatorControllerFeatureGate.Enabled(features.HelmChartSupport) {
 		meta := new(chart.Metadata)
 		if ok, _ := imageutil.IsBundleSourceChart(bundleFS, meta); ok {
-			return imageutil.LoadChartFSWithOptions(
+			chrt, err := imageutil.LoadChartFSWithOptions(
 				bundleFS,
 				fmt.Sprintf("%s-%s.tgz", meta.Name, meta.Version),
 				imageutil.WithInstallNamespace(ext.Spec.Namespace),
 			)
+			if err != nil {
+				return nil, nil, err
+			}
+			values, err := chartValues(chrt, ext)
+			if err != nil {
+				return nil, nil, err
+			}
+			return chrt, values, nil
 		}
 	}
-	return h.HelmChartProvider.Get(bundleFS, ext)
+	chrt, err := h.HelmChartProvider.Get(bundleFS, ext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return chrt, chartutil.Values{}, nil
+}
+
+// chartValues returns the ClusterExtension configuration as release values for chrt.
+// When the chart ships a values.schema.json, the configuration merged with the chart's
+// default values is validated against it, mirroring the validation Helm performs itself.
+func chartValues(chrt *chart.Chart, ext *ocv1.ClusterExtension) (chartutil.Values, error) {
+	cfg, err := config.UnmarshalConfig(extensionConfigBytes(ext), nil, ext.Spec.Namespace)
+	if err != nil {
+		return nil, errorutil.NewTerminalError(ocv1.ReasonInvalidConfiguration, fmt.Errorf("invalid ClusterExtension configuration: %w", err))
+	}
+	values := chartutil.Values(*cfg)
+	if values == nil {
+		values = chartutil.Values{}
+	}
+
+	schema, err := (&chartSchemaProvider{chart: chrt}).GetConfigSchema()
+	if err != nil {
+		return nil, fmt.Errorf("error getting configuration schema: %w", err)
+	}
+	if schema == nil {
+		return values, nil
+	}
+	merged, err := chartutil.CoalesceValues(chrt, values)
+	if err != nil {
+		return nil, errorutil.NewTerminalError(ocv1.ReasonInvalidConfiguration, fmt.Errorf("invalid ClusterExtension configuration: %w", err))
+	}
+	mergedBytes, err := json.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling chart values: %w", err)
+	}
+	if _, err := config.UnmarshalConfig(mergedBytes, schema, ext.Spec.Namespace); err != nil {
+		return nil, errorutil.NewTerminalError(ocv1.ReasonInvalidConfiguration, fmt.Errorf("invalid ClusterExtension configuration: %w", err))
+	}
+	return values, nil
+}
+
+// chartSchemaProvider provides the configuration schema of a Helm chart from its
+// values.schema.json file.
+type chartSchemaProvider struct {
+	chart *chart.Chart
+}
+
+var _ config.SchemaProvider = (*chartSchemaProvider)(nil)
+
+func (p *chartSchemaProvider) GetConfigSchema() (map[string]any, error) {
+	if len(p.chart.Schema) == 0 {
+		return nil, nil
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(p.chart.Schema, &schema); err != nil {
+		return nil, fmt.Errorf("parsing values.schema.json of chart %q: %w", p.chart.Name(), err)
+	}
+	return schema, nil
 }
 
 func (h *Helm) renderClientOnlyRelease(ctx context.Context, ext *ocv1.ClusterExtension, chrt *chart.Chart, values chartutil.Values, post postrender.PostRenderer) (*release.Release, error) {
@@ -307,7 +718,7 @@ func (h *Helm) getReleaseState(cl helmclient.ActionInterface, ext *ocv1.ClusterE
 	}
 
 	desiredRelease, err := cl.Upgrade(ext.GetName(), ext.Spec.Namespace, chrt, values, func(upgrade *action.Upgrade) error {
-		upgrade.MaxHistory = maxHelmReleaseHistory
+		upgrade.MaxHistory = historyLimit(ext)
 		upgrade.DryRun = true
 		upgrade.DryRunOption = "server"
 		return nil
@@ -324,8 +735,321 @@ func (h *Helm) getReleaseState(cl helmclient.ActionInterface, ext *ocv1.ClusterE
 	return currentRelease, desiredRelease, relState, nil
 }
 
+// storeDryRun records the dry-run dr of the release of ext, rendered from chrt and values
+// with objectLabels, for the next Apply of ext.
+func (h *Helm) storeDryRun(ext *ocv1.ClusterExtension, chrt *chart.Chart, values chartutil.Values, objectLabels map[string]string, dr *helmDryRun) error {
+	key, err := dryRunKey(ext, chrt, values, objectLabels)
+	if err != nil {
+		return err
+	}
+	dr.key = key
+	h.dryRunsMu.Lock()
+	defer h.dryRunsMu.Unlock()
+	if h.dryRuns == nil {
+		h.dryRuns = map[types.UID]*helmDryRun{}
+	}
+	h.dryRuns[ext.GetUID()] = dr
+	return nil
+}
+
+// takeDryRun returns the release state recorded by storeDryRun for the same inputs, and
+// forgets it. It returns a nil desired release when there is no such dry-run.
+func (h *Helm) takeDryRun(ext *ocv1.ClusterExtension, chrt *chart.Chart, values chartutil.Values, objectLabels map[string]string) (*release.Release, *release.Release, string, error) {
+	h.dryRunsMu.Lock()
+	dr, ok := h.dryRuns[ext.GetUID()]
+	delete(h.dryRuns, ext.GetUID())
+	h.dryRunsMu.Unlock()
+	if !ok {
+		return nil, nil, "", nil
+	}
+	key, err := dryRunKey(ext, chrt, values, objectLabels)
+	if err != nil || key != dr.key {
+		return nil, nil, "", nil
+	}
+	return dr.rel, dr.desiredRel, dr.state, nil
+}
+
+// dryRunKey identifies the inputs of the dry-run of the release of ext. The resource version
+// of ext changes with its spec, and with its status once a reconcile completes, so that dry-runs
+// are only reused within a reconcile.
+func dryRunKey(ext *ocv1.ClusterExtension, chrt *chart.Chart, values chartutil.Values, objectLabels map[string]string) (string, error) {
+	b, err := json.Marshal(struct {
+		ResourceVersion string            `json:"resourceVersion"`
+		Chart           *chart.Metadata   `json:"chart"`
+		Values          chartutil.Values  `json:"values"`
+		Labels          map[string]string `json:"labels"`
+	}{ext.GetResourceVersion(), chrt.Metadata, values, objectLabels})
+	if err != nil {
+		return "", fmt.Errorf("error computing the key of the dry-run of release %q: %w", ext.GetName(), err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Start runs the background operations of the Helm applier with ctx, the context of the
+// manager, and waits for the running ones to complete once ctx is done. No operation is
+// started after that. Helm actions can't be cancelled, so operations that don't complete
+// within the graceful shutdown timeout of the manager leave their release pending, and
+// the release is recovered by the next Apply, once the controller runs again.
+func (h *Helm) Start(ctx context.Context) error {
+	h.operationsMu.Lock()
+	h.operationsCtx = ctx
+	h.operationsMu.Unlock()
+
+	<-ctx.Done()
+	h.operationsWG.Wait()
+	return nil
+}
+
+// Forget drops the state kept for ext, including background operations that completed
+// but were not reported yet, once ext is finalized. A running operation completes in
+// the background.
+func (h *Helm) Forget(ext *ocv1.ClusterExtension) {
+	h.operationsMu.Lock()
+	delete(h.operations, ext.GetUID())
+	h.operationsMu.Unlock()
+
+	h.dryRunsMu.Lock()
+	delete(h.dryRuns, ext.GetUID())
+	h.dryRunsMu.Unlock()
+
+	h.revertedMu.Lock()
+	delete(h.reverted, ext.GetUID())
+	h.revertedMu.Unlock()
+}
+
+// HooksRunning reports whether the hooks of the release of ext are running in the
+// background, so that ext is reconciled again to report their result.
+func (h *Helm) HooksRunning(ext *ocv1.ClusterExtension) bool {
+	h.operationsMu.Lock()
+	defer h.operationsMu.Unlock()
+	op, ok := h.operations[ext.GetUID()]
+	if !ok {
+		return false
+	}
+	select {
+	case <-op.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// startOperation runs run in the background for the release of ext. Helm waits for each
+// hook to complete, for up to helmHookTimeout, and the release is pending until then. Apply
+// reports the release as rolling out, and picks up the result of run once it is done.
+func (h *Helm) startOperation(ext *ocv1.ClusterExtension, run func() (*release.Release, error)) error {
+	h.operationsMu.Lock()
+	defer h.operationsMu.Unlock()
+	ctx := h.operationsCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("not running the hooks of release %q: %w", ext.GetName(), err)
+	}
+	op := &helmOperation{done: make(chan struct{})}
+	if h.operations == nil {
+		h.operations = map[types.UID]*helmOperation{}
+	}
+	h.operations[ext.GetUID()] = op
+	h.operationsWG.Add(1)
+
+	l := klog.FromContext(ctx).WithValues("release", ext.GetName())
+	go func() {
+		defer h.operationsWG.Done()
+		defer close(op.done)
+		op.rel, op.err = run()
+		if op.err != nil {
+			l.Error(op.err, "failed to run the hooks of release")
+		}
+	}()
+	return nil
+}
+
+// recoverInterruptedRelease marks the release of ext as failed when it is pending while no
+// operation of this process runs on it. Operations only run in the process that started
+// them, and only the leader reconciles releases, so such a release was left pending by an
+// operation that was interrupted, for example by a restart of the controller or a change
+// of leadership. Helm refuses to upgrade a pending release, so the release is marked as
+// failed, and the next upgrade installs it again.
+func (h *Helm) recoverInterruptedRelease(ctx context.Context, ac helmclient.ActionInterface, ext *ocv1.ClusterExtension) error {
+	rel, err := ac.Get(ext.GetName())
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get current release: %w", err)
+	}
+	if rel.Info == nil || !rel.Info.Status.IsPending() {
+		return nil
+	}
+	if h.ActionConfigGetter == nil {
+		return fmt.Errorf("revision %d of release %q is %s, and was left pending by an interrupted operation", rel.Version, rel.Name, rel.Info.Status)
+	}
+	cfg, err := h.ActionConfigGetter.ActionConfigFor(ctx, ext)
+	if err != nil {
+		return err
+	}
+	status := rel.Info.Status
+	rel.SetStatus(release.StatusFailed, fmt.Sprintf("Interrupted while the release was %s", status))
+	if err := cfg.Releases.Update(rel); err != nil {
+		return fmt.Errorf("error recovering revision %d of release %q: %w", rel.Version, rel.Name, err)
+	}
+	klog.FromContext(ctx).Info("marked interrupted release as failed", "release", rel.Name, "revision", rel.Version, "status", status)
+	return nil
+}
+
+// checkOperation reports whether a background operation on the release of ext is
+// running, and returns the error of an operation that finished since the last check.
+func (h *Helm) checkOperation(ext *ocv1.ClusterExtension) (bool, error) {
+	op := h.takeOperation(ext)
+	if op == nil {
+		return false, nil
+	}
+	select {
+	case <-op.done:
+		if op.err != nil {
+			return false, hookFailureOrError(op.rel, op.err)
+		}
+		return false, nil
+	default:
+		return true, nil
+	}
+}
+
+// takeOperation returns the background operation on the release of ext, if any, and
+// forgets it once it is done.
+func (h *Helm) takeOperation(ext *ocv1.ClusterExtension) *helmOperation {
+	h.operationsMu.Lock()
+	defer h.operationsMu.Unlock()
+	op, ok := h.operations[ext.GetUID()]
+	if !ok {
+		return nil
+	}
+	select {
+	case <-op.done:
+		delete(h.operations, ext.GetUID())
+	default:
+	}
+	return op
+}
+
+// hasHooks returns true when rel has hooks for any of the given events.
+func hasHooks(rel *release.Release, events ...release.HookEvent) bool {
+	if rel == nil {
+		return false
+	}
+	return slices.ContainsFunc(rel.Hooks, func(hook *release.Hook) bool {
+		return slices.ContainsFunc(hook.Events, func(event release.HookEvent) bool {
+			return slices.Contains(events, event)
+		})
+	})
+}
+
+// HookError reports a Helm hook that failed while installing or upgrading a release.
+// The release is recorded as failed, and the previously deployed release, if any,
+// remains installed.
+type HookError struct {
+	// Event is the hook event during which the hook failed, for example "pre-upgrade".
+	Event release.HookEvent
+	// Hook identifies the failed hook resource, for example "Job/db-migrate".
+	Hook string
+	// Err is the error reported by Helm, if any.
+	Err error
+}
+
+func (e *HookError) Error() string {
+	msg := fmt.Sprintf("%s hook %s failed", e.Event, e.Hook)
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// releaseHookEvents are the hook events run while installing and upgrading releases,
+// in the order of execution.
+var releaseHookEvents = []release.HookEvent{
+	release.HookPreInstall,
+	release.HookPostInstall,
+	release.HookPreUpgrade,
+	release.HookPostUpgrade,
+}
+
+// failedHook returns the hook of rel whose last run failed, along with the release
+// event it ran for.
+func failedHook(rel *release.Release) (*release.Hook, release.HookEvent) {
+	if rel == nil {
+		return nil, ""
+	}
+	for _, hook := range rel.Hooks {
+		if hook.LastRun.Phase != release.HookPhaseFailed {
+			continue
+		}
+		for _, event := range releaseHookEvents {
+			if slices.Contains(hook.Events, event) {
+				return hook, event
+			}
+		}
+	}
+	return nil, ""
+}
+
+func newHookError(rel *release.Release, err error) *HookError {
+	hook, event := failedHook(rel)
+	if hook == nil {
+		return nil
+	}
+	return &HookError{Event: event, Hook: fmt.Sprintf("%s/%s", hook.Kind, hook.Name), Err: err}
+}
+
+// hookFailureOrError returns a terminal HookFailed error when err was caused by a
+// failed hook of rel, and err otherwise.
+func hookFailureOrError(rel *release.Release, err error) error {
+	if hookErr := newHookError(rel, err); hookErr != nil {
+		return errorutil.NewTerminalError(ocv1.ReasonHookFailed, hookErr)
+	}
+	return err
+}
+
+// isBlockedByFailedHook returns true when currentRel failed on a hook and the upgrade
+// to desiredRel would run the same chart with the same values again.
+func isBlockedByFailedHook(currentRel, desiredRel *release.Release, values chartutil.Values) bool {
+	if currentRel == nil || desiredRel == nil || currentRel.Info == nil || currentRel.Info.Status != release.StatusFailed {
+		return false
+	}
+	if hook, _ := failedHook(currentRel); hook == nil {
+		return false
+	}
+	if currentRel.Manifest != desiredRel.Manifest || chartVersion(currentRel.Chart) != chartVersion(desiredRel.Chart) {
+		return false
+	}
+	return valuesEqual(currentRel.Config, values)
+}
+
+func chartVersion(chrt *chart.Chart) string {
+	if chrt == nil || chrt.Metadata == nil {
+		return ""
+	}
+	return chrt.Metadata.Version
+}
+
+func valuesEqual(a, b map[string]interface{}) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aJSON, bJSON)
+}
+
 type postrenderer struct {
 	labels  map[string]string
+	patcher *objectPatcher
 	cascade postrender.PostRenderer
 }
 
@@ -341,6 +1065,10 @@ func (p *postrenderer) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, erro
 		if err != nil {
 			return nil, err
 		}
+		// Labels are set after patching, so that patches can't change them.
+		if err := p.patcher.Patch(&obj); err != nil {
+			return nil, err
+		}
 		obj.SetLabels(util.MergeMaps(obj.GetLabels(), p.labels))
 		b, err := obj.MarshalJSON()
 		if err != nil {
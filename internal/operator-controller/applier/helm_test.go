@@ -1,24 +1,36 @@
 package applier_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
 	"os"
+	"strings"
 	"testing"
 	"testing/fstest"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/postrender"
 	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
 	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
 
@@ -27,13 +39,18 @@ import (
 	"github.com/operator-framework/operator-controller/internal/operator-controller/authorization"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/contentmanager"
 	cmcache "github.com/operator-framework/operator-controller/internal/operator-controller/contentmanager/cache"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/rukpak/util"
+	errorutil "github.com/operator-framework/operator-controller/internal/shared/util/error"
 )
 
 var _ contentmanager.Manager = (*mockManagedContentCacheManager)(nil)
 
 type mockManagedContentCacheManager struct {
-	err   error
-	cache cmcache.Cache
+	err     error
+	cache   cmcache.Cache
+	deleted bool
 }
 
 func (m *mockManagedContentCacheManager) Get(_ context.Context, _ *ocv1.ClusterExtension) (cmcache.Cache, error) {
@@ -44,6 +61,7 @@ func (m *mockManagedContentCacheManager) Get(_ context.Context, _ *ocv1.ClusterE
 }
 
 func (m *mockManagedContentCacheManager) Delete(_ *ocv1.ClusterExtension) error {
+	m.deleted = true
 	return m.err
 }
 
@@ -88,6 +106,14 @@ func (mp *mockPreflight) Upgrade(context.Context, []client.Object) error {
 	return mp.upgradeErr
 }
 
+type mockActionConfigGetter struct {
+	releases *storage.Storage
+}
+
+func (m *mockActionConfigGetter) ActionConfigFor(context.Context, client.Object) (*action.Configuration, error) {
+	return &action.Configuration{Releases: m.releases}, nil
+}
+
 type mockHelmReleaseToObjectsConverter struct {
 }
 
@@ -107,6 +133,14 @@ type mockActionGetter struct {
 	desiredRel         *release.Release
 	currentRel         *release.Release
 	history            []*release.Release
+	installedVals      map[string]interface{}
+	upgradedVals       map[string]interface{}
+	upgradedLabels     map[string]string
+	upgradedMaxHistory int
+	reconciled         bool
+	reconciledRel      *release.Release
+	postRenderer       postrender.PostRenderer
+	dryRunUpgrades     int
 }
 
 func (mag *mockActionGetter) ActionClientFor(ctx context.Context, obj client.Object) (helmclient.ActionInterface, error) {
@@ -129,8 +163,10 @@ func (mag *mockActionGetter) Install(name, namespace string, chrt *chart.Chart,
 		}
 	}
 	if i.DryRun {
+		mag.postRenderer = i.PostRenderer
 		return mag.desiredRel, mag.dryRunInstallErr
 	}
+	mag.installedVals = vals
 	return mag.desiredRel, mag.installErr
 }
 
@@ -142,8 +178,16 @@ func (mag *mockActionGetter) Upgrade(name, namespace string, chrt *chart.Chart,
 		}
 	}
 	if i.DryRun {
+		mag.dryRunUpgrades++
+		// Like Helm, refuse to upgrade a pending release, even with a dry-run.
+		if mag.currentRel != nil && mag.currentRel.Info != nil && mag.currentRel.Info.Status.IsPending() {
+			return nil, errors.New("another operation (install/upgrade/rollback) is in progress")
+		}
 		return mag.desiredRel, mag.dryRunUpgradeErr
 	}
+	mag.upgradedVals = vals
+	mag.upgradedLabels = i.Labels
+	mag.upgradedMaxHistory = i.MaxHistory
 	return mag.desiredRel, mag.upgradeErr
 }
 
@@ -152,6 +196,8 @@ func (mag *mockActionGetter) Uninstall(name string, opts ...helmclient.Uninstall
 }
 
 func (mag *mockActionGetter) Reconcile(rel *release.Release) error {
+	mag.reconciled = true
+	mag.reconciledRel = rel
 	return mag.reconcileErr
 }
 
@@ -668,6 +714,616 @@ func TestApply_Upgrade(t *testing.T) {
 	})
 }
 
+func TestApply_HelmHooks(t *testing.T) {
+	migrationHook := func(phase release.HookPhase) *release.Hook {
+		return &release.Hook{
+			Name:     "db-migrate",
+			Kind:     "Job",
+			Events:   []release.HookEvent{release.HookPreUpgrade},
+			LastRun:  release.HookExecution{Phase: phase},
+			Manifest: "apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: db-migrate\n  namespace: test-namespace\n",
+		}
+	}
+	deployedRelease := &release.Release{
+		Info:     &release.Info{Status: release.StatusDeployed},
+		Manifest: "previous",
+	}
+
+	t.Run("reports failed hooks as terminal HookFailed errors", func(t *testing.T) {
+		mockAcg := &mockActionGetter{
+			upgradeErr: errors.New("job failed: BackoffLimitExceeded"),
+			currentRel: deployedRelease,
+			desiredRel: &release.Release{
+				Info:     &release.Info{Status: release.StatusFailed},
+				Manifest: validManifest,
+				Hooks:    []*release.Hook{migrationHook(release.HookPhaseFailed)},
+			},
+		}
+		helmApplier := applier.Helm{
+			ActionClientGetter:            mockAcg,
+			HelmChartProvider:             DummyHelmChartProvider,
+			HelmReleaseToObjectsConverter: mockHelmReleaseToObjectsConverter{},
+		}
+
+		installSucceeded, rolloutStatus, err := helmApplier.Apply(context.TODO(), validFS, testCE, testObjectLabels, testStorageLabels)
+		require.NoError(t, err)
+		require.False(t, installSucceeded)
+		require.Equal(t, `Running the hooks of release "test-ext".`, rolloutStatus)
+
+		t.Log("reports the failed hook once the upgrade is done")
+		require.Eventually(t, func() bool {
+			installSucceeded, _, err = helmApplier.Apply(context.TODO(), validFS, testCE, testObjectLabels, testStorageLabels)
+			return err != nil
+		}, 5*time.Second, 10*time.Millisecond)
+		require.False(t, installSucceeded)
+		require.EqualError(t, errorutil.UnwrapTerminal(err), "pre-upgrade hook Job/db-migrate failed: job failed: BackoffLimitExceeded")
+		reason, ok := errorutil.ExtractTerminalReason(err)
+		require.True(t, ok)
+		require.Equal(t, ocv1.ReasonHookFailed, reason)
+		var hookErr *applier.HookError
+		require.ErrorAs(t, err, &hookErr)
+		require.Equal(t, release.HookPreUpgrade, hookErr.Event)
+	})
+
+	t.Run("does not retry a release that failed on a hook until it changes", func(t *testing.T) {
+		failedRelease := &release.Release{
+			Info:     &release.Info{Status: release.StatusFailed},
+			Manifest: validManifest,
+			Hooks:    []*release.Hook{migrationHook(release.HookPhaseFailed)},
+		}
+		mockAcg := &mockActionGetter{
+			currentRel: failedRelease,
+			desiredRel: &release.Release{Info: &release.Info{Status: release.StatusPendingUpgrade}, Manifest: validManifest},
+		}
+		helmApplier := applier.Helm{
+			ActionClientGetter:            mockAcg,
+			HelmChartProvider:             DummyHelmChartProvider,
+			HelmReleaseToObjectsConverter: mockHelmReleaseToObjectsConverter{},
+		}
+
+		installSucceeded, _, err := helmApplier.Apply(context.TODO(), validFS, testCE, testObjectLabels, testStorageLabels)
+		require.False(t, installSucceeded)
+		require.ErrorContains(t, err, "pre-upgrade hook Job/db-migrate failed; the release is retried once its chart version or configuration changes")
+		reason, _ := errorutil.ExtractTerminalReason(err)
+		require.Equal(t, ocv1.ReasonHookFailed, reason)
+		require.Nil(t, mockAcg.upgradedVals)
+
+		t.Log("retries the release once the manifest changes")
+		mockAcg.desiredRel = &release.Release{Info: &release.Info{Status: release.StatusDeployed}, Manifest: validManifest + "\n"}
+		helmApplier.Manager = &mockManagedContentCacheManager{cache: &mockManagedContentCache{}}
+		installSucceeded, _, err = helmApplier.Apply(context.TODO(), validFS, testCE, testObjectLabels, testStorageLabels)
+		require.NoError(t, err)
+		require.True(t, installSucceeded)
+		require.NotNil(t, mockAcg.upgradedVals)
+	})
+
+	t.Run("pre-authorizes hook resources", func(t *testing.T) {
+		mockAcg := &mockActionGetter{
+			currentRel: deployedRelease,
+			desiredRel: &release.Release{
+				Info:     &release.Info{Status: release.StatusDeployed},
+				Manifest: validManifest,
+				Hooks:    []*release.Hook{migrationHook(release.HookPhaseUnknown)},
+			},
+		}
+		var preAuthorized string
+		helmApplier := applier.Helm{
+			ActionClientGetter: mockAcg,
+			PreAuthorizer: &mockPreAuthorizer{
+				fn: func(_ context.Context, _ user.Info, reader io.Reader, _ ...authorization.UserAuthorizerAttributesFactory) ([]authorization.ScopedPolicyRules, error) {
+					b, err := io.ReadAll(reader)
+					preAuthorized = string(b)
+					return nil, err
+				},
+			},
+			HelmChartProvider:             DummyHelmChartProvider,
+			HelmReleaseToObjectsConverter: mockHelmReleaseToObjectsConverter{},
+			Manager:                       &mockManagedContentCacheManager{cache: &mockManagedContentCache{}},
+		}
+
+		_, _, err := helmApplier.Apply(context.TODO(), validFS, testCE, testObjectLabels, testStorageLabels)
+		require.NoError(t, err)
+		require.Contains(t, preAuthorized, "name: service-a")
+		require.Contains(t, preAuthorized, "name: db-migrate")
+	})
+
+	t.Run("recovers a release left pending by an operation interrupted by a restart", func(t *testing.T) {
+		pendingRelease := &release.Release{
+			Name:     testCE.GetName(),
+			Version:  2,
+			Info:     &release.Info{Status: release.StatusPendingUpgrade},
+			Manifest: validManifest,
+			Hooks:    []*release.Hook{migrationHook(release.HookPhaseRunning)},
+		}
+		releases := storage.Init(driver.NewMemory())
+		require.NoError(t, releases.Create(pendingRelease))
+		mockAcg := &mockActionGetter{
+			currentRel: pendingRelease,
+			desiredRel: &release.Release{Info: &release.Info{Status: release.StatusDeployed}, Manifest: validManifest},
+		}
+		// A new applier has no record of the operation that left the release pending,
+		// just like the applier of a restarted controller.
+		helmApplier := applier.Helm{
+			ActionClientGetter:            mockAcg,
+			ActionConfigGetter:            &mockActionConfigGetter{releases: releases},
+			HelmChartProvider:             DummyHelmChartProvider,
+			HelmReleaseToObjectsConverter: mockHelmReleaseToObjectsConverter{},
+			Manager:                       &mockManagedContentCacheManager{cache: &mockManagedContentCache{}},
+		}
+		require.False(t, helmApplier.HooksRunning(testCE))
+
+		installSucceeded, _, err := helmApplier.Apply(context.TODO(), validFS, testCE, testObjectLabels, testStorageLabels)
+		require.NoError(t, err)
+		require.True(t, installSucceeded)
+		stored, err := releases.Get(testCE.GetName(), 2)
+		require.NoError(t, err)
+		require.Equal(t, release.StatusFailed, stored.Info.Status)
+		require.NotNil(t, mockAcg.upgradedVals, "the interrupted upgrade is retried")
+	})
+
+	t.Run("reports a release left pending when it can't be recovered", func(t *testing.T) {
+		helmApplier := applier.Helm{
+			ActionClientGetter: &mockActionGetter{
+				currentRel: &release.Release{Name: testCE.GetName(), Version: 2, Info: &release.Info{Status: release.StatusPendingInstall}},
+			},
+			HelmChartProvider:             DummyHelmChartProvider,
+			HelmReleaseToObjectsConverter: mockHelmReleaseToObjectsConverter{},
+		}
+		installSucceeded, _, err := helmApplier.Apply(context.TODO(), validFS, testCE, testObjectLabels, testStorageLabels)
+		require.False(t, installSucceeded)
+		require.EqualError(t, err, `revision 2 of release "test-ext" is pending-install, and was left pending by an interrupted operation`)
+	})
+
+	t.Run("does not start operations once the manager stops", func(t *testing.T) {
+		mockAcg := &mockActionGetter{
+			currentRel: deployedRelease,
+			desiredRel: &release.Release{
+				Info:     &release.Info{Status: release.StatusDeployed},
+				Manifest: validManifest,
+				Hooks:    []*release.Hook{migrationHook(release.HookPhaseUnknown)},
+			},
+		}
+		helmApplier := &applier.Helm{
+			ActionClientGetter:            mockAcg,
+			HelmChartProvider:             DummyHelmChartProvider,
+			HelmReleaseToObjectsConverter: mockHelmReleaseToObjectsConverter{},
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		started := make(chan error)
+		go func() { started <- helmApplier.Start(ctx) }()
+		cancel()
+		require.NoError(t, <-started)
+
+		installSucceeded, _, err := helmApplier.Apply(context.TODO(), validFS, testCE, testObjectLabels, testStorageLabels)
+		require.False(t, installSucceeded)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Nil(t, mockAcg.upgradedVals)
+		require.False(t, helmApplier.HooksRunning(testCE))
+	})
+}
+
+func TestApply_HistoryLimit(t *testing.T) {
+	ext := testCE.DeepCopy()
+	ext.Spec.Install = &ocv1.ClusterExtensionInstallConfig{HistoryLimit: 3}
+	mockAcg := &mockActionGetter{
+		currentRel: &release.Release{Info: &release.Info{Status: release.StatusDeployed}, Manifest: "previous"},
+		desiredRel: &release.Release{Info: &release.Info{Status: release.StatusDeployed}, Manifest: validManifest},
+	}
+	helmApplier := applier.Helm{
+		ActionClientGetter:            mockAcg,
+		HelmChartProvider:             DummyHelmChartProvider,
+		HelmReleaseToObjectsConverter: mockHelmReleaseToObjectsConverter{},
+		Manager:                       &mockManagedContentCacheManager{cache: &mockManagedContentCache{}},
+	}
+
+	_, _, err := helmApplier.Apply(context.TODO(), validFS, ext, testObjectLabels, testStorageLabels)
+	require.NoError(t, err)
+	require.Equal(t, 3, mockAcg.upgradedMaxHistory)
+
+	_, _, err = helmApplier.Apply(context.TODO(), validFS, testCE, testObjectLabels, testStorageLabels)
+	require.NoError(t, err)
+	require.Equal(t, 10, mockAcg.upgradedMaxHistory)
+}
+
+func TestApply_Patches(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.ManifestPatches)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.ManifestPatches)))
+	})
+	newExt := func(patch string) *ocv1.ClusterExtension {
+		ext := testCE.DeepCopy()
+		ext.Spec.Install = &ocv1.ClusterExtensionInstallConfig{Patches: []ocv1.ClusterExtensionPatch{{
+			Target: ocv1.PatchTarget{Kind: "Service", Name: "service-b"},
+			Type:   ocv1.PatchTypeStrategicMerge,
+			Patch:  patch,
+		}}}
+		return ext
+	}
+
+	t.Run("patches the rendered objects before they are checked and installed", func(t *testing.T) {
+		mockAcg := &mockActionGetter{
+			getClientErr: driver.ErrReleaseNotFound,
+			desiredRel:   &release.Release{Info: &release.Info{Status: release.StatusDeployed}, Manifest: validManifest},
+		}
+		helmApplier := applier.Helm{
+			ActionClientGetter:            mockAcg,
+			HelmChartProvider:             DummyHelmChartProvider,
+			HelmReleaseToObjectsConverter: mockHelmReleaseToObjectsConverter{},
+			Manager:                       &mockManagedContentCacheManager{cache: &mockManagedContentCache{}},
+		}
+		_, _, err := helmApplier.Apply(context.TODO(), validFS, newExt(`{"metadata": {"labels": {"object": "patched"}}, "spec": {"clusterIP": "10.0.0.1"}}`), testObjectLabels, testStorageLabels)
+		require.NoError(t, err)
+		require.NotNil(t, mockAcg.postRenderer)
+
+		rendered, err := mockAcg.postRenderer.Run(bytes.NewBufferString(validManifest))
+		require.NoError(t, err)
+		objs, err := util.ManifestObjects(rendered, "rendered")
+		require.NoError(t, err)
+		require.Len(t, objs, 2)
+		for _, obj := range objs {
+			u := obj.(*unstructured.Unstructured)
+			clusterIP, _, _ := unstructured.NestedString(u.Object, "spec", "clusterIP")
+			if u.GetName() == "service-b" {
+				require.Equal(t, "10.0.0.1", clusterIP)
+			} else {
+				require.Equal(t, "None", clusterIP)
+			}
+			require.Equal(t, testObjectLabels, u.GetLabels(), "object labels can't be patched")
+		}
+	})
+
+	t.Run("blocks invalid patches", func(t *testing.T) {
+		helmApplier := applier.Helm{
+			ActionClientGetter: &mockActionGetter{},
+			HelmChartProvider:  DummyHelmChartProvider,
+		}
+		_, _, err := helmApplier.Apply(context.TODO(), validFS, newExt(`[not an object]`), testObjectLabels, testStorageLabels)
+		require.ErrorContains(t, err, "invalid content of patch 0")
+		reason, ok := errorutil.ExtractTerminalReason(err)
+		require.True(t, ok)
+		require.Equal(t, ocv1.ReasonInvalidConfiguration, reason)
+	})
+}
+
+func TestApply_InstallNamespace(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.InstallNamespaceCreation)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.InstallNamespaceCreation)))
+	})
+	ext := testCE.DeepCopy()
+	ext.UID = "test-ext-uid"
+	ext.Spec.Install = &ocv1.ClusterExtensionInstallConfig{
+		Namespace: &ocv1.ClusterExtensionInstallNamespace{
+			Labels: map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+		},
+	}
+
+	t.Run("creates the namespace with the client of the ServiceAccount", func(t *testing.T) {
+		s := runtime.NewScheme()
+		require.NoError(t, corev1.AddToScheme(s))
+		saClient := fake.NewClientBuilder().WithScheme(s).Build()
+		var clientForExt *ocv1.ClusterExtension
+		helmApplier := applier.Helm{
+			ActionClientGetter: &mockActionGetter{
+				getClientErr: driver.ErrReleaseNotFound,
+				desiredRel:   &release.Release{Info: &release.Info{Status: release.StatusDeployed}, Manifest: validManifest},
+			},
+			HelmChartProvider:             DummyHelmChartProvider,
+			HelmReleaseToObjectsConverter: mockHelmReleaseToObjectsConverter{},
+			Manager:                       &mockManagedContentCacheManager{cache: &mockManagedContentCache{}},
+			ClientFor: func(_ context.Context, ext *ocv1.ClusterExtension) (client.Client, error) {
+				clientForExt = ext
+				return saClient, nil
+			},
+		}
+
+		_, _, err := helmApplier.Apply(context.TODO(), validFS, ext, testObjectLabels, testStorageLabels)
+		require.NoError(t, err)
+		require.Equal(t, ext, clientForExt)
+
+		ns := &corev1.Namespace{}
+		require.NoError(t, saClient.Get(context.TODO(), client.ObjectKey{Name: ext.Spec.Namespace}, ns))
+		require.Equal(t, "restricted", ns.Labels["pod-security.kubernetes.io/enforce"])
+		require.True(t, metav1.IsControlledBy(ns, ext))
+	})
+
+	t.Run("surfaces errors getting the client of the ServiceAccount", func(t *testing.T) {
+		helmApplier := applier.Helm{
+			ActionClientGetter: &mockActionGetter{},
+			HelmChartProvider:  DummyHelmChartProvider,
+			ClientFor: func(context.Context, *ocv1.ClusterExtension) (client.Client, error) {
+				return nil, errors.New("no token")
+			},
+		}
+
+		_, _, err := helmApplier.Apply(context.TODO(), validFS, ext, testObjectLabels, testStorageLabels)
+		require.ErrorContains(t, err, "no token")
+	})
+}
+
+func TestHelm_Pause(t *testing.T) {
+	t.Run("stops the drift detection of the release objects", func(t *testing.T) {
+		cm := &mockManagedContentCacheManager{}
+		helmApplier := applier.Helm{Manager: cm}
+		require.NoError(t, helmApplier.Pause(context.TODO(), testCE))
+		require.True(t, cm.deleted)
+	})
+
+	t.Run("surfaces errors stopping the drift detection", func(t *testing.T) {
+		helmApplier := applier.Helm{Manager: &mockManagedContentCacheManager{err: errors.New("closing cache")}}
+		require.ErrorContains(t, helmApplier.Pause(context.TODO(), testCE), "closing cache")
+	})
+}
+
+func TestHelm_PreviewUpgrade(t *testing.T) {
+	upgradedManifest := `apiVersion: v1
+kind: Service
+metadata:
+  name: service-a
+  namespace: ns-a
+  annotations:
+    example.com/owner: team-a
+spec:
+  clusterIP: 10.0.0.1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deployment-c
+  namespace: ns-a`
+	revisionAnnotations := map[string]string{labels.BundleNameKey: "test-bundle.v2.0.0", labels.BundleVersionKey: "2.0.0"}
+	newApplier := func(currentRel, desiredRel *release.Release) *applier.Helm {
+		return &applier.Helm{
+			ActionClientGetter:            &mockActionGetter{currentRel: currentRel, desiredRel: desiredRel},
+			HelmChartProvider:             DummyHelmChartProvider,
+			HelmReleaseToObjectsConverter: applier.HelmReleaseToObjectsConverter{},
+		}
+	}
+
+	t.Run("reports added, removed and changed objects", func(t *testing.T) {
+		helmApplier := newApplier(
+			&release.Release{Name: "test-ext", Info: &release.Info{Status: release.StatusDeployed}, Manifest: validManifest},
+			&release.Release{Name: "test-ext", Info: &release.Info{Status: release.StatusDeployed}, Manifest: upgradedManifest},
+		)
+		preview, err := helmApplier.PreviewUpgrade(context.TODO(), validFS, testCE, testObjectLabels, revisionAnnotations)
+		require.NoError(t, err)
+		require.NotNil(t, preview)
+		require.Equal(t, ocv1.BundleMetadata{Name: "test-bundle.v2.0.0", Version: "2.0.0"}, preview.Bundle)
+		require.Len(t, preview.Digest, 16)
+		require.Equal(t, int32(1), preview.Added)
+		require.Equal(t, int32(1), preview.Removed)
+		require.Equal(t, int32(1), preview.Changed)
+		require.Equal(t, []ocv1.UpgradePreviewObject{
+			{Action: ocv1.UpgradePreviewActionChanged, ObjectReference: ocv1.ObjectReference{Kind: "Service", Namespace: "ns-a", Name: "service-a"}, Fields: []string{
+				`metadata.annotations["example.com/owner"]`,
+				"spec.clusterIP",
+			}},
+			{Action: ocv1.UpgradePreviewActionRemoved, ObjectReference: ocv1.ObjectReference{Kind: "Service", Namespace: "ns-b", Name: "service-b"}},
+			{Action: ocv1.UpgradePreviewActionAdded, ObjectReference: ocv1.ObjectReference{Group: "apps", Kind: "Deployment", Namespace: "ns-a", Name: "deployment-c"}},
+		}, preview.Objects)
+
+		again, err := helmApplier.PreviewUpgrade(context.TODO(), validFS, testCE, testObjectLabels, revisionAnnotations)
+		require.NoError(t, err)
+		require.Equal(t, preview.Digest, again.Digest)
+	})
+
+	t.Run("identifies previews by bundle and configuration rather than rendered content", func(t *testing.T) {
+		helmApplier := newApplier(
+			&release.Release{Name: "test-ext", Info: &release.Info{Status: release.StatusDeployed}, Manifest: validManifest},
+			&release.Release{Name: "test-ext", Info: &release.Info{Status: release.StatusDeployed}, Manifest: upgradedManifest},
+		)
+		preview, err := helmApplier.PreviewUpgrade(context.TODO(), validFS, testCE, testObjectLabels, revisionAnnotations)
+		require.NoError(t, err)
+
+		t.Log("rendering random values doesn't change the digest")
+		helmApplier.ActionClientGetter.(*mockActionGetter).desiredRel.Manifest = strings.ReplaceAll(upgradedManifest, "10.0.0.1", "10.0.0.2")
+		rerendered, err := helmApplier.PreviewUpgrade(context.TODO(), validFS, testCE, testObjectLabels, revisionAnnotations)
+		require.NoError(t, err)
+		require.Equal(t, preview.Digest, rerendered.Digest)
+
+		t.Log("changing the configuration changes the digest")
+		configured := testCE.DeepCopy()
+		configured.Spec.Config = &ocv1.ClusterExtensionConfig{
+			ConfigType: ocv1.ClusterExtensionConfigTypeInline,
+			Inline:     &apiextensionsv1.JSON{Raw: []byte(`{"watchNamespace": "ns-a"}`)},
+		}
+		reconfigured, err := helmApplier.PreviewUpgrade(context.TODO(), validFS, configured, testObjectLabels, revisionAnnotations)
+		require.NoError(t, err)
+		require.NotEqual(t, preview.Digest, reconfigured.Digest)
+	})
+
+	t.Run("reuses the dry-run of the preview when applying the upgrade", func(t *testing.T) {
+		helmApplier := newApplier(
+			&release.Release{Name: "test-ext", Info: &release.Info{Status: release.StatusDeployed}, Manifest: validManifest},
+			&release.Release{Name: "test-ext", Info: &release.Info{Status: release.StatusDeployed}, Manifest: upgradedManifest},
+		)
+		helmApplier.Manager = &mockManagedContentCacheManager{cache: &mockManagedContentCache{}}
+		mockAcg := helmApplier.ActionClientGetter.(*mockActionGetter)
+
+		_, err := helmApplier.PreviewUpgrade(context.TODO(), validFS, testCE, testObjectLabels, revisionAnnotations)
+		require.NoError(t, err)
+		_, _, err = helmApplier.Apply(context.TODO(), validFS, testCE, testObjectLabels, testStorageLabels)
+		require.NoError(t, err)
+		require.Equal(t, 1, mockAcg.dryRunUpgrades)
+
+		t.Log("a later Apply runs its own dry-run")
+		_, _, err = helmApplier.Apply(context.TODO(), validFS, testCE, testObjectLabels, testStorageLabels)
+		require.NoError(t, err)
+		require.Equal(t, 2, mockAcg.dryRunUpgrades)
+	})
+
+	t.Run("returns no preview when no object changes", func(t *testing.T) {
+		rel := &release.Release{Name: "test-ext", Info: &release.Info{Status: release.StatusDeployed}, Manifest: validManifest}
+		preview, err := newApplier(rel, rel).PreviewUpgrade(context.TODO(), validFS, testCE, testObjectLabels, revisionAnnotations)
+		require.NoError(t, err)
+		require.Nil(t, preview)
+	})
+
+	t.Run("recovers a release left pending by an interrupted operation before the dry-run", func(t *testing.T) {
+		pendingRelease := &release.Release{Name: "test-ext", Version: 2, Info: &release.Info{Status: release.StatusPendingUpgrade}, Manifest: validManifest}
+		releases := storage.Init(driver.NewMemory())
+		require.NoError(t, releases.Create(pendingRelease))
+		helmApplier := newApplier(pendingRelease, &release.Release{Name: "test-ext", Info: &release.Info{Status: release.StatusDeployed}, Manifest: upgradedManifest})
+		helmApplier.ActionConfigGetter = &mockActionConfigGetter{releases: releases}
+
+		preview, err := helmApplier.PreviewUpgrade(context.TODO(), validFS, testCE, testObjectLabels, revisionAnnotations)
+		require.NoError(t, err)
+		require.NotNil(t, preview)
+		stored, err := releases.Get("test-ext", 2)
+		require.NoError(t, err)
+		require.Equal(t, release.StatusFailed, stored.Info.Status)
+	})
+
+	t.Run("reports a release left pending when it can't be recovered", func(t *testing.T) {
+		helmApplier := newApplier(
+			&release.Release{Name: "test-ext", Version: 2, Info: &release.Info{Status: release.StatusPendingInstall}, Manifest: validManifest},
+			&release.Release{Name: "test-ext", Info: &release.Info{Status: release.StatusDeployed}, Manifest: upgradedManifest},
+		)
+		preview, err := helmApplier.PreviewUpgrade(context.TODO(), validFS, testCE, testObjectLabels, revisionAnnotations)
+		require.EqualError(t, err, `revision 2 of release "test-ext" is pending-install, and was left pending by an interrupted operation`)
+		require.Nil(t, preview)
+		require.Zero(t, helmApplier.ActionClientGetter.(*mockActionGetter).dryRunUpgrades)
+	})
+
+	t.Run("returns no preview when nothing is installed", func(t *testing.T) {
+		helmApplier := newApplier(nil, &release.Release{Name: "test-ext", Manifest: upgradedManifest})
+		helmApplier.ActionClientGetter.(*mockActionGetter).getClientErr = driver.ErrReleaseNotFound
+		preview, err := helmApplier.PreviewUpgrade(context.TODO(), validFS, testCE, testObjectLabels, revisionAnnotations)
+		require.NoError(t, err)
+		require.Nil(t, preview)
+	})
+}
+
+func TestHelm_Rollback(t *testing.T) {
+	revision := func(version int, status release.Status, lbls map[string]string) *release.Release {
+		return &release.Release{
+			Name:     "test-ext",
+			Version:  version,
+			Info:     &release.Info{Status: status},
+			Chart:    &chart.Chart{Metadata: &chart.Metadata{Name: "test-chart", Version: fmt.Sprintf("1.%d.0", version)}},
+			Config:   map[string]interface{}{"replicas": version},
+			Labels:   lbls,
+			Manifest: validManifest,
+		}
+	}
+	rev1 := revision(1, release.StatusSuperseded, map[string]string{labels.BundleVersionKey: "1.1.0"})
+	rev2 := revision(2, release.StatusDeployed, map[string]string{labels.BundleVersionKey: "1.2.0"})
+
+	t.Run("reports the bundle of a revision", func(t *testing.T) {
+		helmApplier := applier.Helm{ActionClientGetter: &mockActionGetter{history: []*release.Release{rev2, rev1}}}
+		bundleLabels, err := helmApplier.RevisionBundle(context.TODO(), testCE, 1)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{labels.BundleVersionKey: "1.1.0"}, bundleLabels)
+	})
+
+	t.Run("blocks rollbacks to revisions that are not retained", func(t *testing.T) {
+		helmApplier := applier.Helm{ActionClientGetter: &mockActionGetter{history: []*release.Release{rev2, rev1}}}
+		succeeded, _, err := helmApplier.Rollback(context.TODO(), testCE, 5, testObjectLabels, nil)
+		require.False(t, succeeded)
+		require.ErrorContains(t, err, `revision 5 of Helm release "test-ext" not found`)
+		reason, ok := errorutil.ExtractTerminalReason(err)
+		require.True(t, ok)
+		require.Equal(t, ocv1.ReasonBlocked, reason)
+	})
+
+	t.Run("upgrades to the chart and values of the revision", func(t *testing.T) {
+		mockAcg := &mockActionGetter{
+			history:    []*release.Release{rev2, rev1},
+			currentRel: rev2,
+			desiredRel: revision(3, release.StatusDeployed, nil),
+		}
+		helmApplier := applier.Helm{
+			ActionClientGetter:            mockAcg,
+			HelmReleaseToObjectsConverter: mockHelmReleaseToObjectsConverter{},
+			Manager:                       &mockManagedContentCacheManager{cache: &mockManagedContentCache{}},
+		}
+		succeeded, _, err := helmApplier.Rollback(context.TODO(), testCE, 1, testObjectLabels, nil)
+		require.NoError(t, err)
+		require.True(t, succeeded)
+		require.Equal(t, map[string]interface{}{"replicas": 1}, mockAcg.upgradedVals)
+		require.Equal(t, map[string]string{labels.BundleVersionKey: "1.1.0", labels.RollbackOfRevisionKey: "1"}, mockAcg.upgradedLabels)
+		require.Equal(t, 10, mockAcg.upgradedMaxHistory)
+	})
+
+	t.Run("runs the upgrade preflights against the revision", func(t *testing.T) {
+		mockAcg := &mockActionGetter{
+			history:    []*release.Release{rev2, rev1},
+			currentRel: rev2,
+			desiredRel: revision(3, release.StatusDeployed, nil),
+		}
+		helmApplier := applier.Helm{
+			ActionClientGetter:            mockAcg,
+			HelmReleaseToObjectsConverter: mockHelmReleaseToObjectsConverter{},
+			Preflights:                    []applier.Preflight{&mockPreflight{upgradeErr: errors.New("unsafe CRD change")}},
+		}
+		succeeded, _, err := helmApplier.Rollback(context.TODO(), testCE, 1, testObjectLabels, nil)
+		require.False(t, succeeded)
+		require.EqualError(t, err, "unsafe CRD change")
+		require.Nil(t, mockAcg.upgradedVals)
+	})
+
+	t.Run("runs the hooks of the revision in the background", func(t *testing.T) {
+		withHooks := revision(3, release.StatusDeployed, nil)
+		withHooks.Hooks = []*release.Hook{{Name: "db-migrate", Kind: "Job", Events: []release.HookEvent{release.HookPreUpgrade}}}
+		helmApplier := applier.Helm{
+			ActionClientGetter:            &mockActionGetter{history: []*release.Release{rev2, rev1}, currentRel: rev2, desiredRel: withHooks},
+			HelmReleaseToObjectsConverter: mockHelmReleaseToObjectsConverter{},
+		}
+		succeeded, rolloutStatus, err := helmApplier.Rollback(context.TODO(), testCE, 1, testObjectLabels, nil)
+		require.NoError(t, err)
+		require.False(t, succeeded)
+		require.Equal(t, `Running the hooks of release "test-ext".`, rolloutStatus)
+	})
+
+	t.Run("records storage labels instead of earlier rollbacks", func(t *testing.T) {
+		earlierRollback := revision(2, release.StatusDeployed, map[string]string{
+			labels.BundleVersionKey:      "1.1.0",
+			labels.RollbackOfRevisionKey: "1",
+			labels.FailedRevisionKey:     "1",
+			labels.FailedBundleKey:       "test-chart.v1.0.0",
+		})
+		mockAcg := &mockActionGetter{
+			history:    []*release.Release{revision(3, release.StatusFailed, nil), earlierRollback, rev1},
+			currentRel: revision(3, release.StatusFailed, nil),
+			desiredRel: revision(4, release.StatusDeployed, nil),
+		}
+		helmApplier := applier.Helm{
+			ActionClientGetter:            mockAcg,
+			HelmReleaseToObjectsConverter: mockHelmReleaseToObjectsConverter{},
+			Manager:                       &mockManagedContentCacheManager{cache: &mockManagedContentCache{}},
+		}
+		_, _, err := helmApplier.Rollback(context.TODO(), testCE, 2, testObjectLabels, map[string]string{
+			labels.FailedRevisionKey: "3",
+			labels.FailedBundleKey:   "test-chart.v1.3.0",
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			labels.BundleVersionKey:      "1.1.0",
+			labels.RollbackOfRevisionKey: "2",
+			labels.FailedRevisionKey:     "3",
+			labels.FailedBundleKey:       "test-chart.v1.3.0",
+		}, mockAcg.upgradedLabels)
+	})
+
+	t.Run("reconciles a completed rollback", func(t *testing.T) {
+		rollback := revision(3, release.StatusDeployed, map[string]string{labels.BundleVersionKey: "1.1.0", labels.RollbackOfRevisionKey: "1"})
+		mockAcg := &mockActionGetter{
+			history:    []*release.Release{rollback, rev2, rev1},
+			currentRel: rollback,
+		}
+		helmApplier := applier.Helm{
+			ActionClientGetter:            mockAcg,
+			HelmReleaseToObjectsConverter: mockHelmReleaseToObjectsConverter{},
+			Manager:                       &mockManagedContentCacheManager{cache: &mockManagedContentCache{}},
+		}
+		succeeded, _, err := helmApplier.Rollback(context.TODO(), testCE, 1, testObjectLabels, nil)
+		require.NoError(t, err)
+		require.True(t, succeeded)
+		require.True(t, mockAcg.reconciled)
+		require.Nil(t, mockAcg.upgradedVals)
+	})
+}
+
 func TestApply_RegistryV1ToChartConverterIntegration(t *testing.T) {
 	t.Run("generates bundle resources in AllNamespaces install mode", func(t *testing.T) {
 		helmApplier := applier.Helm{
@@ -730,3 +1386,101 @@ var DummyHelmChartProvider = &FakeHelmChartProvider{
 		return &chart.Chart{}, nil
 	},
 }
+
+func TestApply_HelmChartValues(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.HelmChartSupport)))
+	defer func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.HelmChartSupport)))
+	}()
+
+	chartDir := t.TempDir()
+	_, err := chartutil.Save(&chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "test-chart", Version: "1.0.0"},
+		Templates: []*chart.File{
+			{Name: "templates/configmap.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\ndata:\n  replicas: \"{{ .Values.replicas }}\"\n")},
+		},
+		Raw: []*chart.File{
+			{Name: chartutil.ValuesfileName, Data: []byte("replicas: 1\nimage: example.com/test:v1\n")},
+		},
+		Schema: []byte(`{
+  "type": "object",
+  "properties": {
+    "replicas": {"type": "integer", "minimum": 1},
+    "image": {"type": "string"}
+  },
+  "required": ["replicas", "image"],
+  "additionalProperties": false
+}`),
+	}, chartDir)
+	require.NoError(t, err)
+	chartFS := os.DirFS(chartDir)
+
+	extWithConfig := func(inline string) *ocv1.ClusterExtension {
+		ext := testCE.DeepCopy()
+		ext.Spec.Config = &ocv1.ClusterExtensionConfig{
+			ConfigType: ocv1.ClusterExtensionConfigTypeInline,
+			Inline:     &apiextensionsv1.JSON{Raw: []byte(inline)},
+		}
+		return ext
+	}
+	newApplier := func(acg *mockActionGetter) applier.Helm {
+		return applier.Helm{
+			ActionClientGetter:            acg,
+			HelmChartProvider:             DummyHelmChartProvider,
+			HelmReleaseToObjectsConverter: mockHelmReleaseToObjectsConverter{},
+			Manager: &mockManagedContentCacheManager{
+				cache: &mockManagedContentCache{},
+			},
+		}
+	}
+	deployedRel := &release.Release{
+		Info:     &release.Info{Status: release.StatusDeployed},
+		Manifest: validManifest,
+	}
+
+	t.Run("installs with inline configuration as values", func(t *testing.T) {
+		acg := &mockActionGetter{getClientErr: driver.ErrReleaseNotFound, desiredRel: deployedRel}
+		helmApplier := newApplier(acg)
+
+		installSucceeded, _, err := helmApplier.Apply(context.TODO(), chartFS, extWithConfig(`{"replicas": 3}`), testObjectLabels, testStorageLabels)
+		require.NoError(t, err)
+		require.True(t, installSucceeded)
+		require.Equal(t, map[string]interface{}{"replicas": float64(3)}, acg.installedVals)
+	})
+
+	t.Run("upgrades with inline configuration as values", func(t *testing.T) {
+		acg := &mockActionGetter{
+			currentRel: &release.Release{Info: &release.Info{Status: release.StatusDeployed}, Manifest: "current"},
+			desiredRel: deployedRel,
+		}
+		helmApplier := newApplier(acg)
+
+		installSucceeded, _, err := helmApplier.Apply(context.TODO(), chartFS, extWithConfig(`{"image": "example.com/test:v2"}`), testObjectLabels, testStorageLabels)
+		require.NoError(t, err)
+		require.True(t, installSucceeded)
+		require.Equal(t, map[string]interface{}{"image": "example.com/test:v2"}, acg.upgradedVals)
+	})
+
+	t.Run("installs with empty values without configuration", func(t *testing.T) {
+		acg := &mockActionGetter{getClientErr: driver.ErrReleaseNotFound, desiredRel: deployedRel}
+		helmApplier := newApplier(acg)
+
+		_, _, err := helmApplier.Apply(context.TODO(), chartFS, testCE, testObjectLabels, testStorageLabels)
+		require.NoError(t, err)
+		require.Empty(t, acg.installedVals)
+	})
+
+	t.Run("rejects configuration that does not match values.schema.json", func(t *testing.T) {
+		acg := &mockActionGetter{getClientErr: driver.ErrReleaseNotFound, desiredRel: deployedRel}
+		helmApplier := newApplier(acg)
+
+		for _, inline := range []string{`{"replicas": 0}`, `{"unknown": true}`, `{"image": 5}`} {
+			_, _, err := helmApplier.Apply(context.TODO(), chartFS, extWithConfig(inline), testObjectLabels, testStorageLabels)
+			require.ErrorContains(t, err, "invalid ClusterExtension configuration")
+			reason, ok := errorutil.ExtractTerminalReason(err)
+			require.True(t, ok)
+			require.Equal(t, ocv1.ReasonInvalidConfiguration, reason)
+			require.Nil(t, acg.installedVals)
+		}
+	})
+}
@@ -0,0 +1,263 @@
+package applier
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
+	"github.com/operator-framework/operator-controller/internal/shared/util/cache"
+	errorutil "github.com/operator-framework/operator-controller/internal/shared/util/error"
+)
+
+// olmv0OwnerLabels are set by OLM v0 on the cluster-scoped objects it creates for a CSV.
+// OLM v0 garbage-collects objects carrying them when the CSV is deleted.
+var olmv0OwnerLabels = []string{"olm.owner", "olm.owner.kind", "olm.owner.namespace"}
+
+// OLMv0Migrator migrates operators installed by OLM v0 into ClusterExtensionRevision storage,
+// for ClusterExtensions carrying the ocv1.AnnotationMigrateOLMv0Subscription annotation.
+//
+// The objects installed for the CSV of the Subscription, including the Deployments created by the
+// CSV, are recorded in the first ClusterExtensionRevision of the ClusterExtension as they are found
+// on the cluster, so that nothing is deleted or restarted by the migration itself. The revision is
+// paused until the Subscription and the CSV are removed, and only adopts the objects then, as they
+// are still managed by OLM v0 until that point.
+type OLMv0Migrator struct {
+	// ClientFor returns the client used to read the OLM v0 objects and the objects they
+	// installed, typically using the ServiceAccount of the ClusterExtension.
+	ClientFor         func(ctx context.Context, ext *ocv1.ClusterExtension) (client.Client, error)
+	RevisionGenerator *SimpleRevisionGenerator
+	Client            olmv0MigratorClient
+	Scheme            *runtime.Scheme
+}
+
+type olmv0MigratorClient interface {
+	boxcutterStorageMigratorClient
+	Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error
+}
+
+// olmv0MigrationMessage is the message of the Succeeded condition of revisions migrated from OLM v0.
+const olmv0MigrationMessage = "Revision succeeded - migrated from OLM v0 Subscription"
+
+// Migrate creates the first ClusterExtensionRevision of ext from the OLM v0 Subscription named by
+// its ocv1.AnnotationMigrateOLMv0Subscription annotation. It does nothing when the annotation is
+// missing, and is skipped if revisions already exist. Until the Subscription and its CSV are removed,
+// it returns an error reporting that the migration waits for their removal.
+func (m *OLMv0Migrator) Migrate(ctx context.Context, ext *ocv1.ClusterExtension, objectLabels map[string]string) error {
+	subName, ok := ext.GetAnnotations()[ocv1.AnnotationMigrateOLMv0Subscription]
+	if !ok {
+		return nil
+	}
+
+	existingRevisionList := ocv1.ClusterExtensionRevisionList{}
+	if err := m.Client.List(ctx, &existingRevisionList, client.MatchingLabels{
+		labels.OwnerNameKey: ext.Name,
+	}); err != nil {
+		return fmt.Errorf("listing ClusterExtensionRevisions before attempting migration: %w", err)
+	}
+	if len(existingRevisionList.Items) != 0 {
+		for i := range existingRevisionList.Items {
+			rev := &existingRevisionList.Items[i]
+			// Handles the case where revision creation succeeded but status update failed.
+			if rev.Spec.Revision == 1 && rev.Labels[labels.MigratedFromOLMv0Key] == "true" {
+				if err := ensureMigratedRevisionSucceeded(ctx, m.Client, rev, labels.MigratedFromOLMv0Key, olmv0MigrationMessage); err != nil {
+					return err
+				}
+				return m.resumeWhenRemoved(ctx, ext, rev)
+			}
+		}
+		return nil
+	}
+
+	c, err := m.ClientFor(ctx, ext)
+	if err != nil {
+		return err
+	}
+
+	sub := &v1alpha1.Subscription{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ext.Spec.Namespace, Name: subName}, sub); err != nil {
+		if apierrors.IsNotFound(err) {
+			return errorutil.NewTerminalError(ocv1.ReasonBlocked, fmt.Errorf("cannot migrate Subscription %q: not found in namespace %q", subName, ext.Spec.Namespace))
+		}
+		return fmt.Errorf("getting Subscription %q: %w", subName, err)
+	}
+	if ext.Spec.Source.SourceType != ocv1.SourceTypeCatalog || ext.Spec.Source.Catalog == nil ||
+		ext.Spec.Source.Catalog.PackageName != sub.Spec.Package {
+		return errorutil.NewTerminalError(ocv1.ReasonBlocked, fmt.Errorf("cannot migrate Subscription %q: the ClusterExtension must install package %q from a catalog", subName, sub.Spec.Package))
+	}
+
+	// Only migrate a working installation; OLM v0 may still be installing or upgrading the operator.
+	csvName := sub.Status.InstalledCSV
+	if csvName == "" {
+		return fmt.Errorf("waiting for Subscription %q to report its installed CSV", subName)
+	}
+	csv := &v1alpha1.ClusterServiceVersion{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ext.Spec.Namespace, Name: csvName}, csv); err != nil {
+		return fmt.Errorf("getting CSV %q of Subscription %q: %w", csvName, subName, err)
+	}
+	if csv.Status.Phase != v1alpha1.CSVPhaseSucceeded {
+		return fmt.Errorf("waiting for CSV %q to succeed, current phase %q", csvName, csv.Status.Phase)
+	}
+
+	var installPlan *v1alpha1.InstallPlan
+	if ref := sub.Status.InstallPlanRef; ref != nil {
+		installPlan = &v1alpha1.InstallPlan{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, installPlan); err != nil {
+			return fmt.Errorf("getting InstallPlan %q of Subscription %q: %w", ref.Name, subName, err)
+		}
+	}
+
+	objs, err := m.installedObjects(ctx, c, ext, csv, installPlan, objectLabels)
+	if err != nil {
+		return err
+	}
+
+	revisionAnnotations := map[string]string{
+		labels.BundleNameKey:    csv.Name,
+		labels.PackageNameKey:   sub.Spec.Package,
+		labels.BundleVersionKey: csv.Spec.Version.String(),
+	}
+	if installPlan != nil {
+		for _, lookup := range installPlan.Status.BundleLookups {
+			if lookup.Identifier == csv.Name {
+				revisionAnnotations[labels.BundleReferenceKey] = lookup.Path
+			}
+		}
+	}
+
+	rev, err := m.RevisionGenerator.buildClusterExtensionRevision(objs, ext, revisionAnnotations)
+	if err != nil {
+		return err
+	}
+	rev.Name = fmt.Sprintf("%s-1", ext.Name)
+	rev.Spec.Revision = 1
+	// The objects are adopted with CollisionProtectionNone, which is only safe once OLM v0
+	// stopped managing them: the revision is resumed when the Subscription and the CSV are removed.
+	rev.Spec.Paused = true
+	rev.Annotations[labels.OLMv0SubscriptionKey] = subName
+
+	// Like revisions migrated from Helm, the revision represents a working installation
+	// and is marked as succeeded right away.
+	if err := createMigratedRevision(ctx, m.Client, m.Scheme, ext, rev, labels.MigratedFromOLMv0Key, olmv0MigrationMessage); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("migrated OLM v0 Subscription", "subscription", subName, "csv", csv.Name, "objects", len(objs))
+	return m.resumeWhenRemoved(ctx, ext, rev)
+}
+
+// resumeWhenRemoved resumes rev, a revision migrated from OLM v0 that is paused until the Subscription
+// and the CSV it was migrated from are removed, so that it adopts their objects. It returns an error
+// while they exist.
+func (m *OLMv0Migrator) resumeWhenRemoved(ctx context.Context, ext *ocv1.ClusterExtension, rev *ocv1.ClusterExtensionRevision) error {
+	subName, ok := rev.Annotations[labels.OLMv0SubscriptionKey]
+	if !ok {
+		return nil
+	}
+	csvName := rev.Annotations[labels.BundleNameKey]
+
+	c, err := m.ClientFor(ctx, ext)
+	if err != nil {
+		return err
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ext.Spec.Namespace, Name: subName}, &v1alpha1.Subscription{}); err == nil {
+		return fmt.Errorf("waiting for Subscription %q to be deleted before adopting the objects of CSV %q", subName, csvName)
+	} else if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("getting Subscription %q: %w", subName, err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ext.Spec.Namespace, Name: csvName}, &v1alpha1.ClusterServiceVersion{}); err == nil {
+		return fmt.Errorf("waiting for CSV %q to be deleted with --cascade=orphan before adopting its objects", csvName)
+	} else if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("getting CSV %q: %w", csvName, err)
+	}
+
+	patch := client.MergeFrom(rev.DeepCopy())
+	rev.Spec.Paused = false
+	delete(rev.Annotations, labels.OLMv0SubscriptionKey)
+	if err := m.Client.Patch(ctx, rev, patch); err != nil {
+		return fmt.Errorf("resuming migrated revision: %w", err)
+	}
+	log.FromContext(ctx).Info("adopting the objects of the migrated OLM v0 Subscription", "subscription", subName, "csv", csvName)
+	return nil
+}
+
+// installedObjects returns the objects installed for csv: the objects created by the steps of
+// installPlan resolving the CSV, and the Deployments of its install strategy. Objects that no
+// longer exist are skipped.
+func (m *OLMv0Migrator) installedObjects(
+	ctx context.Context, c client.Client, ext *ocv1.ClusterExtension,
+	csv *v1alpha1.ClusterServiceVersion, installPlan *v1alpha1.InstallPlan,
+	objectLabels map[string]string,
+) ([]ocv1.ClusterExtensionRevisionObject, error) {
+	var refs []unstructured.Unstructured
+	addRef := func(gvk schema.GroupVersionKind, name string) {
+		if slices.ContainsFunc(refs, func(u unstructured.Unstructured) bool {
+			return u.GroupVersionKind().GroupKind() == gvk.GroupKind() && u.GetName() == name
+		}) {
+			return
+		}
+		u := unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		u.SetName(name)
+		refs = append(refs, u)
+	}
+	if installPlan != nil {
+		for _, step := range installPlan.Status.Plan {
+			if step == nil || step.Resolving != csv.Name || step.Resource.Kind == v1alpha1.ClusterServiceVersionKind {
+				continue
+			}
+			addRef(schema.GroupVersionKind{Group: step.Resource.Group, Version: step.Resource.Version, Kind: step.Resource.Kind}, step.Resource.Name)
+		}
+	}
+	for _, dep := range csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+		addRef(appsv1.SchemeGroupVersion.WithKind("Deployment"), dep.Name)
+	}
+
+	objs := make([]ocv1.ClusterExtensionRevisionObject, 0, len(refs))
+	for i := range refs {
+		obj := &refs[i]
+		namespaced, err := c.IsObjectNamespaced(obj)
+		if err != nil {
+			return nil, fmt.Errorf("determining scope of %s %q: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		if namespaced {
+			obj.SetNamespace(ext.Spec.Namespace)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("getting %s %q: %w", obj.GetKind(), obj.GetName(), err)
+		}
+
+		// Memory optimization: strip large annotations
+		if err := cache.ApplyStripAnnotationsTransform(obj); err != nil {
+			return nil, err
+		}
+		sanitizedUnstructured(ctx, obj)
+		lbls := obj.GetLabels()
+		for _, key := range olmv0OwnerLabels {
+			delete(lbls, key)
+		}
+		if lbls = mergeLabelMaps(lbls, objectLabels); len(lbls) > 0 {
+			obj.SetLabels(lbls)
+		}
+
+		objs = append(objs, ocv1.ClusterExtensionRevisionObject{
+			Object: *obj,
+			// The objects are adopted once the CSV is removed, see resumeWhenRemoved.
+			CollisionProtection: ocv1.CollisionProtectionNone,
+		})
+	}
+	return objs, nil
+}
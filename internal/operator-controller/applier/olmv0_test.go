@@ -0,0 +1,325 @@
+package applier_test
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+
+	bsemver "github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8scheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/operator-framework/api/pkg/lib/version"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/applier"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
+	errorutil "github.com/operator-framework/operator-controller/internal/shared/util/error"
+)
+
+func newOLMv0TestClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	testScheme := runtime.NewScheme()
+	require.NoError(t, k8scheme.AddToScheme(testScheme))
+	require.NoError(t, apiextensionsv1.AddToScheme(testScheme))
+	require.NoError(t, v1alpha1.AddToScheme(testScheme))
+	require.NoError(t, ocv1.AddToScheme(testScheme))
+
+	mapper := apimeta.NewDefaultRESTMapper(nil)
+	for _, gvk := range []schema.GroupVersionKind{
+		appsv1.SchemeGroupVersion.WithKind("Deployment"),
+		corev1.SchemeGroupVersion.WithKind("ServiceAccount"),
+		rbacv1.SchemeGroupVersion.WithKind("Role"),
+		rbacv1.SchemeGroupVersion.WithKind("RoleBinding"),
+	} {
+		mapper.Add(gvk, apimeta.RESTScopeNamespace)
+	}
+	for _, gvk := range []schema.GroupVersionKind{
+		apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"),
+		rbacv1.SchemeGroupVersion.WithKind("ClusterRole"),
+	} {
+		mapper.Add(gvk, apimeta.RESTScopeRoot)
+	}
+
+	return fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithRESTMapper(mapper).
+		WithObjects(objs...).
+		WithStatusSubresource(&ocv1.ClusterExtensionRevision{}).
+		Build()
+}
+
+func newOLMv0Migrator(c client.Client) *applier.OLMv0Migrator {
+	return &applier.OLMv0Migrator{
+		ClientFor: func(context.Context, *ocv1.ClusterExtension) (client.Client, error) {
+			return c, nil
+		},
+		RevisionGenerator: &applier.SimpleRevisionGenerator{Scheme: c.Scheme()},
+		Client:            c,
+		Scheme:            c.Scheme(),
+	}
+}
+
+func olmv0TestObjects() (*v1alpha1.Subscription, *v1alpha1.ClusterServiceVersion, *v1alpha1.InstallPlan) {
+	sub := &v1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sub", Namespace: "test-namespace"},
+		Spec: &v1alpha1.SubscriptionSpec{
+			CatalogSource:          "test-catalog",
+			CatalogSourceNamespace: "olm",
+			Package:                "test-package",
+			Channel:                "stable",
+		},
+		Status: v1alpha1.SubscriptionStatus{
+			InstalledCSV:   "test-operator.v1.2.3",
+			InstallPlanRef: &corev1.ObjectReference{Name: "install-abcde", Namespace: "test-namespace"},
+		},
+	}
+	csv := &v1alpha1.ClusterServiceVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-operator.v1.2.3", Namespace: "test-namespace"},
+		Spec: v1alpha1.ClusterServiceVersionSpec{
+			Version: version.OperatorVersion{Version: bsemver.MustParse("1.2.3")},
+			InstallStrategy: v1alpha1.NamedInstallStrategy{
+				StrategyName: v1alpha1.InstallStrategyNameDeployment,
+				StrategySpec: v1alpha1.StrategyDetailsDeployment{
+					DeploymentSpecs: []v1alpha1.StrategyDeploymentSpec{{Name: "test-operator"}},
+				},
+			},
+		},
+		Status: v1alpha1.ClusterServiceVersionStatus{Phase: v1alpha1.CSVPhaseSucceeded},
+	}
+	step := func(resolving, group, kind, name string) *v1alpha1.Step {
+		return &v1alpha1.Step{
+			Resolving: resolving,
+			Resource:  v1alpha1.StepResource{Group: group, Version: "v1", Kind: kind, Name: name},
+		}
+	}
+	ip := &v1alpha1.InstallPlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "install-abcde", Namespace: "test-namespace"},
+		Status: v1alpha1.InstallPlanStatus{
+			Plan: []*v1alpha1.Step{
+				step("test-operator.v1.2.3", "operators.coreos.com", v1alpha1.ClusterServiceVersionKind, "test-operator.v1.2.3"),
+				step("test-operator.v1.2.3", "apiextensions.k8s.io", "CustomResourceDefinition", "widgets.example.com"),
+				step("test-operator.v1.2.3", "", "ServiceAccount", "test-operator"),
+				step("test-operator.v1.2.3", "rbac.authorization.k8s.io", "ClusterRole", "test-operator.v1.2.3-abcde"),
+				step("test-operator.v1.2.3", "rbac.authorization.k8s.io", "Role", "test-operator.v1.2.3-removed"),
+				step("other-operator.v0.1.0", "", "ServiceAccount", "other-operator"),
+			},
+			BundleLookups: []v1alpha1.BundleLookup{
+				{Identifier: "test-operator.v1.2.3", Path: "quay.io/example/test-operator-bundle:v1.2.3"},
+			},
+		},
+	}
+	return sub, csv, ip
+}
+
+func olmv0TestExtension() *ocv1.ClusterExtension {
+	return &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-ext",
+			UID:         "test-uid",
+			Annotations: map[string]string{ocv1.AnnotationMigrateOLMv0Subscription: "test-sub"},
+		},
+		Spec: ocv1.ClusterExtensionSpec{
+			Namespace:      "test-namespace",
+			ServiceAccount: ocv1.ServiceAccountReference{Name: "test-sa"},
+			Source: ocv1.SourceConfig{
+				SourceType: ocv1.SourceTypeCatalog,
+				Catalog:    &ocv1.CatalogFilter{PackageName: "test-package"},
+			},
+		},
+	}
+}
+
+func TestOLMv0Migrator(t *testing.T) {
+	objectLabels := map[string]string{
+		labels.OwnerKindKey: ocv1.ClusterExtensionKind,
+		labels.OwnerNameKey: "test-ext",
+	}
+
+	t.Run("migrates the objects installed for the CSV", func(t *testing.T) {
+		sub, csv, ip := olmv0TestObjects()
+		c := newOLMv0TestClient(t, sub, csv, ip,
+			&apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"}},
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "test-operator", Namespace: "test-namespace"}},
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "other-operator", Namespace: "test-namespace"}},
+			&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{
+				Name: "test-operator.v1.2.3-abcde",
+				Labels: map[string]string{
+					"olm.owner":           "test-operator.v1.2.3",
+					"olm.owner.kind":      v1alpha1.ClusterServiceVersionKind,
+					"olm.owner.namespace": "test-namespace",
+					"app":                 "test-operator",
+				},
+			}},
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-operator",
+					Namespace: "test-namespace",
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: v1alpha1.SchemeGroupVersion.String(),
+						Kind:       v1alpha1.ClusterServiceVersionKind,
+						Name:       "test-operator.v1.2.3",
+						UID:        "csv-uid",
+					}},
+				},
+				Status: appsv1.DeploymentStatus{ReadyReplicas: 1},
+			},
+		)
+		ext := olmv0TestExtension()
+
+		err := newOLMv0Migrator(c).Migrate(t.Context(), ext, objectLabels)
+		require.EqualError(t, err, `waiting for Subscription "test-sub" to be deleted before adopting the objects of CSV "test-operator.v1.2.3"`)
+
+		rev := &ocv1.ClusterExtensionRevision{}
+		require.NoError(t, c.Get(t.Context(), client.ObjectKey{Name: "test-ext-1"}, rev))
+		assert.Equal(t, int64(1), rev.Spec.Revision)
+		assert.True(t, rev.Spec.Paused, "the objects are not adopted while OLM v0 manages them")
+		assert.Equal(t, "true", rev.Labels[labels.MigratedFromOLMv0Key])
+		assert.Equal(t, "test-ext", rev.Labels[labels.OwnerNameKey])
+		assert.Equal(t, map[string]string{
+			labels.BundleNameKey:              "test-operator.v1.2.3",
+			labels.PackageNameKey:             "test-package",
+			labels.BundleVersionKey:           "1.2.3",
+			labels.BundleReferenceKey:         "quay.io/example/test-operator-bundle:v1.2.3",
+			labels.ServiceAccountNameKey:      "test-sa",
+			labels.ServiceAccountNamespaceKey: "test-namespace",
+			labels.OLMv0SubscriptionKey:       "test-sub",
+		}, rev.Annotations)
+		require.Len(t, rev.OwnerReferences, 1)
+		assert.Equal(t, "test-ext", rev.OwnerReferences[0].Name)
+
+		succeeded := apimeta.FindStatusCondition(rev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeSucceeded)
+		require.NotNil(t, succeeded)
+		assert.Equal(t, metav1.ConditionTrue, succeeded.Status)
+		assert.Equal(t, "Revision succeeded - migrated from OLM v0 Subscription", succeeded.Message)
+
+		objs := map[string]ocv1.ClusterExtensionRevisionObject{}
+		for _, phase := range rev.Spec.Phases {
+			for _, obj := range phase.Objects {
+				objs[obj.Object.GetKind()+"/"+obj.Object.GetNamespace()+"/"+obj.Object.GetName()] = obj
+			}
+		}
+		require.ElementsMatch(t, []string{
+			"CustomResourceDefinition//widgets.example.com",
+			"ServiceAccount/test-namespace/test-operator",
+			"ClusterRole//test-operator.v1.2.3-abcde",
+			"Deployment/test-namespace/test-operator",
+		}, slices.Collect(maps.Keys(objs)), "objects of other CSVs, the CSV itself and removed objects are not migrated")
+		for key, obj := range objs {
+			assert.Equal(t, ocv1.CollisionProtectionNone, obj.CollisionProtection, key)
+			assert.Equal(t, "test-ext", obj.Object.GetLabels()[labels.OwnerNameKey], key)
+		}
+
+		clusterRole := objs["ClusterRole//test-operator.v1.2.3-abcde"].Object
+		assert.Equal(t, map[string]string{
+			"app":               "test-operator",
+			labels.OwnerKindKey: ocv1.ClusterExtensionKind,
+			labels.OwnerNameKey: "test-ext",
+		}, clusterRole.GetLabels(), "the OLM v0 owner labels are removed")
+
+		deployment := objs["Deployment/test-namespace/test-operator"].Object
+		assert.Empty(t, deployment.GetOwnerReferences())
+		assert.Empty(t, deployment.GetResourceVersion())
+		assert.NotContains(t, deployment.Object, "status")
+
+		// The revision is resumed once the Subscription and the CSV are removed.
+		require.NoError(t, c.Delete(t.Context(), sub))
+		err = newOLMv0Migrator(c).Migrate(t.Context(), ext, objectLabels)
+		require.EqualError(t, err, `waiting for CSV "test-operator.v1.2.3" to be deleted with --cascade=orphan before adopting its objects`)
+		require.NoError(t, c.Delete(t.Context(), csv))
+		require.NoError(t, newOLMv0Migrator(c).Migrate(t.Context(), ext, objectLabels))
+
+		require.NoError(t, c.Get(t.Context(), client.ObjectKeyFromObject(rev), rev))
+		assert.False(t, rev.Spec.Paused)
+		assert.NotContains(t, rev.Annotations, labels.OLMv0SubscriptionKey)
+
+		// A second migration leaves the revision alone.
+		require.NoError(t, newOLMv0Migrator(c).Migrate(t.Context(), ext, objectLabels))
+		revs := &ocv1.ClusterExtensionRevisionList{}
+		require.NoError(t, c.List(t.Context(), revs))
+		assert.Len(t, revs.Items, 1)
+		assert.False(t, revs.Items[0].Spec.Paused)
+	})
+
+	t.Run("sets status when the migrated revision exists but status is missing", func(t *testing.T) {
+		rev := &ocv1.ClusterExtensionRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-ext-1",
+				Labels: map[string]string{
+					labels.OwnerNameKey:         "test-ext",
+					labels.MigratedFromOLMv0Key: "true",
+				},
+			},
+			Spec: ocv1.ClusterExtensionRevisionSpec{Revision: 1},
+		}
+		c := newOLMv0TestClient(t, rev)
+
+		require.NoError(t, newOLMv0Migrator(c).Migrate(t.Context(), olmv0TestExtension(), objectLabels))
+
+		require.NoError(t, c.Get(t.Context(), client.ObjectKeyFromObject(rev), rev))
+		assert.True(t, apimeta.IsStatusConditionTrue(rev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeSucceeded))
+	})
+
+	t.Run("does nothing without the annotation", func(t *testing.T) {
+		c := newOLMv0TestClient(t)
+		m := newOLMv0Migrator(c)
+		m.ClientFor = func(context.Context, *ocv1.ClusterExtension) (client.Client, error) {
+			t.Fatal("the OLM v0 objects must not be read")
+			return nil, nil
+		}
+		ext := olmv0TestExtension()
+		ext.Annotations = nil
+
+		require.NoError(t, m.Migrate(t.Context(), ext, objectLabels))
+	})
+
+	t.Run("blocks when the Subscription is missing", func(t *testing.T) {
+		c := newOLMv0TestClient(t)
+
+		err := newOLMv0Migrator(c).Migrate(t.Context(), olmv0TestExtension(), objectLabels)
+		require.Error(t, err)
+		reason, ok := errorutil.ExtractTerminalReason(err)
+		require.True(t, ok, "the error must be terminal")
+		assert.Equal(t, ocv1.ReasonBlocked, reason)
+		assert.Contains(t, err.Error(), `cannot migrate Subscription "test-sub": not found in namespace "test-namespace"`)
+	})
+
+	t.Run("blocks when the package differs", func(t *testing.T) {
+		sub, csv, ip := olmv0TestObjects()
+		c := newOLMv0TestClient(t, sub, csv, ip)
+		ext := olmv0TestExtension()
+		ext.Spec.Source.Catalog.PackageName = "other-package"
+
+		err := newOLMv0Migrator(c).Migrate(t.Context(), ext, objectLabels)
+		require.Error(t, err)
+		_, ok := errorutil.ExtractTerminalReason(err)
+		require.True(t, ok, "the error must be terminal")
+		assert.Contains(t, err.Error(), `the ClusterExtension must install package "test-package" from a catalog`)
+	})
+
+	t.Run("waits for the CSV to succeed", func(t *testing.T) {
+		sub, csv, ip := olmv0TestObjects()
+		csv.Status.Phase = v1alpha1.CSVPhaseInstalling
+		c := newOLMv0TestClient(t, sub, csv, ip)
+
+		err := newOLMv0Migrator(c).Migrate(t.Context(), olmv0TestExtension(), objectLabels)
+		require.EqualError(t, err, `waiting for CSV "test-operator.v1.2.3" to succeed, current phase "Installing"`)
+
+		revs := &ocv1.ClusterExtensionRevisionList{}
+		require.NoError(t, c.List(t.Context(), revs))
+		assert.Empty(t, revs.Items)
+	})
+}
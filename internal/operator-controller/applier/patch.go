@@ -0,0 +1,174 @@
+package applier
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
+	errorutil "github.com/operator-framework/operator-controller/internal/shared/util/error"
+)
+
+// objectPatcher applies the patches of a ClusterExtension to the objects rendered from a bundle.
+// A nil objectPatcher applies no patches.
+type objectPatcher struct {
+	patches []objectPatch
+}
+
+type objectPatch struct {
+	index     int
+	target    ocv1.PatchTarget
+	selector  k8slabels.Selector
+	patchType ocv1.PatchType
+	// merge is the JSON content of a strategic merge patch.
+	merge []byte
+	// ops are the operations of a JSON6902 patch.
+	ops jsonpatch.Patch
+}
+
+// newObjectPatcher parses the patches of ext. Invalid patches are reported with a terminal
+// InvalidConfiguration error, as they can only be fixed by changing the ClusterExtension.
+func newObjectPatcher(ext *ocv1.ClusterExtension) (*objectPatcher, error) {
+	if !features.OperatorControllerFeatureGate.Enabled(features.ManifestPatches) || ext.Spec.Install == nil || len(ext.Spec.Install.Patches) == 0 {
+		return nil, nil
+	}
+	p := &objectPatcher{patches: make([]objectPatch, 0, len(ext.Spec.Install.Patches))}
+	for i, patch := range ext.Spec.Install.Patches {
+		parsed, err := parsePatch(i, patch)
+		if err != nil {
+			return nil, errorutil.NewTerminalError(ocv1.ReasonInvalidConfiguration, err)
+		}
+		p.patches = append(p.patches, *parsed)
+	}
+	return p, nil
+}
+
+// patchesDigest returns a digest of the patches of ext, or an empty string when no patches
+// are applied.
+func patchesDigest(ext *ocv1.ClusterExtension) (string, error) {
+	if !features.OperatorControllerFeatureGate.Enabled(features.ManifestPatches) || ext.Spec.Install == nil || len(ext.Spec.Install.Patches) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(ext.Spec.Install.Patches)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+func parsePatch(index int, patch ocv1.ClusterExtensionPatch) (*objectPatch, error) {
+	parsed := &objectPatch{index: index, target: patch.Target, patchType: patch.Type, selector: k8slabels.Everything()}
+	if patch.Target.LabelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(patch.Target.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector of patch %d: %w", index, err)
+		}
+		parsed.selector = selector
+	}
+
+	content, err := yaml.YAMLToJSON([]byte(patch.Patch))
+	if err != nil {
+		return nil, fmt.Errorf("invalid content of patch %d: %w", index, err)
+	}
+	switch patch.Type {
+	case ocv1.PatchTypeStrategicMerge:
+		var obj map[string]any
+		if err := json.Unmarshal(content, &obj); err != nil {
+			return nil, fmt.Errorf("invalid content of patch %d: a strategic merge patch must be an object: %w", index, err)
+		}
+		parsed.merge = content
+	case ocv1.PatchTypeJSON6902:
+		ops, err := jsonpatch.DecodePatch(content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid content of patch %d: %w", index, err)
+		}
+		parsed.ops = ops
+	default:
+		return nil, fmt.Errorf("unsupported type %q of patch %d", patch.Type, index)
+	}
+	return parsed, nil
+}
+
+// Patch applies the patches that target obj, in order.
+func (p *objectPatcher) Patch(obj *unstructured.Unstructured) error {
+	if p == nil {
+		return nil
+	}
+	for _, patch := range p.patches {
+		if !patch.matches(obj) {
+			continue
+		}
+		if err := patch.apply(obj); err != nil {
+			return errorutil.NewTerminalError(ocv1.ReasonInvalidConfiguration,
+				fmt.Errorf("applying patch %d to %s %q: %w", patch.index, obj.GroupVersionKind().Kind, obj.GetName(), err))
+		}
+	}
+	return nil
+}
+
+func (p *objectPatch) matches(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	switch {
+	case p.target.Group != "" && p.target.Group != gvk.Group,
+		p.target.Version != "" && p.target.Version != gvk.Version,
+		p.target.Kind != "" && p.target.Kind != gvk.Kind,
+		p.target.Namespace != "" && p.target.Namespace != obj.GetNamespace(),
+		p.target.Name != "" && p.target.Name != obj.GetName():
+		return false
+	}
+	return p.selector.Matches(k8slabels.Set(obj.GetLabels()))
+}
+
+func (p *objectPatch) apply(obj *unstructured.Unstructured) error {
+	original, err := obj.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	var result []byte
+	switch p.patchType {
+	case ocv1.PatchTypeStrategicMerge:
+		// Built-in types carry the patch strategies of their lists. Other types, such as
+		// custom resources, are patched with a JSON merge patch, like kubectl does.
+		if typed, err := scheme.Scheme.New(obj.GroupVersionKind()); err == nil {
+			result, err = strategicpatch.StrategicMergePatch(original, p.merge, typed)
+			if err != nil {
+				return err
+			}
+		} else {
+			result, err = jsonpatch.MergePatch(original, p.merge)
+			if err != nil {
+				return err
+			}
+		}
+	case ocv1.PatchTypeJSON6902:
+		result, err = p.ops.Apply(original)
+		if err != nil {
+			return err
+		}
+	}
+
+	patched := &unstructured.Unstructured{}
+	if err := patched.UnmarshalJSON(result); err != nil {
+		return err
+	}
+	// Objects are identified by these fields, so patches can't change them.
+	if patched.GetAPIVersion() != obj.GetAPIVersion() || patched.GetKind() != obj.GetKind() ||
+		patched.GetNamespace() != obj.GetNamespace() || patched.GetName() != obj.GetName() {
+		return errors.New("patches must not change the apiVersion, kind, metadata.name or metadata.namespace of objects")
+	}
+	obj.Object = patched.Object
+	return nil
+}
@@ -2,7 +2,11 @@ package applier
 
 import (
 	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
 	"slices"
+	"strconv"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -136,29 +140,149 @@ func compareClusterExtensionRevisionObjects(a, b ocv1.ClusterExtensionRevisionOb
 	)
 }
 
-// PhaseSort takes an unsorted list of objects and organizes them into sorted phases.
-// Each phase will be applied in order according to DefaultPhaseOrder. Objects
-// within a single phase are applied simultaneously.
-func PhaseSort(unsortedObjs []ocv1.ClusterExtensionRevisionObject) []ocv1.ClusterExtensionRevisionPhase {
-	phasesSorted := make([]ocv1.ClusterExtensionRevisionPhase, 0)
-	phaseMap := make(map[Phase][]ocv1.ClusterExtensionRevisionObject, 0)
-
-	for _, obj := range unsortedObjs {
-		phase := determinePhase(obj.Object.GroupVersionKind().GroupKind())
-		phaseMap[phase] = append(phaseMap[phase], obj)
+// phaseWeightStep is the difference of the weights of consecutive well-known phases.
+// The weight of a well-known phase is its position in defaultPhaseOrder, starting
+// at 1, times phaseWeightStep.
+const phaseWeightStep = 100
+
+// wellKnownPhaseWeight returns the weight of phase, and false if phase isn't well-known.
+func wellKnownPhaseWeight(phase Phase) (int, bool) {
+	i := slices.Index(defaultPhaseOrder, phase)
+	if i < 0 {
+		return 0, false
+	}
+	return (i + 1) * phaseWeightStep, true
+}
+
+// sortedPhase is a phase of a revision with its weight.
+type sortedPhase struct {
+	name      Phase
+	weight    int
+	wellKnown bool
+	// weightFrom is the object that set the weight of a custom phase, if any.
+	weightFrom string
+	objects    []ocv1.ClusterExtensionRevisionObject
+}
+
+// comparePhases orders phases by weight. Phases of the same weight are ordered
+// well-known phases first, then by name.
+func comparePhases(a, b *sortedPhase) int {
+	return cmp.Or(
+		cmp.Compare(a.weight, b.weight),
+		-compareBool(a.wellKnown, b.wellKnown),
+		cmp.Compare(a.name, b.name),
+	)
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+const (
+	// maxPhases is the maximum number of phases of a ClusterExtensionRevision.
+	maxPhases = 20
+	// maxPhaseNameLength is the maximum length of the name of a phase.
+	maxPhaseNameLength = 63
+)
+
+// phaseNamePattern is the pattern of the names of the phases of ClusterExtensionRevisions.
+var phaseNamePattern = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+
+// objectPhase returns the phase of obj, as declared with the ocv1.AnnotationPhase
+// annotation, or determined by its Group Kind, and the weight declared with the
+// ocv1.AnnotationPhaseWeight annotation, if any.
+func objectPhase(obj *ocv1.ClusterExtensionRevisionObject) (Phase, *int, error) {
+	annotations := obj.Object.GetAnnotations()
+	phase := determinePhase(obj.Object.GroupVersionKind().GroupKind())
+	if name, ok := annotations[ocv1.AnnotationPhase]; ok {
+		if len(name) > maxPhaseNameLength || !phaseNamePattern.MatchString(name) {
+			return "", nil, fmt.Errorf("invalid %s annotation %q: must consist of at most %d lower case alphanumeric characters or '-', start with a letter and end with an alphanumeric character",
+				ocv1.AnnotationPhase, name, maxPhaseNameLength)
+		}
+		phase = Phase(name)
+	}
+	value, ok := annotations[ocv1.AnnotationPhaseWeight]
+	if !ok {
+		return phase, nil, nil
+	}
+	if _, wellKnown := wellKnownPhaseWeight(phase); wellKnown {
+		return "", nil, fmt.Errorf("%s annotation is only allowed for custom phases, and the weight of phase %q is fixed", ocv1.AnnotationPhaseWeight, phase)
+	}
+	weight, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid %s annotation %q: must be an integer", ocv1.AnnotationPhaseWeight, value)
 	}
+	w := int(weight)
+	return phase, &w, nil
+}
+
+// objectRef returns a human-readable reference to obj for error messages.
+func objectRef(obj *ocv1.ClusterExtensionRevisionObject) string {
+	if ns := obj.Object.GetNamespace(); ns != "" {
+		return fmt.Sprintf("%s %s/%s", obj.Object.GetKind(), ns, obj.Object.GetName())
+	}
+	return fmt.Sprintf("%s %s", obj.Object.GetKind(), obj.Object.GetName())
+}
 
-	for _, phaseName := range defaultPhaseOrder {
-		if objs, ok := phaseMap[phaseName]; ok {
-			// Sort objects within the phase deterministically
-			slices.SortFunc(objs, compareClusterExtensionRevisionObjects)
+// PhaseSort takes an unsorted list of objects and organizes them into sorted phases.
+// Objects are placed into the phase named by their ocv1.AnnotationPhase annotation,
+// or into a well-known phase by their Group Kind. Phases are applied in order of
+// increasing weight: well-known phases in the order of defaultPhaseOrder, and custom
+// phases with the weight declared with the ocv1.AnnotationPhaseWeight annotation of
+// their objects, or the weight of the deploy phase. Objects within a single phase are
+// applied simultaneously.
+//
+// An error is returned if an annotation is invalid, if the objects of a custom
+// phase declare different weights, or if there are more than maxPhases phases.
+func PhaseSort(unsortedObjs []ocv1.ClusterExtensionRevisionObject) ([]ocv1.ClusterExtensionRevisionPhase, error) {
+	phaseMap := make(map[Phase]*sortedPhase, 0)
+	defaultWeight, _ := wellKnownPhaseWeight(PhaseDeploy)
 
-			phasesSorted = append(phasesSorted, ocv1.ClusterExtensionRevisionPhase{
-				Name:    string(phaseName),
-				Objects: objs,
-			})
+	for i := range unsortedObjs {
+		obj := &unsortedObjs[i]
+		name, weight, err := objectPhase(obj)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", objectRef(obj), err)
+		}
+		phase, ok := phaseMap[name]
+		if !ok {
+			phase = &sortedPhase{name: name, weight: defaultWeight}
+			if w, wellKnown := wellKnownPhaseWeight(name); wellKnown {
+				phase.weight, phase.wellKnown = w, true
+			}
+			phaseMap[name] = phase
 		}
+		if weight != nil {
+			if phase.weightFrom != "" && phase.weight != *weight {
+				return nil, fmt.Errorf("%s: phase %q has weight %d, set by %s, and not %d",
+					objectRef(obj), name, phase.weight, phase.weightFrom, *weight)
+			}
+			phase.weight, phase.weightFrom = *weight, objectRef(obj)
+		}
+		phase.objects = append(phase.objects, *obj)
+	}
+
+	if len(phaseMap) > maxPhases {
+		return nil, fmt.Errorf("the objects are sorted into %d phases, more than the %d allowed", len(phaseMap), maxPhases)
+	}
+
+	phases := slices.SortedFunc(maps.Values(phaseMap), comparePhases)
+	phasesSorted := make([]ocv1.ClusterExtensionRevisionPhase, 0, len(phases))
+	for _, phase := range phases {
+		// Sort objects within the phase deterministically
+		slices.SortFunc(phase.objects, compareClusterExtensionRevisionObjects)
+
+		phasesSorted = append(phasesSorted, ocv1.ClusterExtensionRevisionPhase{
+			Name:    string(phase.name),
+			Objects: phase.objects,
+		})
 	}
 
-	return phasesSorted
+	return phasesSorted, nil
 }
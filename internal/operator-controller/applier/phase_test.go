@@ -1,6 +1,7 @@
 package applier_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -933,7 +934,133 @@ func Test_PhaseSort(t *testing.T) {
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			require.Equal(t, tt.want, applier.PhaseSort(tt.objs))
+			phases, err := applier.PhaseSort(tt.objs)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, phases)
 		})
 	}
 }
+
+func newAnnotatedObject(apiVersion, kind, name string, annotations map[string]string) v1.ClusterExtensionRevisionObject {
+	obj := v1.ClusterExtensionRevisionObject{
+		Object: unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": apiVersion,
+				"kind":       kind,
+			},
+		},
+	}
+	obj.Object.SetName(name)
+	obj.Object.SetAnnotations(annotations)
+	return obj
+}
+
+func Test_PhaseSort_Annotations(t *testing.T) {
+	phaseNames := func(phases []v1.ClusterExtensionRevisionPhase) map[string][]string {
+		names := map[string][]string{}
+		for _, phase := range phases {
+			for _, obj := range phase.Objects {
+				names[phase.Name] = append(names[phase.Name], obj.Object.GetName())
+			}
+		}
+		return names
+	}
+	phaseOrder := func(phases []v1.ClusterExtensionRevisionPhase) []string {
+		order := make([]string, 0, len(phases))
+		for _, phase := range phases {
+			order = append(order, phase.Name)
+		}
+		return order
+	}
+
+	t.Run("moves objects to well-known phases", func(t *testing.T) {
+		phases, err := applier.PhaseSort([]v1.ClusterExtensionRevisionObject{
+			newAnnotatedObject("apps/v1", "Deployment", "operator", nil),
+			newAnnotatedObject("v1", "ConfigMap", "config", map[string]string{v1.AnnotationPhase: "storage"}),
+			newAnnotatedObject("example.com/v1", "Example", "default-instance", map[string]string{v1.AnnotationPhase: "publish"}),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"storage", "deploy", "publish"}, phaseOrder(phases))
+		require.Equal(t, map[string][]string{
+			"storage": {"config"},
+			"deploy":  {"operator"},
+			"publish": {"default-instance"},
+		}, phaseNames(phases))
+	})
+
+	t.Run("orders custom phases by weight", func(t *testing.T) {
+		phases, err := applier.PhaseSort([]v1.ClusterExtensionRevisionObject{
+			newAnnotatedObject("apps/v1", "Deployment", "operator", nil),
+			newAnnotatedObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "examples.example.com", nil),
+			newAnnotatedObject("example.com/v1", "Example", "default-instance", map[string]string{
+				v1.AnnotationPhase:       "instances",
+				v1.AnnotationPhaseWeight: "900",
+			}),
+			newAnnotatedObject("v1", "ConfigMap", "config", map[string]string{
+				v1.AnnotationPhase:       "config",
+				v1.AnnotationPhaseWeight: "650",
+			}),
+			newAnnotatedObject("v1", "Secret", "config-secret", map[string]string{
+				v1.AnnotationPhase: "config",
+			}),
+			newAnnotatedObject("v1", "ConfigMap", "extras", map[string]string{v1.AnnotationPhase: "extras"}),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"crds", "config", "deploy", "extras", "instances"}, phaseOrder(phases),
+			"custom phases without weight are rolled out after the deploy phase")
+		require.Equal(t, []string{"config", "config-secret"}, phaseNames(phases)["config"])
+	})
+
+	for _, tc := range []struct {
+		name   string
+		obj    v1.ClusterExtensionRevisionObject
+		errMsg string
+	}{
+		{
+			name:   "invalid phase name",
+			obj:    newAnnotatedObject("v1", "ConfigMap", "config", map[string]string{v1.AnnotationPhase: "Config_Phase"}),
+			errMsg: "ConfigMap config: invalid olm.operatorframework.io/phase annotation",
+		},
+		{
+			name:   "phase name starting with a digit",
+			obj:    newAnnotatedObject("v1", "ConfigMap", "config", map[string]string{v1.AnnotationPhase: "1-config"}),
+			errMsg: "ConfigMap config: invalid olm.operatorframework.io/phase annotation",
+		},
+		{
+			name: "invalid weight",
+			obj: newAnnotatedObject("v1", "ConfigMap", "config", map[string]string{
+				v1.AnnotationPhase:       "config",
+				v1.AnnotationPhaseWeight: "first",
+			}),
+			errMsg: "must be an integer",
+		},
+		{
+			name:   "weight of a well-known phase",
+			obj:    newAnnotatedObject("v1", "ConfigMap", "config", map[string]string{v1.AnnotationPhaseWeight: "50"}),
+			errMsg: `the weight of phase "deploy" is fixed`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := applier.PhaseSort([]v1.ClusterExtensionRevisionObject{tc.obj})
+			require.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+
+	t.Run("rejects more phases than a revision can hold", func(t *testing.T) {
+		var objs []v1.ClusterExtensionRevisionObject
+		for i := range 21 {
+			name := fmt.Sprintf("config-%d", i)
+			objs = append(objs, newAnnotatedObject("v1", "ConfigMap", name, map[string]string{v1.AnnotationPhase: name}))
+		}
+		_, err := applier.PhaseSort(objs)
+		require.ErrorContains(t, err, "the objects are sorted into 21 phases, more than the 20 allowed")
+	})
+
+	t.Run("rejects conflicting weights of a custom phase", func(t *testing.T) {
+		_, err := applier.PhaseSort([]v1.ClusterExtensionRevisionObject{
+			newAnnotatedObject("v1", "ConfigMap", "a", map[string]string{v1.AnnotationPhase: "config", v1.AnnotationPhaseWeight: "650"}),
+			newAnnotatedObject("v1", "ConfigMap", "b", map[string]string{v1.AnnotationPhase: "config", v1.AnnotationPhaseWeight: "600"}),
+		})
+		require.ErrorContains(t, err, `ConfigMap b: phase "config" has weight 650, set by ConfigMap a, and not 600`)
+	})
+}
@@ -2,6 +2,7 @@ package applier
 
 import (
 	"context"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -16,6 +17,8 @@ const (
 	StateUnchanged        string = "Unchanged"
 	StateError            string = "Error"
 	maxHelmReleaseHistory        = 10
+	// helmHookTimeout bounds how long each Helm hook may run, matching the default of the Helm CLI.
+	helmHookTimeout = 5 * time.Minute
 )
 
 // Preflight is a check that should be run before making any changes to the cluster
@@ -52,3 +55,18 @@ func shouldSkipPreflight(ctx context.Context, preflight Preflight, ext *ocv1.Clu
 	}
 	return false
 }
+
+// runUpgradePreflights runs the Upgrade checks of preflights against objs, the objects a
+// rollback restores. A rollback replaces the installed objects just like an upgrade does,
+// so it is subject to the same checks, such as CRD upgrade safety.
+func runUpgradePreflights(ctx context.Context, preflights []Preflight, ext *ocv1.ClusterExtension, objs []client.Object) error {
+	for _, preflight := range preflights {
+		if shouldSkipPreflight(ctx, preflight, ext, StateNeedsUpgrade) {
+			continue
+		}
+		if err := preflight.Upgrade(ctx, objs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package applier
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
+)
+
+const (
+	// maxPreviewObjects is the maximum number of objects listed in an upgrade preview.
+	maxPreviewObjects = 100
+	// maxPreviewFields is the maximum number of changed fields listed per object in an upgrade preview.
+	maxPreviewFields = 20
+)
+
+// previewObject is an object rendered for an upgrade preview, keyed by its identity.
+type previewObject struct {
+	ref     ocv1.UpgradePreviewObject
+	content map[string]any
+}
+
+// newUpgradePreview compares the current objects of a ClusterExtension with the desired ones, and
+// summarizes the objects that are added, removed and changed. It returns nil when no object changes.
+// The bundle of the preview is read from revisionAnnotations.
+func newUpgradePreview(ext *ocv1.ClusterExtension, current, desired []client.Object, revisionAnnotations map[string]string) (*ocv1.ClusterExtensionUpgradePreview, error) {
+	currentObjs, err := toPreviewObjects(current)
+	if err != nil {
+		return nil, fmt.Errorf("converting current objects: %w", err)
+	}
+	desiredObjs, err := toPreviewObjects(desired)
+	if err != nil {
+		return nil, fmt.Errorf("converting desired objects: %w", err)
+	}
+
+	preview := &ocv1.ClusterExtensionUpgradePreview{
+		Bundle: ocv1.BundleMetadata{
+			Name:    revisionAnnotations[labels.BundleNameKey],
+			Version: revisionAnnotations[labels.BundleVersionKey],
+		},
+	}
+	var changes []ocv1.UpgradePreviewObject
+	for key, d := range desiredObjs {
+		c, ok := currentObjs[key]
+		if !ok {
+			preview.Added++
+			change := d.ref
+			change.Action = ocv1.UpgradePreviewActionAdded
+			changes = append(changes, change)
+			continue
+		}
+		fields := changedFields("", c.content, d.content, nil)
+		if len(fields) == 0 {
+			continue
+		}
+		preview.Changed++
+		change := d.ref
+		change.Action = ocv1.UpgradePreviewActionChanged
+		change.Fields = fields[:min(len(fields), maxPreviewFields)]
+		changes = append(changes, change)
+	}
+	for key, c := range currentObjs {
+		if _, ok := desiredObjs[key]; ok {
+			continue
+		}
+		preview.Removed++
+		change := c.ref
+		change.Action = ocv1.UpgradePreviewActionRemoved
+		changes = append(changes, change)
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	slices.SortFunc(changes, func(a, b ocv1.UpgradePreviewObject) int {
+		return cmp.Or(
+			cmp.Compare(a.Group, b.Group),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	preview.Objects = changes[:min(len(changes), maxPreviewObjects)]
+
+	digest, err := previewDigest(ext, revisionAnnotations)
+	if err != nil {
+		return nil, err
+	}
+	preview.Digest = digest
+	return preview, nil
+}
+
+func toPreviewObjects(objs []client.Object) (map[string]previewObject, error) {
+	result := make(map[string]previewObject, len(objs))
+	for _, obj := range objs {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		ref := ocv1.UpgradePreviewObject{ObjectReference: ocv1.ObjectReference{
+			Group:     gvk.Group,
+			Kind:      gvk.Kind,
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+		}}
+		result[strings.Join([]string{ref.Group, ref.Kind, ref.Namespace, ref.Name}, "/")] = previewObject{ref: ref, content: content}
+	}
+	return result, nil
+}
+
+// changedFields appends the paths of the fields that differ between current and desired to fields,
+// in a stable order. Lists are compared item by item, and missing maps and lists are compared as
+// empty ones, so that the paths of added and removed fields are reported.
+func changedFields(path string, current, desired any, fields []string) []string {
+	if current == nil {
+		current = emptyLike(desired)
+	}
+	if desired == nil {
+		desired = emptyLike(current)
+	}
+	switch d := desired.(type) {
+	case map[string]any:
+		c, ok := current.(map[string]any)
+		if !ok {
+			return append(fields, path)
+		}
+		keys := make([]string, 0, len(c)+len(d))
+		for k := range c {
+			keys = append(keys, k)
+		}
+		for k := range d {
+			if _, ok := c[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			fields = changedFields(fieldPath(path, k), c[k], d[k], fields)
+		}
+		return fields
+	case []any:
+		c, ok := current.([]any)
+		if !ok {
+			return append(fields, path)
+		}
+		for i := range max(len(c), len(d)) {
+			var ci, di any
+			if i < len(c) {
+				ci = c[i]
+			}
+			if i < len(d) {
+				di = d[i]
+			}
+			fields = changedFields(fmt.Sprintf("%s[%d]", path, i), ci, di, fields)
+		}
+		return fields
+	default:
+		if !reflect.DeepEqual(current, desired) {
+			return append(fields, path)
+		}
+		return fields
+	}
+}
+
+// emptyLike returns an empty map or list when v is a map or list, and nil otherwise.
+func emptyLike(v any) any {
+	switch v.(type) {
+	case map[string]any:
+		return map[string]any{}
+	case []any:
+		return []any{}
+	}
+	return nil
+}
+
+// fieldPath returns the path of the field key of the object at path. Keys containing dots,
+// such as label and annotation keys, are quoted.
+func fieldPath(path, key string) string {
+	if strings.ContainsAny(key, ".[]") {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// previewDigest returns a digest of the bundle and of the configuration of ext, so that an
+// approval only applies to the upgrade that was previewed. The rendered objects are not part
+// of the digest, since templates such as randAlphaNum render differently every time.
+func previewDigest(ext *ocv1.ClusterExtension, revisionAnnotations map[string]string) (string, error) {
+	h := sha256.New()
+	for _, k := range []string{labels.BundleNameKey, labels.BundleVersionKey, labels.BundleReferenceKey} {
+		fmt.Fprintf(h, "%s=%s\n", k, revisionAnnotations[k])
+	}
+	fmt.Fprintf(h, "namespace=%s\n", ext.Spec.Namespace)
+	config := struct {
+		Config  *ocv1.ClusterExtensionConfig `json:"config,omitempty"`
+		Patches []ocv1.ClusterExtensionPatch `json:"patches,omitempty"`
+	}{Config: ext.Spec.Config}
+	if ext.Spec.Install != nil {
+		config.Patches = ext.Spec.Install.Patches
+	}
+	b, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	h.Write(b)
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
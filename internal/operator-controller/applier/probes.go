@@ -0,0 +1,60 @@
+package applier
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/objectprobe"
+	errorutil "github.com/operator-framework/operator-controller/internal/shared/util/error"
+)
+
+// maxObjectProbes is the maximum number of probes of an object of a ClusterExtensionRevision.
+const maxObjectProbes = 16
+
+// objectProbeSelector selects the probes that a ClusterExtension declares for the objects
+// rendered from a bundle. A nil objectProbeSelector selects no probes.
+type objectProbeSelector []ocv1.ClusterExtensionProbe
+
+// newObjectProbeSelector validates the probes of ext. Invalid probes, such as CEL rules that
+// don't compile, are reported with a terminal InvalidConfiguration error.
+func newObjectProbeSelector(ext *ocv1.ClusterExtension) (objectProbeSelector, error) {
+	if !features.OperatorControllerFeatureGate.Enabled(features.ObjectProbes) || ext.Spec.Install == nil || len(ext.Spec.Install.Probes) == 0 {
+		return nil, nil
+	}
+	for i, p := range ext.Spec.Install.Probes {
+		if _, err := objectprobe.New(p.Assertions); err != nil {
+			return nil, errorutil.NewTerminalError(ocv1.ReasonInvalidConfiguration, fmt.Errorf("probes %d: %w", i, err))
+		}
+	}
+	return ext.Spec.Install.Probes, nil
+}
+
+// ProbesFor returns the probes of all selectors matching obj, in order.
+func (s objectProbeSelector) ProbesFor(obj *unstructured.Unstructured) ([]ocv1.ObjectProbe, error) {
+	var probes []ocv1.ObjectProbe
+	for _, p := range s {
+		if matchesProbeSelector(p.Selector, obj) {
+			probes = append(probes, p.Assertions...)
+		}
+	}
+	if len(probes) > maxObjectProbes {
+		return nil, errorutil.NewTerminalError(ocv1.ReasonInvalidConfiguration,
+			fmt.Errorf("%s %q is selected by %d probes, more than the maximum of %d", obj.GetKind(), obj.GetName(), len(probes), maxObjectProbes))
+	}
+	return probes, nil
+}
+
+func matchesProbeSelector(sel ocv1.ProbeSelector, obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	switch {
+	case sel.Group != gvk.Group,
+		sel.Kind != gvk.Kind,
+		sel.Namespace != "" && sel.Namespace != obj.GetNamespace(),
+		sel.Name != "" && sel.Name != obj.GetName():
+		return false
+	}
+	return true
+}
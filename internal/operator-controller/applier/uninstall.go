@@ -0,0 +1,157 @@
+package applier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfinalizer "sigs.k8s.io/controller-runtime/pkg/finalizer"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	helmclient "github.com/operator-framework/helm-operator-plugins/pkg/client"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/installnamespace"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
+)
+
+// uninstallPolicy returns the uninstall policy of ext, or "" when it isn't configured
+// or the UninstallPolicy feature is disabled.
+func uninstallPolicy(ext *ocv1.ClusterExtension) ocv1.UninstallPolicy {
+	if !features.OperatorControllerFeatureGate.Enabled(features.UninstallPolicy) || ext.Spec.Install == nil {
+		return ""
+	}
+	return ext.Spec.Install.UninstallPolicy
+}
+
+// UninstallPolicyKeeps reports whether installed objects of the given group and kind are kept
+// when their ClusterExtension is deleted with the given uninstall policy. Namespaces are kept
+// along with CustomResourceDefinitions, as deleting them deletes the custom resources they hold.
+func UninstallPolicyKeeps(policy ocv1.UninstallPolicy, gk schema.GroupKind) bool {
+	switch policy {
+	case ocv1.UninstallPolicyOrphanAll:
+		return true
+	case ocv1.UninstallPolicyOrphanCRDs:
+		return gk == apiextensionsv1.Kind("CustomResourceDefinition") || gk == corev1.SchemeGroupVersion.WithKind("Namespace").GroupKind()
+	default:
+		return false
+	}
+}
+
+// OrphanObject removes the owner references to ClusterExtensions and ClusterExtensionRevisions
+// from the object identified by obj, and the labels marking it as managed by a ClusterExtension,
+// so that it is kept when its owners are deleted, and can be adopted by a ClusterExtension later.
+// Missing objects are ignored.
+func OrphanObject(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	patch := client.MergeFromWithOptions(obj.DeepCopy(), client.MergeFromWithOptimisticLock{})
+
+	ownerRefs := obj.GetOwnerReferences()
+	keptOwnerRefs := slices.DeleteFunc(slices.Clone(ownerRefs), isClusterExtensionOwnerRef)
+	objLabels := obj.GetLabels()
+	_, hasOwnerKind := objLabels[labels.OwnerKindKey]
+	_, hasOwnerName := objLabels[labels.OwnerNameKey]
+	if len(keptOwnerRefs) == len(ownerRefs) && !hasOwnerKind && !hasOwnerName {
+		return nil
+	}
+
+	obj.SetOwnerReferences(keptOwnerRefs)
+	delete(objLabels, labels.OwnerKindKey)
+	delete(objLabels, labels.OwnerNameKey)
+	obj.SetLabels(objLabels)
+	if err := c.Patch(ctx, obj, patch); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	return nil
+}
+
+func isClusterExtensionOwnerRef(ref metav1.OwnerReference) bool {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil || gv.Group != ocv1.GroupVersion.Group {
+		return false
+	}
+	return ref.Kind == ocv1.ClusterExtensionKind || ref.Kind == ocv1.ClusterExtensionRevisionKind
+}
+
+// HelmReleaseOrphaner applies the uninstall policy of ClusterExtensions installed as Helm
+// releases. It is a finalizer of ClusterExtensions, since the objects of their releases are
+// owned by the ClusterExtension, and garbage-collected once it is deleted.
+type HelmReleaseOrphaner struct {
+	ActionClientGetter            helmclient.ActionClientGetter
+	HelmReleaseToObjectsConverter HelmReleaseToObjectsConverterInterface
+	// ClientFor returns the client used to orphan the objects of the release, typically
+	// using the ServiceAccount of the ClusterExtension.
+	ClientFor func(ctx context.Context, ext *ocv1.ClusterExtension) (client.Client, error)
+}
+
+// Finalize orphans the objects of the Helm release of the ClusterExtension obj that are kept
+// by its uninstall policy, and the installation namespace that obj requested, which is not part
+// of the release.
+func (o *HelmReleaseOrphaner) Finalize(ctx context.Context, obj client.Object) (crfinalizer.Result, error) {
+	ext := obj.(*ocv1.ClusterExtension)
+	policy := uninstallPolicy(ext)
+	if policy == "" || policy == ocv1.UninstallPolicyDelete {
+		return crfinalizer.Result{}, nil
+	}
+
+	c, err := o.ClientFor(ctx, ext)
+	if err != nil {
+		return crfinalizer.Result{}, err
+	}
+	if installnamespace.Requested(ext) {
+		ns := &unstructured.Unstructured{}
+		ns.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+		ns.SetName(ext.Spec.Namespace)
+		if err := OrphanObject(ctx, c, ns); err != nil {
+			return crfinalizer.Result{}, fmt.Errorf("orphaning namespace %q: %w", ext.Spec.Namespace, err)
+		}
+	}
+
+	ac, err := o.ActionClientGetter.ActionClientFor(ctx, ext)
+	if err != nil {
+		return crfinalizer.Result{}, err
+	}
+	rel, err := ac.Get(ext.GetName())
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return crfinalizer.Result{}, nil
+	}
+	if err != nil {
+		return crfinalizer.Result{}, fmt.Errorf("getting current release: %w", err)
+	}
+	relObjects, err := o.HelmReleaseToObjectsConverter.GetObjectsFromRelease(rel)
+	if err != nil {
+		return crfinalizer.Result{}, err
+	}
+
+	var orphaned int
+	for _, relObj := range relObjects {
+		gvk := relObj.GetObjectKind().GroupVersionKind()
+		if !UninstallPolicyKeeps(policy, gvk.GroupKind()) {
+			continue
+		}
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		u.SetName(relObj.GetName())
+		u.SetNamespace(relObj.GetNamespace())
+		if err := setReleaseObjectNamespace(c, u, ext.Spec.Namespace); err != nil {
+			return crfinalizer.Result{}, err
+		}
+		if err := OrphanObject(ctx, c, u); err != nil {
+			return crfinalizer.Result{}, fmt.Errorf("orphaning %s %q: %w", gvk.Kind, u.GetName(), err)
+		}
+		orphaned++
+	}
+	log.FromContext(ctx).Info("orphaned objects of uninstalled release", "policy", policy, "objects", orphaned)
+	return crfinalizer.Result{}, nil
+}
@@ -0,0 +1,249 @@
+package applier_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/applier"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
+)
+
+func TestUninstallPolicyKeeps(t *testing.T) {
+	crd := apiextensionsv1.Kind("CustomResourceDefinition")
+	sa := corev1.SchemeGroupVersion.WithKind("ServiceAccount").GroupKind()
+	ns := corev1.SchemeGroupVersion.WithKind("Namespace").GroupKind()
+
+	for _, tc := range []struct {
+		policy  ocv1.UninstallPolicy
+		keepCRD bool
+		keepSA  bool
+		keepNS  bool
+	}{
+		{policy: "", keepCRD: false, keepSA: false, keepNS: false},
+		{policy: ocv1.UninstallPolicyDelete, keepCRD: false, keepSA: false, keepNS: false},
+		{policy: ocv1.UninstallPolicyOrphanCRDs, keepCRD: true, keepSA: false, keepNS: true},
+		{policy: ocv1.UninstallPolicyOrphanAll, keepCRD: true, keepSA: true, keepNS: true},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			assert.Equal(t, tc.keepCRD, applier.UninstallPolicyKeeps(tc.policy, crd))
+			assert.Equal(t, tc.keepSA, applier.UninstallPolicyKeeps(tc.policy, sa))
+			assert.Equal(t, tc.keepNS, applier.UninstallPolicyKeeps(tc.policy, ns))
+		})
+	}
+}
+
+func newOwnedTestCRD(ownerRefs ...metav1.OwnerReference) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "widgets.example.com",
+			Labels: map[string]string{
+				labels.OwnerKindKey: ocv1.ClusterExtensionKind,
+				labels.OwnerNameKey: "test-ext",
+				"app":               "widgets",
+			},
+			OwnerReferences: ownerRefs,
+		},
+	}
+}
+
+func crdObjectRef() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"))
+	u.SetName("widgets.example.com")
+	return u
+}
+
+func TestOrphanObject(t *testing.T) {
+	revisionRef := metav1.OwnerReference{
+		APIVersion: ocv1.GroupVersion.String(),
+		Kind:       ocv1.ClusterExtensionRevisionKind,
+		Name:       "test-ext-2",
+		UID:        "rev-uid",
+		Controller: ptr.To(true),
+	}
+	archivedRevisionRef := metav1.OwnerReference{
+		APIVersion: ocv1.GroupVersion.String(),
+		Kind:       ocv1.ClusterExtensionRevisionKind,
+		Name:       "test-ext-1",
+		UID:        "archived-rev-uid",
+	}
+	otherRef := metav1.OwnerReference{
+		APIVersion: "example.com/v1",
+		Kind:       "Widget",
+		Name:       "other-owner",
+		UID:        "other-uid",
+	}
+
+	t.Run("removes the owner references and labels of ClusterExtensions", func(t *testing.T) {
+		c := newOLMv0TestClient(t, newOwnedTestCRD(revisionRef, archivedRevisionRef, otherRef))
+
+		require.NoError(t, applier.OrphanObject(t.Context(), c, crdObjectRef()))
+
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		require.NoError(t, c.Get(t.Context(), client.ObjectKey{Name: "widgets.example.com"}, crd))
+		assert.Equal(t, []metav1.OwnerReference{otherRef}, crd.OwnerReferences)
+		assert.Equal(t, map[string]string{"app": "widgets"}, crd.Labels)
+	})
+
+	t.Run("ignores objects that don't exist", func(t *testing.T) {
+		c := newOLMv0TestClient(t)
+		require.NoError(t, applier.OrphanObject(t.Context(), c, crdObjectRef()))
+	})
+
+	t.Run("does not update objects that are not owned", func(t *testing.T) {
+		unowned := newOwnedTestCRD(otherRef)
+		unowned.Labels = map[string]string{"app": "widgets"}
+		c := newOLMv0TestClient(t, unowned)
+
+		before := &apiextensionsv1.CustomResourceDefinition{}
+		require.NoError(t, c.Get(t.Context(), client.ObjectKey{Name: "widgets.example.com"}, before))
+		require.NoError(t, applier.OrphanObject(t.Context(), c, crdObjectRef()))
+		after := &apiextensionsv1.CustomResourceDefinition{}
+		require.NoError(t, c.Get(t.Context(), client.ObjectKey{Name: "widgets.example.com"}, after))
+		assert.Equal(t, before.ResourceVersion, after.ResourceVersion)
+	})
+}
+
+func TestHelmReleaseOrphaner_Finalize(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.UninstallPolicy)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.UninstallPolicy)))
+	})
+
+	const manifest = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: widget-operator`
+	extRef := metav1.OwnerReference{
+		APIVersion: ocv1.GroupVersion.String(),
+		Kind:       ocv1.ClusterExtensionKind,
+		Name:       "test-ext",
+		UID:        "ext-uid",
+		Controller: ptr.To(true),
+	}
+	newExt := func(policy ocv1.UninstallPolicy) *ocv1.ClusterExtension {
+		return &ocv1.ClusterExtension{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ext", UID: "ext-uid"},
+			Spec: ocv1.ClusterExtensionSpec{
+				Namespace: "test-namespace",
+				Install:   &ocv1.ClusterExtensionInstallConfig{UninstallPolicy: policy},
+			},
+		}
+	}
+	newObjects := func() []client.Object {
+		return []client.Object{
+			newOwnedTestCRD(extRef),
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+				Name:            "widget-operator",
+				Namespace:       "test-namespace",
+				Labels:          map[string]string{labels.OwnerKindKey: ocv1.ClusterExtensionKind, labels.OwnerNameKey: "test-ext"},
+				OwnerReferences: []metav1.OwnerReference{extRef},
+			}},
+		}
+	}
+	newOrphaner := func(c client.Client, getErr error) *applier.HelmReleaseOrphaner {
+		return &applier.HelmReleaseOrphaner{
+			ActionClientGetter: &mockActionGetter{
+				currentRel:   &release.Release{Name: "test-ext", Info: &release.Info{Status: release.StatusDeployed}, Manifest: manifest},
+				getClientErr: getErr,
+			},
+			HelmReleaseToObjectsConverter: applier.HelmReleaseToObjectsConverter{},
+			ClientFor: func(context.Context, *ocv1.ClusterExtension) (client.Client, error) {
+				return c, nil
+			},
+		}
+	}
+	ownerRefs := func(t *testing.T, c client.Client, obj client.Object, key client.ObjectKey) []metav1.OwnerReference {
+		require.NoError(t, c.Get(t.Context(), key, obj))
+		return obj.GetOwnerReferences()
+	}
+	crdKey := client.ObjectKey{Name: "widgets.example.com"}
+	saKey := client.ObjectKey{Name: "widget-operator", Namespace: "test-namespace"}
+
+	for _, tc := range []struct {
+		policy   ocv1.UninstallPolicy
+		crdOwned bool
+		saOwned  bool
+	}{
+		{policy: ocv1.UninstallPolicyDelete, crdOwned: true, saOwned: true},
+		{policy: ocv1.UninstallPolicyOrphanCRDs, crdOwned: false, saOwned: true},
+		{policy: ocv1.UninstallPolicyOrphanAll, crdOwned: false, saOwned: false},
+	} {
+		t.Run(fmt.Sprintf("orphans the objects kept by the %s policy", tc.policy), func(t *testing.T) {
+			c := newOLMv0TestClient(t, newObjects()...)
+			_, err := newOrphaner(c, nil).Finalize(t.Context(), newExt(tc.policy))
+			require.NoError(t, err)
+			assert.Equal(t, tc.crdOwned, len(ownerRefs(t, c, &apiextensionsv1.CustomResourceDefinition{}, crdKey)) > 0)
+			assert.Equal(t, tc.saOwned, len(ownerRefs(t, c, &corev1.ServiceAccount{}, saKey)) > 0)
+		})
+	}
+
+	t.Run("orphans the installation namespace", func(t *testing.T) {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.InstallNamespaceCreation)))
+		defer func() {
+			require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.InstallNamespaceCreation)))
+		}()
+		nsKey := client.ObjectKey{Name: "test-namespace"}
+		for _, tc := range []struct {
+			policy  ocv1.UninstallPolicy
+			nsOwned bool
+		}{
+			{policy: ocv1.UninstallPolicyDelete, nsOwned: true},
+			{policy: ocv1.UninstallPolicyOrphanCRDs, nsOwned: false},
+			{policy: ocv1.UninstallPolicyOrphanAll, nsOwned: false},
+		} {
+			c := newOLMv0TestClient(t, append(newObjects(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-namespace",
+				Labels:          map[string]string{labels.OwnerKindKey: ocv1.ClusterExtensionKind, labels.OwnerNameKey: "test-ext"},
+				OwnerReferences: []metav1.OwnerReference{extRef},
+			}})...)
+			ext := newExt(tc.policy)
+			ext.Spec.Install.Namespace = &ocv1.ClusterExtensionInstallNamespace{}
+
+			// The namespace is not part of the release, and is orphaned even without one.
+			_, err := newOrphaner(c, driver.ErrReleaseNotFound).Finalize(t.Context(), ext)
+			require.NoError(t, err)
+			ns := &corev1.Namespace{}
+			assert.Equal(t, tc.nsOwned, len(ownerRefs(t, c, ns, nsKey)) > 0, tc.policy)
+			assert.Equal(t, tc.nsOwned, ns.Labels[labels.OwnerNameKey] != "", tc.policy)
+		}
+	})
+
+	t.Run("does nothing without a release", func(t *testing.T) {
+		c := newOLMv0TestClient(t, newObjects()...)
+		_, err := newOrphaner(c, driver.ErrReleaseNotFound).Finalize(t.Context(), newExt(ocv1.UninstallPolicyOrphanAll))
+		require.NoError(t, err)
+		assert.NotEmpty(t, ownerRefs(t, c, &apiextensionsv1.CustomResourceDefinition{}, crdKey))
+	})
+
+	t.Run("does nothing when the feature is disabled", func(t *testing.T) {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.UninstallPolicy)))
+		defer func() {
+			require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.UninstallPolicy)))
+		}()
+		c := newOLMv0TestClient(t, newObjects()...)
+		_, err := newOrphaner(c, nil).Finalize(t.Context(), newExt(ocv1.UninstallPolicyOrphanAll))
+		require.NoError(t, err)
+		assert.NotEmpty(t, ownerRefs(t, c, &apiextensionsv1.CustomResourceDefinition{}, crdKey))
+		assert.NotEmpty(t, ownerRefs(t, c, &corev1.ServiceAccount{}, saKey))
+	})
+}
@@ -74,6 +74,42 @@ func ByDeprecationFunc(deprecation declcfg.Deprecation) func(a, b declcfg.Bundle
 	}
 }
 
+// ByDeprecatedChannelsFunc returns a comparison function that sorts bundles
+// that only belong to deprecated channels after bundles that belong to at
+// least one channel that is not deprecated. Bundles that are not in any of
+// the given channels are treated as not deprecated.
+func ByDeprecatedChannelsFunc(deprecation declcfg.Deprecation, channels []declcfg.Channel) func(a, b declcfg.Bundle) int {
+	deprecatedChannels := sets.New[string]()
+	for _, entry := range deprecation.Entries {
+		if entry.Reference.Schema == declcfg.SchemaChannel {
+			deprecatedChannels.Insert(entry.Reference.Name)
+		}
+	}
+	inDeprecatedChannel := sets.New[string]()
+	inCurrentChannel := sets.New[string]()
+	for _, ch := range channels {
+		target := inCurrentChannel
+		if deprecatedChannels.Has(ch.Name) {
+			target = inDeprecatedChannel
+		}
+		for _, entry := range ch.Entries {
+			target.Insert(entry.Name)
+		}
+	}
+	onlyDeprecated := inDeprecatedChannel.Difference(inCurrentChannel)
+	return func(a, b declcfg.Bundle) int {
+		aDeprecated := onlyDeprecated.Has(a.Name)
+		bDeprecated := onlyDeprecated.Has(b.Name)
+		if aDeprecated && !bDeprecated {
+			return 1
+		}
+		if !aDeprecated && bDeprecated {
+			return -1
+		}
+		return 0
+	}
+}
+
 // compareErrors returns 0 if both errors are either nil or not nil,
 // -1 if err1 is not nil and err2 is nil, and
 // +1 if err1 is nil and err2 is not nil
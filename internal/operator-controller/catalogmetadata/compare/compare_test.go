@@ -168,3 +168,26 @@ func TestByDeprecationFunc(t *testing.T) {
 	assert.Equal(t, 0, byDeprecation(c, d))
 	assert.Equal(t, 0, byDeprecation(d, c))
 }
+
+func TestByDeprecatedChannelsFunc(t *testing.T) {
+	deprecation := declcfg.Deprecation{
+		Entries: []declcfg.DeprecationEntry{
+			{Reference: declcfg.PackageScopedReference{Schema: declcfg.SchemaChannel, Name: "stable"}},
+		},
+	}
+	channels := []declcfg.Channel{
+		{Name: "stable", Entries: []declcfg.ChannelEntry{{Name: "a"}, {Name: "b"}}},
+		{Name: "stable-v2", Entries: []declcfg.ChannelEntry{{Name: "b"}, {Name: "c"}}},
+	}
+	byDeprecatedChannels := compare.ByDeprecatedChannelsFunc(deprecation, channels)
+	a := declcfg.Bundle{Name: "a"}
+	b := declcfg.Bundle{Name: "b"}
+	c := declcfg.Bundle{Name: "c"}
+	d := declcfg.Bundle{Name: "d"}
+
+	assert.Equal(t, 1, byDeprecatedChannels(a, b))
+	assert.Equal(t, -1, byDeprecatedChannels(b, a))
+	assert.Equal(t, 1, byDeprecatedChannels(a, d))
+	assert.Equal(t, 0, byDeprecatedChannels(b, c))
+	assert.Equal(t, 0, byDeprecatedChannels(c, d))
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/operator-framework/operator-controller/internal/operator-controller/bundle"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/bundleutil"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/clustercapabilities"
 	"github.com/operator-framework/operator-controller/internal/shared/util/filter"
 )
 
@@ -23,6 +24,18 @@ func ExactVersionRelease(expect bundle.VersionRelease) filter.Predicate[declcfg.
 	}
 }
 
+// NotOlderThan returns a predicate that matches bundles whose version and release
+// are equal to or higher than minimum.
+func NotOlderThan(minimum bundle.VersionRelease) filter.Predicate[declcfg.Bundle] {
+	return func(b declcfg.Bundle) bool {
+		actual, err := bundleutil.GetVersionAndRelease(b)
+		if err != nil {
+			return false
+		}
+		return actual.Compare(minimum) >= 0
+	}
+}
+
 // InSemverRange returns a predicate that matches bundles whose version falls within
 // the provided semver range. The range is applied only to the semver version portion,
 // ignoring the release metadata.
@@ -48,3 +61,16 @@ func InAnyChannel(channels ...declcfg.Channel) filter.Predicate[declcfg.Bundle]
 		return false
 	}
 }
+
+// CompatibleWithCluster returns a predicate that matches bundles whose declared
+// requirements are satisfied by the given cluster capabilities. If onIncompatible
+// is non-nil, it is called with the reason for every bundle that is excluded.
+func CompatibleWithCluster(caps *clustercapabilities.Capabilities, onIncompatible func(declcfg.Bundle, error)) filter.Predicate[declcfg.Bundle] {
+	return func(b declcfg.Bundle) bool {
+		err := caps.CheckBundle(b)
+		if err != nil && onIncompatible != nil {
+			onIncompatible(b, err)
+		}
+		return err == nil
+	}
+}
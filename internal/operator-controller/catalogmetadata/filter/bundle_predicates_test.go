@@ -4,14 +4,17 @@ import (
 	"encoding/json"
 	"testing"
 
+	bsemver "github.com/blang/semver/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/operator-framework/operator-registry/alpha/declcfg"
 	"github.com/operator-framework/operator-registry/alpha/property"
 
+	"github.com/operator-framework/operator-controller/internal/operator-controller/bundle"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/catalogmetadata/compare"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/catalogmetadata/filter"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/clustercapabilities"
 )
 
 func TestInSemverRange(t *testing.T) {
@@ -50,6 +53,18 @@ func TestInSemverRange(t *testing.T) {
 	assert.False(t, f(b3))
 }
 
+func TestNotOlderThan(t *testing.T) {
+	bundleWithVersion := func(version string) declcfg.Bundle {
+		return declcfg.Bundle{Properties: []property.Property{property.MustBuildPackage("package1", version)}}
+	}
+
+	f := filter.NotOlderThan(bundle.VersionRelease{Version: bsemver.MustParse("1.0.0")})
+
+	assert.True(t, f(bundleWithVersion("1.0.0")))
+	assert.True(t, f(bundleWithVersion("1.0.1")))
+	assert.False(t, f(bundleWithVersion("0.9.0")))
+}
+
 func TestInAnyChannel(t *testing.T) {
 	alpha := declcfg.Channel{Name: "alpha", Entries: []declcfg.ChannelEntry{{Name: "b1"}, {Name: "b2"}}}
 	stable := declcfg.Channel{Name: "stable", Entries: []declcfg.ChannelEntry{{Name: "b1"}}}
@@ -68,3 +83,28 @@ func TestInAnyChannel(t *testing.T) {
 	assert.False(t, fStable(b2))
 	assert.False(t, fStable(b3))
 }
+
+func TestCompatibleWithCluster(t *testing.T) {
+	caps := &clustercapabilities.Capabilities{KubernetesVersion: bsemver.MustParse("1.30.0")}
+
+	b1 := declcfg.Bundle{Name: "b1"}
+	b2 := declcfg.Bundle{
+		Name: "b2",
+		Properties: []property.Property{
+			{
+				Type:  property.TypeCSVMetadata,
+				Value: json.RawMessage(`{"minKubeVersion": "1.31.0"}`),
+			},
+		},
+	}
+
+	var excluded []string
+	f := filter.CompatibleWithCluster(caps, func(b declcfg.Bundle, err error) {
+		excluded = append(excluded, b.Name)
+		assert.EqualError(t, err, "requires Kubernetes version >= 1.31.0, cluster runs 1.30.0")
+	})
+
+	assert.True(t, f(b1))
+	assert.False(t, f(b2))
+	assert.Equal(t, []string{"b2"}, excluded)
+}
@@ -0,0 +1,88 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+
+	bsemver "github.com/blang/semver/v4"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/bundle"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/bundleutil"
+	"github.com/operator-framework/operator-controller/internal/shared/util/filter"
+)
+
+// VersionPolicy returns a predicate that matches bundles allowed by policy.
+//
+// PatchOnly, MinorOnly and Pinned are relative to installedBundle and match
+// every bundle when installedBundle is nil. LagBy is relative to the newest
+// minor version among candidates, and always matches installedBundle.
+func VersionPolicy(policy ocv1.VersionPolicy, installedBundle *ocv1.BundleMetadata, candidates []declcfg.Bundle) (filter.Predicate[declcfg.Bundle], error) {
+	if policy.Type == ocv1.VersionPolicyTypeLagBy {
+		lagging := laggingBy(int(policy.LagBy), candidates)
+		if installedBundle == nil {
+			return lagging, nil
+		}
+		return filter.Or(lagging, func(b declcfg.Bundle) bool { return b.Name == installedBundle.Name }), nil
+	}
+
+	if installedBundle == nil {
+		return func(declcfg.Bundle) bool { return true }, nil
+	}
+	installedVersionRelease, err := bundle.NewLegacyRegistryV1VersionRelease(installedBundle.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version and release of installed bundle: %v", err)
+	}
+	installed := installedVersionRelease.Version
+
+	switch policy.Type {
+	case ocv1.VersionPolicyTypePatchOnly:
+		return inVersionStream(func(v bsemver.Version) bool {
+			return v.Major == installed.Major && v.Minor == installed.Minor
+		}), nil
+	case ocv1.VersionPolicyTypeMinorOnly:
+		return inVersionStream(func(v bsemver.Version) bool {
+			return v.Major == installed.Major
+		}), nil
+	case ocv1.VersionPolicyTypePinned:
+		return ExactVersionRelease(*installedVersionRelease), nil
+	default:
+		return nil, fmt.Errorf("unknown version policy type %q", policy.Type)
+	}
+}
+
+func inVersionStream(match func(bsemver.Version) bool) filter.Predicate[declcfg.Bundle] {
+	return func(b declcfg.Bundle) bool {
+		vr, err := bundleutil.GetVersionAndRelease(b)
+		if err != nil {
+			return false
+		}
+		return match(vr.Version)
+	}
+}
+
+// laggingBy matches bundles whose major.minor version stream is at least n
+// streams older than the newest stream among candidates. Streams are ordered
+// across major versions, so lagging 1.5 and 2.0 by one stream yields 1.5.
+// When there are no more than n streams, only the oldest stream is matched.
+func laggingBy(n int, candidates []declcfg.Bundle) filter.Predicate[declcfg.Bundle] {
+	var streams []bsemver.Version
+	for _, b := range candidates {
+		vr, err := bundleutil.GetVersionAndRelease(b)
+		if err != nil {
+			continue
+		}
+		streams = append(streams, bsemver.Version{Major: vr.Version.Major, Minor: vr.Version.Minor})
+	}
+	slices.SortFunc(streams, func(a, b bsemver.Version) int { return b.Compare(a) })
+	streams = slices.CompactFunc(streams, bsemver.Version.Equals)
+	if len(streams) == 0 {
+		return func(declcfg.Bundle) bool { return false }
+	}
+	newest := streams[min(n, len(streams)-1)]
+	return inVersionStream(func(v bsemver.Version) bool {
+		return bsemver.Version{Major: v.Major, Minor: v.Minor}.LTE(newest)
+	})
+}
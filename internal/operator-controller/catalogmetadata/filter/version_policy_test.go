@@ -0,0 +1,96 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/catalogmetadata/filter"
+	filterutil "github.com/operator-framework/operator-controller/internal/shared/util/filter"
+)
+
+func TestVersionPolicy(t *testing.T) {
+	versions := []string{"1.3.0", "1.4.0", "1.4.1", "1.5.0", "2.0.0", "2.0.1"}
+	candidates := make([]declcfg.Bundle, 0, len(versions))
+	for _, v := range versions {
+		candidates = append(candidates, declcfg.Bundle{
+			Name:       "package1.v" + v,
+			Properties: []property.Property{property.MustBuildPackage("package1", v)},
+		})
+	}
+	installed := &ocv1.BundleMetadata{Name: "package1.v1.4.0", Version: "1.4.0"}
+
+	for _, tc := range []struct {
+		name      string
+		policy    ocv1.VersionPolicy
+		installed *ocv1.BundleMetadata
+		expected  []string
+	}{
+		{
+			name:      "PatchOnly stays in the installed minor version",
+			policy:    ocv1.VersionPolicy{Type: ocv1.VersionPolicyTypePatchOnly},
+			installed: installed,
+			expected:  []string{"1.4.0", "1.4.1"},
+		},
+		{
+			name:      "MinorOnly stays in the installed major version",
+			policy:    ocv1.VersionPolicy{Type: ocv1.VersionPolicyTypeMinorOnly},
+			installed: installed,
+			expected:  []string{"1.3.0", "1.4.0", "1.4.1", "1.5.0"},
+		},
+		{
+			name:      "Pinned stays at the installed version",
+			policy:    ocv1.VersionPolicy{Type: ocv1.VersionPolicyTypePinned},
+			installed: installed,
+			expected:  []string{"1.4.0"},
+		},
+		{
+			name:     "installed-relative policies allow any version for new installations",
+			policy:   ocv1.VersionPolicy{Type: ocv1.VersionPolicyTypePatchOnly},
+			expected: versions,
+		},
+		{
+			name:     "LagBy 1 stays one minor version behind the newest",
+			policy:   ocv1.VersionPolicy{Type: ocv1.VersionPolicyTypeLagBy, LagBy: 1},
+			expected: []string{"1.3.0", "1.4.0", "1.4.1", "1.5.0"},
+		},
+		{
+			name:      "LagBy 2 stays two minor versions behind the newest",
+			policy:    ocv1.VersionPolicy{Type: ocv1.VersionPolicyTypeLagBy, LagBy: 2},
+			installed: installed,
+			expected:  []string{"1.3.0", "1.4.0", "1.4.1"},
+		},
+		{
+			name:     "LagBy beyond the available minor versions falls back to the oldest minor version",
+			policy:   ocv1.VersionPolicy{Type: ocv1.VersionPolicyTypeLagBy, LagBy: 4},
+			expected: []string{"1.3.0"},
+		},
+		{
+			name:      "LagBy keeps the installed version allowed",
+			policy:    ocv1.VersionPolicy{Type: ocv1.VersionPolicyTypeLagBy, LagBy: 4},
+			installed: installed,
+			expected:  []string{"1.3.0", "1.4.0"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			predicate, err := filter.VersionPolicy(tc.policy, tc.installed, candidates)
+			require.NoError(t, err)
+
+			actual := []string{}
+			for _, b := range filterutil.Filter(candidates, predicate) {
+				actual = append(actual, b.Name[len("package1.v"):])
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestVersionPolicyInvalidInstalledVersion(t *testing.T) {
+	_, err := filter.VersionPolicy(ocv1.VersionPolicy{Type: ocv1.VersionPolicyTypePinned}, &ocv1.BundleMetadata{Version: "broken"}, nil)
+	require.ErrorContains(t, err, "failed to get version and release of installed bundle")
+}
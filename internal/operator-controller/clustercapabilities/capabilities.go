@@ -0,0 +1,121 @@
+package clustercapabilities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	bsemver "github.com/blang/semver/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+)
+
+// PropertyMaxOpenShiftVersion is the bundle property used to declare the newest
+// OpenShift minor version a bundle can run on.
+const PropertyMaxOpenShiftVersion = "olm.maxOpenShiftVersion"
+
+// Capabilities describes the properties of the running cluster that bundles can
+// declare requirements against.
+type Capabilities struct {
+	// KubernetesVersion is the major.minor.patch version of the API server.
+	KubernetesVersion bsemver.Version
+	// OpenShiftVersion is the version of the OpenShift cluster, or nil if the
+	// cluster is not an OpenShift cluster.
+	OpenShiftVersion *bsemver.Version
+	// APIGroupVersions is the set of group/versions served by the API server.
+	APIGroupVersions sets.Set[schema.GroupVersion]
+}
+
+// CheckBundle returns an error describing every requirement of b that this
+// cluster does not satisfy, or nil if b can run on this cluster.
+func (c *Capabilities) CheckBundle(b declcfg.Bundle) error {
+	var errs []error
+	for _, p := range b.Properties {
+		switch p.Type {
+		case property.TypeCSVMetadata:
+			var md property.CSVMetadata
+			if err := json.Unmarshal(p.Value, &md); err != nil {
+				errs = append(errs, fmt.Errorf("invalid %q property: %w", p.Type, err))
+				continue
+			}
+			errs = append(errs, c.checkMinKubeVersion(md.MinKubeVersion), c.checkNativeAPIs(md.NativeAPIs))
+		case PropertyMaxOpenShiftVersion:
+			errs = append(errs, c.checkMaxOpenShiftVersion(p.Value))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Capabilities) checkMinKubeVersion(minKubeVersion string) error {
+	if minKubeVersion == "" {
+		return nil
+	}
+	minVersion, err := bsemver.ParseTolerant(minKubeVersion)
+	if err != nil {
+		return fmt.Errorf("invalid minKubeVersion %q: %w", minKubeVersion, err)
+	}
+	if c.KubernetesVersion.LT(minVersion) {
+		return fmt.Errorf("requires Kubernetes version >= %s, cluster runs %s", minVersion, c.KubernetesVersion)
+	}
+	return nil
+}
+
+func (c *Capabilities) checkNativeAPIs(nativeAPIs []metav1.GroupVersionKind) error {
+	var missing []string
+	for _, gvk := range nativeAPIs {
+		gv := schema.GroupVersion{Group: gvk.Group, Version: gvk.Version}
+		if !c.APIGroupVersions.Has(gv) {
+			missing = append(missing, gv.String())
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	slices.Sort(missing)
+	missing = slices.Compact(missing)
+	return fmt.Errorf("requires APIs not served by the cluster: %s", strings.Join(missing, ", "))
+}
+
+func (c *Capabilities) checkMaxOpenShiftVersion(value json.RawMessage) error {
+	if c.OpenShiftVersion == nil {
+		return nil
+	}
+	maxVersion, err := parseMaxOpenShiftVersion(value)
+	if err != nil {
+		return fmt.Errorf("invalid %q property: %w", PropertyMaxOpenShiftVersion, err)
+	}
+	// maxOpenShiftVersion applies to the minor release stream; patch versions are ignored.
+	cluster := bsemver.Version{Major: c.OpenShiftVersion.Major, Minor: c.OpenShiftVersion.Minor}
+	if cluster.GT(bsemver.Version{Major: maxVersion.Major, Minor: maxVersion.Minor}) {
+		return fmt.Errorf("requires OpenShift version <= %d.%d, cluster runs %s", maxVersion.Major, maxVersion.Minor, c.OpenShiftVersion)
+	}
+	return nil
+}
+
+// parseMaxOpenShiftVersion accepts either a JSON string ("4.9") or a JSON number (4.9).
+func parseMaxOpenShiftVersion(value json.RawMessage) (*bsemver.Version, error) {
+	var raw any
+	if err := json.Unmarshal(value, &raw); err != nil {
+		return nil, err
+	}
+	var s string
+	switch v := raw.(type) {
+	case string:
+		s = v
+	case float64:
+		s = string(value)
+	default:
+		return nil, fmt.Errorf("expected a string or number, got %s", string(value))
+	}
+	version, err := bsemver.ParseTolerant(s)
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
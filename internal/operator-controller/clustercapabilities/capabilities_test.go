@@ -0,0 +1,126 @@
+package clustercapabilities_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	bsemver "github.com/blang/semver/v4"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+
+	"github.com/operator-framework/operator-controller/internal/operator-controller/clustercapabilities"
+)
+
+func csvMetadata(spec v1alpha1.ClusterServiceVersionSpec) property.Property {
+	return property.MustBuildCSVMetadata(v1alpha1.ClusterServiceVersion{Spec: spec})
+}
+
+func maxOpenShiftVersion(value string) property.Property {
+	return property.Property{Type: clustercapabilities.PropertyMaxOpenShiftVersion, Value: json.RawMessage(value)}
+}
+
+func TestCapabilities_CheckBundle(t *testing.T) {
+	kubernetes := &clustercapabilities.Capabilities{
+		KubernetesVersion: bsemver.MustParse("1.30.2"),
+		APIGroupVersions: sets.New(
+			schema.GroupVersion{Version: "v1"},
+			schema.GroupVersion{Group: "apps", Version: "v1"},
+		),
+	}
+	openshift := &clustercapabilities.Capabilities{
+		KubernetesVersion: bsemver.MustParse("1.30.2"),
+		OpenShiftVersion:  ptr.To(bsemver.MustParse("4.17.3")),
+		APIGroupVersions:  sets.New[schema.GroupVersion](),
+	}
+
+	for _, tc := range []struct {
+		name          string
+		caps          *clustercapabilities.Capabilities
+		properties    []property.Property
+		expectedError string
+	}{
+		{
+			name: "no requirements",
+			caps: kubernetes,
+		},
+		{
+			name:       "minKubeVersion satisfied",
+			caps:       kubernetes,
+			properties: []property.Property{csvMetadata(v1alpha1.ClusterServiceVersionSpec{MinKubeVersion: "1.30.0"})},
+		},
+		{
+			name:          "minKubeVersion not satisfied",
+			caps:          kubernetes,
+			properties:    []property.Property{csvMetadata(v1alpha1.ClusterServiceVersionSpec{MinKubeVersion: "v1.31"})},
+			expectedError: "requires Kubernetes version >= 1.31.0, cluster runs 1.30.2",
+		},
+		{
+			name:          "invalid minKubeVersion",
+			caps:          kubernetes,
+			properties:    []property.Property{csvMetadata(v1alpha1.ClusterServiceVersionSpec{MinKubeVersion: "latest"})},
+			expectedError: `invalid minKubeVersion "latest"`,
+		},
+		{
+			name: "native APIs served",
+			caps: kubernetes,
+			properties: []property.Property{csvMetadata(v1alpha1.ClusterServiceVersionSpec{
+				NativeAPIs: []metav1.GroupVersionKind{{Group: "apps", Version: "v1", Kind: "Deployment"}, {Version: "v1", Kind: "Pod"}},
+			})},
+		},
+		{
+			name: "native APIs not served",
+			caps: kubernetes,
+			properties: []property.Property{csvMetadata(v1alpha1.ClusterServiceVersionSpec{
+				NativeAPIs: []metav1.GroupVersionKind{
+					{Group: "route.openshift.io", Version: "v1", Kind: "Route"},
+					{Group: "config.openshift.io", Version: "v1", Kind: "ClusterVersion"},
+					{Group: "config.openshift.io", Version: "v1", Kind: "Infrastructure"},
+				},
+			})},
+			expectedError: "requires APIs not served by the cluster: config.openshift.io/v1, route.openshift.io/v1",
+		},
+		{
+			name:       "maxOpenShiftVersion ignored on non-OpenShift clusters",
+			caps:       kubernetes,
+			properties: []property.Property{maxOpenShiftVersion(`"4.9"`)},
+		},
+		{
+			name:       "maxOpenShiftVersion string satisfied",
+			caps:       openshift,
+			properties: []property.Property{maxOpenShiftVersion(`"4.17"`)},
+		},
+		{
+			name:       "maxOpenShiftVersion number satisfied",
+			caps:       openshift,
+			properties: []property.Property{maxOpenShiftVersion(`4.18`)},
+		},
+		{
+			name:          "maxOpenShiftVersion not satisfied",
+			caps:          openshift,
+			properties:    []property.Property{maxOpenShiftVersion(`"4.16"`)},
+			expectedError: "requires OpenShift version <= 4.16, cluster runs 4.17.3",
+		},
+		{
+			name:          "invalid maxOpenShiftVersion",
+			caps:          openshift,
+			properties:    []property.Property{maxOpenShiftVersion(`{"version": "4.16"}`)},
+			expectedError: `invalid "olm.maxOpenShiftVersion" property`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.caps.CheckBundle(declcfg.Bundle{Name: "test.v1.0.0", Properties: tc.properties})
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package clustercapabilities
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	bsemver "github.com/blang/semver/v4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultRefreshInterval is how long discovered capabilities are reused before
+// the cluster is queried again.
+const DefaultRefreshInterval = 10 * time.Minute
+
+var clusterVersionGVK = schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "ClusterVersion"}
+
+// Provider returns the capabilities of the running cluster.
+type Provider interface {
+	Get(ctx context.Context) (*Capabilities, error)
+}
+
+// ProviderFunc is a function that implements Provider.
+type ProviderFunc func(ctx context.Context) (*Capabilities, error)
+
+func (f ProviderFunc) Get(ctx context.Context) (*Capabilities, error) {
+	return f(ctx)
+}
+
+// DiscoveryProvider discovers cluster capabilities from the API server and
+// caches them for RefreshInterval.
+type DiscoveryProvider struct {
+	Discovery discovery.DiscoveryInterface
+	// Reader is used to read the OpenShift ClusterVersion when the cluster
+	// serves the config.openshift.io API group. It should not be a cached
+	// client, since ClusterVersion objects are not otherwise watched.
+	Reader client.Reader
+	// RefreshInterval defaults to DefaultRefreshInterval when zero.
+	RefreshInterval time.Duration
+
+	mu        sync.Mutex
+	cached    *Capabilities
+	fetchedAt time.Time
+	now       func() time.Time
+}
+
+func (p *DiscoveryProvider) Get(ctx context.Context) (*Capabilities, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+	refresh := p.RefreshInterval
+	if refresh == 0 {
+		refresh = DefaultRefreshInterval
+	}
+	if p.cached != nil && now().Sub(p.fetchedAt) < refresh {
+		return p.cached, nil
+	}
+
+	caps, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.cached = caps
+	p.fetchedAt = now()
+	return caps, nil
+}
+
+func (p *DiscoveryProvider) discover(ctx context.Context) (*Capabilities, error) {
+	serverVersion, err := p.Discovery.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("error getting server version: %w", err)
+	}
+	kubeVersion, err := bsemver.ParseTolerant(serverVersion.GitVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing server version %q: %w", serverVersion.GitVersion, err)
+	}
+
+	groups, err := p.Discovery.ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("error getting server API groups: %w", err)
+	}
+	groupVersions := sets.New[schema.GroupVersion]()
+	for _, g := range groups.Groups {
+		for _, v := range g.Versions {
+			groupVersions.Insert(schema.GroupVersion{Group: g.Name, Version: v.Version})
+		}
+	}
+
+	caps := &Capabilities{
+		// Distributions commonly add pre-release and build metadata to the server
+		// version (e.g. v1.29.3-gke.100 or v1.29.3+k3s1). Only major.minor.patch is
+		// meaningful for compatibility checks.
+		KubernetesVersion: bsemver.Version{Major: kubeVersion.Major, Minor: kubeVersion.Minor, Patch: kubeVersion.Patch},
+		APIGroupVersions:  groupVersions,
+	}
+
+	if groupVersions.Has(clusterVersionGVK.GroupVersion()) && p.Reader != nil {
+		caps.OpenShiftVersion, err = p.openShiftVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return caps, nil
+}
+
+func (p *DiscoveryProvider) openShiftVersion(ctx context.Context) (*bsemver.Version, error) {
+	cv := &unstructured.Unstructured{}
+	cv.SetGroupVersionKind(clusterVersionGVK)
+	if err := p.Reader.Get(ctx, client.ObjectKey{Name: "version"}, cv); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting OpenShift cluster version: %w", err)
+	}
+	desired, found, err := unstructured.NestedString(cv.Object, "status", "desired", "version")
+	if err != nil || !found || desired == "" {
+		return nil, fmt.Errorf("OpenShift cluster version not yet reported in ClusterVersion status")
+	}
+	v, err := bsemver.ParseTolerant(desired)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing OpenShift cluster version %q: %w", desired, err)
+	}
+	return &v, nil
+}
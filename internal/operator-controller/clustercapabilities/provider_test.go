@@ -0,0 +1,88 @@
+package clustercapabilities
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bsemver "github.com/blang/semver/v4"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newFakeDiscovery(gitVersion string, resources ...*metav1.APIResourceList) *fakediscovery.FakeDiscovery {
+	return &fakediscovery.FakeDiscovery{
+		Fake:               &clienttesting.Fake{Resources: resources},
+		FakedServerVersion: &version.Info{GitVersion: gitVersion},
+	}
+}
+
+func TestDiscoveryProvider_Get(t *testing.T) {
+	t.Run("discovers Kubernetes version and API groups", func(t *testing.T) {
+		p := &DiscoveryProvider{
+			Discovery: newFakeDiscovery("v1.30.2-gke.100",
+				&metav1.APIResourceList{GroupVersion: "v1"},
+				&metav1.APIResourceList{GroupVersion: "apps/v1"},
+			),
+		}
+		caps, err := p.Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, bsemver.MustParse("1.30.2"), caps.KubernetesVersion)
+		require.Nil(t, caps.OpenShiftVersion)
+		require.True(t, caps.APIGroupVersions.Has(schema.GroupVersion{Version: "v1"}))
+		require.True(t, caps.APIGroupVersions.Has(schema.GroupVersion{Group: "apps", Version: "v1"}))
+	})
+
+	t.Run("discovers OpenShift version", func(t *testing.T) {
+		cv := &unstructured.Unstructured{}
+		cv.SetGroupVersionKind(clusterVersionGVK)
+		cv.SetName("version")
+		require.NoError(t, unstructured.SetNestedField(cv.Object, "4.17.3", "status", "desired", "version"))
+
+		p := &DiscoveryProvider{
+			Discovery: newFakeDiscovery("v1.30.2",
+				&metav1.APIResourceList{GroupVersion: "config.openshift.io/v1"},
+			),
+			Reader: fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(cv).Build(),
+		}
+		caps, err := p.Get(context.Background())
+		require.NoError(t, err)
+		require.NotNil(t, caps.OpenShiftVersion)
+		require.Equal(t, bsemver.MustParse("4.17.3"), *caps.OpenShiftVersion)
+	})
+
+	t.Run("caches capabilities until the refresh interval elapses", func(t *testing.T) {
+		now := time.Now()
+		d := newFakeDiscovery("v1.30.2")
+		p := &DiscoveryProvider{
+			Discovery:       d,
+			RefreshInterval: time.Minute,
+			now:             func() time.Time { return now },
+		}
+		first, err := p.Get(context.Background())
+		require.NoError(t, err)
+
+		d.FakedServerVersion = &version.Info{GitVersion: "v1.31.0"}
+		second, err := p.Get(context.Background())
+		require.NoError(t, err)
+		require.Same(t, first, second)
+
+		now = now.Add(time.Minute)
+		third, err := p.Get(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, bsemver.MustParse("1.31.0"), third.KubernetesVersion)
+	})
+
+	t.Run("returns an error for an unparsable server version", func(t *testing.T) {
+		p := &DiscoveryProvider{Discovery: newFakeDiscovery("not-a-version")}
+		_, err := p.Get(context.Background())
+		require.ErrorContains(t, err, `error parsing server version "not-a-version"`)
+	})
+}
@@ -30,6 +30,7 @@ var ConditionTypes = []string{
 	ocv1.TypePackageDeprecated,
 	ocv1.TypeChannelDeprecated,
 	ocv1.TypeBundleDeprecated,
+	ocv1.TypeIncompatibleBundles,
 	ocv1.TypeProgressing,
 }
 
@@ -41,8 +42,14 @@ var ConditionReasons = []string{
 	ocv1.ReasonFailed,
 	ocv1.ReasonBlocked,
 	ocv1.ReasonInvalidConfiguration,
+	ocv1.ReasonHookFailed,
+	ocv1.ReasonRolledBack,
+	ocv1.ReasonAwaitingApproval,
 	ocv1.ReasonRetrying,
 	ocv1.ReasonAbsent,
 	ocv1.ReasonRollingOut,
 	ocv1.ReasonProgressDeadlineExceeded,
+	ocv1.ReasonSoakFailed,
+	ocv1.ReasonPaused,
+	ocv1.ReasonIncompatibleWithCluster,
 }
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+)
+
+// ReferenceError reports a problem with a Secret or ConfigMap referenced by a
+// ClusterExtension configuration that can only be fixed by the user, such as a
+// missing object or key.
+type ReferenceError struct {
+	msg string
+}
+
+func (e *ReferenceError) Error() string {
+	return e.msg
+}
+
+func referenceErrorf(format string, args ...any) error {
+	return &ReferenceError{msg: fmt.Sprintf(format, args...)}
+}
+
+// HasReferences reports whether the configuration of ext is read from a Secret or ConfigMap.
+func HasReferences(ext *ocv1.ClusterExtension) bool {
+	if ext.Spec.Config == nil {
+		return false
+	}
+	switch ext.Spec.Config.ConfigType {
+	case ocv1.ClusterExtensionConfigTypeSecretRef, ocv1.ClusterExtensionConfigTypeConfigMapRef:
+		return true
+	}
+	return false
+}
+
+// ResolveReferences returns the configuration of ext as a JSON object when it is read
+// from a Secret or ConfigMap in the installation namespace. The inline values of ext, if
+// any, are merged on top of the referenced values.
+//
+// It returns nil if ext does not reference a Secret or ConfigMap. c should be a client of
+// the ServiceAccount of ext. Referenced objects must carry the ocv1.LabelExtensionConfig
+// label, so that only objects meant to configure extensions are read.
+func ResolveReferences(ctx context.Context, c client.Reader, ext *ocv1.ClusterExtension) ([]byte, error) {
+	if ext.Spec.Config == nil {
+		return nil, nil
+	}
+
+	var (
+		kind string
+		ref  *ocv1.ConfigSourceReference
+		obj  client.Object
+	)
+	switch ext.Spec.Config.ConfigType {
+	case ocv1.ClusterExtensionConfigTypeSecretRef:
+		kind, ref, obj = "Secret", ext.Spec.Config.SecretRef, &corev1.Secret{}
+	case ocv1.ClusterExtensionConfigTypeConfigMapRef:
+		kind, ref, obj = "ConfigMap", ext.Spec.Config.ConfigMapRef, &corev1.ConfigMap{}
+	default:
+		return nil, nil
+	}
+	if ref == nil {
+		return nil, referenceErrorf("configType %q requires a reference", ext.Spec.Config.ConfigType)
+	}
+
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ext.Spec.Namespace, Name: ref.Name}, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, referenceErrorf("%s %q not found in namespace %q; it must carry the %q label", kind, ref.Name, ext.Spec.Namespace, ocv1.LabelExtensionConfig)
+		}
+		return nil, fmt.Errorf("error getting %s %q: %w", kind, ref.Name, err)
+	}
+	if _, ok := obj.GetLabels()[ocv1.LabelExtensionConfig]; !ok {
+		return nil, referenceErrorf("%s %q must carry the %q label", kind, ref.Name, ocv1.LabelExtensionConfig)
+	}
+
+	data, ok := referencedData(obj, ref.Key)
+	if !ok {
+		return nil, referenceErrorf("key %q not found in %s %q", ref.Key, kind, ref.Name)
+	}
+	var values map[string]any
+	if err := yaml.Unmarshal(data, &values); err != nil || values == nil {
+		return nil, referenceErrorf("key %q of %s %q must contain a JSON or YAML object", ref.Key, kind, ref.Name)
+	}
+
+	if inline := ext.Spec.Config.Inline; inline != nil {
+		var overrides map[string]any
+		if err := json.Unmarshal(inline.Raw, &overrides); err != nil {
+			return nil, referenceErrorf("inline configuration must be a JSON object: %v", err)
+		}
+		values = mergeValues(values, overrides)
+	}
+	return json.Marshal(values)
+}
+
+func referencedData(obj client.Object, key string) ([]byte, bool) {
+	switch o := obj.(type) {
+	case *corev1.Secret:
+		data, ok := o.Data[key]
+		return data, ok
+	case *corev1.ConfigMap:
+		if data, ok := o.Data[key]; ok {
+			return []byte(data), true
+		}
+		data, ok := o.BinaryData[key]
+		return data, ok
+	}
+	return nil, false
+}
+
+// mergeValues merges overrides into base. Nested objects are merged recursively; any
+// other value in overrides replaces the value in base.
+func mergeValues(base, overrides map[string]any) map[string]any {
+	for k, v := range overrides {
+		if vMap, ok := v.(map[string]any); ok {
+			if baseMap, ok := base[k].(map[string]any); ok {
+				base[k] = mergeValues(baseMap, vMap)
+				continue
+			}
+		}
+		base[k] = v
+	}
+	return base
+}
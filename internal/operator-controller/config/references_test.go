@@ -0,0 +1,154 @@
+package config_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/config"
+)
+
+func TestResolveReferences(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	configLabels := map[string]string{ocv1.LabelExtensionConfig: ""}
+	objects := []runtime.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "license", Namespace: "install-ns", Labels: configLabels},
+			Data: map[string][]byte{
+				"config.yaml": []byte("licenseKey: s3cr3t\nwebhook:\n  token: abc\n  port: 8443\n"),
+				"invalid":     []byte("- not\n- an object\n"),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "unlabeled", Namespace: "install-ns"},
+			Data:       map[string][]byte{"config.yaml": []byte("licenseKey: s3cr3t\n")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "install-ns", Labels: configLabels},
+			Data:       map[string]string{"config.json": `{"replicas": 2}`},
+		},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+
+	newExt := func(cfg *ocv1.ClusterExtensionConfig) *ocv1.ClusterExtension {
+		return &ocv1.ClusterExtension{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ext"},
+			Spec:       ocv1.ClusterExtensionSpec{Namespace: "install-ns", Config: cfg},
+		}
+	}
+
+	for _, tc := range []struct {
+		name          string
+		config        *ocv1.ClusterExtensionConfig
+		expected      string
+		expectedError string
+	}{
+		{
+			name: "no configuration",
+		},
+		{
+			name: "inline configuration is not resolved",
+			config: &ocv1.ClusterExtensionConfig{
+				ConfigType: ocv1.ClusterExtensionConfigTypeInline,
+				Inline:     &apiextensionsv1.JSON{Raw: []byte(`{"replicas": 3}`)},
+			},
+		},
+		{
+			name: "reads configuration from a Secret",
+			config: &ocv1.ClusterExtensionConfig{
+				ConfigType: ocv1.ClusterExtensionConfigTypeSecretRef,
+				SecretRef:  &ocv1.ConfigSourceReference{Name: "license", Key: "config.yaml"},
+			},
+			expected: `{"licenseKey": "s3cr3t", "webhook": {"token": "abc", "port": 8443}}`,
+		},
+		{
+			name: "reads configuration from a ConfigMap",
+			config: &ocv1.ClusterExtensionConfig{
+				ConfigType:   ocv1.ClusterExtensionConfigTypeConfigMapRef,
+				ConfigMapRef: &ocv1.ConfigSourceReference{Name: "settings", Key: "config.json"},
+			},
+			expected: `{"replicas": 2}`,
+		},
+		{
+			name: "merges inline configuration on top of referenced configuration",
+			config: &ocv1.ClusterExtensionConfig{
+				ConfigType: ocv1.ClusterExtensionConfigTypeSecretRef,
+				SecretRef:  &ocv1.ConfigSourceReference{Name: "license", Key: "config.yaml"},
+				Inline:     &apiextensionsv1.JSON{Raw: []byte(`{"replicas": 3, "webhook": {"port": 9443}}`)},
+			},
+			expected: `{"licenseKey": "s3cr3t", "replicas": 3, "webhook": {"token": "abc", "port": 9443}}`,
+		},
+		{
+			name: "missing object",
+			config: &ocv1.ClusterExtensionConfig{
+				ConfigType: ocv1.ClusterExtensionConfigTypeSecretRef,
+				SecretRef:  &ocv1.ConfigSourceReference{Name: "missing", Key: "config.yaml"},
+			},
+			expectedError: `Secret "missing" not found in namespace "install-ns"`,
+		},
+		{
+			name: "unlabeled object",
+			config: &ocv1.ClusterExtensionConfig{
+				ConfigType: ocv1.ClusterExtensionConfigTypeSecretRef,
+				SecretRef:  &ocv1.ConfigSourceReference{Name: "unlabeled", Key: "config.yaml"},
+			},
+			expectedError: `Secret "unlabeled" must carry the "olm.operatorframework.io/extension-config" label`,
+		},
+		{
+			name: "missing key",
+			config: &ocv1.ClusterExtensionConfig{
+				ConfigType:   ocv1.ClusterExtensionConfigTypeConfigMapRef,
+				ConfigMapRef: &ocv1.ConfigSourceReference{Name: "settings", Key: "config.yaml"},
+			},
+			expectedError: `key "config.yaml" not found in ConfigMap "settings"`,
+		},
+		{
+			name: "value is not an object",
+			config: &ocv1.ClusterExtensionConfig{
+				ConfigType: ocv1.ClusterExtensionConfigTypeSecretRef,
+				SecretRef:  &ocv1.ConfigSourceReference{Name: "license", Key: "invalid"},
+			},
+			expectedError: `key "invalid" of Secret "license" must contain a JSON or YAML object`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resolved, err := config.ResolveReferences(context.Background(), cl, newExt(tc.config))
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				var refErr *config.ReferenceError
+				require.ErrorAs(t, err, &refErr)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expected == "" {
+				require.Nil(t, resolved)
+				return
+			}
+			require.JSONEq(t, tc.expected, string(resolved))
+		})
+	}
+}
+
+func TestHasReferences(t *testing.T) {
+	for _, tc := range []struct {
+		config   *ocv1.ClusterExtensionConfig
+		expected bool
+	}{
+		{config: nil, expected: false},
+		{config: &ocv1.ClusterExtensionConfig{ConfigType: ocv1.ClusterExtensionConfigTypeInline}, expected: false},
+		{config: &ocv1.ClusterExtensionConfig{ConfigType: ocv1.ClusterExtensionConfigTypeSecretRef}, expected: true},
+		{config: &ocv1.ClusterExtensionConfig{ConfigType: ocv1.ClusterExtensionConfigTypeConfigMapRef}, expected: true},
+	} {
+		ext := &ocv1.ClusterExtension{Spec: ocv1.ClusterExtensionSpec{Config: tc.config}}
+		require.Equal(t, tc.expected, config.HasReferences(ext))
+	}
+}
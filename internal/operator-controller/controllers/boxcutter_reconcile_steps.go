@@ -25,6 +25,7 @@ import (
 	"slices"
 
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -54,6 +55,7 @@ func (d *BoxcutterRevisionStatesGetter) GetRevisionStates(ctx context.Context, e
 
 	rs := &RevisionStates{}
 	for _, rev := range existingRevisionList.Items {
+		rs.History = append(rs.History, revisionHistoryEntry(&rev))
 		if rev.Spec.LifecycleState == ocv1.ClusterExtensionRevisionLifecycleStateArchived {
 			continue
 		}
@@ -63,13 +65,18 @@ func (d *BoxcutterRevisionStatesGetter) GetRevisionStates(ctx context.Context, e
 		//   the set/get logic a bit better to make it more maintainable and less likely to get out of sync.
 		rm := &RevisionMetadata{
 			RevisionName: rev.Name,
+			Revision:     rev.Spec.Revision,
 			Package:      rev.Annotations[labels.PackageNameKey],
 			Image:        rev.Annotations[labels.BundleReferenceKey],
+			Catalog:      rev.Annotations[labels.CatalogNameKey],
 			Conditions:   rev.Status.Conditions,
 			BundleMetadata: ocv1.BundleMetadata{
 				Name:    rev.Annotations[labels.BundleNameKey],
 				Version: rev.Annotations[labels.BundleVersionKey],
 			},
+			RolledBackFrom: rolledBackFrom(rev.Annotations),
+			AdoptedObjects: rev.Status.AdoptedObjects,
+			DriftDetected:  rev.Status.DriftDetected,
 		}
 
 		if apimeta.IsStatusConditionTrue(rev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeSucceeded) {
@@ -79,9 +86,41 @@ func (d *BoxcutterRevisionStatesGetter) GetRevisionStates(ctx context.Context, e
 		}
 	}
 
+	// Only the latest revision is considered failed, since a newer revision
+	// supersedes any earlier revision that exceeded its progress deadline or failed its soak.
+	if latest := rs.latest(); latest != nil && hasFailedRollout(latest.Conditions) {
+		rs.Failed = latest
+	}
 	return rs, nil
 }
 
+// revisionHistoryEntry summarizes rev. Archived revisions report the outcome of their rollout
+// until they were archived, which replaces the reason of their Progressing condition.
+func revisionHistoryEntry(rev *ocv1.ClusterExtensionRevision) ocv1.RevisionHistoryEntry {
+	entry := ocv1.RevisionHistoryEntry{
+		Revision:      rev.Spec.Revision,
+		BundleName:    rev.Annotations[labels.BundleNameKey],
+		BundleVersion: rev.Annotations[labels.BundleVersionKey],
+		Outcome:       ocv1.RevisionOutcomeRollingOut,
+		CreatedAt:     rev.CreationTimestamp,
+	}
+	progressing := apimeta.FindStatusCondition(rev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeProgressing)
+	switch succeeded := apimeta.FindStatusCondition(rev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeSucceeded); {
+	case succeeded != nil && succeeded.Status == metav1.ConditionTrue:
+		entry.Outcome = ocv1.RevisionOutcomeSucceeded
+		entry.CompletedAt = &succeeded.LastTransitionTime
+	case hasFailedRollout(rev.Status.Conditions):
+		entry.Outcome = ocv1.RevisionOutcomeFailed
+		entry.CompletedAt = &progressing.LastTransitionTime
+	case rev.Spec.LifecycleState == ocv1.ClusterExtensionRevisionLifecycleStateArchived:
+		entry.Outcome = ocv1.RevisionOutcomeAbandoned
+	}
+	if progressing != nil && progressing.Reason == ocv1.ClusterExtensionRevisionReasonArchived {
+		entry.SupersededAt = &progressing.LastTransitionTime
+	}
+	return entry
+}
+
 func MigrateStorage(m StorageMigrator) ReconcileStepFunc {
 	return func(ctx context.Context, state *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
 		objLbls := map[string]string{
@@ -96,22 +135,30 @@ func MigrateStorage(m StorageMigrator) ReconcileStepFunc {
 	}
 }
 
+// MigrateOLMv0Subscription migrates the OLM v0 Subscription named by the
+// ocv1.AnnotationMigrateOLMv0Subscription annotation into the first revision
+// of the ClusterExtension. Migration errors are reported in the Progressing condition,
+// including while the migration waits for the Subscription and its CSV to be removed.
+func MigrateOLMv0Subscription(m StorageMigrator) ReconcileStepFunc {
+	return func(ctx context.Context, state *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
+		if err := m.Migrate(ctx, ext, ownerObjectLabels(ext)); err != nil {
+			err = fmt.Errorf("migrating OLM v0 Subscription: %w", err)
+			setInstalledStatusConditionUnknown(ext, err.Error())
+			setStatusProgressing(ext, err)
+			return nil, err
+		}
+		return nil, nil
+	}
+}
+
 func ApplyBundleWithBoxcutter(apply func(ctx context.Context, contentFS fs.FS, ext *ocv1.ClusterExtension, objectLabels, revisionAnnotations map[string]string) (bool, string, error)) ReconcileStepFunc {
 	return func(ctx context.Context, state *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
 		l := log.FromContext(ctx)
-		revisionAnnotations := map[string]string{
-			labels.BundleNameKey:      state.resolvedRevisionMetadata.Name,
-			labels.PackageNameKey:     state.resolvedRevisionMetadata.Package,
-			labels.BundleVersionKey:   state.resolvedRevisionMetadata.Version,
-			labels.BundleReferenceKey: state.resolvedRevisionMetadata.Image,
-		}
-		objLbls := map[string]string{
-			labels.OwnerKindKey: ocv1.ClusterExtensionKind,
-			labels.OwnerNameKey: ext.GetName(),
-		}
+		revisionAnnotations := bundleRevisionAnnotations(state.resolvedRevisionMetadata)
+		objLbls := ownerObjectLabels(ext)
 
 		l.Info("applying bundle contents")
-		_, _, err := apply(ctx, state.imageFS, ext, objLbls, revisionAnnotations)
+		_, _, err := apply(ctx, state.imageFS, extensionWithResolvedConfig(ext, state), objLbls, revisionAnnotations)
 		if err != nil {
 			// If there was an error applying the resolved bundle,
 			// report the error via the Progressing condition.
@@ -126,6 +173,7 @@ func ApplyBundleWithBoxcutter(apply func(ctx context.Context, contentFS fs.FS, e
 		}
 
 		ext.Status.ActiveRevisions = []ocv1.RevisionStatus{}
+		ext.Status.DriftDetected = nil
 		// Mirror Available/Progressing conditions from the installed revision
 		if i := state.revisionStates.Installed; i != nil {
 			for _, cndType := range []string{ocv1.ClusterExtensionRevisionTypeAvailable, ocv1.ClusterExtensionRevisionTypeProgressing} {
@@ -137,10 +185,11 @@ func ApplyBundleWithBoxcutter(apply func(ctx context.Context, contentFS fs.FS, e
 			ext.Status.Install = &ocv1.ClusterExtensionInstallStatus{
 				Bundle: i.BundleMetadata,
 			}
-			ext.Status.ActiveRevisions = []ocv1.RevisionStatus{{Name: i.RevisionName}}
+			ext.Status.ActiveRevisions = []ocv1.RevisionStatus{{Name: i.RevisionName, AdoptedObjects: i.AdoptedObjects}}
+			ext.Status.DriftDetected = i.DriftDetected
 		}
 		for idx, r := range state.revisionStates.RollingOut {
-			rs := ocv1.RevisionStatus{Name: r.RevisionName}
+			rs := ocv1.RevisionStatus{Name: r.RevisionName, AdoptedObjects: r.AdoptedObjects}
 			for _, cndType := range []string{ocv1.ClusterExtensionRevisionTypeAvailable, ocv1.ClusterExtensionRevisionTypeProgressing} {
 				if cnd := apimeta.FindStatusCondition(r.Conditions, cndType); cnd != nil {
 					cnd.ObservedGeneration = ext.GetGeneration()
@@ -16,7 +16,7 @@ func TestClusterExtensionSourceConfig(t *testing.T) {
 	sourceTypeEmptyErrors := []string{"Invalid value: \"null\"", "Invalid value: null"}
 	sourceTypeMismatchError := "spec.source.sourceType: Unsupported value"
 	sourceConfigInvalidError := "spec.source: Invalid value"
-	// unionField represents the required Catalog or (future) Bundle field required by SourceConfig
+	// unionField represents the Catalog or Helm field required by SourceConfig
 	testCases := []struct {
 		name       string
 		sourceType string
@@ -27,6 +27,9 @@ func TestClusterExtensionSourceConfig(t *testing.T) {
 		{"sourceType is invalid", "Invalid", "Catalog", []string{sourceTypeMismatchError}},
 		{"catalog field does not exist", "Catalog", "", []string{sourceConfigInvalidError}},
 		{"sourceConfig has required fields", "Catalog", "Catalog", []string{}},
+		{"helm field does not exist", "Helm", "", []string{sourceConfigInvalidError}},
+		{"helm field is forbidden for the Catalog sourceType", "Catalog", "Helm", []string{sourceConfigInvalidError}},
+		{"helm sourceConfig has required fields", "Helm", "Helm", []string{}},
 	}
 
 	t.Parallel()
@@ -50,6 +53,21 @@ func TestClusterExtensionSourceConfig(t *testing.T) {
 					},
 				}))
 			}
+			if tc.unionField == "Helm" {
+				err = cl.Create(context.Background(), buildClusterExtension(ocv1.ClusterExtensionSpec{
+					Source: ocv1.SourceConfig{
+						SourceType: tc.sourceType,
+						Helm: &ocv1.HelmSource{
+							Repository: "oci://ghcr.io/example/charts",
+							Chart:      "test-chart",
+						},
+					},
+					Namespace: "default",
+					ServiceAccount: ocv1.ServiceAccountReference{
+						Name: "default",
+					},
+				}))
+			}
 			if tc.unionField == "" {
 				err = cl.Create(context.Background(), buildClusterExtension(ocv1.ClusterExtensionSpec{
 					Source: ocv1.SourceConfig{
@@ -398,7 +416,7 @@ func TestClusterExtensionAdmissionServiceAccount(t *testing.T) {
 }
 
 func TestClusterExtensionAdmissionInstall(t *testing.T) {
-	oneOfErrMsg := "at least one of [preflight] are required when install is specified"
+	oneOfErrMsg := "at least one of [preflight, historyLimit, rollback, upgradeApproval, patches, namespace, probes, rollout, collisionProtection, uninstallPolicy, ignoreDifferences] are required when install is specified"
 
 	testCases := []struct {
 		name          string
@@ -421,6 +439,206 @@ func TestClusterExtensionAdmissionInstall(t *testing.T) {
 			},
 			errMsg: "",
 		},
+		{
+			name:          "install specified, historyLimit configured",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{HistoryLimit: 20},
+			errMsg:        "",
+		},
+		{
+			name:          "install specified, historyLimit too large",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{HistoryLimit: 101},
+			errMsg:        "should be less than or equal to 100",
+		},
+		{
+			name: "install specified, rollback configured",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				Rollback: &ocv1.ClusterExtensionRollback{Revision: 2, ConstraintPolicy: ocv1.RollbackConstraintPolicyIgnore},
+			},
+			errMsg: "",
+		},
+		{
+			name: "install specified, rollback to invalid revision",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				Rollback: &ocv1.ClusterExtensionRollback{Revision: 0},
+			},
+			errMsg: "should be greater than or equal to 1",
+		},
+		{
+			name:          "install specified, upgradeApproval configured",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{UpgradeApproval: ocv1.UpgradeApprovalPolicyManual},
+			errMsg:        "",
+		},
+		{
+			name: "install specified, patches configured",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				Patches: []ocv1.ClusterExtensionPatch{{
+					Target: ocv1.PatchTarget{Kind: "Service"},
+					Type:   ocv1.PatchTypeStrategicMerge,
+					Patch:  `{"spec": {"type": "NodePort"}}`,
+				}},
+			},
+			errMsg: "",
+		},
+		{
+			name: "install specified, patch of unknown type",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				Patches: []ocv1.ClusterExtensionPatch{{
+					Type:  "Kustomize",
+					Patch: `{"spec": {"type": "NodePort"}}`,
+				}},
+			},
+			errMsg: "Unsupported value: \"Kustomize\"",
+		},
+		{
+			name: "install specified, namespace configured",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				Namespace: &ocv1.ClusterExtensionInstallNamespace{
+					Labels: map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+				},
+			},
+			errMsg: "",
+		},
+		{
+			name: "install specified, namespace with reserved labels",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				Namespace: &ocv1.ClusterExtensionInstallNamespace{
+					Labels: map[string]string{"olm.operatorframework.io/owner-name": "other"},
+				},
+			},
+			errMsg: "labels with the olm.operatorframework.io/ prefix are reserved",
+		},
+		{
+			name: "install specified, probes configured",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				Probes: []ocv1.ClusterExtensionProbe{{
+					Selector: ocv1.ProbeSelector{Group: "batch", Kind: "Job"},
+					Assertions: []ocv1.ObjectProbe{{
+						Type:      ocv1.ObjectProbeTypeCondition,
+						Condition: &ocv1.ConditionProbe{Type: "Complete", Status: "True"},
+					}},
+				}},
+			},
+			errMsg: "",
+		},
+		{
+			name: "install specified, probe without the configuration of its type",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				Probes: []ocv1.ClusterExtensionProbe{{
+					Selector: ocv1.ProbeSelector{Group: "batch", Kind: "Job"},
+					Assertions: []ocv1.ObjectProbe{{
+						Type:      ocv1.ObjectProbeTypeCEL,
+						Condition: &ocv1.ConditionProbe{Type: "Complete", Status: "True"},
+					}},
+				}},
+			},
+			errMsg: "cel is required when type is CEL, and forbidden otherwise",
+		},
+		{
+			name: "install specified, canary rollout configured",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				Rollout: &ocv1.ClusterExtensionRollout{
+					Strategy: ocv1.RolloutStrategyCanary,
+					Canary:   &ocv1.CanaryRollout{SoakSeconds: 300, MaxContainerRestarts: 1},
+				},
+			},
+			errMsg: "",
+		},
+		{
+			name: "install specified, canary rollout without canary configuration",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				Rollout: &ocv1.ClusterExtensionRollout{Strategy: ocv1.RolloutStrategyCanary},
+			},
+			errMsg: "canary is required when strategy is Canary, and forbidden otherwise",
+		},
+		{
+			name: "install specified, canary configuration with another strategy",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				Rollout: &ocv1.ClusterExtensionRollout{
+					Strategy: ocv1.RolloutStrategyAllAtOnce,
+					Canary:   &ocv1.CanaryRollout{SoakSeconds: 300},
+				},
+			},
+			errMsg: "canary is required when strategy is Canary, and forbidden otherwise",
+		},
+		{
+			name: "install specified, soak shorter than the minimum",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				Rollout: &ocv1.ClusterExtensionRollout{
+					Strategy: ocv1.RolloutStrategyCanary,
+					Canary:   &ocv1.CanaryRollout{SoakSeconds: 5},
+				},
+			},
+			errMsg: "spec.install.rollout.canary.soakSeconds",
+		},
+		{
+			name: "install specified, collision protection configured",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				CollisionProtection: &ocv1.ClusterExtensionCollisionProtection{
+					Policy: ocv1.CollisionProtectionPrevent,
+					Overrides: []ocv1.CollisionProtectionOverride{{
+						Selector: ocv1.ProbeSelector{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"},
+						Policy:   ocv1.CollisionProtectionIfNoController,
+					}},
+				},
+			},
+			errMsg: "",
+		},
+		{
+			name: "install specified, collision protection override with unknown policy",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				CollisionProtection: &ocv1.ClusterExtensionCollisionProtection{
+					Overrides: []ocv1.CollisionProtectionOverride{{
+						Selector: ocv1.ProbeSelector{Kind: "ServiceAccount"},
+						Policy:   "Always",
+					}},
+				},
+			},
+			errMsg: "spec.install.collisionProtection.overrides[0].policy",
+		},
+		{
+			name: "install specified, uninstall policy configured",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				UninstallPolicy: ocv1.UninstallPolicyOrphanCRDs,
+			},
+			errMsg: "",
+		},
+		{
+			name: "install specified, unknown uninstall policy",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				UninstallPolicy: "OrphanEverything",
+			},
+			errMsg: "spec.install.uninstallPolicy",
+		},
+		{
+			name: "install specified, ignore differences configured",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				IgnoreDifferences: []ocv1.IgnoreDifference{{
+					Selector:     ocv1.ProbeSelector{Group: "apps", Kind: "Deployment", Name: "argocd-operator"},
+					JSONPointers: []string{"/spec/replicas", "/metadata/annotations/example.com~1injected"},
+				}},
+			},
+			errMsg: "",
+		},
+		{
+			name: "install specified, ignore differences with a field path",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				IgnoreDifferences: []ocv1.IgnoreDifference{{
+					Selector:     ocv1.ProbeSelector{Group: "apps", Kind: "Deployment"},
+					JSONPointers: []string{"spec.replicas"},
+				}},
+			},
+			errMsg: "jsonPointers must start with /",
+		},
+		{
+			name: "install specified, ignore differences of OLM labels",
+			installConfig: &ocv1.ClusterExtensionInstallConfig{
+				IgnoreDifferences: []ocv1.IgnoreDifference{{
+					Selector:     ocv1.ProbeSelector{Group: "apps", Kind: "Deployment"},
+					JSONPointers: []string{"/metadata/labels/olm.operatorframework.io~1owner-name"},
+				}},
+			},
+			errMsg: "jsonPointers must not select the apiVersion, kind, name, namespace or OLM labels of objects",
+		},
 		{
 			name:          "install not specified",
 			installConfig: nil,
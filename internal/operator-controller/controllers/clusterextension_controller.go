@@ -22,11 +22,14 @@ import (
 	"fmt"
 	"io/fs"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -57,12 +60,16 @@ import (
 const (
 	ClusterExtensionCleanupUnpackCacheFinalizer         = "olm.operatorframework.io/cleanup-unpack-cache"
 	ClusterExtensionCleanupContentManagerCacheFinalizer = "olm.operatorframework.io/cleanup-contentmanager-cache"
+	ClusterExtensionUninstallPolicyFinalizer            = "olm.operatorframework.io/uninstall-policy"
 )
 
 type reconcileState struct {
 	revisionStates           *RevisionStates
 	resolvedRevisionMetadata *RevisionMetadata
 	imageFS                  fs.FS
+	// resolvedConfig holds the configuration read from a Secret or ConfigMap
+	// referenced by the ClusterExtension, merged with its inline configuration.
+	resolvedConfig []byte
 }
 
 // ReconcileStepFunc represents a single step in the ClusterExtension reconciliation process.
@@ -106,6 +113,10 @@ type StorageMigrator interface {
 	Migrate(context.Context, *ocv1.ClusterExtension, map[string]string) error
 }
 
+type ReleaseAdopter interface {
+	Adopt(context.Context, *ocv1.ClusterExtension) error
+}
+
 type Applier interface {
 	// Apply applies the content in the provided fs.FS using the configuration of the provided ClusterExtension.
 	// It also takes in a map[string]string to be applied to all applied resources as labels and another
@@ -113,6 +124,42 @@ type Applier interface {
 	Apply(context.Context, fs.FS, *ocv1.ClusterExtension, map[string]string, map[string]string) (bool, string, error)
 }
 
+// UpgradePreviewer previews the changes to the installed objects that applying the content in the
+// provided fs.FS makes. It takes the same arguments as Applier.Apply, and returns nil when nothing
+// is installed yet or when no object changes.
+type UpgradePreviewer interface {
+	PreviewUpgrade(context.Context, fs.FS, *ocv1.ClusterExtension, map[string]string, map[string]string) (*ocv1.ClusterExtensionUpgradePreview, error)
+}
+
+// Rollbacker rolls the installed content of a ClusterExtension back to a previous revision.
+type Rollbacker interface {
+	// RevisionBundle returns the labels describing the bundle installed by the given revision.
+	RevisionBundle(ctx context.Context, ext *ocv1.ClusterExtension, revision int64) (map[string]string, error)
+	// Rollback installs the content of the given revision as a new revision, labeling all
+	// resources with objectLabels and recording storageLabels on the new revision. Like Apply,
+	// it reports whether the rollout succeeded.
+	Rollback(ctx context.Context, ext *ocv1.ClusterExtension, revision int64, objectLabels, storageLabels map[string]string) (bool, string, error)
+}
+
+// Pauser stops and resumes the reconciliation of the content installed for ClusterExtensions,
+// including the drift detection of the installed objects.
+type Pauser interface {
+	Pause(context.Context, *ocv1.ClusterExtension) error
+	Resume(context.Context, *ocv1.ClusterExtension) error
+}
+
+// DriftReporter reports the installed objects of ClusterExtensions whose changes on the cluster
+// were reverted when the content was last applied.
+type DriftReporter interface {
+	TakeRevertedDrift(*ocv1.ClusterExtension) []ocv1.ObjectReference
+}
+
+// HookOperationTracker reports whether the hooks of the installed content of ClusterExtensions
+// are running in the background.
+type HookOperationTracker interface {
+	HooksRunning(*ocv1.ClusterExtension) bool
+}
+
 type RevisionStatesGetter interface {
 	GetRevisionStates(ctx context.Context, ext *ocv1.ClusterExtension) (*RevisionStates, error)
 }
@@ -423,6 +470,59 @@ func WithOwns(obj client.Object) ControllerBuilderOption {
 	}
 }
 
+// WithExtensionPolicyWatch re-queues every ClusterExtension whenever an ExtensionPolicy changes,
+// so that newly violating ClusterExtensions are blocked and newly compliant ones resume.
+func WithExtensionPolicyWatch(c client.Reader, logger logr.Logger) ControllerBuilderOption {
+	return func(builder *ctrl.Builder) {
+		builder.Watches(&ocv1.ExtensionPolicy{}, crhandler.EnqueueRequestsFromMapFunc(allClusterExtensionRequests(c, logger)))
+	}
+}
+
+// WithConfigSourceWatch re-queues the ClusterExtensions whose configuration references a
+// Secret or ConfigMap whenever that object changes. Only objects labeled with
+// ocv1.LabelExtensionConfig are watched. The ClusterExtensions are looked up with the
+// ConfigSourceIndexKey field index, see SetupConfigSourceIndex.
+func WithConfigSourceWatch(c client.Reader, logger logr.Logger) ControllerBuilderOption {
+	return func(builder *ctrl.Builder) {
+		builder.Watches(&corev1.Secret{}, crhandler.EnqueueRequestsFromMapFunc(clusterExtensionRequestsForConfigSource(c, logger, ocv1.ClusterExtensionConfigTypeSecretRef)))
+		builder.Watches(&corev1.ConfigMap{}, crhandler.EnqueueRequestsFromMapFunc(clusterExtensionRequestsForConfigSource(c, logger, ocv1.ClusterExtensionConfigTypeConfigMapRef)))
+	}
+}
+
+// ConfigSourceIndexKey is the field index of ClusterExtensions by the Secret or ConfigMap
+// their configuration is read from.
+const ConfigSourceIndexKey = ".spec.config.sourceRef"
+
+// SetupConfigSourceIndex registers the ConfigSourceIndexKey field index used by
+// WithConfigSourceWatch.
+func SetupConfigSourceIndex(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &ocv1.ClusterExtension{}, ConfigSourceIndexKey, indexConfigSource)
+}
+
+// indexConfigSource returns the ConfigSourceIndexKey value of the Secret or ConfigMap
+// the configuration of a ClusterExtension is read from, if any.
+func indexConfigSource(obj client.Object) []string {
+	ext, ok := obj.(*ocv1.ClusterExtension)
+	if !ok || ext.Spec.Config == nil {
+		return nil
+	}
+	var ref *ocv1.ConfigSourceReference
+	switch ext.Spec.Config.ConfigType {
+	case ocv1.ClusterExtensionConfigTypeSecretRef:
+		ref = ext.Spec.Config.SecretRef
+	case ocv1.ClusterExtensionConfigTypeConfigMapRef:
+		ref = ext.Spec.Config.ConfigMapRef
+	}
+	if ref == nil {
+		return nil
+	}
+	return []string{configSourceIndexValue(ext.Spec.Config.ConfigType, ext.Spec.Namespace, ref.Name)}
+}
+
+func configSourceIndexValue(configType ocv1.ClusterExtensionConfigType, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", configType, namespace, name)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClusterExtensionReconciler) SetupWithManager(mgr ctrl.Manager, opts ...ControllerBuilderOption) (crcontroller.Controller, error) {
 	ctrlBuilder := ctrl.NewControllerManagedBy(mgr).
@@ -474,13 +574,39 @@ func wrapErrorWithResolutionInfo(resolved ocv1.BundleMetadata, err error) error
 
 // Generate reconcile requests for all cluster extensions affected by a catalog change
 func clusterExtensionRequestsForCatalog(c client.Reader, logger logr.Logger) crhandler.MapFunc {
+	// no way of associating an extension to a catalog so create reconcile requests for everything
+	return allClusterExtensionRequests(c, logger)
+}
+
+// Generate reconcile requests for the cluster extensions whose configuration references obj
+func clusterExtensionRequestsForConfigSource(c client.Reader, logger logr.Logger, configType ocv1.ClusterExtensionConfigType) crhandler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		if _, ok := obj.GetLabels()[ocv1.LabelExtensionConfig]; !ok {
+			return nil
+		}
+		clusterExtensions := ocv1.ClusterExtensionList{}
+		if err := c.List(ctx, &clusterExtensions, client.MatchingFields{
+			ConfigSourceIndexKey: configSourceIndexValue(configType, obj.GetNamespace(), obj.GetName()),
+		}); err != nil {
+			logger.Error(err, "unable to enqueue cluster extensions")
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, ext := range clusterExtensions.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ext.GetName()}})
+		}
+		return requests
+	}
+}
+
+// Generate reconcile requests for all cluster extensions
+func allClusterExtensionRequests(c client.Reader, logger logr.Logger) crhandler.MapFunc {
 	return func(ctx context.Context, _ client.Object) []reconcile.Request {
-		// no way of associating an extension to a catalog so create reconcile requests for everything
 		clusterExtensions := metav1.PartialObjectMetadataList{}
 		clusterExtensions.SetGroupVersionKind(ocv1.GroupVersion.WithKind("ClusterExtensionList"))
 		err := c.List(ctx, &clusterExtensions)
 		if err != nil {
-			logger.Error(err, "unable to enqueue cluster extensions for catalog reconcile")
+			logger.Error(err, "unable to enqueue cluster extensions")
 			return nil
 		}
 		var requests []reconcile.Request
@@ -498,15 +624,52 @@ func clusterExtensionRequestsForCatalog(c client.Reader, logger logr.Logger) crh
 
 type RevisionMetadata struct {
 	RevisionName string
-	Package      string
-	Image        string
+	// Revision is the number of the Helm release revision or ClusterExtensionRevision.
+	Revision int64
+	Package  string
+	Image    string
+	Catalog  string
 	ocv1.BundleMetadata
 	Conditions []metav1.Condition
+	// RolledBackFrom is set on revisions rolling back a failed rollout.
+	RolledBackFrom *FailedRollout
+	// AdoptedObjects are the existing objects adopted by a ClusterExtensionRevision.
+	AdoptedObjects []ocv1.AdoptedObject
+	// DriftDetected reports the changes reverted by a ClusterExtensionRevision.
+	DriftDetected *ocv1.DriftDetected
+}
+
+// FailedRollout identifies a revision that failed to roll out.
+type FailedRollout struct {
+	Revision int64
+	Bundle   string
+}
+
+// rolledBackFrom returns the failed rollout recorded in the storage labels of a revision.
+func rolledBackFrom(storageLabels map[string]string) *FailedRollout {
+	bundleName, ok := storageLabels[labels.FailedBundleKey]
+	if !ok {
+		return nil
+	}
+	revision, _ := strconv.ParseInt(storageLabels[labels.FailedRevisionKey], 10, 64)
+	return &FailedRollout{Revision: revision, Bundle: bundleName}
 }
 
 type RevisionStates struct {
 	Installed  *RevisionMetadata
 	RollingOut []*RevisionMetadata
+	// Failed is the latest revision when it failed to roll out.
+	Failed *RevisionMetadata
+	// History summarizes the retained revisions, oldest first.
+	History []ocv1.RevisionHistoryEntry
+}
+
+// latest returns the most recent revision, or nil when nothing is installed.
+func (rs *RevisionStates) latest() *RevisionMetadata {
+	if n := len(rs.RollingOut); n > 0 {
+		return rs.RollingOut[n-1]
+	}
+	return rs.Installed
 }
 
 type HelmRevisionStatesGetter struct {
@@ -532,16 +695,75 @@ func (d *HelmRevisionStatesGetter) GetRevisionStates(ctx context.Context, ext *o
 	// But we need to look for the most-recent _Deployed_ release
 	for _, rel := range relhis {
 		if rel.Info != nil && rel.Info.Status == release.StatusDeployed {
-			rs.Installed = &RevisionMetadata{
-				Package: rel.Labels[labels.PackageNameKey],
-				Image:   rel.Labels[labels.BundleReferenceKey],
-				BundleMetadata: ocv1.BundleMetadata{
-					Name:    rel.Labels[labels.BundleNameKey],
-					Version: rel.Labels[labels.BundleVersionKey],
-				},
-			}
+			rs.Installed = helmRevisionMetadata(rel)
 			break
 		}
 	}
+	// A Helm release is deployed once its objects are applied, so only upgrades that fail
+	// outright are detected. Unlike ClusterExtensionRevisions, releases are not tracked until
+	// their objects become available, and progressDeadlineMinutes doesn't apply to them.
+	if latest := relhis[0]; latest.Info != nil && latest.Info.Status == release.StatusFailed {
+		rs.Failed = helmRevisionMetadata(latest)
+	}
+	rs.History = helmRevisionHistory(relhis)
 	return rs, nil
 }
+
+// helmRevisionHistory summarizes the release history relhis, which is ordered newest first,
+// oldest first. Releases are superseded when a newer release is deployed.
+//
+// Helm records when each release was created in LastDeployed, while FirstDeployed is the
+// creation of the first release of the history. Helm doesn't record when releases complete,
+// so they are reported as completed when they were created.
+func helmRevisionHistory(relhis []*release.Release) []ocv1.RevisionHistoryEntry {
+	history := make([]ocv1.RevisionHistoryEntry, 0, len(relhis))
+	var supersededAt *metav1.Time
+	for _, rel := range relhis {
+		if rel.Info == nil {
+			continue
+		}
+		entry := ocv1.RevisionHistoryEntry{
+			Revision:      int64(rel.Version),
+			BundleName:    rel.Labels[labels.BundleNameKey],
+			BundleVersion: rel.Labels[labels.BundleVersionKey],
+			CreatedAt:     *statusTime(rel.Info.LastDeployed.Time),
+		}
+		switch rel.Info.Status {
+		case release.StatusDeployed, release.StatusSuperseded:
+			entry.Outcome = ocv1.RevisionOutcomeSucceeded
+			entry.CompletedAt = statusTime(rel.Info.LastDeployed.Time)
+			entry.SupersededAt = supersededAt
+			supersededAt = entry.CompletedAt
+		case release.StatusFailed:
+			entry.Outcome = ocv1.RevisionOutcomeFailed
+			entry.CompletedAt = statusTime(rel.Info.LastDeployed.Time)
+		case release.StatusPendingInstall, release.StatusPendingUpgrade, release.StatusPendingRollback:
+			entry.Outcome = ocv1.RevisionOutcomeRollingOut
+		default:
+			continue
+		}
+		history = append(history, entry)
+	}
+	slices.Reverse(history)
+	return history
+}
+
+// statusTime returns t with the precision of the times of the status, so that the history
+// isn't updated by every reconcile.
+func statusTime(t time.Time) *metav1.Time {
+	return &metav1.Time{Time: t.Truncate(time.Second)}
+}
+
+func helmRevisionMetadata(rel *release.Release) *RevisionMetadata {
+	return &RevisionMetadata{
+		Revision: int64(rel.Version),
+		Package:  rel.Labels[labels.PackageNameKey],
+		Image:    rel.Labels[labels.BundleReferenceKey],
+		Catalog:  rel.Labels[labels.CatalogNameKey],
+		BundleMetadata: ocv1.BundleMetadata{
+			Name:    rel.Labels[labels.BundleNameKey],
+			Version: rel.Labels[labels.BundleVersionKey],
+		},
+		RolledBackFrom: rolledBackFrom(rel.Labels),
+	}
+}
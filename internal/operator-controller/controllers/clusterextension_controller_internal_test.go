@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+)
+
+func Test_clusterExtensionRequestsForConfigSource(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	require.NoError(t, ocv1.AddToScheme(testScheme))
+
+	newExt := func(name, namespace string, config *ocv1.ClusterExtensionConfig) *ocv1.ClusterExtension {
+		return &ocv1.ClusterExtension{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: ocv1.ClusterExtensionSpec{
+				Namespace: namespace,
+				Config:    config,
+			},
+		}
+	}
+	secretRef := func(name string) *ocv1.ClusterExtensionConfig {
+		return &ocv1.ClusterExtensionConfig{
+			ConfigType: ocv1.ClusterExtensionConfigTypeSecretRef,
+			SecretRef:  &ocv1.ConfigSourceReference{Name: name},
+		}
+	}
+	configMapRef := func(name string) *ocv1.ClusterExtensionConfig {
+		return &ocv1.ClusterExtensionConfig{
+			ConfigType:   ocv1.ClusterExtensionConfigTypeConfigMapRef,
+			ConfigMapRef: &ocv1.ConfigSourceReference{Name: name},
+		}
+	}
+	labeled := metav1.ObjectMeta{Namespace: "ns-a", Name: "config", Labels: map[string]string{ocv1.LabelExtensionConfig: ""}}
+
+	for _, tc := range []struct {
+		name             string
+		configType       ocv1.ClusterExtensionConfigType
+		obj              client.Object
+		expectedRequests []reconcile.Request
+	}{
+		{
+			name:       "enqueues the extensions referencing the Secret in their namespace",
+			configType: ocv1.ClusterExtensionConfigTypeSecretRef,
+			obj:        &corev1.Secret{ObjectMeta: labeled},
+			expectedRequests: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Name: "secret-a"}},
+				{NamespacedName: types.NamespacedName{Name: "secret-a-too"}},
+			},
+		},
+		{
+			name:       "enqueues the extensions referencing the ConfigMap in their namespace",
+			configType: ocv1.ClusterExtensionConfigTypeConfigMapRef,
+			obj:        &corev1.ConfigMap{ObjectMeta: labeled},
+			expectedRequests: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Name: "configmap-a"}},
+			},
+		},
+		{
+			name:       "ignores references from other namespaces",
+			configType: ocv1.ClusterExtensionConfigTypeSecretRef,
+			obj:        &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-c", Name: "config", Labels: labeled.Labels}},
+		},
+		{
+			name:       "ignores references to other names",
+			configType: ocv1.ClusterExtensionConfigTypeSecretRef,
+			obj:        &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-a", Name: "other", Labels: labeled.Labels}},
+		},
+		{
+			name:       "ignores unlabeled objects",
+			configType: ocv1.ClusterExtensionConfigTypeSecretRef,
+			obj:        &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-a", Name: "config"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			testClient := fake.NewClientBuilder().
+				WithScheme(testScheme).
+				WithIndex(&ocv1.ClusterExtension{}, ConfigSourceIndexKey, indexConfigSource).
+				WithObjects(
+					newExt("secret-a", "ns-a", secretRef("config")),
+					newExt("secret-a-too", "ns-a", secretRef("config")),
+					newExt("secret-b", "ns-b", secretRef("config")),
+					newExt("configmap-a", "ns-a", configMapRef("config")),
+					newExt("inline-a", "ns-a", &ocv1.ClusterExtensionConfig{ConfigType: ocv1.ClusterExtensionConfigTypeInline}),
+					newExt("unconfigured-a", "ns-a", nil),
+				).
+				Build()
+
+			requests := clusterExtensionRequestsForConfigSource(testClient, logr.Discard(), tc.configType)(context.Background(), tc.obj)
+			assert.ElementsMatch(t, tc.expectedRequests, requests)
+		})
+	}
+}
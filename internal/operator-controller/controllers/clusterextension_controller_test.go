@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"testing"
 	"testing/fstest"
+	"time"
 
 	bsemver "github.com/blang/semver/v4"
 	"github.com/google/go-cmp/cmp"
@@ -15,6 +17,9 @@ import (
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/storage/driver"
+	helmtime "helm.sh/helm/v3/pkg/time"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,10 +34,12 @@ import (
 	"github.com/operator-framework/operator-registry/alpha/declcfg"
 
 	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/action"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/authentication"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/bundle"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/conditionsets"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/controllers"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/extensionpolicy"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/finalizers"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
@@ -823,6 +830,923 @@ func TestClusterExtensionServiceAccountNotFound(t *testing.T) {
 	require.NoError(t, cl.DeleteAllOf(ctx, &ocv1.ClusterExtension{}))
 }
 
+func TestClusterExtensionBlockedByExtensionPolicy(t *testing.T) {
+	cl, reconciler := newClientAndReconciler(t, func(d *deps) {
+		d.PolicyChecker = &extensionpolicy.Checker{Reader: newClient(t)}
+		d.Resolver = resolve.Func(func(ctx context.Context, ext *ocv1.ClusterExtension, installedBundle *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
+			t.Fatal("resolution must not run for a ClusterExtension that violates an ExtensionPolicy")
+			return nil, nil, nil, nil
+		})
+	})
+
+	ctx := context.Background()
+	extKey := types.NamespacedName{Name: fmt.Sprintf("cluster-extension-test-%s", rand.String(8))}
+
+	t.Log("Given an ExtensionPolicy that only allows a different package")
+	policy := &ocv1.ExtensionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("extension-policy-test-%s", rand.String(8))},
+		Spec: ocv1.ExtensionPolicySpec{
+			AllowedPackages: []string{"allowed-package"},
+		},
+	}
+	require.NoError(t, cl.Create(ctx, policy))
+
+	t.Log("And a cluster extension for a disallowed package")
+	clusterExtension := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: extKey.Name},
+		Spec: ocv1.ClusterExtensionSpec{
+			Source: ocv1.SourceConfig{
+				SourceType: "Catalog",
+				Catalog: &ocv1.CatalogFilter{
+					PackageName: "test-package",
+				},
+			},
+			Namespace: "default",
+			ServiceAccount: ocv1.ServiceAccountReference{
+				Name: "default",
+			},
+		},
+	}
+	require.NoError(t, cl.Create(ctx, clusterExtension))
+
+	t.Log("When reconciling the cluster extension")
+	res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: extKey})
+	require.Equal(t, ctrl.Result{}, res)
+	require.ErrorIs(t, err, reconcile.TerminalError(nil))
+	require.ErrorContains(t, err, `package "test-package" is not in allowed packages [allowed-package]`)
+
+	t.Log("By fetching updated cluster extension after reconcile")
+	require.NoError(t, cl.Get(ctx, extKey, clusterExtension))
+
+	t.Log("By checking the status conditions")
+	progressingCond := apimeta.FindStatusCondition(clusterExtension.Status.Conditions, ocv1.TypeProgressing)
+	require.NotNil(t, progressingCond)
+	require.Equal(t, metav1.ConditionFalse, progressingCond.Status)
+	require.Equal(t, ocv1.ReasonBlocked, progressingCond.Reason)
+	require.Contains(t, progressingCond.Message, fmt.Sprintf("policy %q", policy.Name))
+
+	installedCond := apimeta.FindStatusCondition(clusterExtension.Status.Conditions, ocv1.TypeInstalled)
+	require.NotNil(t, installedCond)
+	require.Equal(t, metav1.ConditionFalse, installedCond.Status)
+
+	verifyInvariants(ctx, t, reconciler.Client, clusterExtension)
+	require.NoError(t, cl.DeleteAllOf(ctx, &ocv1.ClusterExtension{}))
+	require.NoError(t, cl.DeleteAllOf(ctx, &ocv1.ExtensionPolicy{}))
+}
+
+// deprecatedChannelsResolver is a resolver that reports a fixed set of
+// deprecated channels.
+type deprecatedChannelsResolver struct {
+	resolve.Func
+	deprecated []resolve.DeprecatedChannel
+}
+
+func (r deprecatedChannelsResolver) ResolveWithCatalog(ctx context.Context, ext *ocv1.ClusterExtension, installedBundle *ocv1.BundleMetadata, _ string) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, *resolve.CatalogResolution, error) {
+	resolvedBundle, resolvedBundleVersion, deprecation, err := r.Func(ctx, ext, installedBundle)
+	return resolvedBundle, resolvedBundleVersion, deprecation, &resolve.CatalogResolution{DeprecatedChannels: r.deprecated}, err
+}
+
+func TestClusterExtensionChannelDeprecationPolicyMigrate(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.ChannelDeprecationPolicy)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.ChannelDeprecationPolicy)))
+	})
+	cl, reconciler := newClientAndReconciler(t, func(d *deps) {
+		d.Resolver = deprecatedChannelsResolver{
+			Func: func(ctx context.Context, ext *ocv1.ClusterExtension, installedBundle *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
+				return nil, nil, &declcfg.Deprecation{
+					Entries: []declcfg.DeprecationEntry{{
+						Reference: declcfg.PackageScopedReference{Schema: declcfg.SchemaChannel, Name: "stable"},
+						Message:   "channel stable is deprecated",
+					}},
+				}, errors.New("no bundles found")
+			},
+			deprecated: []resolve.DeprecatedChannel{{Name: "stable", Successor: "stable-v2"}},
+		}
+	})
+
+	ctx := context.Background()
+	extKey := types.NamespacedName{Name: fmt.Sprintf("cluster-extension-test-%s", rand.String(8))}
+
+	t.Log("Given a cluster extension that requests a deprecated channel with the Migrate policy")
+	clusterExtension := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: extKey.Name},
+		Spec: ocv1.ClusterExtensionSpec{
+			Source: ocv1.SourceConfig{
+				SourceType: "Catalog",
+				Catalog: &ocv1.CatalogFilter{
+					PackageName:              "prometheus",
+					Channels:                 []string{"stable", "fast"},
+					ChannelDeprecationPolicy: ocv1.ChannelDeprecationPolicyMigrate,
+				},
+			},
+			Namespace:      "default",
+			ServiceAccount: ocv1.ServiceAccountReference{Name: "default"},
+		},
+	}
+	require.NoError(t, cl.Create(ctx, clusterExtension))
+
+	t.Log("When reconciling the cluster extension")
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: extKey})
+	require.Error(t, err)
+
+	t.Log("It leaves the spec untouched and reports the migration")
+	require.NoError(t, cl.Get(ctx, extKey, clusterExtension))
+	require.Equal(t, []string{"stable", "fast"}, clusterExtension.Spec.Source.Catalog.Channels)
+	channelCond := apimeta.FindStatusCondition(clusterExtension.Status.Conditions, ocv1.TypeChannelDeprecated)
+	require.NotNil(t, channelCond)
+	require.Equal(t, metav1.ConditionTrue, channelCond.Status)
+	require.Equal(t, "channel stable is deprecated\nchannel \"stable\" migrated to successor channel \"stable-v2\"", channelCond.Message)
+
+	verifyInvariants(ctx, t, reconciler.Client, clusterExtension)
+	require.NoError(t, cl.DeleteAllOf(ctx, &ocv1.ClusterExtension{}))
+}
+
+func TestClusterExtensionChannelDeprecationPolicyBlock(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.ChannelDeprecationPolicy)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.ChannelDeprecationPolicy)))
+	})
+	cl, reconciler := newClientAndReconciler(t, func(d *deps) {
+		d.Resolver = deprecatedChannelsResolver{
+			Func: func(ctx context.Context, ext *ocv1.ClusterExtension, installedBundle *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
+				v := bundle.VersionRelease{Version: bsemver.MustParse("1.1.0")}
+				return &declcfg.Bundle{
+					Name:    "prometheus.v1.1.0",
+					Package: "prometheus",
+					Image:   "quay.io/operatorhubio/prometheus@fake1.1.0",
+				}, &v, nil, nil
+			},
+			deprecated: []resolve.DeprecatedChannel{{Name: "stable", Successor: "stable-v2"}},
+		}
+		d.RevisionStatesGetter = &MockRevisionStatesGetter{
+			RevisionStates: &controllers.RevisionStates{
+				Installed: &controllers.RevisionMetadata{
+					BundleMetadata: ocv1.BundleMetadata{Name: "prometheus.v1.0.0", Version: "1.0.0"},
+					Image:          "quay.io/operatorhubio/prometheus@fake1.0.0",
+				},
+			},
+		}
+	})
+
+	ctx := context.Background()
+	extKey := types.NamespacedName{Name: fmt.Sprintf("cluster-extension-test-%s", rand.String(8))}
+
+	t.Log("Given an installed cluster extension that requests a deprecated channel with the Block policy")
+	clusterExtension := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: extKey.Name},
+		Spec: ocv1.ClusterExtensionSpec{
+			Source: ocv1.SourceConfig{
+				SourceType: "Catalog",
+				Catalog: &ocv1.CatalogFilter{
+					PackageName:              "prometheus",
+					Channels:                 []string{"stable"},
+					ChannelDeprecationPolicy: ocv1.ChannelDeprecationPolicyBlock,
+				},
+			},
+			Namespace:      "default",
+			ServiceAccount: ocv1.ServiceAccountReference{Name: "default"},
+		},
+	}
+	require.NoError(t, cl.Create(ctx, clusterExtension))
+
+	t.Log("When reconciling the cluster extension")
+	res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: extKey})
+	require.Equal(t, ctrl.Result{}, res)
+	require.ErrorIs(t, err, reconcile.TerminalError(nil))
+
+	t.Log("It blocks the upgrade until the deprecation is acknowledged")
+	require.NoError(t, cl.Get(ctx, extKey, clusterExtension))
+	progressingCond := apimeta.FindStatusCondition(clusterExtension.Status.Conditions, ocv1.TypeProgressing)
+	require.NotNil(t, progressingCond)
+	require.Equal(t, metav1.ConditionFalse, progressingCond.Status)
+	require.Equal(t, ocv1.ReasonBlocked, progressingCond.Reason)
+	require.Contains(t, progressingCond.Message, `channel "stable" is deprecated in favor of channel "stable-v2"`)
+
+	installedCond := apimeta.FindStatusCondition(clusterExtension.Status.Conditions, ocv1.TypeInstalled)
+	require.NotNil(t, installedCond)
+	require.Equal(t, metav1.ConditionTrue, installedCond.Status)
+
+	verifyInvariants(ctx, t, reconciler.Client, clusterExtension)
+	require.NoError(t, cl.DeleteAllOf(ctx, &ocv1.ClusterExtension{}))
+}
+
+type configRecordingApplier struct {
+	config *ocv1.ClusterExtensionConfig
+}
+
+func (a *configRecordingApplier) Apply(_ context.Context, _ fs.FS, ext *ocv1.ClusterExtension, _ map[string]string, _ map[string]string) (bool, string, error) {
+	a.config = ext.Spec.Config
+	return true, "", nil
+}
+
+func TestClusterExtensionConfigFromSecret(t *testing.T) {
+	recordingApplier := &configRecordingApplier{}
+	cl, reconciler := newClientAndReconciler(t, func(d *deps) {
+		d.ImagePuller = &imageutil.MockPuller{
+			ImageFS: fstest.MapFS{},
+		}
+		d.Resolver = resolve.Func(func(ctx context.Context, ext *ocv1.ClusterExtension, installedBundle *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
+			v := bundle.VersionRelease{
+				Version: bsemver.MustParse("1.0.0"),
+			}
+			return &declcfg.Bundle{
+				Name:    "prometheus.v1.0.0",
+				Package: "prometheus",
+				Image:   "quay.io/operatorhubio/prometheus@fake1.0.0",
+			}, &v, nil, nil
+		})
+		d.ConfigReader = newClient(t)
+		d.Applier = recordingApplier
+	})
+
+	ctx := context.Background()
+	extKey := types.NamespacedName{Name: fmt.Sprintf("cluster-extension-test-%s", rand.String(8))}
+	secretName := fmt.Sprintf("extension-config-%s", rand.String(8))
+
+	t.Log("Given a labeled Secret holding configuration in the install namespace")
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: "default",
+			Labels:    map[string]string{ocv1.LabelExtensionConfig: ""},
+		},
+		Data: map[string][]byte{"config.yaml": []byte("licenseKey: s3cr3t\nreplicas: 1\n")},
+	}
+	require.NoError(t, cl.Create(ctx, secret))
+
+	t.Log("And a cluster extension reading its configuration from the Secret with inline overrides")
+	clusterExtension := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: extKey.Name},
+		Spec: ocv1.ClusterExtensionSpec{
+			Source: ocv1.SourceConfig{
+				SourceType: "Catalog",
+				Catalog: &ocv1.CatalogFilter{
+					PackageName: "prometheus",
+				},
+			},
+			Namespace: "default",
+			ServiceAccount: ocv1.ServiceAccountReference{
+				Name: "default",
+			},
+			Config: &ocv1.ClusterExtensionConfig{
+				ConfigType: ocv1.ClusterExtensionConfigTypeSecretRef,
+				SecretRef:  &ocv1.ConfigSourceReference{Name: secretName, Key: "config.yaml"},
+				Inline:     &apiextensionsv1.JSON{Raw: []byte(`{"replicas": 3}`)},
+			},
+		},
+	}
+	require.NoError(t, cl.Create(ctx, clusterExtension))
+
+	t.Log("When reconciling the cluster extension")
+	res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: extKey})
+	require.Equal(t, ctrl.Result{}, res)
+	require.NoError(t, err)
+
+	t.Log("It applies the merged configuration inline")
+	require.NotNil(t, recordingApplier.config)
+	require.Equal(t, ocv1.ClusterExtensionConfigTypeInline, recordingApplier.config.ConfigType)
+	require.JSONEq(t, `{"licenseKey": "s3cr3t", "replicas": 3}`, string(recordingApplier.config.Inline.Raw))
+
+	t.Log("When the Secret is deleted")
+	require.NoError(t, cl.Delete(ctx, secret))
+	res, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: extKey})
+	require.Equal(t, ctrl.Result{}, res)
+	require.ErrorIs(t, err, reconcile.TerminalError(nil))
+
+	t.Log("It reports the invalid configuration")
+	require.NoError(t, cl.Get(ctx, extKey, clusterExtension))
+	progressingCond := apimeta.FindStatusCondition(clusterExtension.Status.Conditions, ocv1.TypeProgressing)
+	require.NotNil(t, progressingCond)
+	require.Equal(t, metav1.ConditionFalse, progressingCond.Status)
+	require.Equal(t, ocv1.ReasonInvalidConfiguration, progressingCond.Reason)
+	require.Contains(t, progressingCond.Message, fmt.Sprintf("Secret %q not found", secretName))
+
+	verifyInvariants(ctx, t, reconciler.Client, clusterExtension)
+	require.NoError(t, cl.DeleteAllOf(ctx, &ocv1.ClusterExtension{}))
+}
+
+func TestClusterExtensionHelmSource(t *testing.T) {
+	const pollInterval = 10 * time.Minute
+	var resolvedExt *ocv1.ClusterExtension
+	cl, reconciler := newClientAndReconciler(t, func(d *deps) {
+		d.ImagePuller = &imageutil.MockPuller{
+			ImageFS: fstest.MapFS{},
+		}
+		d.Resolver = &resolve.SourceResolver{
+			Catalog: resolve.Func(func(ctx context.Context, ext *ocv1.ClusterExtension, installedBundle *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
+				return nil, nil, nil, errors.New("unexpected catalog resolution")
+			}),
+			Helm: resolve.Func(func(ctx context.Context, ext *ocv1.ClusterExtension, installedBundle *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
+				resolvedExt = ext
+				v := bundle.VersionRelease{
+					Version: bsemver.MustParse("1.2.0"),
+				}
+				return &declcfg.Bundle{
+					Name:    "testchart.v1.2.0",
+					Package: "testchart",
+					Image:   "ghcr.io/example/charts/testchart:1.2.0",
+				}, &v, nil, nil
+			}),
+		}
+		d.Applier = &MockApplier{installCompleted: true}
+		d.ChartPollInterval = pollInterval
+	})
+
+	ctx := context.Background()
+	extKey := types.NamespacedName{Name: fmt.Sprintf("cluster-extension-test-%s", rand.String(8))}
+
+	t.Log("Given a cluster extension installing a chart from an OCI repository")
+	clusterExtension := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: extKey.Name},
+		Spec: ocv1.ClusterExtensionSpec{
+			Source: ocv1.SourceConfig{
+				SourceType: ocv1.SourceTypeHelm,
+				Helm: &ocv1.HelmSource{
+					Repository: "oci://ghcr.io/example/charts",
+					Chart:      "testchart",
+				},
+			},
+			Namespace: "default",
+			ServiceAccount: ocv1.ServiceAccountReference{
+				Name: "default",
+			},
+		},
+	}
+	require.NoError(t, cl.Create(ctx, clusterExtension))
+
+	t.Log("When reconciling the cluster extension")
+	res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: extKey})
+	require.NoError(t, err)
+
+	t.Log("It resolves the chart from the Helm source and requeues to pick up new chart versions")
+	require.Equal(t, ctrl.Result{RequeueAfter: pollInterval}, res)
+	require.NotNil(t, resolvedExt)
+	require.Equal(t, "testchart", resolvedExt.Spec.Source.Helm.Chart)
+
+	t.Log("It reports the installed chart")
+	require.NoError(t, cl.Get(ctx, extKey, clusterExtension))
+	require.NotNil(t, clusterExtension.Status.Install)
+	require.Equal(t, ocv1.BundleMetadata{Name: "testchart.v1.2.0", Version: "1.2.0"}, clusterExtension.Status.Install.Bundle)
+	progressingCond := apimeta.FindStatusCondition(clusterExtension.Status.Conditions, ocv1.TypeProgressing)
+	require.NotNil(t, progressingCond)
+	require.Equal(t, ocv1.ReasonSucceeded, progressingCond.Reason)
+}
+
+type releaseAdopterFunc func(context.Context, *ocv1.ClusterExtension) error
+
+func (f releaseAdopterFunc) Adopt(ctx context.Context, ext *ocv1.ClusterExtension) error {
+	return f(ctx, ext)
+}
+
+func TestClusterExtensionAdoptHelmRelease(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		adoptErr         error
+		expectTerminal   bool
+		expectedReason   string
+		expectedProgress metav1.ConditionStatus
+	}{
+		{
+			name:             "release conflicts block reconciliation",
+			adoptErr:         &action.ReleaseAdoptionError{Release: "foo", Reason: "the ClusterExtension already manages a release with the same name"},
+			expectTerminal:   true,
+			expectedReason:   ocv1.ReasonBlocked,
+			expectedProgress: metav1.ConditionFalse,
+		},
+		{
+			name:             "other adoption failures are retried",
+			adoptErr:         errors.New("cannot adopt Helm release \"foo\": release: not found"),
+			expectedReason:   ocv1.ReasonRetrying,
+			expectedProgress: metav1.ConditionTrue,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var adopted string
+			cl, reconciler := newClientAndReconciler(t, func(d *deps) {
+				d.ReleaseAdopter = releaseAdopterFunc(func(_ context.Context, ext *ocv1.ClusterExtension) error {
+					adopted = ext.Annotations[ocv1.AnnotationAdoptHelmRelease]
+					return tc.adoptErr
+				})
+			})
+			ctx := context.Background()
+			extKey := types.NamespacedName{Name: fmt.Sprintf("cluster-extension-test-%s", rand.String(8))}
+
+			t.Log("Given a cluster extension adopting a Helm release")
+			clusterExtension := &ocv1.ClusterExtension{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        extKey.Name,
+					Annotations: map[string]string{ocv1.AnnotationAdoptHelmRelease: extKey.Name},
+				},
+				Spec: ocv1.ClusterExtensionSpec{
+					Source: ocv1.SourceConfig{
+						SourceType: ocv1.SourceTypeCatalog,
+						Catalog:    &ocv1.CatalogFilter{PackageName: "prometheus"},
+					},
+					Namespace:      "default",
+					ServiceAccount: ocv1.ServiceAccountReference{Name: "default"},
+				},
+			}
+			require.NoError(t, cl.Create(ctx, clusterExtension))
+
+			t.Log("When the release can't be adopted")
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: extKey})
+			require.Error(t, err)
+			require.Equal(t, extKey.Name, adopted)
+			require.Equal(t, tc.expectTerminal, errors.Is(err, reconcile.TerminalError(nil)))
+
+			t.Log("It reports the failure on the Progressing condition")
+			require.NoError(t, cl.Get(ctx, extKey, clusterExtension))
+			progressingCond := apimeta.FindStatusCondition(clusterExtension.Status.Conditions, ocv1.TypeProgressing)
+			require.NotNil(t, progressingCond)
+			require.Equal(t, tc.expectedProgress, progressingCond.Status)
+			require.Equal(t, tc.expectedReason, progressingCond.Reason)
+			require.Contains(t, progressingCond.Message, "cannot adopt Helm release")
+			installedCond := apimeta.FindStatusCondition(clusterExtension.Status.Conditions, ocv1.TypeInstalled)
+			require.NotNil(t, installedCond)
+			require.Equal(t, metav1.ConditionUnknown, installedCond.Status)
+		})
+	}
+}
+
+type mockRollbacker struct {
+	bundleLabels  map[string]string
+	rolledBack    int64
+	storageLabels map[string]string
+}
+
+func (m *mockRollbacker) RevisionBundle(_ context.Context, _ *ocv1.ClusterExtension, _ int64) (map[string]string, error) {
+	return m.bundleLabels, nil
+}
+
+func (m *mockRollbacker) Rollback(_ context.Context, _ *ocv1.ClusterExtension, revision int64, _, storageLabels map[string]string) (bool, string, error) {
+	m.rolledBack = revision
+	m.storageLabels = storageLabels
+	return true, "", nil
+}
+
+func TestClusterExtensionRollback(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		version          string
+		upgradePolicy    ocv1.UpgradeConstraintPolicy
+		constraintPolicy ocv1.RollbackConstraintPolicy
+		expectedReason   string
+		expectedMessage  string
+	}{
+		{
+			name:            "rolls back within the version range",
+			version:         ">=1.0.0",
+			upgradePolicy:   ocv1.UpgradeConstraintPolicySelfCertified,
+			expectedReason:  ocv1.ReasonRolledBack,
+			expectedMessage: `Rolled back to revision 1 (bundle "prometheus.v1.0.0", version "1.0.0")`,
+		},
+		{
+			name:            "blocks rollbacks outside of the version range",
+			version:         ">=2.0.0",
+			upgradePolicy:   ocv1.UpgradeConstraintPolicySelfCertified,
+			expectedReason:  ocv1.ReasonBlocked,
+			expectedMessage: `outside of version range ">=2.0.0"`,
+		},
+		{
+			name:            "blocks downgrades when upgrade constraints are enforced",
+			upgradePolicy:   ocv1.UpgradeConstraintPolicyCatalogProvided,
+			expectedReason:  ocv1.ReasonBlocked,
+			expectedMessage: `cannot roll back from version "2.0.0" to older version "1.0.0"`,
+		},
+		{
+			name:             "ignores constraints when requested",
+			version:          ">=2.0.0",
+			upgradePolicy:    ocv1.UpgradeConstraintPolicyCatalogProvided,
+			constraintPolicy: ocv1.RollbackConstraintPolicyIgnore,
+			expectedReason:   ocv1.ReasonRolledBack,
+			expectedMessage:  "Rolled back to revision 1",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rollbacker := &mockRollbacker{bundleLabels: map[string]string{
+				labels.PackageNameKey:   "prometheus",
+				labels.BundleNameKey:    "prometheus.v1.0.0",
+				labels.BundleVersionKey: "1.0.0",
+			}}
+			cl, reconciler := newClientAndReconciler(t, func(d *deps) {
+				d.RevisionStatesGetter = &MockRevisionStatesGetter{
+					RevisionStates: &controllers.RevisionStates{
+						Installed: &controllers.RevisionMetadata{
+							Package:        "prometheus",
+							BundleMetadata: ocv1.BundleMetadata{Name: "prometheus.v2.0.0", Version: "2.0.0"},
+						},
+					},
+				}
+				d.Rollbacker = rollbacker
+				d.Resolver = resolve.Func(func(context.Context, *ocv1.ClusterExtension, *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
+					return nil, nil, nil, errors.New("bundles must not be resolved while rolling back")
+				})
+			})
+			ctx := context.Background()
+			extKey := types.NamespacedName{Name: fmt.Sprintf("cluster-extension-test-%s", rand.String(8))}
+
+			t.Log("Given a cluster extension requesting a rollback")
+			clusterExtension := &ocv1.ClusterExtension{
+				ObjectMeta: metav1.ObjectMeta{Name: extKey.Name},
+				Spec: ocv1.ClusterExtensionSpec{
+					Source: ocv1.SourceConfig{
+						SourceType: ocv1.SourceTypeCatalog,
+						Catalog: &ocv1.CatalogFilter{
+							PackageName:             "prometheus",
+							Version:                 tc.version,
+							UpgradeConstraintPolicy: tc.upgradePolicy,
+						},
+					},
+					Namespace:      "default",
+					ServiceAccount: ocv1.ServiceAccountReference{Name: "default"},
+					Install: &ocv1.ClusterExtensionInstallConfig{
+						Rollback: &ocv1.ClusterExtensionRollback{Revision: 1, ConstraintPolicy: tc.constraintPolicy},
+					},
+				},
+			}
+			require.NoError(t, cl.Create(ctx, clusterExtension))
+
+			t.Log("When reconciling the cluster extension")
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: extKey})
+
+			t.Log("It reports the rollback on the Progressing condition")
+			require.NoError(t, cl.Get(ctx, extKey, clusterExtension))
+			progressingCond := apimeta.FindStatusCondition(clusterExtension.Status.Conditions, ocv1.TypeProgressing)
+			require.NotNil(t, progressingCond)
+			require.Equal(t, tc.expectedReason, progressingCond.Reason)
+			require.Contains(t, progressingCond.Message, tc.expectedMessage)
+			if tc.expectedReason == ocv1.ReasonRolledBack {
+				require.NoError(t, err)
+				require.Equal(t, int64(1), rollbacker.rolledBack)
+				require.Equal(t, "prometheus.v1.0.0", clusterExtension.Status.Install.Bundle.Name)
+			} else {
+				require.True(t, errors.Is(err, reconcile.TerminalError(nil)))
+				require.Zero(t, rollbacker.rolledBack)
+			}
+		})
+	}
+}
+
+func TestClusterExtensionRollBackFailedRollouts(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		onFailure        ocv1.OnFailurePolicy
+		failed           *controllers.RevisionMetadata
+		expectRolledBack bool
+	}{
+		{
+			name:             "rolls back a failed rollout",
+			onFailure:        ocv1.OnFailurePolicyRollback,
+			failed:           &controllers.RevisionMetadata{Revision: 3, BundleMetadata: ocv1.BundleMetadata{Name: "prometheus.v2.0.0", Version: "2.0.0"}},
+			expectRolledBack: true,
+		},
+		{
+			name:   "leaves failed rollouts in place by default",
+			failed: &controllers.RevisionMetadata{Revision: 3, BundleMetadata: ocv1.BundleMetadata{Name: "prometheus.v2.0.0", Version: "2.0.0"}},
+		},
+		{
+			name:      "does not roll back a failed rollback",
+			onFailure: ocv1.OnFailurePolicyRollback,
+			failed: &controllers.RevisionMetadata{
+				Revision:       4,
+				BundleMetadata: ocv1.BundleMetadata{Name: "prometheus.v1.0.0", Version: "1.0.0"},
+				RolledBackFrom: &controllers.FailedRollout{Revision: 3, Bundle: "prometheus.v2.0.0"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rollbacker := &mockRollbacker{}
+			cl, reconciler := newClientAndReconciler(t, func(d *deps) {
+				d.RevisionStatesGetter = &MockRevisionStatesGetter{
+					RevisionStates: &controllers.RevisionStates{
+						Installed: &controllers.RevisionMetadata{
+							Revision:       2,
+							Package:        "prometheus",
+							BundleMetadata: ocv1.BundleMetadata{Name: "prometheus.v1.0.0", Version: "1.0.0"},
+						},
+						RollingOut: []*controllers.RevisionMetadata{tc.failed},
+						Failed:     tc.failed,
+					},
+				}
+				d.Rollbacker = rollbacker
+			})
+			ctx := context.Background()
+			extKey := types.NamespacedName{Name: fmt.Sprintf("cluster-extension-test-%s", rand.String(8))}
+
+			t.Log("Given a cluster extension whose latest revision failed to roll out")
+			clusterExtension := &ocv1.ClusterExtension{
+				ObjectMeta: metav1.ObjectMeta{Name: extKey.Name},
+				Spec: ocv1.ClusterExtensionSpec{
+					Source: ocv1.SourceConfig{
+						SourceType: ocv1.SourceTypeCatalog,
+						Catalog:    &ocv1.CatalogFilter{PackageName: "prometheus"},
+					},
+					Namespace:               "default",
+					ServiceAccount:          ocv1.ServiceAccountReference{Name: "default"},
+					ProgressDeadlineMinutes: 10,
+					OnFailure:               tc.onFailure,
+				},
+			}
+			require.NoError(t, cl.Create(ctx, clusterExtension))
+
+			t.Log("When reconciling the cluster extension")
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: extKey})
+			require.NoError(t, err)
+
+			require.NoError(t, cl.Get(ctx, extKey, clusterExtension))
+			if !tc.expectRolledBack {
+				require.Zero(t, rollbacker.rolledBack)
+				return
+			}
+			t.Log("It restores the installed revision and records the failed one")
+			require.Equal(t, int64(2), rollbacker.rolledBack)
+			require.Equal(t, map[string]string{
+				labels.FailedRevisionKey: "3",
+				labels.FailedBundleKey:   "prometheus.v2.0.0",
+			}, rollbacker.storageLabels)
+			progressingCond := apimeta.FindStatusCondition(clusterExtension.Status.Conditions, ocv1.TypeProgressing)
+			require.NotNil(t, progressingCond)
+			require.Equal(t, ocv1.ReasonRolledBack, progressingCond.Reason)
+			require.Contains(t, progressingCond.Message, `bundle "prometheus.v2.0.0" failed to roll out in revision 3 and was rolled back`)
+		})
+	}
+}
+
+func TestClusterExtensionExcludesFailedBundles(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		annotations      map[string]string
+		expectedExcluded []string
+	}{
+		{
+			name:             "excludes the bundle that failed to roll out",
+			expectedExcluded: []string{"prometheus.v2.0.0"},
+		},
+		{
+			name:        "resolves the failed bundle once the failure is acknowledged",
+			annotations: map[string]string{ocv1.AnnotationRetryFailedRevision: "3"},
+		},
+		{
+			name:             "keeps excluding when an older failure is acknowledged",
+			annotations:      map[string]string{ocv1.AnnotationRetryFailedRevision: "1"},
+			expectedExcluded: []string{"prometheus.v2.0.0"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var excluded []string
+			cl, reconciler := newClientAndReconciler(t, func(d *deps) {
+				d.RevisionStatesGetter = &MockRevisionStatesGetter{
+					RevisionStates: &controllers.RevisionStates{
+						Installed: &controllers.RevisionMetadata{
+							Revision:       4,
+							Package:        "prometheus",
+							BundleMetadata: ocv1.BundleMetadata{Name: "prometheus.v1.0.0", Version: "1.0.0"},
+							RolledBackFrom: &controllers.FailedRollout{Revision: 3, Bundle: "prometheus.v2.0.0"},
+						},
+					},
+				}
+				d.Rollbacker = &mockRollbacker{}
+				d.Resolver = resolve.Func(func(ctx context.Context, _ *ocv1.ClusterExtension, installed *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
+					excluded = resolve.ExcludedBundles(ctx).UnsortedList()
+					v := bundle.VersionRelease{Version: bsemver.MustParse(installed.Version)}
+					return &declcfg.Bundle{Name: installed.Name, Package: "prometheus", Image: "quay.io/operatorhubio/prometheus@fake"}, &v, nil, nil
+				})
+			})
+			ctx := context.Background()
+			extKey := types.NamespacedName{Name: fmt.Sprintf("cluster-extension-test-%s", rand.String(8))}
+
+			t.Log("Given a cluster extension that rolled back a failed rollout")
+			clusterExtension := &ocv1.ClusterExtension{
+				ObjectMeta: metav1.ObjectMeta{Name: extKey.Name, Annotations: tc.annotations},
+				Spec: ocv1.ClusterExtensionSpec{
+					Source: ocv1.SourceConfig{
+						SourceType: ocv1.SourceTypeCatalog,
+						Catalog:    &ocv1.CatalogFilter{PackageName: "prometheus"},
+					},
+					Namespace:      "default",
+					ServiceAccount: ocv1.ServiceAccountReference{Name: "default"},
+					OnFailure:      ocv1.OnFailurePolicyRollback,
+				},
+			}
+			require.NoError(t, cl.Create(ctx, clusterExtension))
+
+			t.Log("When the cluster extension is reconciled")
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: extKey})
+			require.NoError(t, err)
+
+			t.Log("It excludes the failed bundle from resolution unless it is retried")
+			require.ElementsMatch(t, tc.expectedExcluded, excluded)
+		})
+	}
+}
+
+type mockUpgradePreviewer struct {
+	preview *ocv1.ClusterExtensionUpgradePreview
+}
+
+func (m *mockUpgradePreviewer) PreviewUpgrade(_ context.Context, _ fs.FS, _ *ocv1.ClusterExtension, _, _ map[string]string) (*ocv1.ClusterExtensionUpgradePreview, error) {
+	return m.preview, nil
+}
+
+func TestClusterExtensionPreviewUpgrade(t *testing.T) {
+	preview := &ocv1.ClusterExtensionUpgradePreview{
+		Bundle:  ocv1.BundleMetadata{Name: "prometheus.v2.0.0", Version: "2.0.0"},
+		Digest:  "0123456789abcdef",
+		Changed: 1,
+		Objects: []ocv1.UpgradePreviewObject{{
+			Action: ocv1.UpgradePreviewActionChanged, ObjectReference: ocv1.ObjectReference{Group: "apps", Kind: "Deployment", Namespace: "default", Name: "prometheus"},
+			Fields: []string{"spec.template.spec.containers[0].image"},
+		}},
+	}
+	for _, tc := range []struct {
+		name             string
+		approval         ocv1.UpgradeApprovalPolicy
+		annotations      map[string]string
+		preview          *ocv1.ClusterExtensionUpgradePreview
+		expectedReason   string
+		expectedBundle   string
+	}{
+		{
+			name:           "applies upgrades automatically and reports the preview",
+			preview:        preview,
+			expectedReason: ocv1.ReasonSucceeded,
+			expectedBundle: "prometheus.v2.0.0",
+		},
+		{
+			name:           "waits for the approval of upgrades with the Manual approval policy",
+			approval:       ocv1.UpgradeApprovalPolicyManual,
+			preview:        preview,
+			expectedReason: ocv1.ReasonAwaitingApproval,
+			expectedBundle: "prometheus.v1.0.0",
+		},
+		{
+			name:           "keeps waiting when an other upgrade is approved",
+			approval:       ocv1.UpgradeApprovalPolicyManual,
+			annotations:    map[string]string{ocv1.AnnotationApprovedUpgrade: "fedcba9876543210"},
+			preview:        preview,
+			expectedReason: ocv1.ReasonAwaitingApproval,
+			expectedBundle: "prometheus.v1.0.0",
+		},
+		{
+			name:           "applies approved upgrades",
+			approval:       ocv1.UpgradeApprovalPolicyManual,
+			annotations:    map[string]string{ocv1.AnnotationApprovedUpgrade: preview.Digest},
+			preview:        preview,
+			expectedReason: ocv1.ReasonSucceeded,
+			expectedBundle: "prometheus.v2.0.0",
+		},
+		{
+			name:           "applies upgrades that change no object without approval",
+			approval:       ocv1.UpgradeApprovalPolicyManual,
+			expectedReason: ocv1.ReasonSucceeded,
+			expectedBundle: "prometheus.v2.0.0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cl, reconciler := newClientAndReconciler(t, func(d *deps) {
+				d.RevisionStatesGetter = &MockRevisionStatesGetter{
+					RevisionStates: &controllers.RevisionStates{
+						Installed: &controllers.RevisionMetadata{
+							Package:        "prometheus",
+							BundleMetadata: ocv1.BundleMetadata{Name: "prometheus.v1.0.0", Version: "1.0.0"},
+						},
+					},
+				}
+				d.Resolver = resolve.Func(func(context.Context, *ocv1.ClusterExtension, *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
+					v := bundle.VersionRelease{Version: bsemver.MustParse("2.0.0")}
+					return &declcfg.Bundle{Name: "prometheus.v2.0.0", Package: "prometheus", Image: "quay.io/operatorhubio/prometheus@fake2.0.0"}, &v, nil, nil
+				})
+				d.ImagePuller = &imageutil.MockPuller{ImageFS: fstest.MapFS{}}
+				d.UpgradePreviewer = &mockUpgradePreviewer{preview: tc.preview}
+				d.Applier = &MockApplier{installCompleted: true}
+			})
+			ctx := context.Background()
+			extKey := types.NamespacedName{Name: fmt.Sprintf("cluster-extension-test-%s", rand.String(8))}
+
+			t.Log("Given a cluster extension with an upgrade available")
+			clusterExtension := &ocv1.ClusterExtension{
+				ObjectMeta: metav1.ObjectMeta{Name: extKey.Name, Annotations: tc.annotations},
+				Spec: ocv1.ClusterExtensionSpec{
+					Source: ocv1.SourceConfig{
+						SourceType: ocv1.SourceTypeCatalog,
+						Catalog:    &ocv1.CatalogFilter{PackageName: "prometheus"},
+					},
+					Namespace:      "default",
+					ServiceAccount: ocv1.ServiceAccountReference{Name: "default"},
+				},
+			}
+			if tc.approval != "" {
+				clusterExtension.Spec.Install = &ocv1.ClusterExtensionInstallConfig{UpgradeApproval: tc.approval}
+			}
+			require.NoError(t, cl.Create(ctx, clusterExtension))
+
+			t.Log("When the upgrade is reconciled")
+			res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: extKey})
+			require.NoError(t, err)
+			require.Equal(t, ctrl.Result{}, res)
+
+			t.Log("It reports the preview, and installs the upgrade unless it awaits approval")
+			require.NoError(t, cl.Get(ctx, extKey, clusterExtension))
+			require.Equal(t, tc.preview, clusterExtension.Status.UpgradePreview)
+			progressingCond := apimeta.FindStatusCondition(clusterExtension.Status.Conditions, ocv1.TypeProgressing)
+			require.NotNil(t, progressingCond)
+			require.Equal(t, metav1.ConditionTrue, progressingCond.Status)
+			require.Equal(t, tc.expectedReason, progressingCond.Reason)
+			if tc.expectedReason == ocv1.ReasonAwaitingApproval {
+				require.Contains(t, progressingCond.Message, ocv1.AnnotationApprovedUpgrade+"="+preview.Digest)
+			}
+			require.NotNil(t, clusterExtension.Status.Install)
+			require.Equal(t, tc.expectedBundle, clusterExtension.Status.Install.Bundle.Name)
+		})
+	}
+}
+
+type mockPauser struct {
+	paused  bool
+	resumed bool
+}
+
+func (m *mockPauser) Pause(context.Context, *ocv1.ClusterExtension) error {
+	m.paused = true
+	return nil
+}
+
+func (m *mockPauser) Resume(context.Context, *ocv1.ClusterExtension) error {
+	m.resumed = true
+	return nil
+}
+
+func TestClusterExtensionPauseReconciliation(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		paused         bool
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+		expectResolve  bool
+	}{
+		{
+			name:           "skips resolution and apply while paused",
+			paused:         true,
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: ocv1.ReasonPaused,
+		},
+		{
+			name:           "resumes the installed content when not paused",
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: ocv1.ReasonSucceeded,
+			expectResolve:  true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pauser := &mockPauser{}
+			resolved := false
+			cl, reconciler := newClientAndReconciler(t, func(d *deps) {
+				d.Pauser = pauser
+				d.Resolver = resolve.Func(func(context.Context, *ocv1.ClusterExtension, *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
+					resolved = true
+					v := bundle.VersionRelease{Version: bsemver.MustParse("1.0.0")}
+					return &declcfg.Bundle{Name: "prometheus.v1.0.0", Package: "prometheus", Image: "quay.io/operatorhubio/prometheus@fake1.0.0"}, &v, nil, nil
+				})
+				d.ImagePuller = &imageutil.MockPuller{ImageFS: fstest.MapFS{}}
+				d.Applier = &MockApplier{installCompleted: true}
+			})
+			ctx := context.Background()
+			extKey := types.NamespacedName{Name: fmt.Sprintf("cluster-extension-test-%s", rand.String(8))}
+
+			t.Log("Given a cluster extension")
+			clusterExtension := &ocv1.ClusterExtension{
+				ObjectMeta: metav1.ObjectMeta{Name: extKey.Name},
+				Spec: ocv1.ClusterExtensionSpec{
+					Source: ocv1.SourceConfig{
+						SourceType: ocv1.SourceTypeCatalog,
+						Catalog:    &ocv1.CatalogFilter{PackageName: "prometheus"},
+					},
+					Namespace:      "default",
+					ServiceAccount: ocv1.ServiceAccountReference{Name: "default"},
+					Paused:         tc.paused,
+				},
+			}
+			require.NoError(t, cl.Create(ctx, clusterExtension))
+
+			t.Log("When it is reconciled")
+			res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: extKey})
+			require.NoError(t, err)
+			require.Equal(t, ctrl.Result{}, res)
+
+			t.Log("It is only resolved and applied when not paused")
+			require.Equal(t, tc.paused, pauser.paused)
+			require.Equal(t, !tc.paused, pauser.resumed)
+			require.Equal(t, tc.expectResolve, resolved)
+			require.NoError(t, cl.Get(ctx, extKey, clusterExtension))
+			progressingCond := apimeta.FindStatusCondition(clusterExtension.Status.Conditions, ocv1.TypeProgressing)
+			require.NotNil(t, progressingCond)
+			require.Equal(t, tc.expectedStatus, progressingCond.Status)
+			require.Equal(t, tc.expectedReason, progressingCond.Reason)
+		})
+	}
+}
+
 func TestClusterExtensionApplierFailsWithBundleInstalled(t *testing.T) {
 	mockApplier := &MockApplier{
 		installCompleted: true,
@@ -2439,6 +3363,140 @@ func TestGetInstalledBundleHistory(t *testing.T) {
 	}
 }
 
+func TestGetRevisionStatesReportsFailedRelease(t *testing.T) {
+	getter := controllers.HelmRevisionStatesGetter{ActionClientGetter: &MockActionGetter{rels: []*release.Release{
+		{
+			Name:    "test-ext",
+			Version: 2,
+			Info:    &release.Info{Status: release.StatusFailed},
+			Labels:  map[string]string{labels.BundleNameKey: "test-ext.v2.0.0"},
+		},
+		{
+			Name:    "test-ext",
+			Version: 1,
+			Info:    &release.Info{Status: release.StatusDeployed},
+			Labels: map[string]string{
+				labels.BundleNameKey:     "test-ext.v1.0.0",
+				labels.FailedRevisionKey: "3",
+				labels.FailedBundleKey:   "test-ext.v1.1.0",
+			},
+		},
+	}}}
+
+	rs, err := getter.GetRevisionStates(context.Background(), &ocv1.ClusterExtension{ObjectMeta: metav1.ObjectMeta{Name: "test-ext"}})
+	require.NoError(t, err)
+	require.NotNil(t, rs.Failed)
+	require.Equal(t, int64(2), rs.Failed.Revision)
+	require.Equal(t, "test-ext.v2.0.0", rs.Failed.Name)
+	require.Equal(t, int64(1), rs.Installed.Revision)
+	require.Equal(t, &controllers.FailedRollout{Revision: 3, Bundle: "test-ext.v1.1.0"}, rs.Installed.RolledBackFrom)
+}
+
+func TestGetRevisionStatesReportsHistory(t *testing.T) {
+	deployedAt := func(minutes int) helmtime.Time {
+		return helmtime.Time{Time: time.Date(2026, 1, 1, 0, minutes, 0, 0, time.UTC)}
+	}
+	// Like Helm, the releases keep the FirstDeployed time of the first release.
+	getter := controllers.HelmRevisionStatesGetter{ActionClientGetter: &MockActionGetter{rels: []*release.Release{
+		{
+			Name:    "test-ext",
+			Version: 3,
+			Info:    &release.Info{Status: release.StatusFailed, FirstDeployed: deployedAt(0), LastDeployed: deployedAt(20)},
+			Labels:  map[string]string{labels.BundleNameKey: "test-ext.v3.0.0", labels.BundleVersionKey: "3.0.0"},
+		},
+		{
+			Name:    "test-ext",
+			Version: 2,
+			Info:    &release.Info{Status: release.StatusDeployed, FirstDeployed: deployedAt(0), LastDeployed: deployedAt(10)},
+			Labels:  map[string]string{labels.BundleNameKey: "test-ext.v2.0.0", labels.BundleVersionKey: "2.0.0"},
+		},
+		{
+			Name:    "test-ext",
+			Version: 1,
+			Info:    &release.Info{Status: release.StatusSuperseded, FirstDeployed: deployedAt(0), LastDeployed: deployedAt(0)},
+			Labels:  map[string]string{labels.BundleNameKey: "test-ext.v1.0.0", labels.BundleVersionKey: "1.0.0"},
+		},
+	}}}
+
+	rs, err := getter.GetRevisionStates(context.Background(), &ocv1.ClusterExtension{ObjectMeta: metav1.ObjectMeta{Name: "test-ext"}})
+	require.NoError(t, err)
+	at := func(minutes int) *metav1.Time {
+		return &metav1.Time{Time: deployedAt(minutes).Time}
+	}
+	require.Equal(t, []ocv1.RevisionHistoryEntry{
+		{
+			Revision: 1, BundleName: "test-ext.v1.0.0", BundleVersion: "1.0.0", Outcome: ocv1.RevisionOutcomeSucceeded,
+			CreatedAt: *at(0), CompletedAt: at(0), SupersededAt: at(10),
+		},
+		{
+			Revision: 2, BundleName: "test-ext.v2.0.0", BundleVersion: "2.0.0", Outcome: ocv1.RevisionOutcomeSucceeded,
+			CreatedAt: *at(10), CompletedAt: at(10),
+		},
+		{
+			Revision: 3, BundleName: "test-ext.v3.0.0", BundleVersion: "3.0.0", Outcome: ocv1.RevisionOutcomeFailed,
+			CreatedAt: *at(20), CompletedAt: at(20),
+		},
+	}, rs.History)
+}
+
+func TestClusterExtensionRecordsRevisionHistory(t *testing.T) {
+	revisionStates := &controllers.RevisionStates{}
+	cl, reconciler := newClientAndReconciler(t, func(d *deps) {
+		d.RecordHistory = true
+		d.RevisionStatesGetter = &MockRevisionStatesGetter{RevisionStates: revisionStates}
+	})
+	ctx := context.Background()
+	extKey := types.NamespacedName{Name: fmt.Sprintf("cluster-extension-test-%s", rand.String(8))}
+	clusterExtension := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: extKey.Name},
+		Spec: ocv1.ClusterExtensionSpec{
+			Source: ocv1.SourceConfig{
+				SourceType: ocv1.SourceTypeCatalog,
+				Catalog:    &ocv1.CatalogFilter{PackageName: "prometheus"},
+			},
+			Namespace:      "default",
+			ServiceAccount: ocv1.ServiceAccountReference{Name: "default"},
+		},
+	}
+	require.NoError(t, cl.Create(ctx, clusterExtension))
+
+	at := func(minutes int) *metav1.Time {
+		return &metav1.Time{Time: time.Date(2026, 1, 1, 0, minutes, 0, 0, time.UTC)}
+	}
+	outcomes := func() map[int64]ocv1.RevisionOutcome {
+		require.NoError(t, cl.Get(ctx, extKey, clusterExtension))
+		out := map[int64]ocv1.RevisionOutcome{}
+		for _, entry := range clusterExtension.Status.History {
+			out[entry.Revision] = entry.Outcome
+		}
+		return out
+	}
+
+	t.Log("When revision 1 rolled out and revision 2 failed to roll out")
+	revisionStates.History = []ocv1.RevisionHistoryEntry{
+		{Revision: 1, BundleName: "prometheus.v1.0.0", BundleVersion: "1.0.0", Outcome: ocv1.RevisionOutcomeSucceeded, CreatedAt: *at(0), CompletedAt: at(1)},
+		{Revision: 2, BundleName: "prometheus.v2.0.0", BundleVersion: "2.0.0", Outcome: ocv1.RevisionOutcomeFailed, CreatedAt: *at(10), CompletedAt: at(11)},
+	}
+	_, _ = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: extKey})
+	require.Equal(t, map[int64]ocv1.RevisionOutcome{1: ocv1.RevisionOutcomeSucceeded, 2: ocv1.RevisionOutcomeFailed}, outcomes())
+
+	t.Log("And revision 1 is deleted, and revision 2 is archived, by a rollback")
+	revisionStates.History = []ocv1.RevisionHistoryEntry{
+		{Revision: 2, BundleName: "prometheus.v2.0.0", BundleVersion: "2.0.0", Outcome: ocv1.RevisionOutcomeAbandoned, CreatedAt: *at(10), SupersededAt: at(21)},
+		{Revision: 3, BundleName: "prometheus.v1.0.0", BundleVersion: "1.0.0", Outcome: ocv1.RevisionOutcomeSucceeded, CreatedAt: *at(20), CompletedAt: at(21)},
+	}
+	_, _ = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: extKey})
+
+	t.Log("It keeps the history of the deleted revision, and the outcome of the failed revision")
+	require.Equal(t, map[int64]ocv1.RevisionOutcome{
+		1: ocv1.RevisionOutcomeSucceeded,
+		2: ocv1.RevisionOutcomeFailed,
+		3: ocv1.RevisionOutcomeSucceeded,
+	}, outcomes())
+	require.NotNil(t, clusterExtension.Status.History[1].SupersededAt)
+	require.True(t, at(21).Equal(clusterExtension.Status.History[1].SupersededAt))
+}
+
 // TestResolutionFallbackToInstalledBundle tests the catalog deletion resilience fallback logic
 func TestResolutionFallbackToInstalledBundle(t *testing.T) {
 	t.Run("falls back when catalog unavailable and no version change", func(t *testing.T) {
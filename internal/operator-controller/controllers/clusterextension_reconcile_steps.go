@@ -17,22 +17,38 @@ limitations under the License.
 package controllers
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/finalizer"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+
 	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/action"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/authentication"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/bundle"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/bundleutil"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/catalogmetadata/compare"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/config"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/extensionpolicy"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/resolve"
+	errorutil "github.com/operator-framework/operator-controller/internal/shared/util/error"
 	imageutil "github.com/operator-framework/operator-controller/internal/shared/util/image"
 )
 
@@ -63,6 +79,35 @@ func HandleFinalizers(f finalizer.Finalizer) ReconcileStepFunc {
 	}
 }
 
+// PauseReconciliation skips the remaining steps while spec.paused is set, after pausing the
+// reconciliation of the installed content, so that the installed objects are left as they are.
+// The content is resumed once spec.paused is unset.
+func PauseReconciliation(p Pauser) ReconcileStepFunc {
+	return func(ctx context.Context, state *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
+		if !ext.Spec.Paused {
+			if err := p.Resume(ctx, ext); err != nil {
+				setStatusProgressing(ext, fmt.Errorf("resuming reconciliation: %w", err))
+				return nil, err
+			}
+			return nil, nil
+		}
+
+		if err := p.Pause(ctx, ext); err != nil {
+			setStatusProgressing(ext, fmt.Errorf("pausing reconciliation: %w", err))
+			return nil, err
+		}
+		log.FromContext(ctx).Info("reconciliation is paused")
+		apimeta.SetStatusCondition(&ext.Status.Conditions, metav1.Condition{
+			Type:               ocv1.TypeProgressing,
+			Status:             metav1.ConditionFalse,
+			Reason:             ocv1.ReasonPaused,
+			Message:            "reconciliation is paused; set spec.paused to false to resume it",
+			ObservedGeneration: ext.GetGeneration(),
+		})
+		return &ctrl.Result{}, nil
+	}
+}
+
 func RetrieveRevisionStates(r RevisionStatesGetter) ReconcileStepFunc {
 	return func(ctx context.Context, state *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
 		l := log.FromContext(ctx)
@@ -85,6 +130,338 @@ func RetrieveRevisionStates(r RevisionStatesGetter) ReconcileStepFunc {
 	}
 }
 
+// maxRevisionHistory is the maximum number of entries of status.history.
+const maxRevisionHistory = 100
+
+// RecordRevisionHistory records the summaries of the retained revisions in status.history.
+// The entries of revisions are kept after the revisions are deleted, up to the
+// maxRevisionHistory most recent revisions.
+func RecordRevisionHistory() ReconcileStepFunc {
+	return func(_ context.Context, state *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
+		ext.Status.History = mergeRevisionHistory(ext.Status.History, state.revisionStates.History)
+		return nil, nil
+	}
+}
+
+// mergeRevisionHistory merges the observed summaries of the retained revisions into the recorded
+// history. The outcome of a revision is final once it succeeded or failed, since archiving a
+// ClusterExtensionRevision replaces the reason of its failure.
+func mergeRevisionHistory(recorded, observed []ocv1.RevisionHistoryEntry) []ocv1.RevisionHistoryEntry {
+	byRevision := make(map[int64]ocv1.RevisionHistoryEntry, len(recorded)+len(observed))
+	for _, entry := range recorded {
+		byRevision[entry.Revision] = entry
+	}
+	for _, entry := range observed {
+		if prev, ok := byRevision[entry.Revision]; ok &&
+			(prev.Outcome == ocv1.RevisionOutcomeSucceeded || prev.Outcome == ocv1.RevisionOutcomeFailed) {
+			if entry.SupersededAt != nil {
+				prev.SupersededAt = entry.SupersededAt
+			}
+			entry = prev
+		}
+		byRevision[entry.Revision] = entry
+	}
+	if len(byRevision) == 0 {
+		return nil
+	}
+	history := slices.SortedFunc(maps.Values(byRevision), func(a, b ocv1.RevisionHistoryEntry) int {
+		return cmp.Compare(a.Revision, b.Revision)
+	})
+	if n := len(history); n > maxRevisionHistory {
+		history = history[n-maxRevisionHistory:]
+	}
+	return history
+}
+
+// AdoptHelmRelease imports a Helm release installed outside of OLM into the release storage
+// of OLM when the ClusterExtension requests it with the ocv1.AnnotationAdoptHelmRelease
+// annotation. It runs before the revision states are retrieved, so that the adopted release
+// is reported as installed and upgraded like any release installed by OLM.
+func AdoptHelmRelease(a ReleaseAdopter) ReconcileStepFunc {
+	return func(ctx context.Context, state *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
+		err := a.Adopt(ctx, ext)
+		if err == nil {
+			return nil, nil
+		}
+		setInstalledStatusConditionUnknown(ext, err.Error())
+		var adoptionErr *action.ReleaseAdoptionError
+		if errors.As(err, &adoptionErr) {
+			err = errorutil.NewTerminalError(ocv1.ReasonBlocked, err)
+		}
+		setStatusProgressing(ext, err)
+		return nil, err
+	}
+}
+
+// rollbackPollInterval is how often a rollback in progress is checked, for Rollbackers that
+// report the progress of a rollback without a watch to trigger the next reconcile.
+const rollbackPollInterval = 10 * time.Second
+
+// RollBack rolls the installed content back to the revision requested by spec.install.rollback.
+// Unless the rollback constraint policy is Ignore, rollbacks to bundles outside of the version
+// range, and to older catalog bundles when upgrade constraints are enforced, are blocked. While
+// a rollback is requested, no bundle is resolved and the remaining steps are skipped.
+func RollBack(r Rollbacker) ReconcileStepFunc {
+	return func(ctx context.Context, state *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
+		if ext.Spec.Install == nil || ext.Spec.Install.Rollback == nil {
+			return nil, nil
+		}
+		rollback := ext.Spec.Install.Rollback
+		l := log.FromContext(ctx)
+
+		bundleLabels, err := r.RevisionBundle(ctx, ext, rollback.Revision)
+		if err != nil {
+			setInstalledStatusFromRevisionStates(ext, state.revisionStates)
+			setStatusProgressing(ext, err)
+			return nil, err
+		}
+		target := &RevisionMetadata{
+			Package: bundleLabels[labels.PackageNameKey],
+			Image:   bundleLabels[labels.BundleReferenceKey],
+			Catalog: bundleLabels[labels.CatalogNameKey],
+			BundleMetadata: ocv1.BundleMetadata{
+				Name:    bundleLabels[labels.BundleNameKey],
+				Version: bundleLabels[labels.BundleVersionKey],
+			},
+		}
+
+		if rollback.ConstraintPolicy != ocv1.RollbackConstraintPolicyIgnore && !isRollingOutOrInstalled(state.revisionStates, target) {
+			if err := checkRollbackConstraints(ext, state.revisionStates.Installed, target); err != nil {
+				err = errorutil.NewTerminalError(ocv1.ReasonBlocked, err)
+				setInstalledStatusFromRevisionStates(ext, state.revisionStates)
+				setStatusProgressing(ext, err)
+				return nil, err
+			}
+		}
+
+		l.Info("rolling back", "revision", rollback.Revision, "bundle", target.Name)
+		objLbls := map[string]string{
+			labels.OwnerKindKey: ocv1.ClusterExtensionKind,
+			labels.OwnerNameKey: ext.GetName(),
+		}
+		rolloutSucceeded, rolloutStatus, err := r.Rollback(ctx, ext, rollback.Revision, objLbls, nil)
+		if rolloutSucceeded {
+			state.revisionStates = &RevisionStates{Installed: target}
+		}
+		setInstalledStatusFromRevisionStates(ext, state.revisionStates)
+
+		switch {
+		case err != nil:
+			setStatusProgressing(ext, wrapErrorWithResolutionInfo(target.BundleMetadata, err))
+			return nil, err
+		case !rolloutSucceeded:
+			apimeta.SetStatusCondition(&ext.Status.Conditions, metav1.Condition{
+				Type:               ocv1.TypeProgressing,
+				Status:             metav1.ConditionTrue,
+				Reason:             ocv1.ReasonRollingOut,
+				Message:            rolloutStatus,
+				ObservedGeneration: ext.GetGeneration(),
+			})
+			// The steps requeueing rollouts in progress don't run after a rollback.
+			return &ctrl.Result{RequeueAfter: rollbackPollInterval}, nil
+		default:
+			apimeta.SetStatusCondition(&ext.Status.Conditions, metav1.Condition{
+				Type:               ocv1.TypeProgressing,
+				Status:             metav1.ConditionTrue,
+				Reason:             ocv1.ReasonRolledBack,
+				Message:            fmt.Sprintf("Rolled back to revision %d (bundle %q, version %q); new bundles are not resolved until spec.install.rollback is removed", rollback.Revision, target.Name, target.Version),
+				ObservedGeneration: ext.GetGeneration(),
+			})
+		}
+		return &ctrl.Result{}, nil
+	}
+}
+
+// RollBackFailedRollouts restores the installed revision when the latest revision failed to
+// roll out and the ClusterExtension sets the Rollback onFailure policy. The rollback records
+// the failed revision and bundle, so that ResolveBundle excludes the bundle from resolution
+// until the failure is acknowledged.
+func RollBackFailedRollouts(r Rollbacker) ReconcileStepFunc {
+	return func(ctx context.Context, state *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
+		failed, installed := state.revisionStates.Failed, state.revisionStates.Installed
+		if ext.Spec.OnFailure != ocv1.OnFailurePolicyRollback || failed == nil || installed == nil {
+			return nil, nil
+		}
+		// A failed rollback is left for a human to resolve, rather than rolling back again.
+		if failed.RolledBackFrom != nil {
+			return nil, nil
+		}
+
+		l := log.FromContext(ctx)
+		l.Info("rolling back failed rollout", "failedRevision", failed.Revision, "failedBundle", failed.Name, "revision", installed.Revision)
+		objLbls := map[string]string{
+			labels.OwnerKindKey: ocv1.ClusterExtensionKind,
+			labels.OwnerNameKey: ext.GetName(),
+		}
+		storageLbls := map[string]string{
+			labels.FailedRevisionKey: strconv.FormatInt(failed.Revision, 10),
+			labels.FailedBundleKey:   failed.Name,
+		}
+		_, _, err := r.Rollback(ctx, ext, installed.Revision, objLbls, storageLbls)
+		setInstalledStatusFromRevisionStates(ext, state.revisionStates)
+		if err != nil {
+			setStatusProgressing(ext, fmt.Errorf("error rolling back failed revision %d: %w", failed.Revision, err))
+			return nil, err
+		}
+		apimeta.SetStatusCondition(&ext.Status.Conditions, metav1.Condition{
+			Type:               ocv1.TypeProgressing,
+			Status:             metav1.ConditionTrue,
+			Reason:             ocv1.ReasonRolledBack,
+			Message:            failedRolloutMessage(&FailedRollout{Revision: failed.Revision, Bundle: failed.Name}),
+			ObservedGeneration: ext.GetGeneration(),
+		})
+		return &ctrl.Result{}, nil
+	}
+}
+
+// excludedFailedBundles returns the bundle that failed to roll out and was rolled back by
+// RollBackFailedRollouts, unless the ClusterExtension is annotated with
+// ocv1.AnnotationRetryFailedRevision to retry it. Newer bundles are resolved as usual.
+func excludedFailedBundles(state *reconcileState, ext *ocv1.ClusterExtension) []string {
+	latest := state.revisionStates.latest()
+	if latest == nil || latest.RolledBackFrom == nil {
+		return nil
+	}
+	failed := latest.RolledBackFrom
+	if ext.GetAnnotations()[ocv1.AnnotationRetryFailedRevision] == strconv.FormatInt(failed.Revision, 10) {
+		return nil
+	}
+	return []string{failed.Bundle}
+}
+
+func failedRolloutMessage(failed *FailedRollout) string {
+	return fmt.Sprintf("bundle %q failed to roll out in revision %d and was rolled back; "+
+		"it is not installed again until the ClusterExtension is annotated with %s=%d", failed.Bundle, failed.Revision, ocv1.AnnotationRetryFailedRevision, failed.Revision)
+}
+
+// isRollingOutOrInstalled reports whether the bundle of target is already installed or
+// rolling out, in which case the rollback was already admitted.
+func isRollingOutOrInstalled(revisionStates *RevisionStates, target *RevisionMetadata) bool {
+	if revisionStates.Installed != nil && revisionStates.Installed.Name == target.Name {
+		return true
+	}
+	return slices.ContainsFunc(revisionStates.RollingOut, func(rm *RevisionMetadata) bool {
+		return rm.Name == target.Name
+	})
+}
+
+// checkRollbackConstraints returns an error when the version of target is outside of the
+// version range of ext, or when target is an older catalog bundle than installed and ext
+// enforces the upgrade constraints of the catalog.
+func checkRollbackConstraints(ext *ocv1.ClusterExtension, installed, target *RevisionMetadata) error {
+	targetVersion, err := bundle.NewLegacyRegistryV1VersionRelease(target.Version)
+	if err != nil {
+		return fmt.Errorf("cannot check rollback constraints: invalid version %q of bundle %q: %w", target.Version, target.Name, err)
+	}
+
+	var versionRange string
+	switch {
+	case ext.Spec.Source.Catalog != nil:
+		versionRange = ext.Spec.Source.Catalog.Version
+	case ext.Spec.Source.Helm != nil:
+		versionRange = ext.Spec.Source.Helm.Version
+	}
+	if versionRange != "" {
+		inRange, err := compare.NewVersionRange(versionRange)
+		if err != nil {
+			return fmt.Errorf("desired version range %q is invalid: %w", versionRange, err)
+		}
+		if !inRange(targetVersion.Version) {
+			return fmt.Errorf("cannot roll back to version %q of bundle %q: outside of version range %q; set spec.install.rollback.constraintPolicy to Ignore to roll back anyway", target.Version, target.Name, versionRange)
+		}
+	}
+
+	if installed == nil || ext.Spec.Source.Catalog == nil || ext.Spec.Source.Catalog.UpgradeConstraintPolicy == ocv1.UpgradeConstraintPolicySelfCertified {
+		return nil
+	}
+	installedVersion, err := bundle.NewLegacyRegistryV1VersionRelease(installed.Version)
+	if err != nil {
+		return fmt.Errorf("cannot check rollback constraints: invalid version %q of installed bundle %q: %w", installed.Version, installed.Name, err)
+	}
+	if targetVersion.Compare(*installedVersion) < 0 {
+		return fmt.Errorf("cannot roll back from version %q to older version %q: upgrade constraints of the catalog are enforced; set spec.source.catalog.upgradeConstraintPolicy to SelfCertified or spec.install.rollback.constraintPolicy to Ignore to roll back anyway", installed.Version, target.Version)
+	}
+	return nil
+}
+
+// EnforceExtensionPolicies blocks reconciliation of a ClusterExtension that violates an ExtensionPolicy.
+// The validating webhook rejects violations at admission time; this step covers ClusterExtensions that
+// were admitted before a policy was created or tightened. Existing installed content is left in place,
+// but nothing is resolved or applied until the ClusterExtension or the policy is changed.
+func EnforceExtensionPolicies(checker *extensionpolicy.Checker) ReconcileStepFunc {
+	return func(ctx context.Context, state *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
+		l := log.FromContext(ctx)
+		l.V(1).Info("checking extension policies")
+
+		err := checker.Check(ctx, ext)
+		if err == nil {
+			return nil, nil
+		}
+
+		setInstalledStatusFromRevisionStates(ext, state.revisionStates)
+		var violation *extensionpolicy.ViolationError
+		if !errors.As(err, &violation) {
+			setStatusProgressing(ext, err)
+			ensureFailureConditionsWithReason(ext, ocv1.ReasonRetrying, err.Error())
+			return nil, err
+		}
+		// Policy violations cannot be fixed by retrying. The ExtensionPolicy watch
+		// re-queues the ClusterExtension when a policy changes.
+		err = errorutil.NewTerminalError(ocv1.ReasonBlocked, err)
+		setStatusProgressing(ext, err)
+		ensureFailureConditionsWithReason(ext, ocv1.ReasonBlocked, violation.Error())
+		return nil, err
+	}
+}
+
+// ResolveConfigReferences reads ClusterExtension configuration from a referenced Secret or
+// ConfigMap in the installation namespace and merges the inline configuration on top of it.
+// The referenced object is read with the client returned by clientFor, the client of the
+// ServiceAccount of the ClusterExtension, so that it can only reference objects that its
+// ServiceAccount can read. Problems with the referenced object can only be fixed by the user
+// and are reported as terminal InvalidConfiguration errors; the config source watch re-queues
+// the ClusterExtension once the object changes.
+func ResolveConfigReferences(clientFor func(context.Context, *ocv1.ClusterExtension) (client.Client, error)) ReconcileStepFunc {
+	return func(ctx context.Context, state *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
+		var (
+			resolved []byte
+			err      error
+		)
+		if config.HasReferences(ext) {
+			var c client.Client
+			if c, err = clientFor(ctx, ext); err == nil {
+				resolved, err = config.ResolveReferences(ctx, c, ext)
+			}
+		}
+		if err != nil {
+			var refErr *config.ReferenceError
+			if errors.As(err, &refErr) {
+				err = errorutil.NewTerminalError(ocv1.ReasonInvalidConfiguration, fmt.Errorf("invalid ClusterExtension configuration: %w", err))
+			}
+			setStatusProgressing(ext, err)
+			setInstalledStatusFromRevisionStates(ext, state.revisionStates)
+			return nil, err
+		}
+		state.resolvedConfig = resolved
+		return nil, nil
+	}
+}
+
+// extensionWithResolvedConfig returns the ClusterExtension to hand to an applier. When the
+// configuration was read from a Secret or ConfigMap, it returns a copy of ext carrying the
+// resolved configuration inline so appliers don't need to know where it came from.
+func extensionWithResolvedConfig(ext *ocv1.ClusterExtension, state *reconcileState) *ocv1.ClusterExtension {
+	if state.resolvedConfig == nil {
+		return ext
+	}
+	resolved := ext.DeepCopy()
+	resolved.Spec.Config = &ocv1.ClusterExtensionConfig{
+		ConfigType: ocv1.ClusterExtensionConfigTypeInline,
+		Inline:     &apiextensionsv1.JSON{Raw: state.resolvedConfig},
+	}
+	return resolved
+}
+
 // ResolveBundle resolves the bundle to install or roll out for a ClusterExtension.
 // It requires a controller-runtime client (in addition to the resolve.Resolver) to enable
 // intelligent error handling when resolution fails. The client is used to check if ClusterCatalogs
@@ -107,14 +484,6 @@ func ResolveBundle(r resolve.Resolver, c client.Client) ReconcileStepFunc {
 			return nil, nil
 		}
 
-		// Resolve a new bundle from the catalog
-		l.V(1).Info("resolving bundle")
-		var bm *ocv1.BundleMetadata
-		if state.revisionStates.Installed != nil {
-			bm = &state.revisionStates.Installed.BundleMetadata
-		}
-		resolvedBundle, resolvedBundleVersion, resolvedDeprecation, err := r.Resolve(ctx, ext, bm)
-
 		// Get the installed bundle name for deprecation status.
 		// BundleDeprecated should reflect what's currently running, not what we're trying to install.
 		installedBundleName := ""
@@ -122,6 +491,37 @@ func ResolveBundle(r resolve.Resolver, c client.Client) ReconcileStepFunc {
 			installedBundleName = state.revisionStates.Installed.Name
 		}
 
+		// Bundles that failed to roll out are excluded, keeping the installed bundle
+		// rather than resolving the failed bundle again.
+		if excluded := excludedFailedBundles(state, ext); len(excluded) > 0 {
+			l.V(1).Info("excluding bundles that failed to roll out", "bundles", excluded)
+			ctx = resolve.WithExcludedBundles(ctx, excluded...)
+		}
+
+		// Resolve a new bundle from the catalog
+		l.V(1).Info("resolving bundle")
+		var (
+			bm               *ocv1.BundleMetadata
+			installedCatalog string
+		)
+		if state.revisionStates.Installed != nil {
+			bm = &state.revisionStates.Installed.BundleMetadata
+			installedCatalog = state.revisionStates.Installed.Catalog
+		}
+		var (
+			resolvedBundle        *declcfg.Bundle
+			resolvedBundleVersion *bundle.VersionRelease
+			resolvedDeprecation   *declcfg.Deprecation
+			resolution            *resolve.CatalogResolution
+			err                   error
+		)
+		if cr, ok := r.(resolve.CatalogSourceResolver); ok {
+			resolvedBundle, resolvedBundleVersion, resolvedDeprecation, resolution, err = cr.ResolveWithCatalog(ctx, ext, bm, installedCatalog)
+			setIncompatibleBundlesStatus(ext, resolution)
+		} else {
+			resolvedBundle, resolvedBundleVersion, resolvedDeprecation, err = r.Resolve(ctx, ext, bm)
+		}
+
 		// Set deprecation status based on resolution results:
 		//  - If resolution succeeds: hasCatalogData=true, deprecation shows catalog data (nil=not deprecated)
 		//  - If resolution fails but returns deprecation: hasCatalogData=true, show package/channel deprecation warnings
@@ -138,13 +538,43 @@ func ResolveBundle(r resolve.Resolver, c client.Client) ReconcileStepFunc {
 		hasCatalogData := err == nil || resolvedDeprecation != nil
 		SetDeprecationStatus(ext, installedBundleName, resolvedDeprecation, hasCatalogData)
 
+		// The resolver applies the Migrate policy, resolving from the successors of
+		// deprecated channels; the spec is left untouched.
+		policy := resolve.ChannelDeprecationPolicy(ext)
+		var deprecatedChannels []resolve.DeprecatedChannel
+		if resolution != nil {
+			deprecatedChannels = resolution.DeprecatedChannels
+		}
+		if policy == ocv1.ChannelDeprecationPolicyMigrate {
+			setChannelMigrationStatus(ext, deprecatedChannels)
+		}
+
 		if err != nil {
 			return handleResolutionError(ctx, c, state, ext, err)
 		}
 
+		// The Block policy only blocks upgrades; initial installs are not blocked.
+		if policy == ocv1.ChannelDeprecationPolicyBlock && installedBundleName != "" && resolvedBundle.Name != installedBundleName {
+			if unacknowledged := unacknowledgedChannelDeprecations(ext, deprecatedChannels); len(unacknowledged) > 0 {
+				msg := fmt.Sprintf("change to bundle %q blocked: %s; update the requested channels or acknowledge the deprecation with the %q annotation",
+					resolvedBundle.Name, strings.Join(unacknowledged, "; "), ocv1.AnnotationAcknowledgedDeprecatedChannels)
+				l.Info("bundle change blocked by channel deprecation policy", "bundle", resolvedBundle.Name)
+				err := errorutil.NewTerminalError(ocv1.ReasonBlocked, errors.New(msg))
+				setStatusProgressing(ext, err)
+				setInstalledStatusFromRevisionStates(ext, state.revisionStates)
+				ensureFailureConditionsWithReason(ext, ocv1.ReasonBlocked, msg)
+				return nil, err
+			}
+		}
+
+		var resolvedCatalog string
+		if resolution != nil {
+			resolvedCatalog = resolution.Catalog
+		}
 		state.resolvedRevisionMetadata = &RevisionMetadata{
 			Package: resolvedBundle.Package,
 			Image:   resolvedBundle.Image,
+			Catalog: resolvedCatalog,
 			// TODO: Right now, operator-controller only supports registry+v1 bundles and has no concept
 			//   of a "release" field. If/when we add a release field concept or a new bundle format
 			//   we need to re-evaluate use of `AsLegacyRegistryV1Version` so that we avoid propagating
@@ -155,16 +585,68 @@ func ResolveBundle(r resolve.Resolver, c client.Client) ReconcileStepFunc {
 	}
 }
 
+// setChannelMigrationStatus amends the ChannelDeprecated condition with the
+// successor channels used in place of the deprecated requested channels.
+func setChannelMigrationStatus(ext *ocv1.ClusterExtension, deprecated []resolve.DeprecatedChannel) {
+	cond := apimeta.FindStatusCondition(ext.Status.Conditions, ocv1.TypeChannelDeprecated)
+	if len(deprecated) == 0 || cond == nil || cond.Status != metav1.ConditionTrue {
+		return
+	}
+	messages := []string{cond.Message}
+	for _, dc := range deprecated {
+		messages = append(messages, fmt.Sprintf("channel %q migrated to successor channel %q", dc.Name, dc.Successor))
+	}
+	cond.Message = strings.Join(messages, "\n")
+	SetStatusCondition(&ext.Status.Conditions, *cond)
+}
+
+// setIncompatibleBundlesStatus reports the bundles that were excluded from
+// resolution because they can't run on the cluster. The condition is left
+// untouched when the resolver did not report a resolution.
+func setIncompatibleBundlesStatus(ext *ocv1.ClusterExtension, resolution *resolve.CatalogResolution) {
+	if resolution == nil {
+		return
+	}
+	if len(resolution.IncompatibleBundles) == 0 {
+		apimeta.RemoveStatusCondition(&ext.Status.Conditions, ocv1.TypeIncompatibleBundles)
+		return
+	}
+	SetStatusCondition(&ext.Status.Conditions, metav1.Condition{
+		Type:               ocv1.TypeIncompatibleBundles,
+		Status:             metav1.ConditionTrue,
+		Reason:             ocv1.ReasonIncompatibleWithCluster,
+		Message:            "excluded bundles incompatible with this cluster: " + strings.Join(resolution.IncompatibleBundles, "; "),
+		ObservedGeneration: ext.GetGeneration(),
+	})
+}
+
+// unacknowledgedChannelDeprecations describes the deprecated channels that are
+// not listed in the acknowledged-deprecated-channels annotation of ext.
+func unacknowledgedChannelDeprecations(ext *ocv1.ClusterExtension, deprecated []resolve.DeprecatedChannel) []string {
+	acknowledged := sets.New[string]()
+	for _, ch := range strings.Split(ext.GetAnnotations()[ocv1.AnnotationAcknowledgedDeprecatedChannels], ",") {
+		acknowledged.Insert(strings.TrimSpace(ch))
+	}
+	var unacknowledged []string
+	for _, dc := range deprecated {
+		if !acknowledged.Has(dc.Name) {
+			unacknowledged = append(unacknowledged, fmt.Sprintf("channel %q is deprecated in favor of channel %q", dc.Name, dc.Successor))
+		}
+	}
+	return unacknowledged
+}
+
 // handleResolutionError handles the case when bundle resolution fails.
 //
 // Decision logic (evaluated in order):
 //  1. No installed bundle → Retry (cannot proceed without any bundle)
 //  2. Version change requested → Retry (cannot upgrade without catalog)
-//  3. Cannot check catalog existence → Retry (API error, cannot safely decide)
-//  4. Catalogs exist → Retry (transient error, catalog may be updating)
-//  5. Catalogs deleted → Fallback to installed bundle (maintain current state)
+//  3. Helm sourceType → Retry (chart repository may be temporarily unavailable)
+//  4. Cannot check catalog existence → Retry (API error, cannot safely decide)
+//  5. Catalogs exist → Retry (transient error, catalog may be updating)
+//  6. Catalogs deleted → Fallback to installed bundle (maintain current state)
 //
-// When falling back (case 5), we set the resolved bundle to the installed bundle and return
+// When falling back (case 6), we set the resolved bundle to the installed bundle and return
 // no error, allowing the Apply step to run and maintain resources using the existing installation.
 // The controller watches ClusterCatalog resources, so reconciliation will automatically resume
 // when catalogs return, enabling upgrades.
@@ -185,6 +667,9 @@ func handleResolutionError(ctx context.Context, c client.Client, state *reconcil
 	if ext.Spec.Source.Catalog != nil {
 		specVersion = ext.Spec.Source.Catalog.Version
 	}
+	if ext.Spec.Source.Helm != nil {
+		specVersion = ext.Spec.Source.Helm.Version
+	}
 	installedVersion := state.revisionStates.Installed.Version
 
 	// If spec requests a different version, we cannot fall back - must fail and retry
@@ -199,6 +684,17 @@ func handleResolutionError(ctx context.Context, c client.Client, state *reconcil
 		return nil, err
 	}
 
+	// Chart repositories are not ClusterCatalogs and can't be told apart from a transient
+	// failure, so resolution from a chart repository is always retried.
+	if ext.Spec.Source.SourceType == ocv1.SourceTypeHelm {
+		msg := fmt.Sprintf("failed to resolve chart, retrying: %v", err)
+		l.Error(err, "resolution from chart repository failed - retrying", "chart", getPackageName(ext))
+		setStatusProgressing(ext, err)
+		setInstalledStatusFromRevisionStates(ext, state.revisionStates)
+		ensureFailureConditionsWithReason(ext, ocv1.ReasonRetrying, msg)
+		return nil, err
+	}
+
 	// No version change requested - check if ClusterCatalogs exist
 	// Only fall back if ClusterCatalogs have been deleted
 	catalogsExist, catalogCheckErr := CheckCatalogsExist(ctx, c, ext)
@@ -266,12 +762,16 @@ func getCatalogNameFromSelector(selector *metav1.LabelSelector) string {
 }
 
 // getPackageName safely extracts the package name from the extension spec.
-// Returns empty string if Catalog source is nil.
+// For Helm sources, the chart name is returned. Returns empty string if
+// neither a Catalog nor a Helm source is set.
 func getPackageName(ext *ocv1.ClusterExtension) string {
-	if ext.Spec.Source.Catalog == nil {
-		return ""
+	switch {
+	case ext.Spec.Source.Catalog != nil:
+		return ext.Spec.Source.Catalog.PackageName
+	case ext.Spec.Source.Helm != nil:
+		return ext.Spec.Source.Helm.Chart
 	}
-	return ext.Spec.Source.Catalog.PackageName
+	return ""
 }
 
 // CheckCatalogsExist checks if any ClusterCatalogs matching the extension's selector exist.
@@ -359,19 +859,72 @@ func UnpackBundle(i imageutil.Puller, cache imageutil.Cache) ReconcileStepFunc {
 	}
 }
 
-func ApplyBundle(a Applier) ReconcileStepFunc {
+// PreviewUpgrade reports the changes that upgrading to the resolved bundle, or to the current
+// configuration, makes to the installed objects in status.upgradePreview. When the upgrade approval
+// policy is Manual, the remaining steps are skipped until the ClusterExtension is annotated with
+// ocv1.AnnotationApprovedUpgrade set to the digest of the preview.
+func PreviewUpgrade(p UpgradePreviewer) ReconcileStepFunc {
 	return func(ctx context.Context, state *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
-		l := log.FromContext(ctx)
-		revisionAnnotations := map[string]string{
-			labels.BundleNameKey:      state.resolvedRevisionMetadata.Name,
-			labels.PackageNameKey:     state.resolvedRevisionMetadata.Package,
-			labels.BundleVersionKey:   state.resolvedRevisionMetadata.Version,
-			labels.BundleReferenceKey: state.resolvedRevisionMetadata.Image,
+		// Without content, the installed content is maintained as is.
+		if state.imageFS == nil || state.revisionStates.Installed == nil {
+			ext.Status.UpgradePreview = nil
+			return nil, nil
 		}
-		objLbls := map[string]string{
-			labels.OwnerKindKey: ocv1.ClusterExtensionKind,
-			labels.OwnerNameKey: ext.GetName(),
+
+		preview, err := p.PreviewUpgrade(ctx, state.imageFS, extensionWithResolvedConfig(ext, state), ownerObjectLabels(ext), bundleRevisionAnnotations(state.resolvedRevisionMetadata))
+		if err != nil {
+			setInstalledStatusFromRevisionStates(ext, state.revisionStates)
+			setStatusProgressing(ext, wrapErrorWithResolutionInfo(state.resolvedRevisionMetadata.BundleMetadata, fmt.Errorf("error previewing upgrade: %w", err)))
+			return nil, err
 		}
+		ext.Status.UpgradePreview = preview
+		if preview == nil || ext.Spec.Install == nil || ext.Spec.Install.UpgradeApproval != ocv1.UpgradeApprovalPolicyManual ||
+			ext.GetAnnotations()[ocv1.AnnotationApprovedUpgrade] == preview.Digest {
+			return nil, nil
+		}
+
+		log.FromContext(ctx).Info("upgrade awaits approval", "bundle", preview.Bundle.Name, "digest", preview.Digest)
+		setInstalledStatusFromRevisionStates(ext, state.revisionStates)
+		apimeta.SetStatusCondition(&ext.Status.Conditions, metav1.Condition{
+			Type:   ocv1.TypeProgressing,
+			Status: metav1.ConditionTrue,
+			Reason: ocv1.ReasonAwaitingApproval,
+			Message: fmt.Sprintf("upgrade to bundle %q (version %q) adds %d, removes %d and changes %d objects; "+
+				"review status.upgradePreview and annotate the ClusterExtension with %s=%s to approve it",
+				preview.Bundle.Name, preview.Bundle.Version, preview.Added, preview.Removed, preview.Changed, ocv1.AnnotationApprovedUpgrade, preview.Digest),
+			ObservedGeneration: ext.GetGeneration(),
+		})
+		return &ctrl.Result{}, nil
+	}
+}
+
+// bundleRevisionAnnotations returns the annotations identifying the bundle of a revision.
+func bundleRevisionAnnotations(meta *RevisionMetadata) map[string]string {
+	revisionAnnotations := map[string]string{
+		labels.BundleNameKey:      meta.Name,
+		labels.PackageNameKey:     meta.Package,
+		labels.BundleVersionKey:   meta.Version,
+		labels.BundleReferenceKey: meta.Image,
+	}
+	if meta.Catalog != "" {
+		revisionAnnotations[labels.CatalogNameKey] = meta.Catalog
+	}
+	return revisionAnnotations
+}
+
+// ownerObjectLabels returns the labels identifying ext as the owner of the objects it installs.
+func ownerObjectLabels(ext *ocv1.ClusterExtension) map[string]string {
+	return map[string]string{
+		labels.OwnerKindKey: ocv1.ClusterExtensionKind,
+		labels.OwnerNameKey: ext.GetName(),
+	}
+}
+
+func ApplyBundle(a Applier) ReconcileStepFunc {
+	return func(ctx context.Context, state *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
+		l := log.FromContext(ctx)
+		revisionAnnotations := bundleRevisionAnnotations(state.resolvedRevisionMetadata)
+		objLbls := ownerObjectLabels(ext)
 
 		l.Info("applying bundle contents")
 		// NOTE: We need to be cautious of eating errors here.
@@ -383,7 +936,7 @@ func ApplyBundle(a Applier) ReconcileStepFunc {
 		// to ensure exponential backoff can occur:
 		//   - Permission errors (it is not possible to watch changes to permissions.
 		//     The only way to eventually recover from permission errors is to keep retrying).
-		rolloutSucceeded, rolloutStatus, err := a.Apply(ctx, state.imageFS, ext, objLbls, revisionAnnotations)
+		rolloutSucceeded, rolloutStatus, err := a.Apply(ctx, state.imageFS, extensionWithResolvedConfig(ext, state), objLbls, revisionAnnotations)
 
 		// Set installed status
 		if rolloutSucceeded {
@@ -412,3 +965,57 @@ func ApplyBundle(a Applier) ReconcileStepFunc {
 		return nil, nil
 	}
 }
+
+// maxDriftedObjects is the maximum number of objects reported in status.driftDetected.
+const maxDriftedObjects = 16
+
+// RecordDrift records the installed objects of a ClusterExtension whose changes on the cluster
+// were reverted by applying the bundle in status.driftDetected. It runs after the bundle is
+// applied.
+func RecordDrift(r DriftReporter) ReconcileStepFunc {
+	return func(_ context.Context, _ *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
+		ext.Status.DriftDetected = recordDrift(ext.Status.DriftDetected, r.TakeRevertedDrift(ext), metav1.Now())
+		return nil, nil
+	}
+}
+
+// recordDrift returns drift updated with the objects whose changes were reverted at now, or
+// drift unchanged when no changes were reverted.
+func recordDrift(drift *ocv1.DriftDetected, reverted []ocv1.ObjectReference, now metav1.Time) *ocv1.DriftDetected {
+	if len(reverted) == 0 {
+		return drift
+	}
+	out := &ocv1.DriftDetected{
+		LastDetectedAt: now,
+		Count:          1,
+		Objects:        reverted[:min(len(reverted), maxDriftedObjects)],
+	}
+	if drift != nil {
+		out.Count = drift.Count + 1
+	}
+	return out
+}
+
+// RequeueRunningHooks requeues ClusterExtensions after interval while the hooks of their
+// release run in the background, so that the result of the hooks is reported once they
+// complete. Nothing else triggers a reconcile when they do.
+func RequeueRunningHooks(c HookOperationTracker, interval time.Duration) ReconcileStepFunc {
+	return func(_ context.Context, _ *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
+		if !c.HooksRunning(ext) {
+			return nil, nil
+		}
+		return &ctrl.Result{RequeueAfter: interval}, nil
+	}
+}
+
+// RequeueChartSources requeues ClusterExtensions with the Helm sourceType after interval so
+// that chart versions newly published to the chart repository are resolved. Unlike
+// ClusterCatalogs, chart repositories can't be watched.
+func RequeueChartSources(interval time.Duration) ReconcileStepFunc {
+	return func(_ context.Context, _ *reconcileState, ext *ocv1.ClusterExtension) (*ctrl.Result, error) {
+		if ext.Spec.Source.SourceType != ocv1.SourceTypeHelm {
+			return nil, nil
+		}
+		return &ctrl.Result{RequeueAfter: interval}, nil
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -15,15 +16,19 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
 	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
 	"pkg.package-operator.run/boxcutter"
 	"pkg.package-operator.run/boxcutter/machinery"
 	machinerytypes "pkg.package-operator.run/boxcutter/machinery/types"
 	"pkg.package-operator.run/boxcutter/probing"
+	"pkg.package-operator.run/boxcutter/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -35,7 +40,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/applier"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/objectprobe"
 )
 
 const (
@@ -48,9 +55,33 @@ type ClusterExtensionRevisionReconciler struct {
 	Client                client.Client
 	RevisionEngineFactory RevisionEngineFactory
 	TrackingCache         trackingCache
+	// PodReader reads the Deployments soaking in a canary rollout, and their ReplicaSets and
+	// pods. It defaults to Client.
+	PodReader client.Reader
+	// ReportDrift records the objects whose changes are reverted after the revision succeeded
+	// in status.driftDetected.
+	ReportDrift bool
 	// track if we have queued up the reconciliation that detects eventual progress deadline issues
 	// keys is revision UUID, value is boolean
 	progressDeadlineCheckInFlight sync.Map
+	// backs off the retries of blocked revisions, keyed by revision UUID
+	blockedBackoff     workqueue.TypedRateLimiter[types.UID]
+	blockedBackoffOnce sync.Once
+}
+
+// maxPhaseStatusObjects is the maximum number of objects listed in the status of a phase.
+const maxPhaseStatusObjects = 16
+
+const (
+	blockedRetryBaseDelay = 10 * time.Second
+	blockedRetryMaxDelay  = 5 * time.Minute
+)
+
+func (c *ClusterExtensionRevisionReconciler) retryBackoff() workqueue.TypedRateLimiter[types.UID] {
+	c.blockedBackoffOnce.Do(func() {
+		c.blockedBackoff = workqueue.NewTypedItemExponentialFailureRateLimiter[types.UID](blockedRetryBaseDelay, blockedRetryMaxDelay)
+	})
+	return c.blockedBackoff
 }
 
 type trackingCache interface {
@@ -79,14 +110,31 @@ func (c *ClusterExtensionRevisionReconciler) Reconcile(ctx context.Context, req
 	reconciledRev := existingRev.DeepCopy()
 	res, reconcileErr := c.reconcile(ctx, reconciledRev)
 
-	if pd := existingRev.Spec.ProgressDeadlineMinutes; pd > 0 {
+	// A failed soak blocks the active revision for good, even if the restarted pods were replaced
+	// since. Archived and deleted revisions are still torn down.
+	if cnd := meta.FindStatusCondition(existingRev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeProgressing); cnd != nil &&
+		cnd.Status == metav1.ConditionFalse && cnd.Reason == ocv1.ReasonSoakFailed && isActive(existingRev) {
+		markAsNotProgressing(reconciledRev, cnd.Reason, cnd.Message)
+		reconcileErr = nil
+		res = ctrl.Result{}
+	}
+
+	if pd := existingRev.Spec.ProgressDeadlineMinutes; pd > 0 && isActive(existingRev) {
 		cnd := meta.FindStatusCondition(reconciledRev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeProgressing)
 		isStillProgressing := cnd != nil && cnd.Status == metav1.ConditionTrue && cnd.Reason != ocv1.ReasonSucceeded
 		succeeded := meta.IsStatusConditionTrue(reconciledRev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeSucceeded)
 		// check if we reached the progress deadline only if the revision is still progressing and has not succeeded yet
 		if isStillProgressing && !succeeded {
 			timeout := time.Duration(pd) * time.Minute
-			if time.Since(existingRev.CreationTimestamp.Time) > timeout {
+			// The deadline is measured from when the revision was last resumed, so that the time it
+			// spent paused doesn't count. Resuming a paused revision is the only way for the
+			// Progressing condition to become True again once it was reported.
+			progressingSince := existingRev.CreationTimestamp.Time
+			if meta.FindStatusCondition(existingRev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeProgressing) != nil &&
+				cnd.LastTransitionTime.After(progressingSince) {
+				progressingSince = cnd.LastTransitionTime.Time
+			}
+			if time.Since(progressingSince) > timeout {
 				// progress deadline reached, reset any errors and stop reconciling this revision
 				markAsNotProgressing(reconciledRev, ocv1.ReasonProgressDeadlineExceeded, fmt.Sprintf("Revision has not rolled out for %d minutes.", pd))
 				reconcileErr = nil
@@ -94,7 +142,9 @@ func (c *ClusterExtensionRevisionReconciler) Reconcile(ctx context.Context, req
 			} else if _, found := c.progressDeadlineCheckInFlight.Load(existingRev.GetUID()); !found && reconcileErr == nil {
 				// if we haven't already queued up a reconcile to check for progress deadline, queue one up, but only once
 				c.progressDeadlineCheckInFlight.Store(existingRev.GetUID(), true)
-				res = ctrl.Result{RequeueAfter: timeout}
+				if res.RequeueAfter == 0 || res.RequeueAfter > timeout {
+					res = ctrl.Result{RequeueAfter: timeout}
+				}
 			}
 		}
 	}
@@ -134,15 +184,27 @@ func checkForUnexpectedClusterExtensionRevisionFieldChange(a, b ocv1.ClusterExte
 func (c *ClusterExtensionRevisionReconciler) reconcile(ctx context.Context, rev *ocv1.ClusterExtensionRevision) (ctrl.Result, error) {
 	l := log.FromContext(ctx)
 
-	revision, opts, err := c.toBoxcutterRevision(ctx, rev)
+	var soak *objectprobe.DeploymentSoak
+	if rev.Spec.Canary != nil && isActive(rev) {
+		podReader := c.PodReader
+		if podReader == nil {
+			podReader = c.Client
+		}
+		soak = objectprobe.NewDeploymentSoak(ctx, podReader, *rev.Spec.Canary, rev.CreationTimestamp.Time, time.Now())
+	}
+
+	revision, opts, err := c.toBoxcutterRevision(ctx, rev, soak)
 	if err != nil {
 		setRetryingConditions(rev, err.Error())
 		return ctrl.Result{}, fmt.Errorf("converting to boxcutter revision: %v", err)
 	}
 
-	if !rev.DeletionTimestamp.IsZero() || rev.Spec.LifecycleState == ocv1.ClusterExtensionRevisionLifecycleStateArchived {
+	if !isActive(rev) {
 		return c.teardown(ctx, rev)
 	}
+	if rev.Spec.Paused {
+		return c.pause(ctx, rev)
+	}
 
 	revVersion := rev.GetAnnotations()[labels.BundleVersionKey]
 	//
@@ -158,6 +220,23 @@ func (c *ClusterExtensionRevisionReconciler) reconcile(ctx context.Context, rev
 		return ctrl.Result{}, werr
 	}
 
+	// Objects that may be adopted are looked up before they are reconciled, to tell them from the
+	// objects that the ClusterExtension installed before. Once the revision succeeded, all of its
+	// objects are owned by it.
+	var adoptable []ocv1.AdoptedObject
+	if !meta.IsStatusConditionTrue(rev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeSucceeded) {
+		adoptable, err = c.adoptableObjects(ctx, rev)
+		if err != nil {
+			setRetryingConditions(rev, err.Error())
+			return ctrl.Result{}, fmt.Errorf("looking up adoptable objects: %v", err)
+		}
+	}
+
+	if err := c.removeIgnoredFields(ctx, rev, revision); err != nil {
+		setRetryingConditions(rev, err.Error())
+		return ctrl.Result{}, fmt.Errorf("removing ignored fields: %v", err)
+	}
+
 	revisionEngine, err := c.RevisionEngineFactory.CreateRevisionEngine(ctx, rev)
 	if err != nil {
 		setRetryingConditions(rev, err.Error())
@@ -174,37 +253,27 @@ func (c *ClusterExtensionRevisionReconciler) reconcile(ctx context.Context, rev
 		return ctrl.Result{}, fmt.Errorf("revision reconcile: %v", err)
 	}
 
-	// Retry failing preflight checks with a flat 10s retry.
-	// TODO: report status, backoff?
-	if verr := rres.GetValidationError(); verr != nil {
-		l.Error(fmt.Errorf("%w", verr), "preflight validation failed, retrying after 10s")
-		setRetryingConditions(rev, fmt.Sprintf("revision validation error: %s", verr))
-		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	report := newRevisionReport(rev, rres)
+	rev.Status.Phases = report.phases
+	recordAdoptedObjects(rev, adoptable, report.applied)
+	if c.ReportDrift && meta.IsStatusConditionTrue(rev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeSucceeded) {
+		rev.Status.DriftDetected = recordDrift(rev.Status.DriftDetected, report.changed, metav1.Now())
 	}
 
-	for i, pres := range rres.GetPhases() {
-		if verr := pres.GetValidationError(); verr != nil {
-			l.Error(fmt.Errorf("%w", verr), "phase preflight validation failed, retrying after 10s", "phase", i)
-			setRetryingConditions(rev, fmt.Sprintf("phase %d validation error: %s", i, verr))
-			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
-		}
-
-		var collidingObjs []string
-		for _, ores := range pres.GetObjects() {
-			if ores.Action() == machinery.ActionCollision {
-				collidingObjs = append(collidingObjs, ores.String())
-			}
-		}
-
-		if len(collidingObjs) > 0 {
-			l.Error(fmt.Errorf("object collision detected"), "object collision, retrying after 10s", "phase", i, "collisions", collidingObjs)
-			setRetryingConditions(rev, fmt.Sprintf("revision object collisions in phase %d\n%s", i, strings.Join(collidingObjs, "\n\n")))
-			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
-		}
+	// Retry failing preflight checks and collisions with an exponential backoff, as no event
+	// signals that they were resolved.
+	if len(report.blocked) > 0 {
+		after := c.retryBackoff().When(rev.GetUID())
+		l.Error(errors.New(report.blocked[0]), "revision is blocked, retrying", "after", after, "blocked", report.blocked)
+		setRetryingConditions(rev, summarizeBlockingObjects(report.blocked))
+		return ctrl.Result{RequeueAfter: after}, nil
 	}
+	c.retryBackoff().Forget(rev.GetUID())
 
 	if !rres.InTransition() {
 		markAsProgressing(rev, ocv1.ReasonSucceeded, fmt.Sprintf("Revision %s has rolled out.", revVersion))
+	} else if len(report.waiting) > 0 {
+		markAsProgressing(rev, ocv1.ReasonRollingOut, fmt.Sprintf("Revision %s is rolling out. %s", revVersion, summarizeBlockingObjects(report.waiting)))
 	} else {
 		markAsProgressing(rev, ocv1.ReasonRollingOut, fmt.Sprintf("Revision %s is rolling out.", revVersion))
 	}
@@ -270,12 +339,24 @@ func (c *ClusterExtensionRevisionReconciler) reconcile(ctx context.Context, rev
 		} else {
 			markAsUnavailable(rev, ocv1.ReasonRollingOut, fmt.Sprintf("Revision %s is rolling out.", revVersion))
 		}
+
+		if soak != nil {
+			if failures := soak.Failures(); len(failures) > 0 {
+				markAsNotProgressing(rev, ocv1.ReasonSoakFailed, strings.Join(failures, "\n"))
+				return ctrl.Result{}, nil
+			}
+			// Check again once the next soak ends, since no event marks the end of a soak.
+			if after := soak.RequeueAfter(); after > 0 {
+				return ctrl.Result{RequeueAfter: after}, nil
+			}
+		}
 	}
 
 	return ctrl.Result{}, nil
 }
 
 func (c *ClusterExtensionRevisionReconciler) teardown(ctx context.Context, rev *ocv1.ClusterExtensionRevision) (ctrl.Result, error) {
+	c.retryBackoff().Forget(rev.GetUID())
 	if err := c.TrackingCache.Free(ctx, rev); err != nil {
 		markAsAvailableUnknown(rev, ocv1.ClusterExtensionRevisionReasonReconciling, err.Error())
 		return ctrl.Result{}, fmt.Errorf("error stopping informers: %v", err)
@@ -286,12 +367,77 @@ func (c *ClusterExtensionRevisionReconciler) teardown(ctx context.Context, rev *
 		return ctrl.Result{}, nil
 	}
 
+	if !rev.DeletionTimestamp.IsZero() {
+		if err := c.orphanObjects(ctx, rev); err != nil {
+			markAsAvailableUnknown(rev, ocv1.ClusterExtensionRevisionReasonReconciling, err.Error())
+			return ctrl.Result{}, fmt.Errorf("error orphaning objects: %v", err)
+		}
+	}
+
 	if err := c.removeFinalizer(ctx, rev, clusterExtensionRevisionTeardownFinalizer); err != nil {
 		return ctrl.Result{}, fmt.Errorf("error removing teardown finalizer: %v", err)
 	}
 	return ctrl.Result{}, nil
 }
 
+// pause stops the reconciliation of the objects of rev, and the watches that requeue rev when
+// they change. The watches are established again once rev is resumed.
+func (c *ClusterExtensionRevisionReconciler) pause(ctx context.Context, rev *ocv1.ClusterExtensionRevision) (ctrl.Result, error) {
+	c.retryBackoff().Forget(rev.GetUID())
+	if err := c.TrackingCache.Free(ctx, rev); err != nil {
+		setRetryingConditions(rev, err.Error())
+		return ctrl.Result{}, fmt.Errorf("error stopping informers: %v", err)
+	}
+	markAsNotProgressing(rev, ocv1.ReasonPaused, "revision is paused")
+	// The progress deadline is checked again once the revision is resumed.
+	c.progressDeadlineCheckInFlight.Delete(rev.GetUID())
+	return ctrl.Result{}, nil
+}
+
+// orphanObjects keeps the objects of rev selected by its uninstall policy, when rev is deleted
+// because its ClusterExtension is deleted. The objects are garbage-collected with rev otherwise.
+func (c *ClusterExtensionRevisionReconciler) orphanObjects(ctx context.Context, rev *ocv1.ClusterExtensionRevision) error {
+	policy := rev.Spec.UninstallPolicy
+	if policy == "" || policy == ocv1.UninstallPolicyDelete {
+		return nil
+	}
+
+	// Revisions are also deleted to enforce the history limit of the ClusterExtension.
+	ext := &ocv1.ClusterExtension{}
+	err := c.Client.Get(ctx, client.ObjectKey{Name: rev.Labels[labels.OwnerNameKey]}, ext)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return fmt.Errorf("getting ClusterExtension: %w", err)
+	case ext.DeletionTimestamp.IsZero():
+		return nil
+	}
+
+	cl, err := c.RevisionEngineFactory.CreateRevisionClient(ctx, rev)
+	if err != nil {
+		return err
+	}
+	var orphaned int
+	for _, phase := range rev.Spec.Phases {
+		for _, specObj := range phase.Objects {
+			gvk := specObj.Object.GroupVersionKind()
+			if !applier.UninstallPolicyKeeps(policy, gvk.GroupKind()) {
+				continue
+			}
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(gvk)
+			obj.SetName(specObj.Object.GetName())
+			obj.SetNamespace(specObj.Object.GetNamespace())
+			if err := applier.OrphanObject(ctx, cl, obj); err != nil {
+				return fmt.Errorf("orphaning %s %q: %w", gvk.Kind, obj.GetName(), err)
+			}
+			orphaned++
+		}
+	}
+	log.FromContext(ctx).Info("orphaned objects of uninstalled revision", "policy", policy, "objects", orphaned)
+	return nil
+}
+
 type Sourcerer interface {
 	Source(handler handler.EventHandler, predicates ...predicate.Predicate) source.Source
 }
@@ -303,11 +449,11 @@ func (c *ClusterExtensionRevisionReconciler) SetupWithManager(mgr ctrl.Manager)
 			if !ok {
 				return true
 			}
-			// allow deletions to happen
-			if !rev.DeletionTimestamp.IsZero() {
+			// allow deletions and archiving to happen
+			if !isActive(rev) {
 				return true
 			}
-			if cnd := meta.FindStatusCondition(rev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeProgressing); cnd != nil && cnd.Status == metav1.ConditionFalse && cnd.Reason == ocv1.ReasonProgressDeadlineExceeded {
+			if hasFailedRollout(rev.Status.Conditions) {
 				return false
 			}
 			return true
@@ -428,7 +574,9 @@ func (c *ClusterExtensionRevisionReconciler) listPreviousRevisions(ctx context.C
 	return previous, nil
 }
 
-func (c *ClusterExtensionRevisionReconciler) toBoxcutterRevision(ctx context.Context, rev *ocv1.ClusterExtensionRevision) (*boxcutter.Revision, []boxcutter.RevisionReconcileOption, error) {
+// toBoxcutterRevision converts rev to a boxcutter revision. The Deployments of the revision
+// must pass soak, if set, to complete their phase, and roll out as canaries until then.
+func (c *ClusterExtensionRevisionReconciler) toBoxcutterRevision(ctx context.Context, rev *ocv1.ClusterExtensionRevision, soak *objectprobe.DeploymentSoak) (*boxcutter.Revision, []boxcutter.RevisionReconcileOption, error) {
 	previous, err := c.listPreviousRevisions(ctx, rev)
 	if err != nil {
 		return nil, nil, fmt.Errorf("listing previous revisions: %w", err)
@@ -440,11 +588,20 @@ func (c *ClusterExtensionRevisionReconciler) toBoxcutterRevision(ctx context.Con
 		previousObjs[i] = rev
 	}
 
+	// Probes declared for objects of the revision are checked in addition to the built-in ones.
+	objectProbes := objectprobe.ObjectProbes{}
+	progressProbes := probing.And{
+		deploymentProbe, statefulSetProbe, crdProbe, issuerProbe, certProbe, objectProbes,
+	}
+	if soak != nil {
+		progressProbes = append(progressProbes, &probing.GroupKindSelector{
+			GroupKind: schema.GroupKind{Group: appsv1.GroupName, Kind: "Deployment"},
+			Prober:    soak,
+		})
+	}
 	opts := []boxcutter.RevisionReconcileOption{
 		boxcutter.WithPreviousOwners(previousObjs),
-		boxcutter.WithProbe(boxcutter.ProgressProbeType, probing.And{
-			deploymentProbe, statefulSetProbe, crdProbe, issuerProbe, certProbe,
-		}),
+		boxcutter.WithProbe(boxcutter.ProgressProbeType, progressProbes),
 	}
 
 	r := &boxcutter.Revision{
@@ -464,6 +621,27 @@ func (c *ClusterExtensionRevisionReconciler) toBoxcutterRevision(ctx context.Con
 			objLabels[labels.OwnerNameKey] = rev.Labels[labels.OwnerNameKey]
 			obj.SetLabels(objLabels)
 
+			// Deployments roll out as canaries until they soaked.
+			if soak != nil && obj.GroupVersionKind().GroupKind() == (schema.GroupKind{Group: appsv1.GroupName, Kind: "Deployment"}) {
+				soaked, err := soak.Soaked(client.ObjectKeyFromObject(obj), rev)
+				if err != nil {
+					return nil, nil, fmt.Errorf("checking the soak of Deployment %q: %w", obj.GetName(), err)
+				}
+				if !soaked {
+					if err := objectprobe.CanaryDeployment(obj, *rev.Spec.Canary); err != nil {
+						return nil, nil, fmt.Errorf("rolling out Deployment %q as a canary: %w", obj.GetName(), err)
+					}
+				}
+			}
+
+			if len(specObj.Probes) > 0 {
+				prober, err := objectprobe.New(specObj.Probes)
+				if err != nil {
+					return nil, nil, fmt.Errorf("probes of %s %q: %w", obj.GetKind(), obj.GetName(), err)
+				}
+				objectProbes[objectprobe.KeyOf(obj)] = prober
+			}
+
 			switch specObj.CollisionProtection {
 			case ocv1.CollisionProtectionIfNoController, ocv1.CollisionProtectionNone:
 				opts = append(opts, boxcutter.WithObjectReconcileOptions(
@@ -538,6 +716,243 @@ var (
 	}
 )
 
+// adoptableObjects returns the existing objects of rev that the ClusterExtension did not install,
+// and that rev may adopt because of their collision protection.
+func (c *ClusterExtensionRevisionReconciler) adoptableObjects(ctx context.Context, rev *ocv1.ClusterExtensionRevision) ([]ocv1.AdoptedObject, error) {
+	var adoptable []ocv1.AdoptedObject
+	for _, phase := range rev.Spec.Phases {
+		for _, specObj := range phase.Objects {
+			if specObj.CollisionProtection != ocv1.CollisionProtectionIfNoController && specObj.CollisionProtection != ocv1.CollisionProtectionNone {
+				continue
+			}
+
+			existing := &unstructured.Unstructured{}
+			existing.SetGroupVersionKind(specObj.Object.GroupVersionKind())
+			if err := c.TrackingCache.Get(ctx, client.ObjectKeyFromObject(&specObj.Object), existing); apierrors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return nil, fmt.Errorf("getting %s %q: %w", specObj.Object.GetKind(), specObj.Object.GetName(), err)
+			}
+			if existing.GetLabels()[labels.OwnerNameKey] == rev.Labels[labels.OwnerNameKey] {
+				continue
+			}
+
+			obj := ocv1.AdoptedObject{ObjectReference: ocv1.ObjectReference{
+				Group:     existing.GroupVersionKind().Group,
+				Kind:      existing.GetKind(),
+				Namespace: existing.GetNamespace(),
+				Name:      existing.GetName(),
+			}}
+			if owner := metav1.GetControllerOf(existing); owner != nil {
+				obj.PreviousController = owner.Kind + "/" + owner.Name
+			}
+			adoptable = append(adoptable, obj)
+		}
+	}
+	return adoptable, nil
+}
+
+// removeIgnoredFields removes the fields of the existing objects of revision that are ignored by
+// rev, so that they are not reverted. Objects that don't exist yet are created with these fields.
+func (c *ClusterExtensionRevisionReconciler) removeIgnoredFields(ctx context.Context, rev *ocv1.ClusterExtensionRevision, revision *boxcutter.Revision) error {
+	if len(rev.Spec.IgnoreDifferences) == 0 {
+		return nil
+	}
+	for i := range revision.Phases {
+		for j := range revision.Phases[i].Objects {
+			obj := &revision.Phases[i].Objects[j]
+			pointers := applier.IgnoredFields(rev.Spec.IgnoreDifferences, obj)
+			if len(pointers) == 0 {
+				continue
+			}
+			live := &unstructured.Unstructured{}
+			live.SetGroupVersionKind(obj.GroupVersionKind())
+			// Objects whose CustomResourceDefinition is installed by an earlier phase don't exist yet.
+			if err := c.TrackingCache.Get(ctx, client.ObjectKeyFromObject(obj), live); apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			} else if err != nil {
+				return fmt.Errorf("getting %s %q: %w", obj.GetKind(), obj.GetName(), err)
+			}
+			applier.RemoveIgnoredFields(obj, pointers)
+		}
+	}
+	return nil
+}
+
+// recordAdoptedObjects adds the adoptable objects that were applied to the adopted objects of rev.
+func recordAdoptedObjects(rev *ocv1.ClusterExtensionRevision, adoptable []ocv1.AdoptedObject, applied []ocv1.ObjectReference) {
+	now := metav1.Now()
+	for _, obj := range adoptable {
+		if slices.ContainsFunc(rev.Status.AdoptedObjects, func(a ocv1.AdoptedObject) bool {
+			return a.ObjectReference == obj.ObjectReference
+		}) {
+			continue
+		}
+		if slices.Contains(applied, obj.ObjectReference) {
+			obj.AdoptedAt = now
+			rev.Status.AdoptedObjects = append(rev.Status.AdoptedObjects, obj)
+		}
+	}
+}
+
+// revisionReport reports the results of the reconciliation of a revision by phase, and
+// describes the objects that block its rollout, in rollout order.
+type revisionReport struct {
+	phases []ocv1.ClusterExtensionRevisionPhaseStatus
+	// blocked describes the objects that failed validation or collide with other objects.
+	blocked []string
+	// waiting describes the objects that fail their readiness probes.
+	waiting []string
+	// applied identifies the objects that were applied without collision.
+	applied []ocv1.ObjectReference
+	// changed identifies the objects that were created, updated or recovered. Once the revision
+	// succeeded, these are the objects whose changes on the cluster were reverted.
+	changed []ocv1.ObjectReference
+}
+
+// collisionResult is implemented by the results of objects that collide with an object
+// controlled by another owner.
+type collisionResult interface {
+	ConflictingOwner() (*metav1.OwnerReference, bool)
+}
+
+func newRevisionReport(rev *ocv1.ClusterExtensionRevision, rres machinery.RevisionResult) revisionReport {
+	var r revisionReport
+	if verr := rres.GetValidationError(); verr != nil {
+		for _, pverr := range verr.Phases {
+			ps := ocv1.ClusterExtensionRevisionPhaseStatus{Name: pverr.PhaseName, ObjectCount: phaseObjectCount(rev, pverr.PhaseName)}
+			r.blocked = append(r.blocked, addPhaseValidationError(&ps, pverr)...)
+			ps.Objects = ps.Objects[:min(len(ps.Objects), maxPhaseStatusObjects)]
+			r.phases = append(r.phases, ps)
+		}
+		if len(r.blocked) == 0 {
+			r.blocked = append(r.blocked, fmt.Sprintf("revision validation error: %s", verr))
+		}
+		return r
+	}
+
+	for _, pres := range rres.GetPhases() {
+		ps := ocv1.ClusterExtensionRevisionPhaseStatus{Name: pres.GetName()}
+		var collisions []string
+		for _, ores := range pres.GetObjects() {
+			obj := ores.Object()
+			if obj == nil {
+				continue
+			}
+			ps.ObjectCount++
+			objStatus := newObjectStatus(obj.GetObjectKind().GroupVersionKind(), client.ObjectKeyFromObject(obj))
+			objStatus.Action = string(ores.Action())
+			ref := objStatus.ObjectReference
+			switch ores.Action() {
+			case machinery.ActionCreated, machinery.ActionUpdated, machinery.ActionRecovered:
+				r.changed = append(r.changed, ref)
+			}
+			if ores.Action() == machinery.ActionCollision {
+				if cres, ok := ores.(collisionResult); ok {
+					if owner, ok := cres.ConflictingOwner(); ok {
+						objStatus.CollisionOwner = owner.Kind + "/" + owner.Name
+					}
+				}
+				if objStatus.CollisionOwner != "" {
+					collisions = append(collisions, fmt.Sprintf("%s collides with an object controlled by %s", describeObject(ps.Name, objStatus), objStatus.CollisionOwner))
+				} else {
+					collisions = append(collisions, fmt.Sprintf("%s collides with an existing object", describeObject(ps.Name, objStatus)))
+				}
+				ps.Objects = append(ps.Objects, objStatus)
+				continue
+			}
+			if ores.Action() != "" {
+				r.applied = append(r.applied, ref)
+			}
+			if pr := ores.ProbeResults()[boxcutter.ProgressProbeType]; !pres.IsComplete() && pr.Status != machinerytypes.ProbeStatusTrue {
+				objStatus.ProbeMessage = strings.Join(pr.Messages, " and ")
+				waiting := fmt.Sprintf("%s is not ready", describeObject(ps.Name, objStatus))
+				if objStatus.ProbeMessage != "" {
+					waiting += ": " + objStatus.ProbeMessage
+				}
+				r.waiting = append(r.waiting, waiting)
+				ps.Objects = append(ps.Objects, objStatus)
+				continue
+			}
+			ps.ReadyObjectCount++
+		}
+		if pverr := pres.GetValidationError(); pverr != nil {
+			// The objects of a phase that failed validation were not applied.
+			ps.ObjectCount = phaseObjectCount(rev, ps.Name)
+			ps.ReadyObjectCount = 0
+			r.blocked = append(r.blocked, addPhaseValidationError(&ps, *pverr)...)
+		}
+		r.blocked = append(r.blocked, collisions...)
+		ps.Objects = ps.Objects[:min(len(ps.Objects), maxPhaseStatusObjects)]
+		r.phases = append(r.phases, ps)
+	}
+	return r
+}
+
+// phaseObjectCount returns the number of objects in the phase of rev named name.
+func phaseObjectCount(rev *ocv1.ClusterExtensionRevision, name string) int32 {
+	for _, phase := range rev.Spec.Phases {
+		if phase.Name == name {
+			//nolint:gosec // the objects of a revision fit into a single API object.
+			return int32(len(phase.Objects))
+		}
+	}
+	return 0
+}
+
+// addPhaseValidationError records pverr in the status of the phase, and describes the
+// objects that failed validation.
+func addPhaseValidationError(ps *ocv1.ClusterExtensionRevisionPhaseStatus, pverr validation.PhaseValidationError) []string {
+	var blocked []string
+	if pverr.PhaseError != nil {
+		ps.ValidationError = pverr.PhaseError.Error()
+		blocked = append(blocked, fmt.Sprintf("Phase %q failed validation: %s", ps.Name, ps.ValidationError))
+	}
+	for _, overr := range pverr.Objects {
+		errs := make([]string, 0, len(overr.Errors))
+		for _, err := range overr.Errors {
+			errs = append(errs, err.Error())
+		}
+
+		ref := newObjectStatus(overr.ObjectRef.GroupVersionKind, overr.ObjectRef.ObjectKey)
+		idx := slices.IndexFunc(ps.Objects, func(o ocv1.ClusterExtensionRevisionObjectStatus) bool {
+			return o.ObjectReference == ref.ObjectReference
+		})
+		if idx < 0 {
+			idx = len(ps.Objects)
+			ps.Objects = append(ps.Objects, ref)
+		}
+		ps.Objects[idx].ValidationError = strings.Join(errs, ", ")
+		blocked = append(blocked, fmt.Sprintf("%s failed validation: %s", describeObject(ps.Name, ps.Objects[idx]), ps.Objects[idx].ValidationError))
+	}
+	return blocked
+}
+
+func newObjectStatus(gvk schema.GroupVersionKind, key client.ObjectKey) ocv1.ClusterExtensionRevisionObjectStatus {
+	return ocv1.ClusterExtensionRevisionObjectStatus{
+		ObjectReference: ocv1.ObjectReference{
+			Group:     gvk.Group,
+			Kind:      gvk.Kind,
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+		Version: gvk.Version,
+	}
+}
+
+func describeObject(phase string, obj ocv1.ClusterExtensionRevisionObjectStatus) string {
+	gv := schema.GroupVersion{Group: obj.Group, Version: obj.Version}
+	return fmt.Sprintf("Object %s.%s %s/%s in phase %q", obj.Kind, gv.String(), obj.Namespace, obj.Name, phase)
+}
+
+// summarizeBlockingObjects names the first of the blocking objects, which are ordered by rollout.
+func summarizeBlockingObjects(blocked []string) string {
+	if len(blocked) == 1 {
+		return blocked[0]
+	}
+	return fmt.Sprintf("%s (and %d more, see status.phases)", blocked[0], len(blocked)-1)
+}
+
 func setRetryingConditions(cer *ocv1.ClusterExtensionRevision, message string) {
 	markAsProgressing(cer, ocv1.ClusterExtensionRevisionReasonRetrying, message)
 	if meta.FindStatusCondition(cer.Status.Conditions, ocv1.ClusterExtensionRevisionTypeAvailable) != nil {
@@ -555,6 +970,20 @@ func markAsProgressing(cer *ocv1.ClusterExtensionRevision, reason, message strin
 	})
 }
 
+// isActive reports whether rev is neither being deleted nor archived, and is therefore rolled out
+// rather than torn down.
+func isActive(rev *ocv1.ClusterExtensionRevision) bool {
+	return rev.DeletionTimestamp.IsZero() && rev.Spec.LifecycleState != ocv1.ClusterExtensionRevisionLifecycleStateArchived
+}
+
+// hasFailedRollout returns true if the conditions of a revision report that it stopped rolling
+// out, because it exceeded its progress deadline or failed its soak.
+func hasFailedRollout(conditions []metav1.Condition) bool {
+	cnd := meta.FindStatusCondition(conditions, ocv1.ClusterExtensionRevisionTypeProgressing)
+	return cnd != nil && cnd.Status == metav1.ConditionFalse &&
+		(cnd.Reason == ocv1.ReasonProgressDeadlineExceeded || cnd.Reason == ocv1.ReasonSoakFailed)
+}
+
 func markAsNotProgressing(cer *ocv1.ClusterExtensionRevision, reason, message string) bool {
 	return meta.SetStatusCondition(&cer.Status.Conditions, metav1.Condition{
 		Type:               ocv1.ClusterExtensionRevisionTypeProgressing,
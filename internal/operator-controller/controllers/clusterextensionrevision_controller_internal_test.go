@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -235,3 +236,25 @@ func (m *mockTrackingCacheInternal) Watch(ctx context.Context, user client.Objec
 func (m *mockTrackingCacheInternal) Source(h handler.EventHandler, predicates ...predicate.Predicate) source.Source {
 	return nil
 }
+
+func Test_recordDrift(t *testing.T) {
+	now := metav1.Now()
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	deployment := ocv1.ObjectReference{Group: "apps", Kind: "Deployment", Namespace: "test-ns", Name: "test-operator"}
+	previous := &ocv1.DriftDetected{LastDetectedAt: earlier, Count: 2, Objects: []ocv1.ObjectReference{deployment}}
+
+	require.Nil(t, recordDrift(nil, nil, now))
+	require.Same(t, previous, recordDrift(previous, nil, now))
+	require.Equal(t, &ocv1.DriftDetected{LastDetectedAt: now, Count: 1, Objects: []ocv1.ObjectReference{deployment}},
+		recordDrift(nil, []ocv1.ObjectReference{deployment}, now))
+
+	reverted := make([]ocv1.ObjectReference, maxDriftedObjects+4)
+	for i := range reverted {
+		reverted[i] = ocv1.ObjectReference{Kind: "ConfigMap", Namespace: "test-ns", Name: fmt.Sprintf("cm-%d", i)}
+	}
+	drift := recordDrift(previous, reverted, now)
+	require.Equal(t, int64(3), drift.Count)
+	require.Equal(t, now, drift.LastDetectedAt)
+	require.Equal(t, reverted[:maxDriftedObjects], drift.Objects)
+	require.Equal(t, int64(2), previous.Count)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -17,6 +18,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
 	"pkg.package-operator.run/boxcutter"
 	"pkg.package-operator.run/boxcutter/machinery"
 	machinerytypes "pkg.package-operator.run/boxcutter/machinery/types"
@@ -228,6 +230,33 @@ func Test_ClusterExtensionRevisionReconciler_Reconcile_RevisionReconciliation(t
 				require.Equal(t, ocv1.ClusterExtensionRevisionReasonProbeFailure, cond.Reason)
 				require.Equal(t, "Object Service.v1 my-namespace/my-service: something bad happened and something worse happened\nObject ConfigMap.v1 my-namespace/my-configmap: we have a problem", cond.Message)
 				require.Equal(t, int64(1), cond.ObservedGeneration)
+
+				cond = meta.FindStatusCondition(rev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeProgressing)
+				require.NotNil(t, cond)
+				require.Equal(t, metav1.ConditionTrue, cond.Status)
+				require.Equal(t, ocv1.ReasonRollingOut, cond.Reason)
+				require.Equal(t, `Revision 1.0.0 is rolling out. Object Service.v1 my-namespace/my-service in phase "somephase" is not ready: something bad happened and something worse happened (and 1 more, see status.phases)`, cond.Message)
+
+				require.Equal(t, []ocv1.ClusterExtensionRevisionPhaseStatus{
+					{
+						Name:        "somephase",
+						ObjectCount: 1,
+						Objects: []ocv1.ClusterExtensionRevisionObjectStatus{{
+							ObjectReference: ocv1.ObjectReference{Kind: "Service", Namespace: "my-namespace", Name: "my-service"},
+							Version:         "v1",
+							ProbeMessage:    "something bad happened and something worse happened",
+						}},
+					},
+					{
+						Name:        "someotherphase",
+						ObjectCount: 1,
+						Objects: []ocv1.ClusterExtensionRevisionObjectStatus{{
+							ObjectReference: ocv1.ObjectReference{Kind: "ConfigMap", Namespace: "my-namespace", Name: "my-configmap"},
+							Version:         "v1",
+							ProbeMessage:    "we have a problem",
+						}},
+					},
+				}, rev.Status.Phases)
 			},
 		},
 		{
@@ -564,6 +593,7 @@ func Test_ClusterExtensionRevisionReconciler_Reconcile_ValidationError_Retries(t
 			revisionResult: mockRevisionResult{
 				phases: []machinery.PhaseResult{
 					mockPhaseResult{
+						name: "everything",
 						validationError: &validation.PhaseValidationError{
 							PhaseName:  "everything",
 							PhaseError: fmt.Errorf("some error"),
@@ -609,11 +639,12 @@ func Test_ClusterExtensionRevisionReconciler_Reconcile_ValidationError_Retries(t
 					return tc.revisionResult, nil
 				},
 			}
-			result, err := (&controllers.ClusterExtensionRevisionReconciler{
+			reconciler := &controllers.ClusterExtensionRevisionReconciler{
 				Client:                testClient,
 				RevisionEngineFactory: &mockRevisionEngineFactory{engine: mockEngine},
 				TrackingCache:         &mockTrackingCache{client: testClient},
-			}).Reconcile(t.Context(), ctrl.Request{
+			}
+			result, err := reconciler.Reconcile(t.Context(), ctrl.Request{
 				NamespacedName: types.NamespacedName{
 					Name: clusterExtensionRevisionName,
 				},
@@ -624,10 +655,110 @@ func Test_ClusterExtensionRevisionReconciler_Reconcile_ValidationError_Retries(t
 				RequeueAfter: 10 * time.Second,
 			}, result)
 			require.NoError(t, err)
+
+			rev := &ocv1.ClusterExtensionRevision{}
+			require.NoError(t, testClient.Get(t.Context(), client.ObjectKey{Name: clusterExtensionRevisionName}, rev))
+			cond := meta.FindStatusCondition(rev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeProgressing)
+			require.NotNil(t, cond)
+			require.Equal(t, ocv1.ClusterExtensionRevisionReasonRetrying, cond.Reason)
+			require.Equal(t, `Phase "everything" failed validation: some error (and 1 more, see status.phases)`, cond.Message)
+			require.Equal(t, []ocv1.ClusterExtensionRevisionPhaseStatus{{
+				Name:            "everything",
+				ValidationError: "some error",
+				ObjectCount:     1,
+				Objects: []ocv1.ClusterExtensionRevisionObjectStatus{{
+					ObjectReference: ocv1.ObjectReference{Kind: "ConfigMap", Namespace: "my-namespace", Name: "my-configmap"},
+					Version:         "v1",
+					ValidationError: "is not a config, is not a map",
+				}},
+			}}, rev.Status.Phases)
+
+			// retries back off exponentially
+			result, err = reconciler.Reconcile(t.Context(), ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Name: clusterExtensionRevisionName,
+				},
+			})
+			require.Equal(t, ctrl.Result{
+				RequeueAfter: 20 * time.Second,
+			}, result)
+			require.NoError(t, err)
 		})
 	}
 }
 
+func Test_ClusterExtensionRevisionReconciler_Reconcile_Collision(t *testing.T) {
+	testScheme := newScheme(t)
+	ext := newTestClusterExtension()
+	rev1 := newTestClusterExtensionRevision(t, clusterExtensionRevisionName, ext, testScheme)
+	testClient := fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithStatusSubresource(&ocv1.ClusterExtensionRevision{}).
+		WithObjects(ext, rev1).
+		Build()
+
+	newConfigMap := func(name string) client.Object {
+		obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "my-namespace"}}
+		obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		return obj
+	}
+	mockEngine := &mockRevisionEngine{
+		reconcile: func(ctx context.Context, rev machinerytypes.Revision, opts ...machinerytypes.RevisionReconcileOption) (machinery.RevisionResult, error) {
+			return mockRevisionResult{
+				inTransition: true,
+				phases: []machinery.PhaseResult{
+					mockPhaseResult{
+						name: "deploy",
+						objects: []machinery.ObjectResult{
+							mockObjectResult{
+								action: machinery.ActionCreated,
+								object: newConfigMap("created"),
+								probes: machinerytypes.ProbeResultContainer{
+									boxcutter.ProgressProbeType: {Status: machinerytypes.ProbeStatusTrue},
+								},
+							},
+							mockObjectResult{
+								action:           machinery.ActionCollision,
+								object:           newConfigMap("owned"),
+								conflictingOwner: &metav1.OwnerReference{Kind: "ClusterExtensionRevision", Name: "other-ext-1"},
+							},
+							mockObjectResult{action: machinery.ActionCollision, object: newConfigMap("unowned")},
+						},
+					},
+				},
+			}, nil
+		},
+	}
+	result, err := (&controllers.ClusterExtensionRevisionReconciler{
+		Client:                testClient,
+		RevisionEngineFactory: &mockRevisionEngineFactory{engine: mockEngine},
+		TrackingCache:         &mockTrackingCache{client: testClient},
+	}).Reconcile(t.Context(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name: clusterExtensionRevisionName,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ctrl.Result{RequeueAfter: 10 * time.Second}, result)
+
+	rev := &ocv1.ClusterExtensionRevision{}
+	require.NoError(t, testClient.Get(t.Context(), client.ObjectKey{Name: clusterExtensionRevisionName}, rev))
+	cond := meta.FindStatusCondition(rev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeProgressing)
+	require.NotNil(t, cond)
+	require.Equal(t, metav1.ConditionTrue, cond.Status)
+	require.Equal(t, ocv1.ClusterExtensionRevisionReasonRetrying, cond.Reason)
+	require.Equal(t, `Object ConfigMap.v1 my-namespace/owned in phase "deploy" collides with an object controlled by ClusterExtensionRevision/other-ext-1 (and 1 more, see status.phases)`, cond.Message)
+	require.Equal(t, []ocv1.ClusterExtensionRevisionPhaseStatus{{
+		Name:             "deploy",
+		ObjectCount:      3,
+		ReadyObjectCount: 1,
+		Objects: []ocv1.ClusterExtensionRevisionObjectStatus{
+			{ObjectReference: ocv1.ObjectReference{Kind: "ConfigMap", Namespace: "my-namespace", Name: "owned"}, Version: "v1", Action: "Collision", CollisionOwner: "ClusterExtensionRevision/other-ext-1"},
+			{ObjectReference: ocv1.ObjectReference{Kind: "ConfigMap", Namespace: "my-namespace", Name: "unowned"}, Version: "v1", Action: "Collision"},
+		},
+	}}, rev.Status.Phases)
+}
+
 func Test_ClusterExtensionRevisionReconciler_Reconcile_Deletion(t *testing.T) {
 	const (
 		clusterExtensionRevisionName = "test-ext-1"
@@ -697,6 +828,98 @@ func Test_ClusterExtensionRevisionReconciler_Reconcile_Deletion(t *testing.T) {
 				require.True(t, apierrors.IsNotFound(err))
 			},
 		},
+		{
+			name:           "objects kept by the uninstall policy are orphaned when the extension is deleted",
+			revisionResult: mockRevisionResult{},
+			existingObjs: func() []client.Object {
+				ext := newTestClusterExtension()
+				rev1 := newTestClusterExtensionRevision(t, clusterExtensionRevisionName, ext, testScheme)
+				rev1.Finalizers = []string{
+					"olm.operatorframework.io/teardown",
+				}
+				rev1.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				rev1.Spec.UninstallPolicy = ocv1.UninstallPolicyOrphanAll
+				rev1.Spec.Phases[0].Objects[0].Object.SetName("test-config")
+				rev1.Spec.Phases[0].Objects[0].Object.SetNamespace("test-ns")
+				return []client.Object{rev1, newOwnedTestConfigMap(rev1)}
+			},
+			revisionEngineTeardownFn: func(t *testing.T) func(ctx context.Context, rev machinerytypes.Revision, opts ...machinerytypes.RevisionTeardownOption) (machinery.RevisionTeardownResult, error) {
+				return nil
+			},
+			validate: func(t *testing.T, c client.Client) {
+				cm := &corev1.ConfigMap{}
+				require.NoError(t, c.Get(t.Context(), client.ObjectKey{Name: "test-config", Namespace: "test-ns"}, cm))
+				require.Empty(t, cm.OwnerReferences)
+				require.NotContains(t, cm.Labels, labels.OwnerNameKey)
+				require.NotContains(t, cm.Labels, labels.OwnerKindKey)
+			},
+		},
+		{
+			name:           "the installation namespace is kept with the CRDs when the extension is deleted",
+			revisionResult: mockRevisionResult{},
+			existingObjs: func() []client.Object {
+				ext := newTestClusterExtension()
+				rev1 := newTestClusterExtensionRevision(t, clusterExtensionRevisionName, ext, testScheme)
+				rev1.Finalizers = []string{
+					"olm.operatorframework.io/teardown",
+				}
+				rev1.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				rev1.Spec.UninstallPolicy = ocv1.UninstallPolicyOrphanCRDs
+				rev1.Spec.Phases[0].Objects[0].Object.SetName("test-config")
+				rev1.Spec.Phases[0].Objects[0].Object.SetNamespace("test-ns")
+				nsObj := unstructured.Unstructured{}
+				nsObj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+				nsObj.SetName("test-ns")
+				rev1.Spec.Phases = append([]ocv1.ClusterExtensionRevisionPhase{{
+					Name:    "namespaces",
+					Objects: []ocv1.ClusterExtensionRevisionObject{{Object: nsObj}},
+				}}, rev1.Spec.Phases...)
+				cm := newOwnedTestConfigMap(rev1)
+				ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+					Name:            "test-ns",
+					Labels:          cm.Labels,
+					OwnerReferences: cm.OwnerReferences,
+				}}
+				return []client.Object{rev1, ns, cm}
+			},
+			revisionEngineTeardownFn: func(t *testing.T) func(ctx context.Context, rev machinerytypes.Revision, opts ...machinerytypes.RevisionTeardownOption) (machinery.RevisionTeardownResult, error) {
+				return nil
+			},
+			validate: func(t *testing.T, c client.Client) {
+				ns := &corev1.Namespace{}
+				require.NoError(t, c.Get(t.Context(), client.ObjectKey{Name: "test-ns"}, ns))
+				require.Empty(t, ns.OwnerReferences)
+				require.NotContains(t, ns.Labels, labels.OwnerNameKey)
+				cm := &corev1.ConfigMap{}
+				require.NoError(t, c.Get(t.Context(), client.ObjectKey{Name: "test-config", Namespace: "test-ns"}, cm))
+				require.Len(t, cm.OwnerReferences, 1)
+			},
+		},
+		{
+			name:           "objects are not orphaned when a revision of an existing extension is deleted",
+			revisionResult: mockRevisionResult{},
+			existingObjs: func() []client.Object {
+				ext := newTestClusterExtension()
+				rev1 := newTestClusterExtensionRevision(t, clusterExtensionRevisionName, ext, testScheme)
+				rev1.Finalizers = []string{
+					"olm.operatorframework.io/teardown",
+				}
+				rev1.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				rev1.Spec.UninstallPolicy = ocv1.UninstallPolicyOrphanAll
+				rev1.Spec.Phases[0].Objects[0].Object.SetName("test-config")
+				rev1.Spec.Phases[0].Objects[0].Object.SetNamespace("test-ns")
+				return []client.Object{rev1, ext, newOwnedTestConfigMap(rev1)}
+			},
+			revisionEngineTeardownFn: func(t *testing.T) func(ctx context.Context, rev machinerytypes.Revision, opts ...machinerytypes.RevisionTeardownOption) (machinery.RevisionTeardownResult, error) {
+				return nil
+			},
+			validate: func(t *testing.T, c client.Client) {
+				cm := &corev1.ConfigMap{}
+				require.NoError(t, c.Get(t.Context(), client.ObjectKey{Name: "test-config", Namespace: "test-ns"}, cm))
+				require.Len(t, cm.OwnerReferences, 1)
+				require.Equal(t, "test-ext", cm.Labels[labels.OwnerNameKey])
+			},
+		},
 		{
 			name:           "set Available:Unknown:Reconciling and surface tracking cache cleanup errors when deleted",
 			revisionResult: mockRevisionResult{},
@@ -735,6 +958,45 @@ func Test_ClusterExtensionRevisionReconciler_Reconcile_Deletion(t *testing.T) {
 				require.Equal(t, int64(1), cond.ObservedGeneration)
 			},
 		},
+		{
+			name:           "surface teardown errors of revisions that failed their soak when deleted",
+			revisionResult: mockRevisionResult{},
+			existingObjs: func() []client.Object {
+				ext := newTestClusterExtension()
+				rev1 := newTestClusterExtensionRevision(t, clusterExtensionRevisionName, ext, testScheme)
+				rev1.Finalizers = []string{
+					"olm.operatorframework.io/teardown",
+				}
+				rev1.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				meta.SetStatusCondition(&rev1.Status.Conditions, metav1.Condition{
+					Type:               ocv1.ClusterExtensionRevisionTypeProgressing,
+					Status:             metav1.ConditionFalse,
+					Reason:             ocv1.ReasonSoakFailed,
+					ObservedGeneration: rev1.Generation,
+				})
+				return []client.Object{rev1, ext}
+			},
+			revisionEngineTeardownFn: func(t *testing.T) func(ctx context.Context, rev machinerytypes.Revision, opts ...machinerytypes.RevisionTeardownOption) (machinery.RevisionTeardownResult, error) {
+				return func(ctx context.Context, rev machinerytypes.Revision, opts ...machinerytypes.RevisionTeardownOption) (machinery.RevisionTeardownResult, error) {
+					return &mockRevisionTeardownResult{
+						isComplete: true,
+					}, nil
+				}
+			},
+			trackingCacheFreeFn: func(ctx context.Context, object client.Object) error {
+				return fmt.Errorf("some tracking cache cleanup error")
+			},
+			expectedErr: "some tracking cache cleanup error",
+			validate: func(t *testing.T, c client.Client) {
+				t.Log("cluster revision is not deleted and still contains finalizer")
+				rev := &ocv1.ClusterExtensionRevision{}
+				err := c.Get(t.Context(), client.ObjectKey{
+					Name: clusterExtensionRevisionName,
+				}, rev)
+				require.NoError(t, err)
+				require.Contains(t, rev.Finalizers, "olm.operatorframework.io/teardown")
+			},
+		},
 		{
 			name:           "set Available:Archived:Unknown and Progressing:False:Archived conditions when a revision is archived",
 			revisionResult: mockRevisionResult{},
@@ -835,7 +1097,7 @@ func Test_ClusterExtensionRevisionReconciler_Reconcile_Deletion(t *testing.T) {
 			}
 			result, err := (&controllers.ClusterExtensionRevisionReconciler{
 				Client:                testClient,
-				RevisionEngineFactory: &mockRevisionEngineFactory{engine: mockEngine},
+				RevisionEngineFactory: &mockRevisionEngineFactory{engine: mockEngine, client: testClient},
 				TrackingCache: &mockTrackingCache{
 					client: testClient,
 					freeFn: tc.trackingCacheFreeFn,
@@ -860,6 +1122,58 @@ func Test_ClusterExtensionRevisionReconciler_Reconcile_Deletion(t *testing.T) {
 	}
 }
 
+func Test_ClusterExtensionRevisionReconciler_Reconcile_Paused(t *testing.T) {
+	const clusterExtensionRevisionName = "test-ext-1"
+
+	testScheme := newScheme(t)
+	require.NoError(t, corev1.AddToScheme(testScheme))
+
+	ext := newTestClusterExtension()
+	rev1 := newTestClusterExtensionRevision(t, clusterExtensionRevisionName, ext, testScheme)
+	rev1.Finalizers = []string{
+		"olm.operatorframework.io/teardown",
+	}
+	rev1.Spec.Paused = true
+	testClient := fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithStatusSubresource(&ocv1.ClusterExtensionRevision{}).
+		WithObjects(ext, rev1).
+		Build()
+
+	var freed bool
+	result, err := (&controllers.ClusterExtensionRevisionReconciler{
+		Client: testClient,
+		RevisionEngineFactory: &mockRevisionEngineFactory{engine: &mockRevisionEngine{
+			reconcile: func(ctx context.Context, rev machinerytypes.Revision, opts ...machinerytypes.RevisionReconcileOption) (machinery.RevisionResult, error) {
+				t.Fatal("paused revisions must not be reconciled")
+				return nil, nil
+			},
+		}},
+		TrackingCache: &mockTrackingCache{
+			client: testClient,
+			freeFn: func(context.Context, client.Object) error {
+				freed = true
+				return nil
+			},
+		},
+	}).Reconcile(t.Context(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name: clusterExtensionRevisionName,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ctrl.Result{}, result)
+	require.True(t, freed, "the watches of paused revisions are stopped")
+
+	rev := &ocv1.ClusterExtensionRevision{}
+	require.NoError(t, testClient.Get(t.Context(), client.ObjectKey{Name: clusterExtensionRevisionName}, rev))
+	cond := meta.FindStatusCondition(rev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeProgressing)
+	require.NotNil(t, cond)
+	require.Equal(t, metav1.ConditionFalse, cond.Status)
+	require.Equal(t, ocv1.ReasonPaused, cond.Reason)
+	require.Contains(t, rev.Finalizers, "olm.operatorframework.io/teardown")
+}
+
 func Test_ClusterExtensionRevisionReconciler_Reconcile_ProgressDeadline(t *testing.T) {
 	const (
 		clusterExtensionRevisionName = "test-ext-1"
@@ -923,6 +1237,67 @@ func Test_ClusterExtensionRevisionReconciler_Reconcile_ProgressDeadline(t *testi
 				require.Equal(t, ocv1.ReasonRollingOut, cnd.Reason)
 			},
 		},
+		{
+			name: "time spent paused doesn't count towards the progress deadline",
+			existingObjs: func() []client.Object {
+				ext := newTestClusterExtension()
+				rev1 := newTestClusterExtensionRevision(t, clusterExtensionRevisionName, ext, testScheme)
+				rev1.Spec.ProgressDeadlineMinutes = 1
+				rev1.CreationTimestamp = metav1.NewTime(time.Now().Add(-5 * time.Minute))
+				meta.SetStatusCondition(&rev1.Status.Conditions, metav1.Condition{
+					Type:               ocv1.ClusterExtensionRevisionTypeProgressing,
+					Status:             metav1.ConditionFalse,
+					Reason:             ocv1.ReasonPaused,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-4 * time.Minute)),
+					ObservedGeneration: rev1.Generation,
+				})
+				return []client.Object{rev1, ext}
+			},
+			revisionResult: &mockRevisionResult{
+				inTransition: true,
+			},
+			reconcileResult: ctrl.Result{RequeueAfter: 1 * time.Minute},
+			validate: func(t *testing.T, c client.Client) {
+				rev := &ocv1.ClusterExtensionRevision{}
+				err := c.Get(t.Context(), client.ObjectKey{
+					Name: clusterExtensionRevisionName,
+				}, rev)
+				require.NoError(t, err)
+				cnd := meta.FindStatusCondition(rev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeProgressing)
+				require.Equal(t, metav1.ConditionTrue, cnd.Status)
+				require.Equal(t, ocv1.ReasonRollingOut, cnd.Reason)
+			},
+		},
+		{
+			name: "progress deadline is measured from the last resume",
+			existingObjs: func() []client.Object {
+				ext := newTestClusterExtension()
+				rev1 := newTestClusterExtensionRevision(t, clusterExtensionRevisionName, ext, testScheme)
+				rev1.Spec.ProgressDeadlineMinutes = 1
+				rev1.CreationTimestamp = metav1.NewTime(time.Now().Add(-5 * time.Minute))
+				meta.SetStatusCondition(&rev1.Status.Conditions, metav1.Condition{
+					Type:               ocv1.ClusterExtensionRevisionTypeProgressing,
+					Status:             metav1.ConditionTrue,
+					Reason:             ocv1.ReasonRollingOut,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-61 * time.Second)),
+					ObservedGeneration: rev1.Generation,
+				})
+				return []client.Object{rev1, ext}
+			},
+			revisionResult: &mockRevisionResult{
+				inTransition: true,
+			},
+			validate: func(t *testing.T, c client.Client) {
+				rev := &ocv1.ClusterExtensionRevision{}
+				err := c.Get(t.Context(), client.ObjectKey{
+					Name: clusterExtensionRevisionName,
+				}, rev)
+				require.NoError(t, err)
+				cnd := meta.FindStatusCondition(rev.Status.Conditions, ocv1.ClusterExtensionRevisionTypeProgressing)
+				require.Equal(t, metav1.ConditionFalse, cnd.Status)
+				require.Equal(t, ocv1.ReasonProgressDeadlineExceeded, cnd.Reason)
+			},
+		},
 		{
 			name: "no progression deadline checks on revision recovery",
 			existingObjs: func() []client.Object {
@@ -992,6 +1367,235 @@ func Test_ClusterExtensionRevisionReconciler_Reconcile_ProgressDeadline(t *testi
 	}
 }
 
+func Test_ClusterExtensionRevisionReconciler_Reconcile_AdoptedObjects(t *testing.T) {
+	testScheme := newScheme(t)
+	ext := newTestClusterExtension()
+	rev1 := newTestClusterExtensionRevision(t, clusterExtensionRevisionName, ext, testScheme)
+	specObject := func(name string) ocv1.ClusterExtensionRevisionObject {
+		obj := unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName(name)
+		obj.SetNamespace("my-namespace")
+		return ocv1.ClusterExtensionRevisionObject{Object: obj, CollisionProtection: ocv1.CollisionProtectionNone}
+	}
+	rev1.Spec.Phases[0].Objects = []ocv1.ClusterExtensionRevisionObject{
+		specObject("adopted"),
+		specObject("installed"),
+		specObject("new"),
+	}
+
+	controller := true
+	adopted := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      "adopted",
+		Namespace: "my-namespace",
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "operators.coreos.com/v1alpha1",
+			Kind:       "ClusterServiceVersion",
+			Name:       "test-operator.v0.9.0",
+			UID:        "csv",
+			Controller: &controller,
+		}},
+	}}
+	installed := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      "installed",
+		Namespace: "my-namespace",
+		Labels:    map[string]string{labels.OwnerNameKey: "test-ext"},
+	}}
+	testClient := fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithStatusSubresource(&ocv1.ClusterExtensionRevision{}).
+		WithObjects(ext, rev1, adopted, installed).
+		Build()
+
+	mockEngine := &mockRevisionEngine{
+		reconcile: func(ctx context.Context, rev machinerytypes.Revision, opts ...machinerytypes.RevisionReconcileOption) (machinery.RevisionResult, error) {
+			var objects []machinery.ObjectResult
+			for _, obj := range rev.Phases[0].Objects {
+				objects = append(objects, mockObjectResult{action: machinery.ActionUpdated, object: &obj})
+			}
+			return mockRevisionResult{
+				inTransition: true,
+				phases:       []machinery.PhaseResult{mockPhaseResult{name: "everything", objects: objects}},
+			}, nil
+		},
+	}
+	_, err := (&controllers.ClusterExtensionRevisionReconciler{
+		Client:                testClient,
+		RevisionEngineFactory: &mockRevisionEngineFactory{engine: mockEngine},
+		TrackingCache:         &mockTrackingCache{client: testClient},
+	}).Reconcile(t.Context(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name: clusterExtensionRevisionName,
+		},
+	})
+	require.NoError(t, err)
+
+	rev := &ocv1.ClusterExtensionRevision{}
+	require.NoError(t, testClient.Get(t.Context(), client.ObjectKey{Name: clusterExtensionRevisionName}, rev))
+	require.Len(t, rev.Status.AdoptedObjects, 1)
+	require.False(t, rev.Status.AdoptedObjects[0].AdoptedAt.IsZero())
+	rev.Status.AdoptedObjects[0].AdoptedAt = metav1.Time{}
+	require.Equal(t, ocv1.AdoptedObject{
+		ObjectReference:    ocv1.ObjectReference{Kind: "ConfigMap", Namespace: "my-namespace", Name: "adopted"},
+		PreviousController: "ClusterServiceVersion/test-operator.v0.9.0",
+	}, rev.Status.AdoptedObjects[0])
+}
+
+func Test_ClusterExtensionRevisionReconciler_Reconcile_CanaryRollout(t *testing.T) {
+	testScheme := newScheme(t)
+	require.NoError(t, appsv1.AddToScheme(testScheme))
+	ext := newTestClusterExtension()
+	rev1 := newTestClusterExtensionRevision(t, clusterExtensionRevisionName, ext, testScheme)
+	rev1.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	rev1.Spec.Canary = &ocv1.CanaryRollout{SoakSeconds: 300}
+	deploymentObj := unstructured.Unstructured{}
+	deploymentObj.SetAPIVersion("apps/v1")
+	deploymentObj.SetKind("Deployment")
+	deploymentObj.SetName("test-operator")
+	deploymentObj.SetNamespace("my-namespace")
+	deploymentObj.Object["spec"] = map[string]interface{}{"replicas": int64(3)}
+	rev1.Spec.Phases[0].Objects = []ocv1.ClusterExtensionRevisionObject{{Object: deploymentObj}}
+
+	reconcile := func(live ...client.Object) *unstructured.Unstructured {
+		testClient := fake.NewClientBuilder().
+			WithScheme(testScheme).
+			WithStatusSubresource(&ocv1.ClusterExtensionRevision{}).
+			WithObjects(ext, rev1.DeepCopy()).
+			Build()
+		var applied *unstructured.Unstructured
+		mockEngine := &mockRevisionEngine{
+			reconcile: func(ctx context.Context, rev machinerytypes.Revision, opts ...machinerytypes.RevisionReconcileOption) (machinery.RevisionResult, error) {
+				applied = rev.Phases[0].Objects[0].DeepCopy()
+				return mockRevisionResult{inTransition: true}, nil
+			},
+		}
+		_, err := (&controllers.ClusterExtensionRevisionReconciler{
+			Client:                testClient,
+			RevisionEngineFactory: &mockRevisionEngineFactory{engine: mockEngine},
+			TrackingCache:         &mockTrackingCache{client: testClient},
+			PodReader:             fake.NewClientBuilder().WithScheme(testScheme).WithObjects(live...).Build(),
+		}).Reconcile(t.Context(), ctrl.Request{NamespacedName: types.NamespacedName{Name: clusterExtensionRevisionName}})
+		require.NoError(t, err)
+		require.NotNil(t, applied)
+		return applied
+	}
+
+	applied := reconcile()
+	minReadySeconds, _, _ := unstructured.NestedInt64(applied.Object, "spec", "minReadySeconds")
+	require.Equal(t, int64(300), minReadySeconds, "Deployments roll out as canaries until they soaked")
+	maxUnavailable, _, _ := unstructured.NestedInt64(applied.Object, "spec", "strategy", "rollingUpdate", "maxUnavailable")
+	require.Equal(t, int64(0), maxUnavailable)
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-operator",
+			Namespace:   "my-namespace",
+			UID:         "test-operator",
+			Annotations: map[string]string{"deployment.kubernetes.io/revision": "2"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: ocv1.GroupVersion.String(),
+				Kind:       "ClusterExtensionRevision",
+				Name:       rev1.Name,
+				UID:        rev1.UID,
+				Controller: ptr.To(true),
+			}},
+		},
+		Spec: appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test-operator"}}},
+	}
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-operator-new",
+			Namespace:   "my-namespace",
+			Labels:      map[string]string{"app": "test-operator", appsv1.DefaultDeploymentUniqueLabelKey: "new"},
+			Annotations: map[string]string{"deployment.kubernetes.io/revision": "2"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       deployment.Name,
+				UID:        deployment.UID,
+				Controller: ptr.To(true),
+			}},
+		},
+		Status: appsv1.ReplicaSetStatus{AvailableReplicas: 1},
+	}
+	applied = reconcile(deployment, replicaSet)
+	require.Equal(t, deploymentObj.Object["spec"], applied.Object["spec"], "Deployments roll out completely once their canary soaked")
+}
+
+func Test_ClusterExtensionRevisionReconciler_Reconcile_DriftDetected(t *testing.T) {
+	testScheme := newScheme(t)
+	ext := newTestClusterExtension()
+	rev1 := newTestClusterExtensionRevision(t, clusterExtensionRevisionName, ext, testScheme)
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName("test-config")
+	obj.SetNamespace("test-ns")
+	require.NoError(t, unstructured.SetNestedStringMap(obj.Object, map[string]string{"foo": "bar", "bar": "baz"}, "data"))
+	rev1.Spec.Phases[0].Objects = []ocv1.ClusterExtensionRevisionObject{{Object: obj}}
+	rev1.Spec.IgnoreDifferences = []ocv1.IgnoreDifference{{
+		Selector:     ocv1.ProbeSelector{Kind: "ConfigMap", Name: "test-config"},
+		JSONPointers: []string{"/data/foo"},
+	}}
+	meta.SetStatusCondition(&rev1.Status.Conditions, metav1.Condition{
+		Type:   ocv1.ClusterExtensionRevisionTypeSucceeded,
+		Status: metav1.ConditionTrue,
+		Reason: ocv1.ReasonSucceeded,
+	})
+
+	live := newOwnedTestConfigMap(rev1)
+	live.Data = map[string]string{"foo": "changed", "bar": "changed"}
+	testClient := fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithStatusSubresource(&ocv1.ClusterExtensionRevision{}).
+		WithObjects(ext, rev1, live).
+		Build()
+
+	var reconciledData map[string]string
+	action := machinery.ActionUpdated
+	mockEngine := &mockRevisionEngine{
+		reconcile: func(ctx context.Context, rev machinerytypes.Revision, opts ...machinerytypes.RevisionReconcileOption) (machinery.RevisionResult, error) {
+			var objects []machinery.ObjectResult
+			for _, obj := range rev.Phases[0].Objects {
+				reconciledData, _, _ = unstructured.NestedStringMap(obj.Object, "data")
+				objects = append(objects, mockObjectResult{action: action, object: &obj, success: true, complete: true})
+			}
+			return mockRevisionResult{
+				isComplete: true,
+				phases:     []machinery.PhaseResult{mockPhaseResult{name: "everything", objects: objects, isComplete: true}},
+			}, nil
+		},
+	}
+	reconciler := &controllers.ClusterExtensionRevisionReconciler{
+		Client:                testClient,
+		RevisionEngineFactory: &mockRevisionEngineFactory{engine: mockEngine},
+		TrackingCache:         &mockTrackingCache{client: testClient},
+		ReportDrift:           true,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: clusterExtensionRevisionName}}
+	_, err := reconciler.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"bar": "baz"}, reconciledData, "the ignored fields of existing objects are not applied")
+
+	rev := &ocv1.ClusterExtensionRevision{}
+	require.NoError(t, testClient.Get(t.Context(), client.ObjectKey{Name: clusterExtensionRevisionName}, rev))
+	require.NotNil(t, rev.Status.DriftDetected)
+	require.False(t, rev.Status.DriftDetected.LastDetectedAt.IsZero())
+	require.Equal(t, int64(1), rev.Status.DriftDetected.Count)
+	require.Equal(t, []ocv1.ObjectReference{
+		{Kind: "ConfigMap", Namespace: "test-ns", Name: "test-config"},
+	}, rev.Status.DriftDetected.Objects)
+
+	// Reconciles that don't change any object don't change the status.
+	action = machinery.ActionIdle
+	_, err = reconciler.Reconcile(t.Context(), req)
+	require.NoError(t, err)
+	unchanged := &ocv1.ClusterExtensionRevision{}
+	require.NoError(t, testClient.Get(t.Context(), client.ObjectKey{Name: clusterExtensionRevisionName}, unchanged))
+	require.Equal(t, rev.ResourceVersion, unchanged.ResourceVersion)
+}
+
 func newTestClusterExtension() *ocv1.ClusterExtension {
 	return &ocv1.ClusterExtension{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1063,6 +1667,28 @@ func newTestClusterExtensionRevision(t *testing.T, revisionName string, ext *ocv
 	return rev
 }
 
+// newOwnedTestConfigMap returns the ConfigMap of the test revision rev, as installed by rev.
+func newOwnedTestConfigMap(rev *ocv1.ClusterExtensionRevision) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-config",
+			Namespace: "test-ns",
+			Labels: map[string]string{
+				labels.OwnerKindKey: ocv1.ClusterExtensionKind,
+				labels.OwnerNameKey: "test-ext",
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: ocv1.GroupVersion.String(),
+				Kind:       ocv1.ClusterExtensionRevisionKind,
+				Name:       rev.Name,
+				UID:        rev.UID,
+				Controller: ptr.To(true),
+			}},
+		},
+		Data: map[string]string{"foo": "bar"},
+	}
+}
+
 type mockRevisionEngine struct {
 	teardown  func(ctx context.Context, rev machinerytypes.Revision, opts ...machinerytypes.RevisionTeardownOption) (machinery.RevisionTeardownResult, error)
 	reconcile func(ctx context.Context, rev machinerytypes.Revision, opts ...machinerytypes.RevisionReconcileOption) (machinery.RevisionResult, error)
@@ -1079,6 +1705,7 @@ func (m mockRevisionEngine) Reconcile(ctx context.Context, rev machinerytypes.Re
 // mockRevisionEngineFactory creates mock RevisionEngines for testing
 type mockRevisionEngineFactory struct {
 	engine    controllers.RevisionEngine
+	client    client.Client
 	createErr error
 }
 
@@ -1089,6 +1716,13 @@ func (f *mockRevisionEngineFactory) CreateRevisionEngine(ctx context.Context, re
 	return f.engine, nil
 }
 
+func (f *mockRevisionEngineFactory) CreateRevisionClient(ctx context.Context, rev *ocv1.ClusterExtensionRevision) (client.Client, error) {
+	if f.createErr != nil {
+		return nil, f.createErr
+	}
+	return f.client, nil
+}
+
 type mockRevisionResult struct {
 	validationError *validation.RevisionValidationError
 	phases          []machinery.PhaseResult
@@ -1172,6 +1806,8 @@ type mockObjectResult struct {
 	paused   bool
 	probes   machinerytypes.ProbeResultContainer
 	string   string
+
+	conflictingOwner *metav1.OwnerReference
 }
 
 func (m mockObjectResult) ProbeResults() machinerytypes.ProbeResultContainer {
@@ -1202,6 +1838,10 @@ func (m mockObjectResult) String() string {
 	return m.string
 }
 
+func (m mockObjectResult) ConflictingOwner() (*metav1.OwnerReference, bool) {
+	return m.conflictingOwner, m.conflictingOwner != nil
+}
+
 var _ machinery.RevisionTeardownResult = mockRevisionTeardownResult{}
 
 type mockRevisionTeardownResult struct {
@@ -63,7 +63,8 @@ func setInstalledStatusFromRevisionStates(ext *ocv1.ClusterExtension, revisionSt
 	}
 	// Something is installed
 	installStatus := &ocv1.ClusterExtensionInstallStatus{
-		Bundle: revisionStates.Installed.BundleMetadata,
+		Bundle:  revisionStates.Installed.BundleMetadata,
+		Catalog: revisionStates.Installed.Catalog,
 	}
 	setInstallStatus(ext, installStatus)
 	setInstalledStatusConditionSuccess(ext, fmt.Sprintf("Installed bundle %s successfully", revisionStates.Installed.Image))
@@ -409,3 +409,19 @@ func TestSetInstalledStatusFromRevisionStates_ConfigValidationError(t *testing.T
 		})
 	}
 }
+
+func TestSetInstalledStatusFromRevisionStates_RecordsCatalog(t *testing.T) {
+	ext := &ocv1.ClusterExtension{}
+	setInstalledStatusFromRevisionStates(ext, &RevisionStates{
+		Installed: &RevisionMetadata{
+			Image:          "quay.io/example/prometheus@sha256:abc",
+			Catalog:        "operatorhubio",
+			BundleMetadata: ocv1.BundleMetadata{Name: "prometheus.v1.0.0", Version: "1.0.0"},
+		},
+	})
+
+	require.Equal(t, &ocv1.ClusterExtensionInstallStatus{
+		Bundle:  ocv1.BundleMetadata{Name: "prometheus.v1.0.0", Version: "1.0.0"},
+		Catalog: "operatorhubio",
+	}, ext.Status.Install)
+}
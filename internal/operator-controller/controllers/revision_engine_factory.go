@@ -39,6 +39,8 @@ type RevisionEngine interface {
 // RevisionEngineFactory creates a RevisionEngine for a ClusterExtensionRevision.
 type RevisionEngineFactory interface {
 	CreateRevisionEngine(ctx context.Context, rev *ocv1.ClusterExtensionRevision) (RevisionEngine, error)
+	// CreateRevisionClient creates a client acting as the ServiceAccount of the ClusterExtensionRevision.
+	CreateRevisionClient(ctx context.Context, rev *ocv1.ClusterExtensionRevision) (client.Client, error)
 }
 
 // defaultRevisionEngineFactory creates boxcutter RevisionEngines with serviceAccount-scoped clients.
@@ -79,6 +81,16 @@ func (f *defaultRevisionEngineFactory) CreateRevisionEngine(_ context.Context, r
 	), nil
 }
 
+// CreateRevisionClient creates a client scoped to the ServiceAccount read from the annotations
+// of the given ClusterExtensionRevision.
+func (f *defaultRevisionEngineFactory) CreateRevisionClient(_ context.Context, rev *ocv1.ClusterExtensionRevision) (client.Client, error) {
+	saNamespace, saName, err := f.getServiceAccount(rev)
+	if err != nil {
+		return nil, err
+	}
+	return f.createScopedClient(saNamespace, saName)
+}
+
 func (f *defaultRevisionEngineFactory) getServiceAccount(rev *ocv1.ClusterExtensionRevision) (string, string, error) {
 	annotations := rev.GetAnnotations()
 	if annotations == nil {
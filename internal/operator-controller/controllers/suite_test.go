@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/rest"
@@ -33,6 +34,7 @@ import (
 
 	ocv1 "github.com/operator-framework/operator-controller/api/v1"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/controllers"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/extensionpolicy"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/resolve"
 	"github.com/operator-framework/operator-controller/internal/shared/util/image"
 	"github.com/operator-framework/operator-controller/test"
@@ -41,6 +43,7 @@ import (
 func newScheme(t *testing.T) *apimachineryruntime.Scheme {
 	sch := apimachineryruntime.NewScheme()
 	require.NoError(t, ocv1.AddToScheme(sch))
+	require.NoError(t, corev1.AddToScheme(sch))
 	return sch
 }
 
@@ -82,12 +85,22 @@ func (m *MockApplier) Apply(_ context.Context, _ fs.FS, _ *ocv1.ClusterExtension
 type reconcilerOption func(*deps)
 
 type deps struct {
+	ReleaseAdopter       controllers.ReleaseAdopter
 	RevisionStatesGetter controllers.RevisionStatesGetter
 	Finalizers           crfinalizer.Finalizers
 	Resolver             resolve.Resolver
 	ImagePuller          image.Puller
 	ImageCache           image.Cache
 	Applier              controllers.Applier
+	PolicyChecker        *extensionpolicy.Checker
+	Rollbacker           controllers.Rollbacker
+	UpgradePreviewer     controllers.UpgradePreviewer
+	ConfigReader         client.Client
+	Pauser               controllers.Pauser
+	// ChartPollInterval, when set, requeues ClusterExtensions with the Helm sourceType.
+	ChartPollInterval time.Duration
+	// RecordHistory, when set, records the revision history in the status.
+	RecordHistory bool
 }
 
 func newClientAndReconciler(t *testing.T, opts ...reconcilerOption) (client.Client, *controllers.ClusterExtensionReconciler) {
@@ -105,16 +118,43 @@ func newClientAndReconciler(t *testing.T, opts ...reconcilerOption) (client.Clie
 	for _, opt := range opts {
 		opt(d)
 	}
-	reconciler.ReconcileSteps = []controllers.ReconcileStepFunc{controllers.HandleFinalizers(d.Finalizers), controllers.RetrieveRevisionStates(d.RevisionStatesGetter)}
+	reconciler.ReconcileSteps = []controllers.ReconcileStepFunc{controllers.HandleFinalizers(d.Finalizers)}
+	if p := d.Pauser; p != nil {
+		reconciler.ReconcileSteps = append(reconciler.ReconcileSteps, controllers.PauseReconciliation(p))
+	}
+	if a := d.ReleaseAdopter; a != nil {
+		reconciler.ReconcileSteps = append(reconciler.ReconcileSteps, controllers.AdoptHelmRelease(a))
+	}
+	reconciler.ReconcileSteps = append(reconciler.ReconcileSteps, controllers.RetrieveRevisionStates(d.RevisionStatesGetter))
+	if d.RecordHistory {
+		reconciler.ReconcileSteps = append(reconciler.ReconcileSteps, controllers.RecordRevisionHistory())
+	}
+	if p := d.PolicyChecker; p != nil {
+		reconciler.ReconcileSteps = append(reconciler.ReconcileSteps, controllers.EnforceExtensionPolicies(p))
+	}
+	if r := d.Rollbacker; r != nil {
+		reconciler.ReconcileSteps = append(reconciler.ReconcileSteps, controllers.RollBack(r), controllers.RollBackFailedRollouts(r))
+	}
 	if r := d.Resolver; r != nil {
 		reconciler.ReconcileSteps = append(reconciler.ReconcileSteps, controllers.ResolveBundle(r, cl))
 	}
 	if i := d.ImagePuller; i != nil {
 		reconciler.ReconcileSteps = append(reconciler.ReconcileSteps, controllers.UnpackBundle(i, d.ImageCache))
 	}
+	if c := d.ConfigReader; c != nil {
+		reconciler.ReconcileSteps = append(reconciler.ReconcileSteps, controllers.ResolveConfigReferences(func(context.Context, *ocv1.ClusterExtension) (client.Client, error) {
+			return c, nil
+		}))
+	}
+	if p := d.UpgradePreviewer; p != nil {
+		reconciler.ReconcileSteps = append(reconciler.ReconcileSteps, controllers.PreviewUpgrade(p))
+	}
 	if a := d.Applier; a != nil {
 		reconciler.ReconcileSteps = append(reconciler.ReconcileSteps, controllers.ApplyBundle(a))
 	}
+	if i := d.ChartPollInterval; i != 0 {
+		reconciler.ReconcileSteps = append(reconciler.ReconcileSteps, controllers.RequeueChartSources(i))
+	}
 
 	return cl, reconciler
 }
@@ -0,0 +1,174 @@
+package extensionpolicy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+)
+
+// ViolationError is returned when a ClusterExtension does not satisfy one or
+// more ExtensionPolicies.
+type ViolationError struct {
+	Violations []string
+}
+
+func (e *ViolationError) Error() string {
+	return fmt.Sprintf("ClusterExtension violates ExtensionPolicy: %s", strings.Join(e.Violations, "; "))
+}
+
+// Checker evaluates ClusterExtensions against every ExtensionPolicy on the cluster.
+type Checker struct {
+	Reader client.Reader
+}
+
+// Check lists all ExtensionPolicies and evaluates ext against them. It returns a
+// *ViolationError if ext does not comply, or another error if the policies
+// could not be listed.
+func (c *Checker) Check(ctx context.Context, ext *ocv1.ClusterExtension) error {
+	var policies ocv1.ExtensionPolicyList
+	if err := c.Reader.List(ctx, &policies); err != nil {
+		return fmt.Errorf("listing ExtensionPolicies: %w", err)
+	}
+	return Evaluate(ext, policies.Items)
+}
+
+// Evaluate returns a *ViolationError describing every way in which ext violates
+// the given policies, or nil if ext satisfies all of them.
+func Evaluate(ext *ocv1.ClusterExtension, policies []ocv1.ExtensionPolicy) error {
+	// sort for a consistent error message
+	policies = slices.Clone(policies)
+	slices.SortFunc(policies, func(a, b ocv1.ExtensionPolicy) int { return strings.Compare(a.Name, b.Name) })
+
+	var violations []string
+	for _, p := range policies {
+		for _, v := range evaluatePolicy(ext, p.Spec) {
+			violations = append(violations, fmt.Sprintf("policy %q: %s", p.Name, v))
+		}
+	}
+	if len(violations) > 0 {
+		return &ViolationError{Violations: violations}
+	}
+	return nil
+}
+
+func evaluatePolicy(ext *ocv1.ClusterExtension, spec ocv1.ExtensionPolicySpec) []string {
+	var violations []string
+
+	if len(spec.AllowedInstallNamespaces) > 0 && !slices.Contains(spec.AllowedInstallNamespaces, ext.Spec.Namespace) {
+		violations = append(violations, fmt.Sprintf("namespace %q is not in allowed install namespaces %v", ext.Spec.Namespace, spec.AllowedInstallNamespaces))
+	}
+
+	if spec.MaxProgressDeadlineMinutes > 0 && ext.Spec.ProgressDeadlineMinutes > spec.MaxProgressDeadlineMinutes {
+		violations = append(violations, fmt.Sprintf("progressDeadlineMinutes %d exceeds the maximum of %d", ext.Spec.ProgressDeadlineMinutes, spec.MaxProgressDeadlineMinutes))
+	}
+
+	if spec.MinimumCRDUpgradeSafetyEnforcement == ocv1.CRDUpgradeSafetyEnforcementStrict && crdUpgradeSafetyEnforcement(ext) == ocv1.CRDUpgradeSafetyEnforcementNone {
+		violations = append(violations, "CRD upgrade safety enforcement must not be \"None\"")
+	}
+
+	if catalog := ext.Spec.Source.Catalog; catalog != nil {
+		if len(spec.AllowedPackages) > 0 && !slices.Contains(spec.AllowedPackages, catalog.PackageName) {
+			violations = append(violations, fmt.Sprintf("package %q is not in allowed packages %v", catalog.PackageName, spec.AllowedPackages))
+		}
+
+		if spec.SelfCertifiedUpgrades == ocv1.SelfCertifiedUpgradesForbid && catalog.UpgradeConstraintPolicy == ocv1.UpgradeConstraintPolicySelfCertified {
+			violations = append(violations, "upgradeConstraintPolicy \"SelfCertified\" is forbidden")
+		}
+
+		if len(spec.AllowedCatalogs) > 0 {
+			if v := checkCatalogSelector(catalog.Selector, spec.AllowedCatalogs); v != "" {
+				violations = append(violations, v)
+			}
+		}
+	}
+
+	if helm := ext.Spec.Source.Helm; helm != nil {
+		violations = append(violations, checkHelmSource(helm, spec)...)
+	}
+
+	return violations
+}
+
+// crdUpgradeSafetyEnforcement returns the effective CRD upgrade safety enforcement
+// for ext. An absent preflight configuration means the check runs in Strict mode.
+func crdUpgradeSafetyEnforcement(ext *ocv1.ClusterExtension) ocv1.CRDUpgradeSafetyEnforcement {
+	if ext.Spec.Install == nil || ext.Spec.Install.Preflight == nil || ext.Spec.Install.Preflight.CRDUpgradeSafety == nil {
+		return ocv1.CRDUpgradeSafetyEnforcementStrict
+	}
+	return ext.Spec.Install.Preflight.CRDUpgradeSafety.Enforcement
+}
+
+// checkHelmSource evaluates a Helm chart source against spec. A policy that
+// restricts packages or catalogs but does not list any Helm repositories denies
+// Helm sources, so that it cannot be bypassed by installing a chart instead.
+func checkHelmSource(helm *ocv1.HelmSource, spec ocv1.ExtensionPolicySpec) []string {
+	var violations []string
+
+	if len(spec.AllowedPackages) > 0 && !slices.Contains(spec.AllowedPackages, helm.Chart) {
+		violations = append(violations, fmt.Sprintf("chart %q is not in allowed packages %v", helm.Chart, spec.AllowedPackages))
+	}
+
+	switch {
+	case len(spec.AllowedHelmRepositories) > 0:
+		repository := strings.TrimSuffix(helm.Repository, "/")
+		if !slices.ContainsFunc(spec.AllowedHelmRepositories, func(allowed string) bool {
+			return strings.TrimSuffix(allowed, "/") == repository
+		}) {
+			violations = append(violations, fmt.Sprintf("Helm repository %q is not in allowed Helm repositories %v", helm.Repository, spec.AllowedHelmRepositories))
+		}
+	case len(spec.AllowedPackages) > 0 || len(spec.AllowedCatalogs) > 0:
+		violations = append(violations, "Helm sources are not allowed because the policy restricts packages or catalogs and lists no allowed Helm repositories")
+	}
+
+	return violations
+}
+
+// checkCatalogSelector ensures that the given selector can only match ClusterCatalogs
+// whose names are in allowed. Since we cannot know which catalogs will exist in the
+// future, the selector must restrict the catalog name label explicitly.
+func checkCatalogSelector(selector *metav1.LabelSelector, allowed []string) string {
+	selected, restricted := selectedCatalogNames(selector)
+	if !restricted {
+		return fmt.Sprintf("catalog selector must restrict the %q label to allowed catalogs %v", ocv1.MetadataNameLabel, allowed)
+	}
+	if disallowed := sets.List(selected.Difference(sets.New(allowed...))); len(disallowed) > 0 {
+		return fmt.Sprintf("catalogs %v are not in allowed catalogs %v", disallowed, allowed)
+	}
+	return ""
+}
+
+// selectedCatalogNames returns the set of catalog names that the selector restricts
+// the ClusterCatalog name label to, and whether the selector restricts it at all.
+func selectedCatalogNames(selector *metav1.LabelSelector) (sets.Set[string], bool) {
+	if selector == nil {
+		return nil, false
+	}
+	var (
+		selected   sets.Set[string]
+		restricted bool
+	)
+	intersect := func(names ...string) {
+		if !restricted {
+			selected = sets.New(names...)
+			restricted = true
+			return
+		}
+		selected = selected.Intersection(sets.New(names...))
+	}
+	if name, ok := selector.MatchLabels[ocv1.MetadataNameLabel]; ok {
+		intersect(name)
+	}
+	for _, req := range selector.MatchExpressions {
+		if req.Key == ocv1.MetadataNameLabel && req.Operator == metav1.LabelSelectorOpIn {
+			intersect(req.Values...)
+		}
+	}
+	return selected, restricted
+}
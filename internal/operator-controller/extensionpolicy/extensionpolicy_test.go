@@ -0,0 +1,310 @@
+package extensionpolicy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/extensionpolicy"
+)
+
+func testExtension(mutate func(*ocv1.ClusterExtension)) *ocv1.ClusterExtension {
+	ext := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ext"},
+		Spec: ocv1.ClusterExtensionSpec{
+			Namespace:      "test-ns",
+			ServiceAccount: ocv1.ServiceAccountReference{Name: "test-sa"},
+			Source: ocv1.SourceConfig{
+				SourceType: ocv1.SourceTypeCatalog,
+				Catalog: &ocv1.CatalogFilter{
+					PackageName: "test-package",
+				},
+			},
+		},
+	}
+	if mutate != nil {
+		mutate(ext)
+	}
+	return ext
+}
+
+func testHelmExtension() *ocv1.ClusterExtension {
+	return testExtension(func(ext *ocv1.ClusterExtension) {
+		ext.Spec.Source = ocv1.SourceConfig{
+			SourceType: ocv1.SourceTypeHelm,
+			Helm: &ocv1.HelmSource{
+				Repository: "https://charts.example.com",
+				Chart:      "test-chart",
+			},
+		}
+	})
+}
+
+func testPolicy(name string, spec ocv1.ExtensionPolicySpec) ocv1.ExtensionPolicy {
+	return ocv1.ExtensionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       spec,
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	for _, tc := range []struct {
+		name               string
+		ext                *ocv1.ClusterExtension
+		policies           []ocv1.ExtensionPolicy
+		expectedViolations []string
+	}{
+		{
+			name: "no policies",
+			ext:  testExtension(nil),
+		},
+		{
+			name:     "empty policy allows everything",
+			ext:      testExtension(nil),
+			policies: []ocv1.ExtensionPolicy{testPolicy("empty", ocv1.ExtensionPolicySpec{})},
+		},
+		{
+			name: "allowed package",
+			ext:  testExtension(nil),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				AllowedPackages: []string{"other", "test-package"},
+			})},
+		},
+		{
+			name: "disallowed package",
+			ext:  testExtension(nil),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				AllowedPackages: []string{"other"},
+			})},
+			expectedViolations: []string{`policy "p": package "test-package" is not in allowed packages [other]`},
+		},
+		{
+			name: "disallowed namespace",
+			ext:  testExtension(nil),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				AllowedInstallNamespaces: []string{"operators"},
+			})},
+			expectedViolations: []string{`policy "p": namespace "test-ns" is not in allowed install namespaces [operators]`},
+		},
+		{
+			name: "progress deadline within limit",
+			ext: testExtension(func(ext *ocv1.ClusterExtension) {
+				ext.Spec.ProgressDeadlineMinutes = 30
+			}),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				MaxProgressDeadlineMinutes: 30,
+			})},
+		},
+		{
+			name: "progress deadline unset is not limited",
+			ext:  testExtension(nil),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				MaxProgressDeadlineMinutes: 30,
+			})},
+		},
+		{
+			name: "progress deadline exceeds limit",
+			ext: testExtension(func(ext *ocv1.ClusterExtension) {
+				ext.Spec.ProgressDeadlineMinutes = 60
+			}),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				MaxProgressDeadlineMinutes: 30,
+			})},
+			expectedViolations: []string{`policy "p": progressDeadlineMinutes 60 exceeds the maximum of 30`},
+		},
+		{
+			name: "strict CRD upgrade safety with default preflight config",
+			ext:  testExtension(nil),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				MinimumCRDUpgradeSafetyEnforcement: ocv1.CRDUpgradeSafetyEnforcementStrict,
+			})},
+		},
+		{
+			name: "strict CRD upgrade safety with enforcement disabled",
+			ext: testExtension(func(ext *ocv1.ClusterExtension) {
+				ext.Spec.Install = &ocv1.ClusterExtensionInstallConfig{
+					Preflight: &ocv1.PreflightConfig{
+						CRDUpgradeSafety: &ocv1.CRDUpgradeSafetyPreflightConfig{Enforcement: ocv1.CRDUpgradeSafetyEnforcementNone},
+					},
+				}
+			}),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				MinimumCRDUpgradeSafetyEnforcement: ocv1.CRDUpgradeSafetyEnforcementStrict,
+			})},
+			expectedViolations: []string{`policy "p": CRD upgrade safety enforcement must not be "None"`},
+		},
+		{
+			name: "self-certified upgrades forbidden",
+			ext: testExtension(func(ext *ocv1.ClusterExtension) {
+				ext.Spec.Source.Catalog.UpgradeConstraintPolicy = ocv1.UpgradeConstraintPolicySelfCertified
+			}),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				SelfCertifiedUpgrades: ocv1.SelfCertifiedUpgradesForbid,
+			})},
+			expectedViolations: []string{`policy "p": upgradeConstraintPolicy "SelfCertified" is forbidden`},
+		},
+		{
+			name: "self-certified upgrades allowed",
+			ext: testExtension(func(ext *ocv1.ClusterExtension) {
+				ext.Spec.Source.Catalog.UpgradeConstraintPolicy = ocv1.UpgradeConstraintPolicySelfCertified
+			}),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				SelfCertifiedUpgrades: ocv1.SelfCertifiedUpgradesAllow,
+			})},
+		},
+		{
+			name: "allowed catalogs without selector",
+			ext:  testExtension(nil),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				AllowedCatalogs: []string{"trusted"},
+			})},
+			expectedViolations: []string{`policy "p": catalog selector must restrict the "olm.operatorframework.io/metadata.name" label to allowed catalogs [trusted]`},
+		},
+		{
+			name: "allowed catalogs with matching matchLabels",
+			ext: testExtension(func(ext *ocv1.ClusterExtension) {
+				ext.Spec.Source.Catalog.Selector = &metav1.LabelSelector{
+					MatchLabels: map[string]string{ocv1.MetadataNameLabel: "trusted"},
+				}
+			}),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				AllowedCatalogs: []string{"trusted"},
+			})},
+		},
+		{
+			name: "allowed catalogs with partially disallowed matchExpressions",
+			ext: testExtension(func(ext *ocv1.ClusterExtension) {
+				ext.Spec.Source.Catalog.Selector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{
+						Key:      ocv1.MetadataNameLabel,
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   []string{"trusted", "untrusted"},
+					}},
+				}
+			}),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				AllowedCatalogs: []string{"trusted"},
+			})},
+			expectedViolations: []string{`policy "p": catalogs [untrusted] are not in allowed catalogs [trusted]`},
+		},
+		{
+			name: "allowed catalogs with intersecting matchLabels and matchExpressions",
+			ext: testExtension(func(ext *ocv1.ClusterExtension) {
+				ext.Spec.Source.Catalog.Selector = &metav1.LabelSelector{
+					MatchLabels: map[string]string{ocv1.MetadataNameLabel: "trusted"},
+					MatchExpressions: []metav1.LabelSelectorRequirement{{
+						Key:      ocv1.MetadataNameLabel,
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   []string{"trusted", "untrusted"},
+					}},
+				}
+			}),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				AllowedCatalogs: []string{"trusted"},
+			})},
+		},
+		{
+			name: "helm source with empty policy",
+			ext:  testHelmExtension(),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				MaxProgressDeadlineMinutes: 30,
+			})},
+		},
+		{
+			name: "helm source with allowed repository and chart",
+			ext:  testHelmExtension(),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				AllowedPackages:         []string{"test-chart"},
+				AllowedHelmRepositories: []string{"https://charts.example.com/"},
+			})},
+		},
+		{
+			name: "helm source with disallowed repository and chart",
+			ext:  testHelmExtension(),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				AllowedPackages:         []string{"other"},
+				AllowedHelmRepositories: []string{"oci://registry.example.com/charts"},
+			})},
+			expectedViolations: []string{
+				`policy "p": chart "test-chart" is not in allowed packages [other]`,
+				`policy "p": Helm repository "https://charts.example.com" is not in allowed Helm repositories [oci://registry.example.com/charts]`,
+			},
+		},
+		{
+			name: "helm source denied by policy that only restricts packages",
+			ext:  testHelmExtension(),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				AllowedPackages: []string{"test-chart"},
+			})},
+			expectedViolations: []string{`policy "p": Helm sources are not allowed because the policy restricts packages or catalogs and lists no allowed Helm repositories`},
+		},
+		{
+			name: "helm source denied by policy that only restricts catalogs",
+			ext:  testHelmExtension(),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				AllowedCatalogs: []string{"trusted"},
+			})},
+			expectedViolations: []string{`policy "p": Helm sources are not allowed because the policy restricts packages or catalogs and lists no allowed Helm repositories`},
+		},
+		{
+			name: "allowed helm repositories do not affect catalog sources",
+			ext:  testExtension(nil),
+			policies: []ocv1.ExtensionPolicy{testPolicy("p", ocv1.ExtensionPolicySpec{
+				AllowedHelmRepositories: []string{"https://charts.example.com"},
+			})},
+		},
+		{
+			name: "violations from multiple policies are sorted by policy name",
+			ext:  testExtension(nil),
+			policies: []ocv1.ExtensionPolicy{
+				testPolicy("b", ocv1.ExtensionPolicySpec{AllowedPackages: []string{"other"}}),
+				testPolicy("a", ocv1.ExtensionPolicySpec{AllowedInstallNamespaces: []string{"operators"}}),
+			},
+			expectedViolations: []string{
+				`policy "a": namespace "test-ns" is not in allowed install namespaces [operators]`,
+				`policy "b": package "test-package" is not in allowed packages [other]`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := extensionpolicy.Evaluate(tc.ext, tc.policies)
+			if len(tc.expectedViolations) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var violation *extensionpolicy.ViolationError
+			require.ErrorAs(t, err, &violation)
+			require.Equal(t, tc.expectedViolations, violation.Violations)
+		})
+	}
+}
+
+func TestChecker(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, ocv1.AddToScheme(scheme))
+
+	t.Run("lists policies from the cluster", func(t *testing.T) {
+		policy := testPolicy("p", ocv1.ExtensionPolicySpec{AllowedPackages: []string{"other"}})
+		checker := &extensionpolicy.Checker{
+			Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(&policy).Build(),
+		}
+		err := checker.Check(context.Background(), testExtension(nil))
+		var violation *extensionpolicy.ViolationError
+		require.ErrorAs(t, err, &violation)
+	})
+
+	t.Run("returns list errors", func(t *testing.T) {
+		checker := &extensionpolicy.Checker{
+			Reader: fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build(),
+		}
+		err := checker.Check(context.Background(), testExtension(nil))
+		require.Error(t, err)
+		require.False(t, errors.As(err, new(*extensionpolicy.ViolationError)))
+	})
+}
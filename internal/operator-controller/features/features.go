@@ -18,6 +18,23 @@ const (
 	WebhookProviderOpenshiftServiceCA featuregate.Feature = "WebhookProviderOpenshiftServiceCA"
 	HelmChartSupport                  featuregate.Feature = "HelmChartSupport"
 	BoxcutterRuntime                  featuregate.Feature = "BoxcutterRuntime"
+	ExtensionPolicy                   featuregate.Feature = "ExtensionPolicy"
+	ConfigSourceReferences            featuregate.Feature = "ConfigSourceReferences"
+	HelmReleaseAdoption               featuregate.Feature = "HelmReleaseAdoption"
+	ReleaseRollback                   featuregate.Feature = "ReleaseRollback"
+	UpgradePreview                    featuregate.Feature = "UpgradePreview"
+	ManifestPatches                   featuregate.Feature = "ManifestPatches"
+	InstallNamespaceCreation          featuregate.Feature = "InstallNamespaceCreation"
+	ObjectProbes                      featuregate.Feature = "ObjectProbes"
+	CanaryRollout                     featuregate.Feature = "CanaryRollout"
+	RevisionHistory                   featuregate.Feature = "RevisionHistory"
+	CollisionProtectionPolicy         featuregate.Feature = "CollisionProtectionPolicy"
+	OLMv0Migration                    featuregate.Feature = "OLMv0Migration"
+	UninstallPolicy                   featuregate.Feature = "UninstallPolicy"
+	PauseReconciliation               featuregate.Feature = "PauseReconciliation"
+	DriftPolicy                       featuregate.Feature = "DriftPolicy"
+	ClusterCapabilities               featuregate.Feature = "ClusterCapabilities"
+	ChannelDeprecationPolicy          featuregate.Feature = "ChannelDeprecationPolicy"
 )
 
 var operatorControllerFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
@@ -80,6 +97,143 @@ var operatorControllerFeatureGates = map[featuregate.Feature]featuregate.Feature
 		PreRelease:    featuregate.Alpha,
 		LockToDefault: false,
 	},
+
+	// ExtensionPolicy enables enforcement of cluster-wide ExtensionPolicy
+	// guardrails on ClusterExtensions, both at admission time via a
+	// validating webhook and during reconciliation.
+	ExtensionPolicy: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// ConfigSourceReferences enables reading ClusterExtension configuration
+	// from labeled Secrets and ConfigMaps in the installation namespace.
+	ConfigSourceReferences: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// HelmReleaseAdoption enables importing Helm releases installed
+	// outside of OLM into ClusterExtensions that request it.
+	HelmReleaseAdoption: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// ReleaseRollback enables rolling ClusterExtensions back to a previous
+	// revision of their installed content with spec.install.rollback.
+	ReleaseRollback: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// UpgradePreview enables previewing the changes of upgrades in the
+	// ClusterExtension status, and approving upgrades manually.
+	UpgradePreview: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// ManifestPatches enables patching the objects rendered from bundles
+	// with spec.install.patches.
+	ManifestPatches: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// InstallNamespaceCreation enables the creation of the installation
+	// namespace of a ClusterExtension with spec.install.namespace.
+	InstallNamespaceCreation: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// ObjectProbes enables readiness probes of the objects of
+	// ClusterExtensionRevisions declared with spec.install.probes.
+	ObjectProbes: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// CanaryRollout enables the soak of the Deployments of
+	// ClusterExtensionRevisions configured with spec.install.rollout.
+	CanaryRollout: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// RevisionHistory enables the summary of the revisions of
+	// ClusterExtensions in status.history.
+	RevisionHistory: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// CollisionProtectionPolicy enables the adoption of existing objects by
+	// ClusterExtensions configured with spec.install.collisionProtection.
+	CollisionProtectionPolicy: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// OLMv0Migration enables migrating operators installed by OLM v0
+	// Subscriptions into ClusterExtensions that request it.
+	OLMv0Migration: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// UninstallPolicy enables keeping installed objects when ClusterExtensions
+	// configured with spec.install.uninstallPolicy are deleted.
+	UninstallPolicy: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// PauseReconciliation enables pausing the reconciliation of ClusterExtensions
+	// and their ClusterExtensionRevisions with spec.paused.
+	PauseReconciliation: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// DriftPolicy enables keeping the fields of installed objects listed in
+	// spec.install.ignoreDifferences, and reporting reverted changes in status.driftDetected.
+	DriftPolicy: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// ClusterCapabilities enables excluding bundles that can't run on the
+	// cluster from resolution, and reporting them in the IncompatibleBundles condition.
+	ClusterCapabilities: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
+
+	// ChannelDeprecationPolicy enables migrating ClusterExtensions to the successors
+	// of deprecated channels, or blocking their upgrades, with channelDeprecationPolicy.
+	ChannelDeprecationPolicy: {
+		Default:       false,
+		PreRelease:    featuregate.Alpha,
+		LockToDefault: false,
+	},
 }
 
 var OperatorControllerFeatureGate featuregate.MutableFeatureGate = featuregate.NewFeatureGate()
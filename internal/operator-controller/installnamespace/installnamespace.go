@@ -0,0 +1,185 @@
+// Package installnamespace builds and applies the installation namespaces that
+// ClusterExtensions request with spec.install.namespace.
+package installnamespace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
+)
+
+// FieldOwner is the field manager of the metadata that OLM applies to the namespaces it creates.
+const FieldOwner = "olm.operatorframework.io/install-namespace"
+
+// ConfigurationError reports a namespace configuration of a ClusterExtension that the
+// API server rejects, such as an invalid label, and that can only be fixed by the user.
+type ConfigurationError struct {
+	Err error
+}
+
+func (e *ConfigurationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConfigurationError) Unwrap() error {
+	return e.Err
+}
+
+// Requested reports whether ext requests its installation namespace with spec.install.namespace.
+func Requested(ext *ocv1.ClusterExtension) bool {
+	return features.OperatorControllerFeatureGate.Enabled(features.InstallNamespaceCreation) &&
+		ext.Spec.Install != nil && ext.Spec.Install.Namespace != nil
+}
+
+// Object returns the namespace requested by ext, with the labels and annotations of
+// spec.install.namespace, to install it alongside the content of ext. It returns nil when ext
+// doesn't request its namespace.
+func Object(ext *ocv1.ClusterExtension) *corev1.Namespace {
+	if !Requested(ext) {
+		return nil
+	}
+	cfg := ext.Spec.Install.Namespace
+	return &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ext.Spec.Namespace,
+			Labels:      maps.Clone(cfg.Labels),
+			Annotations: maps.Clone(cfg.Annotations),
+		},
+	}
+}
+
+// Ensurer applies the installation namespace of ClusterExtensions that are installed without
+// ClusterExtensionRevisions, which install the namespace with their objects instead.
+type Ensurer struct {
+	// Reader reads namespaces, typically with the ServiceAccount of the ClusterExtension. It
+	// should not be backed by a cache, so that OLM doesn't need to watch all namespaces of
+	// the cluster.
+	Reader client.Reader
+	// Writer applies namespaces, typically with the ServiceAccount of the ClusterExtension.
+	Writer client.Writer
+}
+
+// Ensure applies the namespace of ext when ext requests it, and keeps its labels and annotations
+// up to date. Namespaces that OLM creates are controlled by ext, so that they are garbage
+// collected when ext is deleted. Namespaces that existed before are only labeled and annotated,
+// and are kept when ext is deleted. Namespaces controlled by another owner are left untouched.
+func (e *Ensurer) Ensure(ctx context.Context, ext *ocv1.ClusterExtension) error {
+	if !Requested(ext) {
+		return nil
+	}
+	ns, err := e.get(ctx, ext)
+	if err != nil {
+		return err
+	}
+	switch {
+	case ns == nil:
+		return e.apply(ctx, ext, true)
+	case metav1.GetControllerOf(ns) != nil && !metav1.IsControlledBy(ns, ext):
+		return nil
+	default:
+		return e.apply(ctx, ext, createdFor(ns, ext))
+	}
+}
+
+// EnsurePreexisting applies the labels and annotations of the namespace of ext like Ensure when
+// the namespace existed before, but doesn't create it. It reports whether the namespace existed
+// before, so that it's left out of the ClusterExtensionRevisions of ext, which create it otherwise.
+func (e *Ensurer) EnsurePreexisting(ctx context.Context, ext *ocv1.ClusterExtension) (bool, error) {
+	if !Requested(ext) {
+		return false, nil
+	}
+	ns, err := e.get(ctx, ext)
+	if err != nil || ns == nil || createdFor(ns, ext) {
+		return false, err
+	}
+	if metav1.GetControllerOf(ns) != nil {
+		return true, nil
+	}
+	return true, e.apply(ctx, ext, false)
+}
+
+// Preexisting reports whether the namespace requested by ext exists and was not created by OLM
+// for ext. OLM doesn't own such namespaces, so that they are kept when ext is deleted.
+func Preexisting(ctx context.Context, reader client.Reader, ext *ocv1.ClusterExtension) (bool, error) {
+	if !Requested(ext) {
+		return false, nil
+	}
+	ns, err := (&Ensurer{Reader: reader}).get(ctx, ext)
+	if err != nil || ns == nil {
+		return false, err
+	}
+	return !createdFor(ns, ext), nil
+}
+
+// get returns the namespace of ext, or nil when it doesn't exist.
+func (e *Ensurer) get(ctx context.Context, ext *ocv1.ClusterExtension) (*corev1.Namespace, error) {
+	ns := &corev1.Namespace{}
+	err := e.Reader.Get(ctx, client.ObjectKey{Name: ext.Spec.Namespace}, ns)
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("getting namespace %q: %w", ext.Spec.Namespace, err)
+	case ns.DeletionTimestamp != nil:
+		return nil, fmt.Errorf("namespace %q is being deleted", ext.Spec.Namespace)
+	}
+	return ns, nil
+}
+
+func (e *Ensurer) apply(ctx context.Context, ext *ocv1.ClusterExtension, owned bool) error {
+	if err := e.Writer.Apply(ctx, desiredNamespace(ext, owned), client.FieldOwner(FieldOwner), client.ForceOwnership); err != nil {
+		if apierrors.IsInvalid(err) || apierrors.IsBadRequest(err) {
+			return &ConfigurationError{Err: fmt.Errorf("applying namespace %q: %w", ext.Spec.Namespace, err)}
+		}
+		return fmt.Errorf("applying namespace %q: %w", ext.Spec.Namespace, err)
+	}
+	return nil
+}
+
+// createdFor reports whether OLM created ns for ext, which it labels as the owner of ns.
+func createdFor(ns *corev1.Namespace, ext *ocv1.ClusterExtension) bool {
+	return ns.Labels[labels.OwnerKindKey] == ocv1.ClusterExtensionKind && ns.Labels[labels.OwnerNameKey] == ext.Name
+}
+
+// IsConfigurationError reports whether err is caused by the namespace configuration of a ClusterExtension.
+func IsConfigurationError(err error) bool {
+	var cfgErr *ConfigurationError
+	return errors.As(err, &cfgErr)
+}
+
+func desiredNamespace(ext *ocv1.ClusterExtension, owned bool) *corev1ac.NamespaceApplyConfiguration {
+	cfg := ext.Spec.Install.Namespace
+	ns := corev1ac.Namespace(ext.Spec.Namespace)
+	if owned {
+		nsLabels := make(map[string]string, len(cfg.Labels)+2)
+		maps.Copy(nsLabels, cfg.Labels)
+		nsLabels[labels.OwnerKindKey] = ocv1.ClusterExtensionKind
+		nsLabels[labels.OwnerNameKey] = ext.Name
+		ns.WithLabels(nsLabels).
+			WithOwnerReferences(metav1ac.OwnerReference().
+				WithAPIVersion(ocv1.GroupVersion.String()).
+				WithKind(ocv1.ClusterExtensionKind).
+				WithName(ext.Name).
+				WithUID(ext.UID).
+				WithController(true))
+	} else if len(cfg.Labels) > 0 {
+		ns.WithLabels(cfg.Labels)
+	}
+	if len(cfg.Annotations) > 0 {
+		ns.WithAnnotations(cfg.Annotations)
+	}
+	return ns
+}
@@ -0,0 +1,218 @@
+package installnamespace_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/installnamespace"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/labels"
+)
+
+func TestMain(m *testing.M) {
+	if err := features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.InstallNamespaceCreation)); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func newExtension(nsConfig *ocv1.ClusterExtensionInstallNamespace) *ocv1.ClusterExtension {
+	ext := &ocv1.ClusterExtension{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-ext", UID: types.UID("my-ext-uid")},
+		Spec: ocv1.ClusterExtensionSpec{
+			Namespace:      "my-ns",
+			ServiceAccount: ocv1.ServiceAccountReference{Name: "installer"},
+		},
+	}
+	if nsConfig != nil {
+		ext.Spec.Install = &ocv1.ClusterExtensionInstallConfig{Namespace: nsConfig}
+	}
+	return ext
+}
+
+func newEnsurer(t *testing.T, objs ...client.Object) (*installnamespace.Ensurer, client.Client) {
+	s := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(s))
+	require.NoError(t, ocv1.AddToScheme(s))
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+	return &installnamespace.Ensurer{Reader: cl, Writer: cl}, cl
+}
+
+func ownedNamespace(ext *ocv1.ClusterExtension, nsLabels, nsAnnotations map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ext.Spec.Namespace,
+			Labels:      nsLabels,
+			Annotations: nsAnnotations,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: ocv1.GroupVersion.String(),
+				Kind:       ocv1.ClusterExtensionKind,
+				Name:       ext.Name,
+				UID:        ext.UID,
+				Controller: ptr.To(true),
+			}},
+		},
+	}
+}
+
+func TestEnsure_NotRequested(t *testing.T) {
+	ext := newExtension(nil)
+	e, cl := newEnsurer(t)
+
+	require.NoError(t, e.Ensure(context.Background(), ext))
+
+	err := cl.Get(context.Background(), client.ObjectKey{Name: ext.Spec.Namespace}, &corev1.Namespace{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestEnsure_CreatesNamespace(t *testing.T) {
+	ext := newExtension(&ocv1.ClusterExtensionInstallNamespace{
+		Labels:      map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+		Annotations: map[string]string{"example.com/team": "platform"},
+	})
+	e, cl := newEnsurer(t)
+
+	require.NoError(t, e.Ensure(context.Background(), ext))
+
+	ns := &corev1.Namespace{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Name: ext.Spec.Namespace}, ns))
+	require.Equal(t, map[string]string{
+		"pod-security.kubernetes.io/enforce": "restricted",
+		labels.OwnerKindKey:                  ocv1.ClusterExtensionKind,
+		labels.OwnerNameKey:                  ext.Name,
+	}, ns.Labels)
+	require.Equal(t, map[string]string{"example.com/team": "platform"}, ns.Annotations)
+	require.True(t, metav1.IsControlledBy(ns, ext))
+}
+
+func TestEnsure_UpdatesCreatedNamespace(t *testing.T) {
+	ext := newExtension(&ocv1.ClusterExtensionInstallNamespace{
+		Labels: map[string]string{"pod-security.kubernetes.io/enforce": "baseline"},
+	})
+	e, cl := newEnsurer(t, ownedNamespace(ext, map[string]string{
+		"pod-security.kubernetes.io/enforce": "restricted",
+		labels.OwnerKindKey:                  ocv1.ClusterExtensionKind,
+		labels.OwnerNameKey:                  ext.Name,
+	}, nil))
+
+	require.NoError(t, e.Ensure(context.Background(), ext))
+
+	ns := &corev1.Namespace{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Name: ext.Spec.Namespace}, ns))
+	require.Equal(t, "baseline", ns.Labels["pod-security.kubernetes.io/enforce"])
+	require.True(t, metav1.IsControlledBy(ns, ext))
+}
+
+func TestEnsure_KeepsPreexistingNamespaceOnUninstall(t *testing.T) {
+	ext := newExtension(&ocv1.ClusterExtensionInstallNamespace{
+		Labels: map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+	})
+	existing := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   ext.Spec.Namespace,
+			Labels: map[string]string{"team": "platform"},
+		},
+	}
+	e, cl := newEnsurer(t, existing)
+
+	require.NoError(t, e.Ensure(context.Background(), ext))
+
+	ns := &corev1.Namespace{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Name: ext.Spec.Namespace}, ns))
+	require.Equal(t, map[string]string{
+		"team":                               "platform",
+		"pod-security.kubernetes.io/enforce": "restricted",
+	}, ns.Labels)
+	require.Empty(t, ns.OwnerReferences, "the namespace must not be garbage collected with the ClusterExtension")
+
+	t.Log("the namespace is kept when the ClusterExtension is uninstalled")
+	preexisting, err := installnamespace.Preexisting(context.Background(), cl, ext)
+	require.NoError(t, err)
+	require.True(t, preexisting)
+}
+
+func TestEnsurePreexisting(t *testing.T) {
+	ext := newExtension(&ocv1.ClusterExtensionInstallNamespace{
+		Labels: map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+	})
+
+	t.Run("doesn't create the namespace", func(t *testing.T) {
+		e, cl := newEnsurer(t)
+		preexisting, err := e.EnsurePreexisting(context.Background(), ext)
+		require.NoError(t, err)
+		require.False(t, preexisting)
+		err = cl.Get(context.Background(), client.ObjectKey{Name: ext.Spec.Namespace}, &corev1.Namespace{})
+		require.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("labels a namespace that existed before without owning it", func(t *testing.T) {
+		e, cl := newEnsurer(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ext.Spec.Namespace}})
+		preexisting, err := e.EnsurePreexisting(context.Background(), ext)
+		require.NoError(t, err)
+		require.True(t, preexisting)
+		ns := &corev1.Namespace{}
+		require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Name: ext.Spec.Namespace}, ns))
+		require.Equal(t, map[string]string{"pod-security.kubernetes.io/enforce": "restricted"}, ns.Labels)
+		require.Empty(t, ns.OwnerReferences)
+	})
+
+	t.Run("leaves a namespace created for the extension to its revisions", func(t *testing.T) {
+		e, _ := newEnsurer(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: ext.Spec.Namespace,
+			Labels: map[string]string{
+				labels.OwnerKindKey: ocv1.ClusterExtensionKind,
+				labels.OwnerNameKey: ext.Name,
+			},
+		}})
+		preexisting, err := e.EnsurePreexisting(context.Background(), ext)
+		require.NoError(t, err)
+		require.False(t, preexisting)
+	})
+}
+
+func TestEnsure_LeavesNamespaceOfOtherExtensionUntouched(t *testing.T) {
+	ext := newExtension(&ocv1.ClusterExtensionInstallNamespace{
+		Labels: map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+	})
+	other := newExtension(nil)
+	other.Name = "other-ext"
+	other.UID = types.UID("other-ext-uid")
+	e, cl := newEnsurer(t, ownedNamespace(other, nil, nil))
+
+	require.NoError(t, e.Ensure(context.Background(), ext))
+
+	ns := &corev1.Namespace{}
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKey{Name: ext.Spec.Namespace}, ns))
+	require.Empty(t, ns.Labels)
+	require.True(t, metav1.IsControlledBy(ns, other))
+}
+
+func TestObject(t *testing.T) {
+	require.Nil(t, installnamespace.Object(newExtension(nil)))
+
+	ns := installnamespace.Object(newExtension(&ocv1.ClusterExtensionInstallNamespace{
+		Labels:      map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+		Annotations: map[string]string{"example.com/team": "platform"},
+	}))
+	require.Equal(t, &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-ns",
+			Labels:      map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+			Annotations: map[string]string{"example.com/team": "platform"},
+		},
+	}, ns)
+}
@@ -26,6 +26,10 @@ const (
 	// ClusterExtensionRevision.
 	BundleReferenceKey = "olm.operatorframework.io/bundle-reference"
 
+	// CatalogNameKey is the label key used to record the name of the
+	// ClusterCatalog that provided the bundle for a ClusterExtensionRevision.
+	CatalogNameKey = "olm.operatorframework.io/catalog-name"
+
 	// ServiceAccountNameKey is the annotation key used to record the name of
 	// the ServiceAccount configured on the owning ClusterExtension. It is
 	// applied as an annotation on ClusterExtensionRevision resources to
@@ -44,4 +48,44 @@ const (
 	// that were created during migration from Helm releases. This label is used
 	// to distinguish migrated revisions from those created by normal Boxcutter operation.
 	MigratedFromHelmKey = "olm.operatorframework.io/migrated-from-helm"
+
+	// MigratedFromOLMv0Key is the label key used to mark ClusterExtensionRevisions
+	// that were created during migration from OLM v0 Subscriptions.
+	MigratedFromOLMv0Key = "olm.operatorframework.io/migrated-from-olmv0"
+
+	// OLMv0SubscriptionKey is the annotation key used to record the OLM v0
+	// Subscription that a ClusterExtensionRevision was migrated from. It is set
+	// while the revision is paused, until the Subscription and its CSV are removed
+	// and the objects they installed can be adopted.
+	OLMv0SubscriptionKey = "olm.operatorframework.io/olmv0-subscription"
+
+	// RollbackOfRevisionKey is the label key used to record the revision that
+	// a Helm release or a ClusterExtensionRevision rolled back to. It is used
+	// to recognize rollbacks that were already performed.
+	RollbackOfRevisionKey = "olm.operatorframework.io/rollback-of-revision"
+
+	// FailedRevisionKey is the label key used to record the number of the
+	// revision that failed to roll out on the Helm release or the
+	// ClusterExtensionRevision rolling it back.
+	FailedRevisionKey = "olm.operatorframework.io/failed-revision"
+
+	// FailedBundleKey is the label key used to record the name of the bundle
+	// that failed to roll out on the Helm release or the ClusterExtensionRevision
+	// rolling it back. The bundle is not installed again until the failure is
+	// acknowledged.
+	FailedBundleKey = "olm.operatorframework.io/failed-bundle"
+
+	// PatchesDigestKey is the annotation key used to record a digest of the
+	// spec.install.patches of the ClusterExtension that the objects of a
+	// ClusterExtensionRevision were patched with.
+	PatchesDigestKey = "olm.operatorframework.io/patches-digest"
+
+	// AdoptedHelmReleaseKey is the label key used to mark Helm releases that were
+	// imported from a release installed outside of OLM. Its value is
+	// AdoptedHelmReleasePending until the original release was deleted, and
+	// AdoptedHelmReleaseComplete afterwards.
+	AdoptedHelmReleaseKey = "olm.operatorframework.io/adopted-helm-release"
+
+	AdoptedHelmReleasePending  = "pending"
+	AdoptedHelmReleaseComplete = "complete"
 )
@@ -0,0 +1,221 @@
+// Package objectprobe builds the readiness probes that ClusterExtensions declare for the
+// objects of ClusterExtensionRevisions.
+package objectprobe
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	"pkg.package-operator.run/boxcutter/machinery/types"
+	"pkg.package-operator.run/boxcutter/probing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+)
+
+// New returns a prober that checks all the given probes.
+func New(probes []ocv1.ObjectProbe) (types.Prober, error) {
+	probers := make(probing.And, 0, len(probes))
+	for i, p := range probes {
+		prober, err := newProber(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid probe %d: %w", i, err)
+		}
+		probers = append(probers, prober)
+	}
+	return probers, nil
+}
+
+// ObjectKey identifies an object of a ClusterExtensionRevision.
+type ObjectKey struct {
+	schema.GroupKind
+	Namespace string
+	Name      string
+}
+
+// KeyOf returns the ObjectKey of obj.
+func KeyOf(obj client.Object) ObjectKey {
+	return ObjectKey{
+		GroupKind: obj.GetObjectKind().GroupVersionKind().GroupKind(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+}
+
+// ObjectProbes probes each object with the prober of its ObjectKey. Objects without
+// a prober pass.
+type ObjectProbes map[ObjectKey]types.Prober
+
+func (p ObjectProbes) Probe(obj client.Object) types.ProbeResult {
+	prober, ok := p[KeyOf(obj)]
+	if !ok {
+		return passed()
+	}
+	return prober.Probe(obj)
+}
+
+func newProber(p ocv1.ObjectProbe) (types.Prober, error) {
+	switch {
+	case p.Type == ocv1.ObjectProbeTypeCondition && p.Condition != nil:
+		return &probing.ObservedGenerationProbe{
+			Prober: &probing.ConditionProbe{Type: p.Condition.Type, Status: p.Condition.Status},
+		}, nil
+	case p.Type == ocv1.ObjectProbeTypeFieldsEqual && p.FieldsEqual != nil:
+		return &probing.FieldsEqualProbe{FieldA: p.FieldsEqual.FieldA, FieldB: p.FieldsEqual.FieldB}, nil
+	case p.Type == ocv1.ObjectProbeTypeFieldValue && p.FieldValue != nil:
+		return &FieldValueProbe{FieldPath: p.FieldValue.FieldPath, Value: p.FieldValue.Value}, nil
+	case p.Type == ocv1.ObjectProbeTypeCEL && p.CEL != nil:
+		return NewCELProbe(p.CEL.Rule, p.CEL.Message)
+	}
+	return nil, fmt.Errorf("type %q requires the matching probe configuration", p.Type)
+}
+
+// FieldValueProbe checks that the field at FieldPath, for example ".status.phase",
+// has the given value. Values that are not strings are compared with their JSON representation.
+type FieldValueProbe struct {
+	FieldPath string
+	Value     string
+}
+
+func (p *FieldValueProbe) Probe(obj client.Object) types.ProbeResult {
+	content, err := toUnstructured(obj)
+	if err != nil {
+		return unknown(err.Error())
+	}
+	value, found, err := unstructured.NestedFieldNoCopy(content, fieldPathElements(p.FieldPath)...)
+	switch {
+	case err != nil:
+		return unknown(fmt.Sprintf("reading %s: %v", p.FieldPath, err))
+	case !found:
+		return failed(fmt.Sprintf("%s is missing", p.FieldPath))
+	}
+	actual, ok := value.(string)
+	if !ok {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return unknown(fmt.Sprintf("reading %s: %v", p.FieldPath, err))
+		}
+		actual = string(b)
+	}
+	if actual != p.Value {
+		return failed(fmt.Sprintf("%s is %q, expected %q", p.FieldPath, actual, p.Value))
+	}
+	return passed()
+}
+
+const (
+	// celCostLimit is the cost limit of each evaluation of a CEL probe, which is the limit of
+	// the API server for each evaluation of a validation rule.
+	celCostLimit = celconfig.PerCallLimit
+	// celEstimatedCostLimit is the limit of the estimated cost of a CEL probe, which is the
+	// limit of the API server for the estimated cost of a validation rule of a CRD.
+	celEstimatedCostLimit = 10000000
+	// celMaxValueSize bounds the size of the values of objects in cost estimates. Like the
+	// API server does for fields without maxItems or maxLength, it is the number of the
+	// smallest serialized list elements, "{},", that fit in the largest request.
+	celMaxValueSize = uint64(celconfig.MaxRequestSizeBytes / 3)
+)
+
+// CELProbe checks that a CEL expression over the object, available as the variable "self",
+// evaluates to true.
+type CELProbe struct {
+	rule    string
+	message string
+	program cel.Program
+}
+
+// NewCELProbe compiles rule, which must evaluate to a boolean. message is reported when the
+// rule does not evaluate to true; it defaults to the rule. Rules are rejected when their
+// estimated cost exceeds the limit of the API server for validation rules, and each
+// evaluation is aborted when it exceeds the cost limit of the API server for a single call.
+func NewCELProbe(rule, message string) (*CELProbe, error) {
+	env, err := cel.NewEnv(cel.Variable("self", cel.DynType))
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(rule)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("compiling rule %q: %w", rule, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("rule %q must evaluate to a bool, not %s", rule, ast.OutputType())
+	}
+	cost, err := env.EstimateCost(ast, objectSizeEstimator{})
+	if err != nil {
+		return nil, fmt.Errorf("estimating the cost of rule %q: %w", rule, err)
+	}
+	if cost.Max > celEstimatedCostLimit {
+		return nil, fmt.Errorf("estimated cost of rule %q exceeds the limit of %d by a factor of %.1f", rule, celEstimatedCostLimit, float64(cost.Max)/celEstimatedCostLimit)
+	}
+	program, err := env.Program(ast, cel.CostLimit(celCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("compiling rule %q: %w", rule, err)
+	}
+	if message == "" {
+		message = fmt.Sprintf("%s is not true", rule)
+	}
+	return &CELProbe{rule: rule, message: message, program: program}, nil
+}
+
+func (p *CELProbe) Probe(obj client.Object) types.ProbeResult {
+	content, err := toUnstructured(obj)
+	if err != nil {
+		return unknown(err.Error())
+	}
+	out, _, err := p.program.Eval(map[string]any{"self": content})
+	if err != nil {
+		// Fields that are not set yet, such as status fields, fail the evaluation
+		// until they are set by the controller of the object.
+		return failed(fmt.Sprintf("%s: %v", p.message, err))
+	}
+	if result, ok := out.Value().(bool); !ok || !result {
+		return failed(p.message)
+	}
+	return passed()
+}
+
+// objectSizeEstimator estimates the size of the values of the objects that CEL probes evaluate,
+// whose schemas are not known, with celMaxValueSize.
+type objectSizeEstimator struct{}
+
+func (objectSizeEstimator) EstimateSize(element checker.AstNode) *checker.SizeEstimate {
+	if element.Path() != nil {
+		return &checker.SizeEstimate{Min: 0, Max: celMaxValueSize}
+	}
+	return nil
+}
+
+func (objectSizeEstimator) EstimateCallCost(string, string, *checker.AstNode, []checker.AstNode) *checker.CallEstimate {
+	return nil
+}
+
+func toUnstructured(obj client.Object) (map[string]any, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.Object, nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
+
+// fieldPathElements splits a path such as ".status.phase" into its fields.
+func fieldPathElements(path string) []string {
+	return strings.Split(strings.TrimPrefix(path, "."), ".")
+}
+
+func passed() types.ProbeResult {
+	return types.ProbeResult{Status: types.ProbeStatusTrue}
+}
+
+func failed(msg string) types.ProbeResult {
+	return types.ProbeResult{Status: types.ProbeStatusFalse, Messages: []string{msg}}
+}
+
+func unknown(msg string) types.ProbeResult {
+	return types.ProbeResult{Status: types.ProbeStatusUnknown, Messages: []string{msg}}
+}
@@ -0,0 +1,128 @@
+package objectprobe_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"pkg.package-operator.run/boxcutter/machinery/types"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/objectprobe"
+)
+
+func newJob(name string, status map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "test-namespace"},
+	}}
+	if status != nil {
+		obj.Object["status"] = status
+	}
+	return obj
+}
+
+func TestFieldValueProbe(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		probe    objectprobe.FieldValueProbe
+		status   map[string]interface{}
+		expected types.ProbeStatus
+	}{
+		{
+			name:     "string field with the expected value",
+			probe:    objectprobe.FieldValueProbe{FieldPath: ".status.phase", Value: "Ready"},
+			status:   map[string]interface{}{"phase": "Ready"},
+			expected: types.ProbeStatusTrue,
+		},
+		{
+			name:     "string field with another value",
+			probe:    objectprobe.FieldValueProbe{FieldPath: ".status.phase", Value: "Ready"},
+			status:   map[string]interface{}{"phase": "Pending"},
+			expected: types.ProbeStatusFalse,
+		},
+		{
+			name:     "numeric field compared with its JSON representation",
+			probe:    objectprobe.FieldValueProbe{FieldPath: ".status.succeeded", Value: "1"},
+			status:   map[string]interface{}{"succeeded": int64(1)},
+			expected: types.ProbeStatusTrue,
+		},
+		{
+			name:     "missing field",
+			probe:    objectprobe.FieldValueProbe{FieldPath: ".status.phase", Value: "Ready"},
+			expected: types.ProbeStatusFalse,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := tc.probe.Probe(newJob("test", tc.status))
+			assert.Equal(t, tc.expected, result.Status, result.Messages)
+		})
+	}
+}
+
+func TestCELProbe(t *testing.T) {
+	probe, err := objectprobe.NewCELProbe("self.status.succeeded >= 1", "")
+	require.NoError(t, err)
+
+	result := probe.Probe(newJob("test", map[string]interface{}{"succeeded": int64(1)}))
+	assert.Equal(t, types.ProbeStatusTrue, result.Status)
+
+	result = probe.Probe(newJob("test", map[string]interface{}{"succeeded": int64(0)}))
+	assert.Equal(t, types.ProbeStatusFalse, result.Status)
+	assert.Equal(t, []string{"self.status.succeeded >= 1 is not true"}, result.Messages)
+
+	result = probe.Probe(newJob("test", nil))
+	assert.Equal(t, types.ProbeStatusFalse, result.Status, "fields that are not set yet fail the probe")
+
+	probe, err = objectprobe.NewCELProbe("has(self.status) && self.status.active == 0", "the job is still running")
+	require.NoError(t, err)
+	result = probe.Probe(newJob("test", map[string]interface{}{"active": int64(1)}))
+	assert.Equal(t, types.ProbeStatusFalse, result.Status)
+	assert.Equal(t, []string{"the job is still running"}, result.Messages)
+}
+
+func TestNewCELProbe_Invalid(t *testing.T) {
+	for name, rule := range map[string]string{
+		"syntax error":                     "self.status.succeeded >=",
+		"not a boolean":                    "'done'",
+		"unknown symbol":                   "other.status",
+		"estimated cost exceeds the limit": "self.status.conditions.all(a, self.status.conditions.all(b, a.type != b.type || a == b))",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := objectprobe.NewCELProbe(rule, "")
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestCELProbe_CostLimit(t *testing.T) {
+	_, err := objectprobe.NewCELProbe("self.status.conditions.exists(c, c.type == 'Complete' && c.status == 'True')", "")
+	require.NoError(t, err, "rules over lists of unknown size are within the estimated cost limit")
+
+	probe, err := objectprobe.NewCELProbe("self.status.message.contains('done')", "")
+	require.NoError(t, err)
+	result := probe.Probe(newJob("test", map[string]interface{}{"message": strings.Repeat("running ", 2_500_000)}))
+	assert.Equal(t, types.ProbeStatusFalse, result.Status)
+	require.Len(t, result.Messages, 1)
+	assert.Contains(t, result.Messages[0], "cost limit exceeded", "evaluations are aborted when they exceed the cost limit")
+}
+
+func TestNew_RequiresMatchingConfiguration(t *testing.T) {
+	_, err := objectprobe.New([]ocv1.ObjectProbe{{
+		Type:      ocv1.ObjectProbeTypeCEL,
+		Condition: &ocv1.ConditionProbe{Type: "Ready", Status: "True"},
+	}})
+	require.ErrorContains(t, err, "invalid probe 0")
+}
+
+func TestObjectProbes(t *testing.T) {
+	probe := &objectprobe.FieldValueProbe{FieldPath: ".status.phase", Value: "Done"}
+	migrate := newJob("migrate", map[string]interface{}{"phase": "Running"})
+	probes := objectprobe.ObjectProbes{objectprobe.KeyOf(migrate): probe}
+
+	assert.Equal(t, types.ProbeStatusTrue, probes.Probe(newJob("cleanup", nil)).Status, "objects without probes pass")
+	assert.Equal(t, types.ProbeStatusFalse, probes.Probe(migrate).Status)
+}
@@ -0,0 +1,248 @@
+package objectprobe
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"pkg.package-operator.run/boxcutter/machinery/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+)
+
+const (
+	// deploymentRevisionAnnotation is the annotation with which the Deployment controller
+	// numbers the rollouts of Deployments and their ReplicaSets.
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+	// defaultProgressDeadlineSeconds is the default of spec.progressDeadlineSeconds of Deployments.
+	defaultProgressDeadlineSeconds = 600
+)
+
+// DeploymentSoak probes that Deployments soaked for the soak period of a canary rollout,
+// without more container restarts than tolerated. While a Deployment soaks, it is applied as a
+// canary with CanaryDeployment, so that only one pod of its new ReplicaSet rolls out, and the
+// rest of its pods roll out once the canary soaked. The soak of a Deployment ends no earlier
+// than the soak period after notBefore.
+//
+// A DeploymentSoak records the soak failures and the time until the next soak ends, so that
+// the caller can block the revision or check the soak again.
+type DeploymentSoak struct {
+	ctx       context.Context
+	reader    client.Reader
+	config    ocv1.CanaryRollout
+	notBefore time.Time
+	now       time.Time
+
+	mu           sync.Mutex
+	failures     []string
+	requeueAfter time.Duration
+}
+
+// NewDeploymentSoak returns a DeploymentSoak of config, reading Deployments and their
+// ReplicaSets and pods with reader.
+func NewDeploymentSoak(ctx context.Context, reader client.Reader, config ocv1.CanaryRollout, notBefore, now time.Time) *DeploymentSoak {
+	return &DeploymentSoak{ctx: ctx, reader: reader, config: config, notBefore: notBefore, now: now}
+}
+
+func (s *DeploymentSoak) Probe(obj client.Object) types.ProbeResult {
+	deployment := &appsv1.Deployment{}
+	if err := toTyped(obj, deployment); err != nil {
+		return unknown(err.Error())
+	}
+	res := s.soak(deployment)
+	switch {
+	case res.err != nil:
+		return unknown(res.err.Error())
+	case res.failed:
+		s.mu.Lock()
+		s.failures = append(s.failures, fmt.Sprintf("Deployment %s/%s: %s", deployment.Namespace, deployment.Name, res.msg))
+		s.mu.Unlock()
+		return failed(res.msg)
+	case res.remaining > 0:
+		s.mu.Lock()
+		if s.requeueAfter == 0 || res.remaining < s.requeueAfter {
+			s.requeueAfter = res.remaining
+		}
+		s.mu.Unlock()
+		return failed(res.msg)
+	case res.msg != "":
+		return failed(res.msg)
+	}
+	return passed()
+}
+
+// Soaked reports whether the Deployment key, as last applied by owner, soaked, so that the rest
+// of its pods can roll out. Deployments that were not applied by owner yet did not soak.
+func (s *DeploymentSoak) Soaked(key client.ObjectKey, owner metav1.Object) (bool, error) {
+	deployment := &appsv1.Deployment{}
+	if err := s.reader.Get(s.ctx, key, deployment); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(deployment, owner) {
+		return false, nil
+	}
+	res := s.soak(deployment)
+	return res.err == nil && !res.failed && res.remaining <= 0 && res.msg == "", res.err
+}
+
+// Failures returns the Deployments that failed the soak.
+func (s *DeploymentSoak) Failures() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.failures
+}
+
+// RequeueAfter returns the time until the next soak ends, or zero if no Deployment is soaking.
+func (s *DeploymentSoak) RequeueAfter() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requeueAfter
+}
+
+// soakResult is the state of the soak of a Deployment. The soak passed when msg is empty.
+type soakResult struct {
+	msg       string
+	failed    bool
+	remaining time.Duration
+	err       error
+}
+
+func (s *DeploymentSoak) soak(deployment *appsv1.Deployment) soakResult {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return soakResult{msg: "the soak starts once the canary rolled out"}
+	}
+	rs, err := s.currentReplicaSet(deployment)
+	if err != nil {
+		return soakResult{err: fmt.Errorf("reading the ReplicaSet of the rollout: %w", err)}
+	}
+	if rs == nil {
+		return soakResult{msg: "the soak starts once the canary rolled out"}
+	}
+
+	restarts, err := s.containerRestarts(deployment, rs)
+	if err != nil {
+		return soakResult{err: fmt.Errorf("counting container restarts: %w", err)}
+	}
+	if restarts > s.config.MaxContainerRestarts {
+		return soakResult{
+			msg:    fmt.Sprintf("soak failed: containers restarted %d times, more than the %d tolerated restarts", restarts, s.config.MaxContainerRestarts),
+			failed: true,
+		}
+	}
+
+	// While the Deployment is a canary, its new pods only become available once they
+	// were ready for the soak period.
+	if rs.Status.AvailableReplicas < 1 && ptr.Deref(deployment.Spec.Replicas, 1) > 0 {
+		return soakResult{msg: "soaking until the canary is available"}
+	}
+	if remaining := s.notBefore.Add(time.Duration(s.config.SoakSeconds) * time.Second).Sub(s.now); remaining > 0 {
+		return soakResult{msg: fmt.Sprintf("soaking for another %s", remaining.Round(time.Second)), remaining: remaining}
+	}
+	return soakResult{}
+}
+
+// currentReplicaSet returns the ReplicaSet of the current rollout of deployment, or nil if the
+// Deployment controller did not create it yet.
+func (s *DeploymentSoak) currentReplicaSet(deployment *appsv1.Deployment) (*appsv1.ReplicaSet, error) {
+	revision, ok := deployment.Annotations[deploymentRevisionAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	replicaSets := &appsv1.ReplicaSetList{}
+	if err := s.reader.List(s.ctx, replicaSets, client.InNamespace(deployment.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	for i, rs := range replicaSets.Items {
+		if metav1.IsControlledBy(&rs, deployment) && rs.Annotations[deploymentRevisionAnnotation] == revision {
+			return &replicaSets.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// containerRestarts returns the number of container restarts of the pods of rs, the current
+// ReplicaSet of deployment. Containers that last terminated before notBefore restarted before
+// the soak, and are not counted.
+func (s *DeploymentSoak) containerRestarts(deployment *appsv1.Deployment, rs *appsv1.ReplicaSet) (int32, error) {
+	hash, ok := rs.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+	if !ok {
+		return 0, fmt.Errorf("ReplicaSet %s has no %s label", rs.Name, appsv1.DefaultDeploymentUniqueLabelKey)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return 0, err
+	}
+	pods := &corev1.PodList{}
+	if err := s.reader.List(s.ctx, pods, client.InNamespace(deployment.Namespace),
+		client.MatchingLabelsSelector{Selector: selector}, client.MatchingLabels{appsv1.DefaultDeploymentUniqueLabelKey: hash}); err != nil {
+		return 0, err
+	}
+	var restarts int32
+	for _, pod := range pods.Items {
+		for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+			for _, cs := range statuses {
+				if terminated := cs.LastTerminationState.Terminated; terminated != nil && terminated.FinishedAt.Time.Before(s.notBefore) {
+					continue
+				}
+				restarts += cs.RestartCount
+			}
+		}
+	}
+	return restarts, nil
+}
+
+// CanaryDeployment turns the Deployment obj into a canary for config: its rollout surges one
+// pod at a time and makes no pod unavailable, and new pods only become available once they
+// were ready for the soak period. The rollout thereby stops after the first new pod until
+// the Deployment is applied again without CanaryDeployment. Deployments with the Recreate
+// strategy keep it, so that all their pods soak.
+func CanaryDeployment(obj *unstructured.Unstructured, config ocv1.CanaryRollout) error {
+	strategyType, _, err := unstructured.NestedString(obj.Object, "spec", "strategy", "type")
+	if err != nil {
+		return err
+	}
+	if strategyType != string(appsv1.RecreateDeploymentStrategyType) {
+		if err := unstructured.SetNestedMap(obj.Object, map[string]any{
+			"type": string(appsv1.RollingUpdateDeploymentStrategyType),
+			"rollingUpdate": map[string]any{
+				"maxSurge":       int64(1),
+				"maxUnavailable": int64(0),
+			},
+		}, "spec", "strategy"); err != nil {
+			return err
+		}
+	}
+	// The Deployment makes no progress while the canary soaks, which must not exceed its
+	// progress deadline.
+	deadline, found, err := unstructured.NestedInt64(obj.Object, "spec", "progressDeadlineSeconds")
+	if err != nil {
+		return err
+	}
+	if !found {
+		deadline = defaultProgressDeadlineSeconds
+	}
+	if err := unstructured.SetNestedField(obj.Object, deadline+int64(config.SoakSeconds), "spec", "progressDeadlineSeconds"); err != nil {
+		return err
+	}
+	return unstructured.SetNestedField(obj.Object, int64(config.SoakSeconds), "spec", "minReadySeconds")
+}
+
+func toTyped(obj client.Object, into any) error {
+	content, err := toUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(content, into)
+}
@@ -0,0 +1,242 @@
+package objectprobe_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"pkg.package-operator.run/boxcutter/machinery/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/objectprobe"
+)
+
+func newDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-operator",
+			Namespace:   "test-namespace",
+			UID:         "test-operator-uid",
+			Generation:  2,
+			Annotations: map[string]string{"deployment.kubernetes.io/revision": "2"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test-operator"}},
+		},
+		Status: appsv1.DeploymentStatus{ObservedGeneration: 2},
+	}
+}
+
+func newReplicaSet(deployment *appsv1.Deployment, hash, revision string, available int32) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        deployment.Name + "-" + hash,
+			Namespace:   deployment.Namespace,
+			Labels:      map[string]string{"app": "test-operator", appsv1.DefaultDeploymentUniqueLabelKey: hash},
+			Annotations: map[string]string{"deployment.kubernetes.io/revision": revision},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       deployment.Name,
+				UID:        deployment.UID,
+				Controller: ptr.To(true),
+			}},
+		},
+		Status: appsv1.ReplicaSetStatus{AvailableReplicas: available},
+	}
+}
+
+func newPod(name, app, hash string, restarts int32, lastTerminated time.Time) *corev1.Pod {
+	status := corev1.ContainerStatus{Name: "manager", RestartCount: restarts}
+	if restarts > 0 {
+		status.LastTerminationState.Terminated = &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(lastTerminated)}
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-namespace",
+			Labels:    map[string]string{"app": app, appsv1.DefaultDeploymentUniqueLabelKey: hash},
+		},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{status}},
+	}
+}
+
+func TestDeploymentSoak(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	created := now.Add(-time.Hour)
+	config := ocv1.CanaryRollout{SoakSeconds: 300, MaxContainerRestarts: 1}
+	deployment := newDeployment()
+	current := newReplicaSet(deployment, "new", "2", 1)
+	previous := newReplicaSet(deployment, "old", "1", 2)
+
+	for _, tc := range []struct {
+		name         string
+		deployment   *appsv1.Deployment
+		notBefore    time.Time
+		objs         []client.Object
+		expected     types.ProbeStatus
+		failed       bool
+		requeueAfter time.Duration
+	}{
+		{
+			name:       "soak ended without restarts",
+			deployment: deployment,
+			notBefore:  created,
+			objs:       []client.Object{current, previous, newPod("test-operator-new-1", "test-operator", "new", 0, time.Time{})},
+			expected:   types.ProbeStatusTrue,
+		},
+		{
+			name:         "soaking since the creation of the revision",
+			deployment:   deployment,
+			notBefore:    now.Add(-2 * time.Minute),
+			objs:         []client.Object{current, newPod("test-operator-new-1", "test-operator", "new", 1, now.Add(-time.Minute))},
+			expected:     types.ProbeStatusFalse,
+			requeueAfter: 3 * time.Minute,
+		},
+		{
+			name:       "canary not available yet",
+			deployment: deployment,
+			notBefore:  created,
+			objs:       []client.Object{newReplicaSet(deployment, "new", "2", 0), previous},
+			expected:   types.ProbeStatusFalse,
+		},
+		{
+			name:       "more restarts than tolerated",
+			deployment: deployment,
+			notBefore:  created,
+			objs: []client.Object{
+				current,
+				newPod("test-operator-new-1", "test-operator", "new", 1, now.Add(-time.Minute)),
+				newPod("test-operator-new-2", "test-operator", "new", 1, now.Add(-time.Minute)),
+			},
+			expected: types.ProbeStatusFalse,
+			failed:   true,
+		},
+		{
+			name:       "restarts of pods of previous rollouts are ignored",
+			deployment: deployment,
+			notBefore:  created,
+			objs:       []client.Object{current, previous, newPod("test-operator-old-1", "test-operator", "old", 5, now.Add(-time.Minute))},
+			expected:   types.ProbeStatusTrue,
+		},
+		{
+			name:       "restarts of pods of other deployments are ignored",
+			deployment: deployment,
+			notBefore:  created,
+			objs:       []client.Object{current, newPod("other-1", "other", "new", 5, now.Add(-time.Minute))},
+			expected:   types.ProbeStatusTrue,
+		},
+		{
+			name:       "restarts before the creation of the revision are ignored",
+			deployment: deployment,
+			notBefore:  created,
+			objs:       []client.Object{current, newPod("test-operator-new-1", "test-operator", "new", 5, created.Add(-time.Minute))},
+			expected:   types.ProbeStatusTrue,
+		},
+		{
+			name: "rollout not observed yet",
+			deployment: func() *appsv1.Deployment {
+				d := newDeployment()
+				d.Generation = 3
+				return d
+			}(),
+			notBefore: created,
+			objs:      []client.Object{current},
+			expected:  types.ProbeStatusFalse,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := fake.NewClientBuilder().WithObjects(tc.objs...).Build()
+			soak := objectprobe.NewDeploymentSoak(context.Background(), reader, config, tc.notBefore, now)
+
+			result := soak.Probe(tc.deployment)
+			assert.Equal(t, tc.expected, result.Status, result.Messages)
+			assert.Equal(t, tc.failed, len(soak.Failures()) > 0, soak.Failures())
+			assert.Equal(t, tc.requeueAfter, soak.RequeueAfter())
+		})
+	}
+}
+
+func TestDeploymentSoak_Soaked(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	rev := &ocv1.ClusterExtensionRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-ext-2", UID: "test-ext-2-uid"}}
+	config := ocv1.CanaryRollout{SoakSeconds: 300}
+	newSoak := func(objs ...client.Object) *objectprobe.DeploymentSoak {
+		reader := fake.NewClientBuilder().WithObjects(objs...).Build()
+		return objectprobe.NewDeploymentSoak(context.Background(), reader, config, now.Add(-time.Hour), now)
+	}
+	key := client.ObjectKey{Namespace: "test-namespace", Name: "test-operator"}
+
+	soaked, err := newSoak().Soaked(key, rev)
+	require.NoError(t, err)
+	assert.False(t, soaked, "Deployments that don't exist yet did not soak")
+
+	deployment := newDeployment()
+	soaked, err = newSoak(deployment, newReplicaSet(deployment, "new", "2", 1)).Soaked(key, rev)
+	require.NoError(t, err)
+	assert.False(t, soaked, "Deployments applied by previous revisions did not soak")
+
+	deployment = newDeployment()
+	deployment.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: ocv1.GroupVersion.String(),
+		Kind:       "ClusterExtensionRevision",
+		Name:       rev.Name,
+		UID:        rev.UID,
+		Controller: ptr.To(true),
+	}}
+	soaked, err = newSoak(deployment, newReplicaSet(deployment, "new", "2", 0)).Soaked(key, rev)
+	require.NoError(t, err)
+	assert.False(t, soaked)
+
+	soaked, err = newSoak(deployment, newReplicaSet(deployment, "new", "2", 1)).Soaked(key, rev)
+	require.NoError(t, err)
+	assert.True(t, soaked)
+}
+
+func TestCanaryDeployment(t *testing.T) {
+	config := ocv1.CanaryRollout{SoakSeconds: 300}
+
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"spec": map[string]any{
+			"replicas": int64(3),
+			"strategy": map[string]any{"type": "RollingUpdate", "rollingUpdate": map[string]any{"maxSurge": "50%"}},
+		},
+	}}
+	require.NoError(t, objectprobe.CanaryDeployment(obj, config))
+	assert.Equal(t, map[string]any{
+		"replicas": int64(3),
+		"strategy": map[string]any{
+			"type":          "RollingUpdate",
+			"rollingUpdate": map[string]any{"maxSurge": int64(1), "maxUnavailable": int64(0)},
+		},
+		"minReadySeconds":         int64(300),
+		"progressDeadlineSeconds": int64(900),
+	}, obj.Object["spec"])
+
+	obj = &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"spec": map[string]any{
+			"strategy":                map[string]any{"type": "Recreate"},
+			"progressDeadlineSeconds": int64(60),
+		},
+	}}
+	require.NoError(t, objectprobe.CanaryDeployment(obj, config))
+	assert.Equal(t, map[string]any{
+		"strategy":                map[string]any{"type": "Recreate"},
+		"minReadySeconds":         int64(300),
+		"progressDeadlineSeconds": int64(360),
+	}, obj.Object["spec"], "Deployments with the Recreate strategy keep it")
+}
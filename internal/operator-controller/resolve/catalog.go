@@ -21,6 +21,7 @@ import (
 	"github.com/operator-framework/operator-controller/internal/operator-controller/bundleutil"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/catalogmetadata/compare"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/catalogmetadata/filter"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/clustercapabilities"
 	filterutil "github.com/operator-framework/operator-controller/internal/shared/util/filter"
 	slicesutil "github.com/operator-framework/operator-controller/internal/shared/util/slices"
 )
@@ -30,6 +31,9 @@ type ValidationFunc func(*declcfg.Bundle) error
 type CatalogResolver struct {
 	WalkCatalogsFunc func(context.Context, string, CatalogWalkFunc, ...client.ListOption) error
 	Validations      []ValidationFunc
+	// CapabilitiesProvider, when set, is used to exclude bundles whose declared
+	// requirements (e.g. minimum Kubernetes version) are not met by the cluster.
+	CapabilitiesProvider clustercapabilities.Provider
 }
 
 type foundBundle struct {
@@ -38,47 +42,71 @@ type foundBundle struct {
 	priority int32
 }
 
+type incompatibleBundle struct {
+	name    string
+	catalog string
+	reason  error
+}
+
 // Resolve returns a Bundle from a catalog that needs to get installed on the cluster.
 func (r *CatalogResolver) Resolve(ctx context.Context, ext *ocv1.ClusterExtension, installedBundle *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
+	resolvedBundle, resolvedBundleVersion, deprecation, _, err := r.ResolveWithCatalog(ctx, ext, installedBundle, "")
+	return resolvedBundle, resolvedBundleVersion, deprecation, err
+}
+
+// ResolveWithCatalog is like Resolve, but also returns the name of the
+// ClusterCatalog that provided the resolved bundle, the bundles excluded
+// because they are incompatible with the cluster, and the deprecated channels
+// the channel deprecation policy applies to. installedCatalog is the
+// ClusterCatalog that provided the installed bundle, if known, and is preferred
+// by the StickyCatalog tie-break policy.
+func (r *CatalogResolver) ResolveWithCatalog(ctx context.Context, ext *ocv1.ClusterExtension, installedBundle *ocv1.BundleMetadata, installedCatalog string) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, *CatalogResolution, error) {
 	l := log.FromContext(ctx)
 	packageName := ext.Spec.Source.Catalog.PackageName
 	versionRange := ext.Spec.Source.Catalog.Version
 	channels := ext.Spec.Source.Catalog.Channels
 
-	// unless overridden, default to selecting all bundles
-	var selector = labels.Everything()
-	var err error
-	if ext.Spec.Source.Catalog != nil {
-		selector, err = metav1.LabelSelectorAsSelector(ext.Spec.Source.Catalog.Selector)
-		if err != nil {
-			return nil, nil, nil, fmt.Errorf("desired catalog selector is invalid: %w", err)
-		}
-		// A nothing (empty) selector selects everything
-		if selector == labels.Nothing() {
-			selector = labels.Everything()
-		}
+	selector, err := catalogSelector(ext)
+	if err != nil {
+		return nil, nil, nil, nil, err
 	}
 
 	var versionRangeConstraints bsemver.Range
 	if versionRange != "" {
 		versionRangeConstraints, err = compare.NewVersionRange(versionRange)
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("desired version range %q is invalid: %w", versionRange, err)
+			return nil, nil, nil, nil, fmt.Errorf("desired version range %q is invalid: %w", versionRange, err)
+		}
+	}
+
+	// Bundles are not excluded while the capabilities of the cluster are unknown, for example
+	// until the OpenShift ClusterVersion reports its version, rather than failing resolution.
+	var caps *clustercapabilities.Capabilities
+	if r.CapabilitiesProvider != nil {
+		caps, err = r.CapabilitiesProvider.Get(ctx)
+		if err != nil {
+			l.Info("cluster capabilities are unknown, not excluding incompatible bundles", "error", err.Error())
+			caps = nil
 		}
 	}
 
 	type catStat struct {
-		CatalogName    string `json:"catalogName"`
-		PackageFound   bool   `json:"packageFound"`
-		TotalBundles   int    `json:"totalBundles"`
-		MatchedBundles int    `json:"matchedBundles"`
+		CatalogName         string `json:"catalogName"`
+		PackageFound        bool   `json:"packageFound"`
+		TotalBundles        int    `json:"totalBundles"`
+		MatchedBundles      int    `json:"matchedBundles"`
+		IncompatibleBundles int    `json:"incompatibleBundles,omitempty"`
 	}
 
 	var catStats []*catStat
 
 	var resolvedBundles []foundBundle
+	var incompatibleBundles []incompatibleBundle
 	var priorDeprecation *declcfg.Deprecation
 
+	// The package is collected from every catalog before resolving, so that the channel
+	// deprecations of all catalogs are known before the requested channels are migrated.
+	var packages []catalogPackage
 	listOptions := []client.ListOption{
 		client.MatchingLabelsSelector{Selector: selector},
 	}
@@ -86,7 +114,29 @@ func (r *CatalogResolver) Resolve(ctx context.Context, ext *ocv1.ClusterExtensio
 		if err != nil {
 			return fmt.Errorf("error getting package %q from catalog %q: %w", packageName, cat.Name, err)
 		}
+		packages = append(packages, catalogPackage{catalog: cat, fbc: packageFBC})
+		return nil
+	}, listOptions...); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("error walking catalogs: %w", err)
+	}
 
+	resolution := &CatalogResolution{}
+	policy := ChannelDeprecationPolicy(ext)
+	switch policy {
+	case ocv1.ChannelDeprecationPolicyMigrate, ocv1.ChannelDeprecationPolicyBlock:
+		resolution.DeprecatedChannels, err = deprecatedChannels(ctx, packageName, channels, packages)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		// With the Migrate policy, deprecated channels are replaced by their successors
+		// for resolution only; the spec is left untouched.
+		if policy == ocv1.ChannelDeprecationPolicyMigrate && len(resolution.DeprecatedChannels) > 0 {
+			channels = migrateChannels(channels, resolution.DeprecatedChannels)
+			l.Info("resolving from successors of deprecated channels", "channels", channels)
+		}
+	}
+
+	resolveFromCatalog := func(cat *ocv1.ClusterCatalog, packageFBC *declcfg.DeclarativeConfig) error {
 		cs := catStat{CatalogName: cat.Name}
 		catStats = append(catStats, &cs)
 
@@ -98,18 +148,30 @@ func (r *CatalogResolver) Resolve(ctx context.Context, ext *ocv1.ClusterExtensio
 		cs.TotalBundles = len(packageFBC.Bundles)
 
 		var predicates []filterutil.Predicate[declcfg.Bundle]
+		inRequestedChannels := filterutil.And[declcfg.Bundle]()
 		if len(channels) > 0 {
 			channelSet := sets.New(channels...)
 			filteredChannels := slices.DeleteFunc(packageFBC.Channels, func(c declcfg.Channel) bool {
 				return !channelSet.Has(c.Name)
 			})
-			predicates = append(predicates, filter.InAnyChannel(filteredChannels...))
+			inRequestedChannels = filter.InAnyChannel(filteredChannels...)
+			predicates = append(predicates, inRequestedChannels)
 		}
 
 		if versionRangeConstraints != nil {
 			predicates = append(predicates, filter.InSemverRange(versionRangeConstraints))
 		}
 
+		if versionPolicy := ext.Spec.Source.Catalog.VersionPolicy; versionPolicy != nil {
+			// Version policies that are relative to the newest available version only
+			// consider bundles in the requested channels.
+			versionPolicyPredicate, err := filter.VersionPolicy(*versionPolicy, installedBundle, filterutil.Filter(packageFBC.Bundles, inRequestedChannels))
+			if err != nil {
+				return fmt.Errorf("error applying version policy: %w", err)
+			}
+			predicates = append(predicates, versionPolicyPredicate)
+		}
+
 		if ext.Spec.Source.Catalog.UpgradeConstraintPolicy != ocv1.UpgradeConstraintPolicySelfCertified && installedBundle != nil {
 			successorPredicate, err := filter.SuccessorsOf(*installedBundle, packageFBC.Channels...)
 			if err != nil {
@@ -120,6 +182,37 @@ func (r *CatalogResolver) Resolve(ctx context.Context, ext *ocv1.ClusterExtensio
 
 		// Apply the predicates to get the candidate bundles
 		packageFBC.Bundles = filterutil.InPlace(packageFBC.Bundles, filterutil.And(predicates...))
+
+		// Excluded bundles, such as bundles that failed to roll out, are dropped. Rather than
+		// falling back to an older bundle when that excludes a candidate, the installed bundle
+		// is kept.
+		if excluded := ExcludedBundles(ctx); excluded.Len() > 0 {
+			candidates := len(packageFBC.Bundles)
+			packageFBC.Bundles = filterutil.InPlace(packageFBC.Bundles, func(b declcfg.Bundle) bool {
+				return !excluded.Has(b.Name)
+			})
+			if len(packageFBC.Bundles) < candidates && installedBundle != nil {
+				installedVersion, err := bundle.NewLegacyRegistryV1VersionRelease(installedBundle.Version)
+				if err != nil {
+					return fmt.Errorf("error parsing version %q of installed bundle %q: %w", installedBundle.Version, installedBundle.Name, err)
+				}
+				packageFBC.Bundles = filterutil.InPlace(packageFBC.Bundles, filter.NotOlderThan(*installedVersion))
+			}
+		}
+
+		// Drop candidates that cannot run on this cluster. This is done separately from
+		// the other predicates so that only otherwise-acceptable bundles are reported.
+		// The installed bundle is always kept, so that a cluster upgrade that it no longer
+		// supports doesn't fail the resolution of the ClusterExtension.
+		if caps != nil {
+			packageFBC.Bundles = filterutil.InPlace(packageFBC.Bundles, filterutil.Or(
+				isInstalledBundle(installedBundle),
+				filter.CompatibleWithCluster(caps, func(b declcfg.Bundle, err error) {
+					incompatibleBundles = append(incompatibleBundles, incompatibleBundle{name: b.Name, catalog: cat.GetName(), reason: err})
+					cs.IncompatibleBundles++
+				}),
+			))
+		}
 		cs.MatchedBundles = len(packageFBC.Bundles)
 		if len(packageFBC.Bundles) == 0 {
 			return nil
@@ -129,11 +222,18 @@ func (r *CatalogResolver) Resolve(ctx context.Context, ext *ocv1.ClusterExtensio
 		//   1. Want to sort deprecated bundles to the end of the list
 		//   2. Want to keep track of it so that we can return it if we end
 		//      up resolving a bundle from this package.
+		//
+		// With the Migrate policy and no requested channels, bundles that are only reachable
+		// through deprecated channels are also sorted after bundles in current channels.
 		byDeprecation := func(a, b declcfg.Bundle) int { return 0 }
+		byDeprecatedChannels := func(a, b declcfg.Bundle) int { return 0 }
 		var thisDeprecation *declcfg.Deprecation
 		if len(packageFBC.Deprecations) > 0 {
 			thisDeprecation = &packageFBC.Deprecations[0]
 			byDeprecation = compare.ByDeprecationFunc(*thisDeprecation)
+			if len(channels) == 0 && policy == ocv1.ChannelDeprecationPolicyMigrate {
+				byDeprecatedChannels = compare.ByDeprecatedChannelsFunc(*thisDeprecation, packageFBC.Channels)
+			}
 		}
 
 		// Sort the bundles by deprecation and then by version
@@ -141,6 +241,9 @@ func (r *CatalogResolver) Resolve(ctx context.Context, ext *ocv1.ClusterExtensio
 			if lessDep := byDeprecation(a, b); lessDep != 0 {
 				return lessDep
 			}
+			if lessDep := byDeprecatedChannels(a, b); lessDep != 0 {
+				return lessDep
+			}
 			return compare.ByVersionAndRelease(a, b)
 		})
 
@@ -163,8 +266,11 @@ func (r *CatalogResolver) Resolve(ctx context.Context, ext *ocv1.ClusterExtensio
 		resolvedBundles = append(resolvedBundles, foundBundle{&thisBundle, cat.GetName(), cat.Spec.Priority})
 		priorDeprecation = thisDeprecation
 		return nil
-	}, listOptions...); err != nil {
-		return nil, nil, nil, fmt.Errorf("error walking catalogs: %w", err)
+	}
+	for _, pkg := range packages {
+		if err := resolveFromCatalog(pkg.catalog, pkg.fbc); err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("error resolving package %q from catalog %q: %w", packageName, pkg.catalog.Name, err)
+		}
 	}
 
 	// Resolve for priority
@@ -178,21 +284,33 @@ func (r *CatalogResolver) Resolve(ctx context.Context, ext *ocv1.ClusterExtensio
 		}
 	}
 
+	// Break ties between catalogs with the same priority
+	if len(resolvedBundles) > 1 {
+		if installedBundle == nil {
+			installedCatalog = ""
+		}
+		resolvedBundles = breakTie(resolvedBundles, ext.Spec.Source.Catalog.TieBreakPolicy, installedCatalog)
+	}
+
+	resolution.IncompatibleBundles = incompatibleBundleSummaries(incompatibleBundles)
+
 	// Check for ambiguity
 	if len(resolvedBundles) != 1 {
 		l.Info("resolution failed", "stats", catStats)
-		return nil, nil, nil, resolutionError{
-			PackageName:     packageName,
-			Version:         versionRange,
-			Channels:        channels,
-			InstalledBundle: installedBundle,
-			ResolvedBundles: resolvedBundles,
+		return nil, nil, nil, resolution, resolutionError{
+			PackageName:         packageName,
+			Version:             versionRange,
+			Channels:            channels,
+			VersionPolicy:       ext.Spec.Source.Catalog.VersionPolicy,
+			InstalledBundle:     installedBundle,
+			ResolvedBundles:     resolvedBundles,
+			IncompatibleBundles: incompatibleBundles,
 		}
 	}
 	resolvedBundle := resolvedBundles[0].bundle
 	resolvedBundleVersion, err := bundleutil.GetVersionAndRelease(*resolvedBundle)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("error getting resolved bundle version for bundle %q: %w", resolvedBundle.Name, err)
+		return nil, nil, nil, nil, fmt.Errorf("error getting resolved bundle version for bundle %q: %w", resolvedBundle.Name, err)
 	}
 
 	// Run validations against the resolved bundle to ensure only valid resolved bundles are being returned
@@ -201,20 +319,77 @@ func (r *CatalogResolver) Resolve(ctx context.Context, ext *ocv1.ClusterExtensio
 	//                constrained in order to eliminate the invalid bundle from the resolution.
 	for _, validation := range r.Validations {
 		if err := validation(resolvedBundle); err != nil {
-			return nil, nil, nil, fmt.Errorf("validating bundle %q: %w", resolvedBundle.Name, err)
+			return nil, nil, nil, nil, fmt.Errorf("validating bundle %q: %w", resolvedBundle.Name, err)
 		}
 	}
 
+	if len(resolution.IncompatibleBundles) > 0 {
+		l.Info("excluded bundles that are incompatible with the cluster", "bundles", resolution.IncompatibleBundles)
+	}
 	l.V(4).Info("resolution succeeded", "stats", catStats)
-	return resolvedBundle, resolvedBundleVersion, priorDeprecation, nil
+	resolution.Catalog = resolvedBundles[0].catalog
+	return resolvedBundle, resolvedBundleVersion, priorDeprecation, resolution, nil
+}
+
+// isInstalledBundle returns a predicate that matches the installed bundle.
+func isInstalledBundle(installedBundle *ocv1.BundleMetadata) filterutil.Predicate[declcfg.Bundle] {
+	return func(b declcfg.Bundle) bool {
+		return installedBundle != nil && b.Name == installedBundle.Name
+	}
+}
+
+// catalogSelector returns the selector for the ClusterCatalogs that ext
+// resolves bundles from.
+func catalogSelector(ext *ocv1.ClusterExtension) (labels.Selector, error) {
+	// unless overridden, default to selecting all bundles
+	if ext.Spec.Source.Catalog == nil {
+		return labels.Everything(), nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(ext.Spec.Source.Catalog.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("desired catalog selector is invalid: %w", err)
+	}
+	// A nothing (empty) selector selects everything
+	if selector == labels.Nothing() {
+		return labels.Everything(), nil
+	}
+	return selector, nil
+}
+
+// breakTie selects a single bundle from bundles found in catalogs that share
+// the highest priority, according to policy. bundles must be sorted by
+// descending priority. If policy does not select a bundle, bundles is
+// returned unchanged.
+func breakTie(bundles []foundBundle, policy ocv1.TieBreakPolicy, installedCatalog string) []foundBundle {
+	tied := slices.DeleteFunc(slices.Clone(bundles), func(fb foundBundle) bool { return fb.priority != bundles[0].priority })
+	switch policy {
+	case ocv1.TieBreakPolicyStickyCatalog:
+		for _, fb := range tied {
+			if installedCatalog != "" && fb.catalog == installedCatalog {
+				return []foundBundle{fb}
+			}
+		}
+		fallthrough
+	case ocv1.TieBreakPolicyHighestVersion:
+		slices.SortFunc(tied, func(a, b foundBundle) int {
+			if c := compare.ByVersionAndRelease(*a.bundle, *b.bundle); c != 0 {
+				return c
+			}
+			return strings.Compare(a.catalog, b.catalog)
+		})
+		return tied[:1]
+	}
+	return bundles
 }
 
 type resolutionError struct {
-	PackageName     string
-	Version         string
-	Channels        []string
-	InstalledBundle *ocv1.BundleMetadata
-	ResolvedBundles []foundBundle
+	PackageName         string
+	Version             string
+	Channels            []string
+	VersionPolicy       *ocv1.VersionPolicy
+	InstalledBundle     *ocv1.BundleMetadata
+	ResolvedBundles     []foundBundle
+	IncompatibleBundles []incompatibleBundle
 }
 
 func (rei resolutionError) Error() string {
@@ -233,6 +408,14 @@ func (rei resolutionError) Error() string {
 		sb.WriteString(fmt.Sprintf("matching version %q ", rei.Version))
 	}
 
+	if rei.VersionPolicy != nil {
+		if rei.VersionPolicy.Type == ocv1.VersionPolicyTypeLagBy {
+			sb.WriteString(fmt.Sprintf("matching version policy %q %d ", rei.VersionPolicy.Type, rei.VersionPolicy.LagBy))
+		} else {
+			sb.WriteString(fmt.Sprintf("matching version policy %q ", rei.VersionPolicy.Type))
+		}
+	}
+
 	if len(rei.Channels) > 0 {
 		sb.WriteString(fmt.Sprintf("in channels %v ", rei.Channels))
 	}
@@ -246,7 +429,36 @@ func (rei resolutionError) Error() string {
 		sb.WriteString(fmt.Sprintf("in multiple catalogs with the same priority %v ", matchedCatalogs))
 	}
 
-	return strings.TrimSpace(sb.String())
+	msg := strings.TrimSpace(sb.String())
+	if len(rei.IncompatibleBundles) > 0 {
+		msg = fmt.Sprintf("%s; excluded bundles incompatible with this cluster: %s", msg, strings.Join(incompatibleBundleSummaries(rei.IncompatibleBundles), "; "))
+	}
+	return msg
+}
+
+// maxIncompatibleBundleSummaries limits how many excluded bundles are described,
+// keeping resolution errors readable when a catalog has many incompatible bundles.
+const maxIncompatibleBundleSummaries = 3
+
+func incompatibleBundleSummaries(bundles []incompatibleBundle) []string {
+	bundles = slices.Clone(bundles)
+	// sort for consistent error message
+	slices.SortFunc(bundles, func(a, b incompatibleBundle) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.catalog, b.catalog)
+	})
+	summaries := make([]string, 0, min(len(bundles), maxIncompatibleBundleSummaries)+1)
+	for i, b := range bundles {
+		if i == maxIncompatibleBundleSummaries {
+			summaries = append(summaries, fmt.Sprintf("and %d more", len(bundles)-i))
+			break
+		}
+		reason := strings.ReplaceAll(b.reason.Error(), "\n", ", ")
+		summaries = append(summaries, fmt.Sprintf("bundle %q in catalog %q %s", b.name, b.catalog, reason))
+	}
+	return summaries
 }
 
 func isDeprecated(bundle declcfg.Bundle, deprecation *declcfg.Deprecation) bool {
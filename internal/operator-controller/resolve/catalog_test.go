@@ -15,11 +15,13 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/operator-framework/operator-registry/alpha/declcfg"
 	"github.com/operator-framework/operator-registry/alpha/property"
 
 	ocv1 "github.com/operator-framework/operator-controller/api/v1"
 	"github.com/operator-framework/operator-controller/internal/operator-controller/bundle"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/clustercapabilities"
 )
 
 func TestInvalidClusterExtensionVersionRange(t *testing.T) {
@@ -120,6 +122,108 @@ func TestValidationFailed(t *testing.T) {
 	require.Error(t, err)
 }
 
+func withMinKubeVersion(b declcfg.Bundle, minKubeVersion string) declcfg.Bundle {
+	b.Properties = append(b.Properties, property.MustBuildCSVMetadata(v1alpha1.ClusterServiceVersion{
+		Spec: v1alpha1.ClusterServiceVersionSpec{MinKubeVersion: minKubeVersion},
+	}))
+	return b
+}
+
+func staticCapabilities(kubeVersion string) clustercapabilities.Provider {
+	return clustercapabilities.ProviderFunc(func(context.Context) (*clustercapabilities.Capabilities, error) {
+		return &clustercapabilities.Capabilities{KubernetesVersion: bsemver.MustParse(kubeVersion)}, nil
+	})
+}
+
+func TestIncompatibleBundlesExcluded(t *testing.T) {
+	pkgName := randPkg()
+	w := staticCatalogWalker{
+		"a": func() (*declcfg.DeclarativeConfig, *ocv1.ClusterCatalogSpec, error) {
+			fbc := genPackage(pkgName)
+			fbc.Bundles[5] = withMinKubeVersion(fbc.Bundles[5], "1.31.0")
+			return fbc, nil, nil
+		},
+	}
+	r := CatalogResolver{
+		WalkCatalogsFunc:     w.WalkCatalogs,
+		CapabilitiesProvider: staticCapabilities("1.30.2"),
+	}
+	ce := buildFooClusterExtension(pkgName, []string{}, "", ocv1.UpgradeConstraintPolicyCatalogProvided)
+	gotBundle, gotVersion, _, gotResolution, err := r.ResolveWithCatalog(context.Background(), ce, nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, genBundle(pkgName, "2.0.0"), *gotBundle)
+	assert.Equal(t, bundle.VersionRelease{Version: bsemver.MustParse("2.0.0")}, *gotVersion)
+	assert.Equal(t, &CatalogResolution{
+		Catalog:             "a",
+		IncompatibleBundles: []string{fmt.Sprintf(`bundle %q in catalog "a" requires Kubernetes version >= 1.31.0, cluster runs 1.30.2`, bundleName(pkgName, "3.0.0"))},
+	}, gotResolution)
+}
+
+func TestAllBundlesIncompatible(t *testing.T) {
+	pkgName := randPkg()
+	w := staticCatalogWalker{
+		"a": func() (*declcfg.DeclarativeConfig, *ocv1.ClusterCatalogSpec, error) {
+			fbc := genPackage(pkgName)
+			fbc.Bundles[5] = withMinKubeVersion(fbc.Bundles[5], "1.31.0")
+			return fbc, nil, nil
+		},
+	}
+	r := CatalogResolver{
+		WalkCatalogsFunc:     w.WalkCatalogs,
+		CapabilitiesProvider: staticCapabilities("1.30.2"),
+	}
+	ce := buildFooClusterExtension(pkgName, []string{}, "3.0.0", ocv1.UpgradeConstraintPolicyCatalogProvided)
+	_, _, _, gotResolution, err := r.ResolveWithCatalog(context.Background(), ce, nil, "")
+	assert.EqualError(t, err, fmt.Sprintf(`no bundles found for package %q matching version "3.0.0"; excluded bundles incompatible with this cluster: bundle %q in catalog "a" requires Kubernetes version >= 1.31.0, cluster runs 1.30.2`, pkgName, bundleName(pkgName, "3.0.0")))
+	require.NotNil(t, gotResolution)
+	assert.Empty(t, gotResolution.Catalog)
+	assert.Equal(t, []string{fmt.Sprintf(`bundle %q in catalog "a" requires Kubernetes version >= 1.31.0, cluster runs 1.30.2`, bundleName(pkgName, "3.0.0"))}, gotResolution.IncompatibleBundles)
+}
+
+func TestErrorGettingCapabilities(t *testing.T) {
+	pkgName := randPkg()
+	w := staticCatalogWalker{
+		"a": func() (*declcfg.DeclarativeConfig, *ocv1.ClusterCatalogSpec, error) {
+			return genPackage(pkgName), nil, nil
+		},
+	}
+	r := CatalogResolver{
+		WalkCatalogsFunc: w.WalkCatalogs,
+		CapabilitiesProvider: clustercapabilities.ProviderFunc(func(context.Context) (*clustercapabilities.Capabilities, error) {
+			return nil, errors.New("discovery failed")
+		}),
+	}
+	ce := buildFooClusterExtension(pkgName, []string{}, "", ocv1.UpgradeConstraintPolicyCatalogProvided)
+	gotBundle, _, _, err := r.Resolve(context.Background(), ce, nil)
+	require.NoError(t, err)
+	assert.Equal(t, genBundle(pkgName, "3.0.0"), *gotBundle)
+}
+
+func TestInstalledBundleIncompatible(t *testing.T) {
+	pkgName := randPkg()
+	w := staticCatalogWalker{
+		"a": func() (*declcfg.DeclarativeConfig, *ocv1.ClusterCatalogSpec, error) {
+			fbc := genPackage(pkgName)
+			for i := range fbc.Bundles {
+				fbc.Bundles[i] = withMinKubeVersion(fbc.Bundles[i], "1.31.0")
+			}
+			return fbc, nil, nil
+		},
+	}
+	r := CatalogResolver{
+		WalkCatalogsFunc:     w.WalkCatalogs,
+		CapabilitiesProvider: staticCapabilities("1.30.2"),
+	}
+	ce := buildFooClusterExtension(pkgName, []string{}, "", ocv1.UpgradeConstraintPolicyCatalogProvided)
+	installedBundle := &ocv1.BundleMetadata{
+		Name:    bundleName(pkgName, "2.0.0"),
+		Version: "2.0.0",
+	}
+	gotBundle, _, _, err := r.Resolve(context.Background(), ce, installedBundle)
+	require.NoError(t, err)
+	assert.Equal(t, bundleName(pkgName, "2.0.0"), gotBundle.Name)
+}
+
 func TestVersionDoesNotExist(t *testing.T) {
 	pkgName := randPkg()
 	w := staticCatalogWalker{
@@ -423,6 +527,99 @@ func TestPackageVariationsBetweenCatalogs(t *testing.T) {
 	})
 }
 
+func TestTieBreakPolicy(t *testing.T) {
+	pkgName := randPkg()
+	genFBC := func(versions ...string) *declcfg.DeclarativeConfig {
+		fbc := &declcfg.DeclarativeConfig{Packages: []declcfg.Package{{Name: pkgName}}}
+		for _, v := range versions {
+			fbc.Bundles = append(fbc.Bundles, genBundle(pkgName, v))
+		}
+		return fbc
+	}
+	w := staticCatalogWalker{
+		"upstream": func() (*declcfg.DeclarativeConfig, *ocv1.ClusterCatalogSpec, error) {
+			return genFBC("1.0.0", "1.0.1"), nil, nil
+		},
+		"mirror": func() (*declcfg.DeclarativeConfig, *ocv1.ClusterCatalogSpec, error) {
+			return genFBC("1.0.0", "1.0.1"), nil, nil
+		},
+		"newer": func() (*declcfg.DeclarativeConfig, *ocv1.ClusterCatalogSpec, error) {
+			return genFBC("1.0.2"), nil, nil
+		},
+		"fallback": func() (*declcfg.DeclarativeConfig, *ocv1.ClusterCatalogSpec, error) {
+			return genFBC("2.0.0"), &ocv1.ClusterCatalogSpec{Priority: -1}, nil
+		},
+	}
+	r := CatalogResolver{WalkCatalogsFunc: w.WalkCatalogs}
+	installed := &ocv1.BundleMetadata{Name: bundleName(pkgName, "1.0.0"), Version: "1.0.0"}
+
+	for _, tc := range []struct {
+		name             string
+		policy           ocv1.TieBreakPolicy
+		version          string
+		installedCatalog string
+		expectedBundle   string
+		expectedCatalog  string
+		expectedError    string
+	}{
+		{
+			name:          "fail by default",
+			expectedError: "in multiple catalogs with the same priority",
+		},
+		{
+			name:          "fail",
+			policy:        ocv1.TieBreakPolicyFail,
+			expectedError: "in multiple catalogs with the same priority",
+		},
+		{
+			name:            "highest version wins",
+			policy:          ocv1.TieBreakPolicyHighestVersion,
+			expectedBundle:  bundleName(pkgName, "1.0.2"),
+			expectedCatalog: "newer",
+		},
+		{
+			name:            "equal versions are broken by catalog name",
+			policy:          ocv1.TieBreakPolicyHighestVersion,
+			version:         "<=1.0.1",
+			expectedBundle:  bundleName(pkgName, "1.0.1"),
+			expectedCatalog: "mirror",
+		},
+		{
+			name:             "sticky catalog prefers the installed catalog",
+			policy:           ocv1.TieBreakPolicyStickyCatalog,
+			installedCatalog: "upstream",
+			expectedBundle:   bundleName(pkgName, "1.0.1"),
+			expectedCatalog:  "upstream",
+		},
+		{
+			name:            "sticky catalog falls back to the highest version",
+			policy:          ocv1.TieBreakPolicyStickyCatalog,
+			expectedBundle:  bundleName(pkgName, "1.0.2"),
+			expectedCatalog: "newer",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ce := buildFooClusterExtension(pkgName, nil, tc.version, ocv1.UpgradeConstraintPolicySelfCertified)
+			ce.Spec.Source.Catalog.TieBreakPolicy = tc.policy
+			var installedBundle *ocv1.BundleMetadata
+			if tc.installedCatalog != "" {
+				installedBundle = installed
+				ce.Spec.Source.Catalog.Version = "<2.0.0"
+				// The status lags behind the installed revision, and is not used to break ties.
+				ce.Status.Install = &ocv1.ClusterExtensionInstallStatus{Bundle: *installed, Catalog: "mirror"}
+			}
+			gotBundle, _, _, gotResolution, err := r.ResolveWithCatalog(context.Background(), ce, installedBundle, tc.installedCatalog)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedBundle, gotBundle.Name)
+			assert.Equal(t, tc.expectedCatalog, gotResolution.Catalog)
+		})
+	}
+}
+
 func TestUpgradeFoundLegacy(t *testing.T) {
 	pkgName := randPkg()
 	w := staticCatalogWalker{
@@ -526,6 +723,62 @@ func TestDowngradeNotFound(t *testing.T) {
 	assert.EqualError(t, err, fmt.Sprintf(`error upgrading from currently installed version "1.0.2": no bundles found for package %q matching version ">0.1.0 <1.0.0"`, pkgName))
 }
 
+func TestVersionPolicyPatchOnly(t *testing.T) {
+	pkgName := randPkg()
+	w := staticCatalogWalker{
+		"a": func() (*declcfg.DeclarativeConfig, *ocv1.ClusterCatalogSpec, error) {
+			return genPackage(pkgName), nil, nil
+		},
+	}
+	r := CatalogResolver{WalkCatalogsFunc: w.WalkCatalogs}
+	ce := buildFooClusterExtension(pkgName, []string{"alpha"}, "", ocv1.UpgradeConstraintPolicyCatalogProvided)
+	ce.Spec.Source.Catalog.VersionPolicy = &ocv1.VersionPolicy{Type: ocv1.VersionPolicyTypePatchOnly}
+	installedBundle := &ocv1.BundleMetadata{
+		Name:    bundleName(pkgName, "1.0.0"),
+		Version: "1.0.0",
+	}
+	// 1.0.0 upgrades to 2.0.0 with its legacy upgrade edges, but PatchOnly keeps it on 1.0.z.
+	gotBundle, gotVersion, _, err := r.Resolve(context.Background(), ce, installedBundle)
+	require.NoError(t, err)
+	assert.Equal(t, genBundle(pkgName, "1.0.2"), *gotBundle)
+	assert.Equal(t, bundle.VersionRelease{Version: bsemver.MustParse("1.0.2")}, *gotVersion)
+}
+
+func TestVersionPolicyLagBy(t *testing.T) {
+	pkgName := randPkg()
+	w := staticCatalogWalker{
+		"a": func() (*declcfg.DeclarativeConfig, *ocv1.ClusterCatalogSpec, error) {
+			return genPackage(pkgName), nil, nil
+		},
+	}
+	r := CatalogResolver{WalkCatalogsFunc: w.WalkCatalogs}
+
+	ce := buildFooClusterExtension(pkgName, []string{}, "", ocv1.UpgradeConstraintPolicyCatalogProvided)
+	ce.Spec.Source.Catalog.VersionPolicy = &ocv1.VersionPolicy{Type: ocv1.VersionPolicyTypeLagBy, LagBy: 1}
+	gotBundle, _, _, err := r.Resolve(context.Background(), ce, nil)
+	require.NoError(t, err)
+	assert.Equal(t, genBundle(pkgName, "2.0.0"), *gotBundle)
+
+	// Only the requested channels determine the newest minor version.
+	ce = buildFooClusterExtension(pkgName, []string{"alpha"}, "", ocv1.UpgradeConstraintPolicyCatalogProvided)
+	ce.Spec.Source.Catalog.VersionPolicy = &ocv1.VersionPolicy{Type: ocv1.VersionPolicyTypeLagBy, LagBy: 1}
+	gotBundle, _, _, err = r.Resolve(context.Background(), ce, nil)
+	require.NoError(t, err)
+	assert.Equal(t, genBundle(pkgName, "1.0.2"), *gotBundle)
+
+	// Lagging by more minor versions than available falls back to the oldest minor version.
+	ce.Spec.Source.Catalog.VersionPolicy.LagBy = 3
+	gotBundle, _, _, err = r.Resolve(context.Background(), ce, nil)
+	require.NoError(t, err)
+	assert.Equal(t, genBundle(pkgName, "0.1.0"), *gotBundle)
+
+	// The installed bundle stays installable, even when it is newer than allowed.
+	installedBundle := &ocv1.BundleMetadata{Name: bundleName(pkgName, "2.0.0"), Version: "2.0.0"}
+	gotBundle, _, _, err = r.Resolve(context.Background(), ce, installedBundle)
+	require.NoError(t, err)
+	assert.Equal(t, genBundle(pkgName, "2.0.0"), *gotBundle)
+}
+
 func TestCatalogWalker(t *testing.T) {
 	t.Run("error listing catalogs", func(t *testing.T) {
 		w := CatalogWalker(
@@ -858,6 +1111,44 @@ func TestUnequalPriority(t *testing.T) {
 	require.Equal(t, bundle.VersionRelease{Version: bsemver.MustParse("1.0.0")}, *gotVersion)
 }
 
+func TestExcludedBundles(t *testing.T) {
+	pkgName := randPkg()
+	w := staticCatalogWalker{
+		"a": func() (*declcfg.DeclarativeConfig, *ocv1.ClusterCatalogSpec, error) {
+			return genPackage(pkgName), &ocv1.ClusterCatalogSpec{}, nil
+		},
+	}
+	r := CatalogResolver{WalkCatalogsFunc: w.WalkCatalogs}
+	ctx := WithExcludedBundles(context.Background(), bundleName(pkgName, "2.0.0"))
+
+	t.Run("keeps the installed bundle instead of an excluded successor", func(t *testing.T) {
+		ce := buildFooClusterExtension(pkgName, []string{"alpha"}, "", ocv1.UpgradeConstraintPolicyCatalogProvided)
+		installed := &ocv1.BundleMetadata{Name: bundleName(pkgName, "1.0.2"), Version: "1.0.2"}
+		gotBundle, _, _, err := r.Resolve(ctx, ce, installed)
+		require.NoError(t, err)
+		assert.Equal(t, bundleName(pkgName, "1.0.2"), gotBundle.Name)
+	})
+
+	t.Run("resolves newer bundles than the excluded ones", func(t *testing.T) {
+		ce := buildFooClusterExtension(pkgName, nil, "", ocv1.UpgradeConstraintPolicySelfCertified)
+		installed := &ocv1.BundleMetadata{Name: bundleName(pkgName, "1.0.2"), Version: "1.0.2"}
+		gotBundle, _, _, err := r.Resolve(ctx, ce, installed)
+		require.NoError(t, err)
+		assert.Equal(t, bundleName(pkgName, "3.0.0"), gotBundle.Name)
+	})
+
+	t.Run("does not fall back to bundles older than the installed bundle", func(t *testing.T) {
+		ce := buildFooClusterExtension(pkgName, []string{"alpha"}, "", ocv1.UpgradeConstraintPolicySelfCertified)
+		installed := &ocv1.BundleMetadata{Name: bundleName(pkgName, "1.0.3"), Version: "1.0.3"}
+		gotBundle, _, _, err := r.Resolve(context.Background(), ce, installed)
+		require.NoError(t, err)
+		assert.Equal(t, bundleName(pkgName, "2.0.0"), gotBundle.Name)
+
+		_, _, _, err = r.Resolve(ctx, ce, installed)
+		require.ErrorContains(t, err, "no bundles found")
+	})
+}
+
 func TestMultiplePriority(t *testing.T) {
 	pkgName := randPkg()
 	w := staticCatalogWalker{
@@ -0,0 +1,182 @@
+package resolve
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
+)
+
+// PropertyChannelSuccessor is the channel property a package author uses to
+// name the channel that replaces a deprecated channel, for example:
+//
+//	{"type": "olm.channel.successor", "value": {"channel": "stable-v2"}}
+const PropertyChannelSuccessor = "olm.channel.successor"
+
+type channelSuccessor struct {
+	Channel string `json:"channel"`
+}
+
+// DeprecatedChannel is a requested channel that the package author has
+// deprecated in favor of a successor channel.
+type DeprecatedChannel struct {
+	// Name is the requested channel.
+	Name string
+	// Successor is the channel that replaces Name. When the package author has
+	// chained several deprecations, Successor is the last channel in the chain.
+	Successor string
+}
+
+// ChannelDeprecationPolicy returns the channel deprecation policy of ext. It is Ignore when the
+// policy is omitted, or when the ChannelDeprecationPolicy feature-gate is disabled.
+func ChannelDeprecationPolicy(ext *ocv1.ClusterExtension) ocv1.ChannelDeprecationPolicy {
+	if !features.OperatorControllerFeatureGate.Enabled(features.ChannelDeprecationPolicy) ||
+		ext.Spec.Source.Catalog == nil || ext.Spec.Source.Catalog.ChannelDeprecationPolicy == "" {
+		return ocv1.ChannelDeprecationPolicyIgnore
+	}
+	return ext.Spec.Source.Catalog.ChannelDeprecationPolicy
+}
+
+// catalogPackage is the content of a package in a ClusterCatalog.
+type catalogPackage struct {
+	catalog *ocv1.ClusterCatalog
+	fbc     *declcfg.DeclarativeConfig
+}
+
+// deprecatedChannels returns the requested channels that are deprecated and
+// name a successor channel, sorted by name. When several catalogs provide the
+// package, the first catalog that deprecates a channel determines its
+// successor.
+func deprecatedChannels(ctx context.Context, packageName string, requested []string, packages []catalogPackage) ([]DeprecatedChannel, error) {
+	if len(requested) == 0 {
+		return nil, nil
+	}
+	found := map[string]DeprecatedChannel{}
+	for _, pkg := range packages {
+		if isFBCEmpty(pkg.fbc) {
+			continue
+		}
+		successors, invalid := channelSuccessors(pkg.fbc)
+		for _, name := range requested {
+			if _, ok := found[name]; ok {
+				continue
+			}
+			successor, ok, err := latestSuccessor(name, successors, invalid)
+			if err != nil {
+				return nil, fmt.Errorf("error reading channel deprecations for package %q from catalog %q: %w", packageName, pkg.catalog.Name, err)
+			}
+			if !ok {
+				continue
+			}
+			log.FromContext(ctx).V(1).Info("requested channel is deprecated", "channel", name, "successor", successor, "catalog", pkg.catalog.Name)
+			found[name] = DeprecatedChannel{Name: name, Successor: successor}
+		}
+	}
+
+	deprecated := make([]DeprecatedChannel, 0, len(found))
+	for _, dc := range found {
+		deprecated = append(deprecated, dc)
+	}
+	slices.SortFunc(deprecated, func(a, b DeprecatedChannel) int { return strings.Compare(a.Name, b.Name) })
+	return deprecated, nil
+}
+
+// migrateChannels replaces each deprecated channel in channels with its
+// successor, preserving order and dropping duplicates.
+func migrateChannels(channels []string, deprecated []DeprecatedChannel) []string {
+	successors := make(map[string]string, len(deprecated))
+	for _, dc := range deprecated {
+		successors[dc.Name] = dc.Successor
+	}
+	migrated := make([]string, 0, len(channels))
+	for _, ch := range channels {
+		if successor, ok := successors[ch]; ok {
+			ch = successor
+		}
+		if !slices.Contains(migrated, ch) {
+			migrated = append(migrated, ch)
+		}
+	}
+	return migrated
+}
+
+// channelSuccessors maps each deprecated channel of the package that names an
+// existing successor channel to that successor. Deprecated channels whose
+// successor property is invalid are returned with the reason instead, so that
+// they only fail the resolution of ClusterExtensions that request them.
+func channelSuccessors(packageFBC *declcfg.DeclarativeConfig) (map[string]string, map[string]error) {
+	deprecatedChannels := sets.New[string]()
+	for _, deprecation := range packageFBC.Deprecations {
+		for _, entry := range deprecation.Entries {
+			if entry.Reference.Schema == declcfg.SchemaChannel {
+				deprecatedChannels.Insert(entry.Reference.Name)
+			}
+		}
+	}
+	existingChannels := sets.New[string]()
+	for _, ch := range packageFBC.Channels {
+		existingChannels.Insert(ch.Name)
+	}
+
+	successors := map[string]string{}
+	invalid := map[string]error{}
+	for _, ch := range packageFBC.Channels {
+		if !deprecatedChannels.Has(ch.Name) {
+			continue
+		}
+		for _, p := range ch.Properties {
+			if p.Type != PropertyChannelSuccessor {
+				continue
+			}
+			var s channelSuccessor
+			if err := json.Unmarshal(p.Value, &s); err != nil {
+				invalid[ch.Name] = fmt.Errorf("invalid %q property on channel %q: %w", PropertyChannelSuccessor, ch.Name, err)
+				continue
+			}
+			if s.Channel == "" || s.Channel == ch.Name || !existingChannels.Has(s.Channel) {
+				invalid[ch.Name] = fmt.Errorf("invalid %q property on channel %q: successor channel %q does not exist", PropertyChannelSuccessor, ch.Name, s.Channel)
+				continue
+			}
+			successors[ch.Name] = s.Channel
+		}
+	}
+	return successors, invalid
+}
+
+// latestSuccessor follows the chain of successors starting at channel and
+// returns the last channel in it. It returns false if channel has no successor
+// or the chain is cyclic, and an error if a channel in the chain names an
+// invalid successor.
+func latestSuccessor(channel string, successors map[string]string, invalid map[string]error) (string, bool, error) {
+	if err, ok := invalid[channel]; ok {
+		return "", false, err
+	}
+	visited := sets.New(channel)
+	current, ok := successors[channel]
+	if !ok {
+		return "", false, nil
+	}
+	for {
+		if visited.Has(current) {
+			return "", false, nil
+		}
+		if err, ok := invalid[current]; ok {
+			return "", false, err
+		}
+		visited.Insert(current)
+		next, ok := successors[current]
+		if !ok {
+			return current, true, nil
+		}
+		current = next
+	}
+}
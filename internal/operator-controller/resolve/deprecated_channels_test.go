@@ -0,0 +1,209 @@
+package resolve
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/features"
+)
+
+func enableChannelDeprecationPolicy(t *testing.T) {
+	require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=true", features.ChannelDeprecationPolicy)))
+	t.Cleanup(func() {
+		require.NoError(t, features.OperatorControllerFeatureGate.Set(fmt.Sprintf("%s=false", features.ChannelDeprecationPolicy)))
+	})
+}
+
+// withDeprecatedChannel deprecates channel in fbc and, if successor is not
+// empty, names successor as its replacement.
+func withDeprecatedChannel(fbc *declcfg.DeclarativeConfig, channel, successor string) *declcfg.DeclarativeConfig {
+	fbc.Deprecations[0].Entries = append(fbc.Deprecations[0].Entries, declcfg.DeprecationEntry{
+		Reference: declcfg.PackageScopedReference{Schema: declcfg.SchemaChannel, Name: channel},
+		Message:   fmt.Sprintf("channel %s is deprecated", channel),
+	})
+	if successor == "" {
+		return fbc
+	}
+	for i := range fbc.Channels {
+		if fbc.Channels[i].Name == channel {
+			fbc.Channels[i].Properties = append(fbc.Channels[i].Properties, property.Property{
+				Type:  PropertyChannelSuccessor,
+				Value: json.RawMessage(fmt.Sprintf(`{"channel": %q}`, successor)),
+			})
+		}
+	}
+	return fbc
+}
+
+func TestPreferNonDeprecatedChannels(t *testing.T) {
+	pkgName := randPkg()
+	w := staticCatalogWalker{
+		"a": func() (*declcfg.DeclarativeConfig, *ocv1.ClusterCatalogSpec, error) {
+			return withDeprecatedChannel(genPackage(pkgName), "gamma", ""), nil, nil
+		},
+	}
+	r := CatalogResolver{WalkCatalogsFunc: w.WalkCatalogs}
+
+	// Standard resolution doesn't consider channel deprecations.
+	ce := buildFooClusterExtension(pkgName, nil, "", ocv1.UpgradeConstraintPolicyCatalogProvided)
+	ce.Spec.Source.Catalog.ChannelDeprecationPolicy = ocv1.ChannelDeprecationPolicyMigrate
+	gotBundle, _, _, err := r.Resolve(context.Background(), ce, nil)
+	require.NoError(t, err)
+	assert.Equal(t, genBundle(pkgName, "3.0.0"), *gotBundle)
+
+	enableChannelDeprecationPolicy(t)
+	ce.Spec.Source.Catalog.ChannelDeprecationPolicy = ocv1.ChannelDeprecationPolicyIgnore
+	gotBundle, _, _, err = r.Resolve(context.Background(), ce, nil)
+	require.NoError(t, err)
+	assert.Equal(t, genBundle(pkgName, "3.0.0"), *gotBundle)
+
+	ce.Spec.Source.Catalog.ChannelDeprecationPolicy = ocv1.ChannelDeprecationPolicyMigrate
+	gotBundle, _, _, err = r.Resolve(context.Background(), ce, nil)
+	require.NoError(t, err)
+	assert.Equal(t, genBundle(pkgName, "2.0.0"), *gotBundle)
+
+	// Explicitly requesting the deprecated channel still resolves from it.
+	ce = buildFooClusterExtension(pkgName, []string{"gamma"}, "", ocv1.UpgradeConstraintPolicyCatalogProvided)
+	ce.Spec.Source.Catalog.ChannelDeprecationPolicy = ocv1.ChannelDeprecationPolicyMigrate
+	gotBundle, _, _, err = r.Resolve(context.Background(), ce, nil)
+	require.NoError(t, err)
+	assert.Equal(t, genBundle(pkgName, "3.0.0"), *gotBundle)
+}
+
+func TestDeprecatedChannels(t *testing.T) {
+	enableChannelDeprecationPolicy(t)
+	for _, tc := range []struct {
+		name          string
+		channels      []string
+		deprecate     func(*declcfg.DeclarativeConfig) *declcfg.DeclarativeConfig
+		expected      []DeprecatedChannel
+		expectedError string
+	}{
+		{
+			name: "no channels requested",
+			deprecate: func(fbc *declcfg.DeclarativeConfig) *declcfg.DeclarativeConfig {
+				return withDeprecatedChannel(fbc, "alpha", "gamma")
+			},
+		},
+		{
+			name:     "requested channel deprecated without successor",
+			channels: []string{"alpha"},
+			deprecate: func(fbc *declcfg.DeclarativeConfig) *declcfg.DeclarativeConfig {
+				return withDeprecatedChannel(fbc, "alpha", "")
+			},
+		},
+		{
+			name:     "successor chains are followed",
+			channels: []string{"beta", "alpha"},
+			deprecate: func(fbc *declcfg.DeclarativeConfig) *declcfg.DeclarativeConfig {
+				return withDeprecatedChannel(withDeprecatedChannel(fbc, "alpha", "gamma"), "beta", "alpha")
+			},
+			expected: []DeprecatedChannel{{Name: "alpha", Successor: "gamma"}, {Name: "beta", Successor: "gamma"}},
+		},
+		{
+			name:     "cyclic successors are ignored",
+			channels: []string{"alpha"},
+			deprecate: func(fbc *declcfg.DeclarativeConfig) *declcfg.DeclarativeConfig {
+				return withDeprecatedChannel(withDeprecatedChannel(fbc, "alpha", "beta"), "beta", "alpha")
+			},
+		},
+		{
+			name:     "successor channel does not exist",
+			channels: []string{"alpha"},
+			deprecate: func(fbc *declcfg.DeclarativeConfig) *declcfg.DeclarativeConfig {
+				return withDeprecatedChannel(fbc, "alpha", "delta")
+			},
+			expectedError: `successor channel "delta" does not exist`,
+		},
+		{
+			name:     "invalid successors of channels that are not requested are ignored",
+			channels: []string{"beta"},
+			deprecate: func(fbc *declcfg.DeclarativeConfig) *declcfg.DeclarativeConfig {
+				return withDeprecatedChannel(withDeprecatedChannel(fbc, "alpha", "delta"), "beta", "gamma")
+			},
+			expected: []DeprecatedChannel{{Name: "beta", Successor: "gamma"}},
+		},
+		{
+			name:     "invalid successors in the chain of a requested channel",
+			channels: []string{"beta"},
+			deprecate: func(fbc *declcfg.DeclarativeConfig) *declcfg.DeclarativeConfig {
+				return withDeprecatedChannel(withDeprecatedChannel(fbc, "alpha", "delta"), "beta", "alpha")
+			},
+			expectedError: `successor channel "delta" does not exist`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pkgName := randPkg()
+			w := staticCatalogWalker{
+				"a": func() (*declcfg.DeclarativeConfig, *ocv1.ClusterCatalogSpec, error) {
+					return tc.deprecate(genPackage(pkgName)), nil, nil
+				},
+			}
+			r := CatalogResolver{WalkCatalogsFunc: w.WalkCatalogs}
+			ce := buildFooClusterExtension(pkgName, tc.channels, "", ocv1.UpgradeConstraintPolicyCatalogProvided)
+			ce.Spec.Source.Catalog.ChannelDeprecationPolicy = ocv1.ChannelDeprecationPolicyBlock
+			_, _, _, got, err := r.ResolveWithCatalog(context.Background(), ce, nil, "")
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expected, got.DeprecatedChannels)
+		})
+	}
+}
+
+func TestChannelDeprecationPolicies(t *testing.T) {
+	enableChannelDeprecationPolicy(t)
+	pkgName := randPkg()
+	walks := 0
+	w := staticCatalogWalker{
+		"a": func() (*declcfg.DeclarativeConfig, *ocv1.ClusterCatalogSpec, error) {
+			walks++
+			return withDeprecatedChannel(genPackage(pkgName), "alpha", "gamma"), nil, nil
+		},
+	}
+	r := CatalogResolver{WalkCatalogsFunc: w.WalkCatalogs}
+
+	for _, tc := range []struct {
+		policy             ocv1.ChannelDeprecationPolicy
+		expectedBundle     string
+		expectedDeprecated []DeprecatedChannel
+	}{
+		{
+			policy:         ocv1.ChannelDeprecationPolicyIgnore,
+			expectedBundle: "2.0.0",
+		},
+		{
+			policy:             ocv1.ChannelDeprecationPolicyMigrate,
+			expectedBundle:     "3.0.0",
+			expectedDeprecated: []DeprecatedChannel{{Name: "alpha", Successor: "gamma"}},
+		},
+		{
+			policy:             ocv1.ChannelDeprecationPolicyBlock,
+			expectedBundle:     "2.0.0",
+			expectedDeprecated: []DeprecatedChannel{{Name: "alpha", Successor: "gamma"}},
+		},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			walks = 0
+			ce := buildFooClusterExtension(pkgName, []string{"alpha"}, "", ocv1.UpgradeConstraintPolicyCatalogProvided)
+			ce.Spec.Source.Catalog.ChannelDeprecationPolicy = tc.policy
+			gotBundle, _, _, gotResolution, err := r.ResolveWithCatalog(context.Background(), ce, nil, "")
+			require.NoError(t, err)
+			assert.Equal(t, genBundle(pkgName, tc.expectedBundle), *gotBundle)
+			assert.Equal(t, tc.expectedDeprecated, gotResolution.DeprecatedChannels)
+			assert.Equal(t, []string{"alpha"}, ce.Spec.Source.Catalog.Channels, "the spec must be left untouched")
+			assert.Equal(t, 1, walks, "the catalogs must be walked once")
+		})
+	}
+}
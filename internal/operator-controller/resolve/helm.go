@@ -0,0 +1,291 @@
+package resolve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	bsemver "github.com/blang/semver/v4"
+	"github.com/opencontainers/go-digest"
+	"helm.sh/helm/v3/pkg/repo"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/bundle"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/catalogmetadata/compare"
+	imageutil "github.com/operator-framework/operator-controller/internal/shared/util/image"
+)
+
+// HelmRepositoryResolver resolves the chart to install for ClusterExtensions with the
+// Helm sourceType, directly from the index.yaml of an HTTP chart repository or from
+// the tags of an OCI repository.
+//
+// Helm charts have no upgrade graph, so the highest version of the chart that
+// satisfies the requested version constraint is resolved. Pre-release versions are
+// only resolved when a version constraint is requested. Versions excluded with
+// WithExcludedBundles are skipped without falling back below the installed version.
+type HelmRepositoryResolver struct {
+	// HTTPClientFunc returns the client used to fetch the index of HTTP chart repositories.
+	HTTPClientFunc func() (*http.Client, error)
+	// ListTags returns the tags of an OCI repository, given its reference without the
+	// oci:// scheme, for example "ghcr.io/example/charts/mychart".
+	ListTags func(ctx context.Context, ref string) ([]string, error)
+
+	indexesMu sync.Mutex
+	indexes   map[string]*cachedIndex
+}
+
+// maxIndexSize limits the size of the index.yaml of HTTP chart repositories, which
+// is read into memory. The indexes of the largest public chart repositories are
+// tens of megabytes.
+const maxIndexSize = 100 << 20
+
+// cachedIndex is the parsed index.yaml of an HTTP chart repository, along with the
+// validators used to revalidate it with conditional requests.
+type cachedIndex struct {
+	index        *repo.IndexFile
+	etag         string
+	lastModified string
+}
+
+type chartCandidate struct {
+	version bsemver.Version
+	ref     string
+}
+
+// Resolve returns a bundle describing the chart that needs to get installed on the cluster.
+// The image of the bundle is the reference of the chart: an OCI reference for OCI
+// repositories, or an imageutil.HTTPChartReference for HTTP chart repositories.
+func (r *HelmRepositoryResolver) Resolve(ctx context.Context, ext *ocv1.ClusterExtension, installedBundle *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
+	l := log.FromContext(ctx)
+	src := ext.Spec.Source.Helm
+	if src == nil {
+		return nil, nil, nil, errors.New("helm source is required when sourceType is Helm")
+	}
+
+	var versionRange bsemver.Range
+	if src.Version != "" {
+		var err error
+		versionRange, err = compare.NewVersionRange(src.Version)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("desired version range %q is invalid: %w", src.Version, err)
+		}
+	}
+
+	var (
+		candidates []chartCandidate
+		err        error
+	)
+	if repository, ok := strings.CutPrefix(src.Repository, "oci://"); ok {
+		candidates, err = r.ociCandidates(ctx, repository, src.Chart)
+	} else {
+		candidates, err = r.httpCandidates(ctx, src.Repository, src.Chart)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var (
+		resolved   *chartCandidate
+		installed  *chartCandidate
+		isExcluded bool
+	)
+	excluded := ExcludedBundles(ctx)
+	for i, c := range candidates {
+		if versionRange == nil && len(c.version.Pre) > 0 {
+			continue
+		}
+		if versionRange != nil && !versionRange(c.version) {
+			continue
+		}
+		if installedBundle != nil && c.version.String() == installedBundle.Version {
+			installed = &candidates[i]
+		}
+		if excluded.Has(chartBundleName(src.Chart, c.version.String())) {
+			isExcluded = true
+			continue
+		}
+		if resolved == nil || c.version.GT(resolved.version) {
+			resolved = &candidates[i]
+		}
+	}
+	// Rather than falling back to an older version when a version is excluded, the
+	// installed version is kept.
+	if isExcluded && installedBundle != nil {
+		if installedVersion, err := bsemver.Parse(installedBundle.Version); err == nil && (resolved == nil || resolved.version.LT(installedVersion)) {
+			resolved = installed
+		}
+	}
+	if resolved == nil {
+		if src.Version != "" {
+			return nil, nil, nil, fmt.Errorf("no versions of chart %q matching version %q found in repository %q", src.Chart, src.Version, src.Repository)
+		}
+		return nil, nil, nil, fmt.Errorf("no versions of chart %q found in repository %q", src.Chart, src.Repository)
+	}
+
+	version := resolved.version.String()
+	versionRelease, err := bundle.NewLegacyRegistryV1VersionRelease(version)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error getting version of chart %q: %w", src.Chart, err)
+	}
+	l.V(4).Info("resolution succeeded", "chart", src.Chart, "version", version, "ref", resolved.ref)
+	return &declcfg.Bundle{
+		Schema:     declcfg.SchemaBundle,
+		Name:       chartBundleName(src.Chart, version),
+		Package:    src.Chart,
+		Image:      resolved.ref,
+		Properties: []property.Property{property.MustBuildPackage(src.Chart, version)},
+	}, versionRelease, nil, nil
+}
+
+// chartBundleName returns the name of the bundle describing version of chart, following
+// the naming convention of registry+v1 bundles. Build metadata is not allowed in names,
+// so its separator is replaced.
+func chartBundleName(chart, version string) string {
+	return fmt.Sprintf("%s.v%s", chart, strings.ReplaceAll(version, "+", "-"))
+}
+
+// ociCandidates returns the versions of chart in the OCI repository. Helm pushes chart
+// versions as tags, replacing the "+" of build metadata, which is not allowed in tags,
+// with "_".
+func (r *HelmRepositoryResolver) ociCandidates(ctx context.Context, repository, chart string) ([]chartCandidate, error) {
+	if r.ListTags == nil {
+		return nil, errors.New("resolving charts from OCI repositories is not enabled")
+	}
+	ref := strings.TrimSuffix(repository, "/") + "/" + chart
+	tags, err := r.ListTags(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tags of chart repository %q: %w", ref, err)
+	}
+	candidates := make([]chartCandidate, 0, len(tags))
+	for _, tag := range tags {
+		v, err := bsemver.Parse(strings.ReplaceAll(tag, "_", "+"))
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, chartCandidate{version: v, ref: ref + ":" + tag})
+	}
+	return candidates, nil
+}
+
+// httpCandidates returns the versions of chart listed in the index.yaml of the HTTP
+// chart repository at repoURL.
+func (r *HelmRepositoryResolver) httpCandidates(ctx context.Context, repoURL, chart string) ([]chartCandidate, error) {
+	if r.HTTPClientFunc == nil {
+		return nil, errors.New("resolving charts from HTTP chart repositories is not enabled")
+	}
+	baseURL, err := url.Parse(strings.TrimSuffix(repoURL, "/") + "/")
+	if err != nil {
+		return nil, fmt.Errorf("chart repository URL %q is invalid: %w", repoURL, err)
+	}
+	index, err := r.fetchIndex(ctx, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []chartCandidate
+	for _, cv := range index.Entries[chart] {
+		if cv == nil || cv.Metadata == nil || cv.Removed || len(cv.URLs) == 0 {
+			continue
+		}
+		v, err := bsemver.ParseTolerant(cv.Version)
+		if err != nil {
+			continue
+		}
+		chartURL, err := baseURL.Parse(cv.URLs[0])
+		if err != nil {
+			continue
+		}
+		var dgst digest.Digest
+		if cv.Digest != "" {
+			dgst = digest.NewDigestFromEncoded(digest.SHA256, cv.Digest)
+			if dgst.Validate() != nil {
+				dgst = ""
+			}
+		}
+		candidates = append(candidates, chartCandidate{version: v, ref: imageutil.HTTPChartReference(chartURL.String(), dgst)})
+	}
+	return candidates, nil
+}
+
+// fetchIndex returns the parsed index.yaml of the HTTP chart repository at baseURL.
+// Indexes are cached per URL, and revalidated with conditional requests when the
+// repository returns an ETag or Last-Modified header.
+func (r *HelmRepositoryResolver) fetchIndex(ctx context.Context, baseURL *url.URL) (*repo.IndexFile, error) {
+	indexURL := baseURL.JoinPath("index.yaml").String()
+	httpClient, err := r.HTTPClientFunc()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, indexURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	cached := r.cachedIndex(indexURL)
+	if cached != nil {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching chart repository index %q: %w", indexURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached.index, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching chart repository index %q: unexpected status %q", indexURL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxIndexSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading chart repository index %q: %w", indexURL, err)
+	}
+	if len(data) > maxIndexSize {
+		return nil, fmt.Errorf("error reading chart repository index %q: exceeds the maximum size of %d bytes", indexURL, maxIndexSize)
+	}
+	index := &repo.IndexFile{}
+	if err := yaml.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("error parsing chart repository index %q: %w", indexURL, err)
+	}
+	r.cacheIndex(indexURL, &cachedIndex{
+		index:        index,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	})
+	return index, nil
+}
+
+func (r *HelmRepositoryResolver) cachedIndex(indexURL string) *cachedIndex {
+	r.indexesMu.Lock()
+	defer r.indexesMu.Unlock()
+	return r.indexes[indexURL]
+}
+
+// cacheIndex caches the index of indexURL if it can be revalidated, and drops the
+// cached index otherwise.
+func (r *HelmRepositoryResolver) cacheIndex(indexURL string, ci *cachedIndex) {
+	r.indexesMu.Lock()
+	defer r.indexesMu.Unlock()
+	if ci.etag == "" && ci.lastModified == "" {
+		delete(r.indexes, indexURL)
+		return
+	}
+	if r.indexes == nil {
+		r.indexes = map[string]*cachedIndex{}
+	}
+	r.indexes[indexURL] = ci
+}
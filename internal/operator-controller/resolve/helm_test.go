@@ -0,0 +1,292 @@
+package resolve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/bundle"
+)
+
+const testChartIndex = `apiVersion: v1
+entries:
+  testchart:
+  - name: testchart
+    version: 1.0.0
+    urls:
+    - testchart-1.0.0.tgz
+    digest: 0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f9
+  - name: testchart
+    version: 1.2.0
+    urls:
+    - https://downloads.example.com/testchart-1.2.0.tgz
+  - name: testchart
+    version: 2.0.0-rc.1
+    urls:
+    - testchart-2.0.0-rc.1.tgz
+  - name: testchart
+    version: 1.3.0
+    removed: true
+    urls:
+    - testchart-1.3.0.tgz
+  otherchart:
+  - name: otherchart
+    version: 9.0.0
+    urls:
+    - otherchart-9.0.0.tgz
+`
+
+func buildHelmClusterExtension(repository, chart, version string) *ocv1.ClusterExtension {
+	return &ocv1.ClusterExtension{
+		Spec: ocv1.ClusterExtensionSpec{
+			Source: ocv1.SourceConfig{
+				SourceType: ocv1.SourceTypeHelm,
+				Helm: &ocv1.HelmSource{
+					Repository: repository,
+					Chart:      chart,
+					Version:    version,
+				},
+			},
+		},
+	}
+}
+
+func TestHelmRepositoryResolver_HTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/charts/index.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(testChartIndex))
+	}))
+	defer server.Close()
+
+	r := &HelmRepositoryResolver{HTTPClientFunc: func() (*http.Client, error) { return server.Client(), nil }}
+
+	for _, tc := range []struct {
+		name          string
+		repository    string
+		chart         string
+		version       string
+		expectedName  string
+		expectedImage string
+		expectedErr   string
+	}{
+		{
+			name:          "resolves the highest version that is not a pre-release",
+			repository:    server.URL + "/charts",
+			chart:         "testchart",
+			expectedName:  "testchart.v1.2.0",
+			expectedImage: "https://downloads.example.com/testchart-1.2.0.tgz",
+		},
+		{
+			name:          "resolves relative chart URLs and records the digest",
+			repository:    server.URL + "/charts/",
+			chart:         "testchart",
+			version:       "~1.0",
+			expectedName:  "testchart.v1.0.0",
+			expectedImage: server.URL + "/charts/testchart-1.0.0.tgz@sha256:0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f9",
+		},
+		{
+			name:          "resolves pre-releases matching the version constraint",
+			repository:    server.URL + "/charts",
+			chart:         "testchart",
+			version:       ">=2.0.0-0",
+			expectedName:  "testchart.v2.0.0-rc.1",
+			expectedImage: server.URL + "/charts/testchart-2.0.0-rc.1.tgz",
+		},
+		{
+			name:        "ignores removed versions",
+			repository:  server.URL + "/charts",
+			chart:       "testchart",
+			version:     "1.3.0",
+			expectedErr: `no versions of chart "testchart" matching version "1.3.0" found in repository`,
+		},
+		{
+			name:        "reports charts missing from the repository",
+			repository:  server.URL + "/charts",
+			chart:       "missing",
+			expectedErr: `no versions of chart "missing" found in repository`,
+		},
+		{
+			name:        "reports unavailable repositories",
+			repository:  server.URL + "/missing",
+			chart:       "testchart",
+			expectedErr: "unexpected status",
+		},
+		{
+			name:        "reports invalid version constraints",
+			repository:  server.URL + "/charts",
+			chart:       "testchart",
+			version:     "foobar",
+			expectedErr: `desired version range "foobar" is invalid`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b, vr, deprecation, err := r.Resolve(context.Background(), buildHelmClusterExtension(tc.repository, tc.chart, tc.version), nil)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedName, b.Name)
+			assert.Equal(t, tc.chart, b.Package)
+			assert.Equal(t, tc.expectedImage, b.Image)
+			assert.Nil(t, deprecation)
+
+			expectedVersion, err := bundle.NewLegacyRegistryV1VersionRelease(vr.AsLegacyRegistryV1Version().String())
+			require.NoError(t, err)
+			assert.Equal(t, expectedVersion, vr)
+			assert.Equal(t, []property.Property{property.MustBuildPackage(tc.chart, vr.AsLegacyRegistryV1Version().String())}, b.Properties)
+		})
+	}
+}
+
+func TestHelmRepositoryResolver_HTTPIndexCache(t *testing.T) {
+	var (
+		index    = testChartIndex
+		etag     = `"1"`
+		fetches  int
+		requests int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fetches++
+		_, _ = w.Write([]byte(index))
+	}))
+	defer server.Close()
+
+	r := &HelmRepositoryResolver{HTTPClientFunc: func() (*http.Client, error) { return server.Client(), nil }}
+	ext := buildHelmClusterExtension(server.URL, "testchart", "")
+
+	b, _, _, err := r.Resolve(context.Background(), ext, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "testchart.v1.2.0", b.Name)
+	b, _, _, err = r.Resolve(context.Background(), ext, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "testchart.v1.2.0", b.Name)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, 1, fetches, "the unmodified index should be served from the cache")
+
+	index = strings.Replace(testChartIndex, "version: 1.2.0", "version: 1.2.1", 1)
+	etag = `"2"`
+	b, _, _, err = r.Resolve(context.Background(), ext, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "testchart.v1.2.1", b.Name)
+	assert.Equal(t, 2, fetches)
+}
+
+func TestHelmRepositoryResolver_OversizedIndex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.CopyN(w, neverEndingReader{}, maxIndexSize+1)
+	}))
+	defer server.Close()
+
+	r := &HelmRepositoryResolver{HTTPClientFunc: func() (*http.Client, error) { return server.Client(), nil }}
+	_, _, _, err := r.Resolve(context.Background(), buildHelmClusterExtension(server.URL, "testchart", ""), nil)
+	require.ErrorContains(t, err, fmt.Sprintf("exceeds the maximum size of %d bytes", maxIndexSize))
+}
+
+// neverEndingReader returns an endless stream of YAML comment characters.
+type neverEndingReader struct{}
+
+func (neverEndingReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = '#'
+	}
+	return len(p), nil
+}
+
+func TestHelmRepositoryResolver_OCI(t *testing.T) {
+	var listed string
+	r := &HelmRepositoryResolver{
+		ListTags: func(_ context.Context, ref string) ([]string, error) {
+			listed = ref
+			return []string{"latest", "1.0.0", "1.1.0_build.1", "2.0.0-rc.1", "sha256-abcdef.sig"}, nil
+		},
+	}
+
+	b, vr, _, err := r.Resolve(context.Background(), buildHelmClusterExtension("oci://ghcr.io/example/charts/", "testchart", ""), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ghcr.io/example/charts/testchart", listed)
+	assert.Equal(t, "testchart.v1.1.0-build.1", b.Name)
+	assert.Equal(t, "ghcr.io/example/charts/testchart:1.1.0_build.1", b.Image)
+	assert.Equal(t, "1.1.0+build.1", vr.AsLegacyRegistryV1Version().String())
+
+	b, _, _, err = r.Resolve(context.Background(), buildHelmClusterExtension("oci://ghcr.io/example/charts", "testchart", "<1.1.0"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ghcr.io/example/charts/testchart:1.0.0", b.Image)
+
+	r.ListTags = func(context.Context, string) ([]string, error) { return nil, errors.New("unauthorized") }
+	_, _, _, err = r.Resolve(context.Background(), buildHelmClusterExtension("oci://ghcr.io/example/charts", "testchart", ""), nil)
+	require.ErrorContains(t, err, `error listing tags of chart repository "ghcr.io/example/charts/testchart": unauthorized`)
+}
+
+func TestHelmRepositoryResolver_ExcludedBundles(t *testing.T) {
+	r := &HelmRepositoryResolver{
+		ListTags: func(context.Context, string) ([]string, error) {
+			return []string{"1.0.0", "1.1.0", "1.2.0"}, nil
+		},
+	}
+	ce := buildHelmClusterExtension("oci://ghcr.io/example/charts", "testchart", "")
+	ctx := WithExcludedBundles(context.Background(), "testchart.v1.2.0")
+
+	b, _, _, err := r.Resolve(ctx, ce, &ocv1.BundleMetadata{Name: "testchart.v1.1.0", Version: "1.1.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "testchart.v1.1.0", b.Name)
+
+	b, _, _, err = r.Resolve(ctx, ce, &ocv1.BundleMetadata{Name: "testchart.v1.0.0", Version: "1.0.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "testchart.v1.1.0", b.Name)
+
+	_, _, _, err = r.Resolve(ctx, buildHelmClusterExtension("oci://ghcr.io/example/charts", "testchart", "<1.1.0 || >=1.2.0"), &ocv1.BundleMetadata{Name: "testchart.v1.1.0", Version: "1.1.0"})
+	require.ErrorContains(t, err, `no versions of chart "testchart" matching version`)
+}
+
+func TestSourceResolver(t *testing.T) {
+	catalogBundle := &declcfg.Bundle{Name: "catalog-bundle"}
+	helmBundle := &declcfg.Bundle{Name: "helm-bundle"}
+	catalogResolver := Func(func(context.Context, *ocv1.ClusterExtension, *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
+		return catalogBundle, nil, nil, nil
+	})
+	helmResolver := Func(func(context.Context, *ocv1.ClusterExtension, *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
+		return helmBundle, nil, nil, nil
+	})
+	catalogExt := &ocv1.ClusterExtension{Spec: ocv1.ClusterExtensionSpec{Source: ocv1.SourceConfig{
+		SourceType: ocv1.SourceTypeCatalog,
+		Catalog:    &ocv1.CatalogFilter{PackageName: "foo"},
+	}}}
+	helmExt := buildHelmClusterExtension("https://charts.example.com", "testchart", "")
+
+	r := &SourceResolver{Catalog: catalogResolver, Helm: helmResolver}
+	b, _, _, err := r.Resolve(context.Background(), catalogExt, nil)
+	require.NoError(t, err)
+	assert.Equal(t, catalogBundle, b)
+	b, _, _, resolution, err := r.ResolveWithCatalog(context.Background(), helmExt, nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, helmBundle, b)
+	assert.Nil(t, resolution)
+
+	r = &SourceResolver{Catalog: catalogResolver}
+	_, _, _, err = r.Resolve(context.Background(), helmExt, nil)
+	require.ErrorIs(t, err, reconcile.TerminalError(nil))
+	require.ErrorContains(t, err, "the Helm sourceType is not enabled")
+}
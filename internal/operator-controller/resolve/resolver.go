@@ -2,6 +2,10 @@ package resolve
 
 import (
 	"context"
+	"errors"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/operator-framework/operator-registry/alpha/declcfg"
 
@@ -18,3 +22,76 @@ type Func func(ctx context.Context, ext *ocv1.ClusterExtension, installedBundle
 func (f Func) Resolve(ctx context.Context, ext *ocv1.ClusterExtension, installedBundle *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
 	return f(ctx, ext, installedBundle)
 }
+
+type excludedBundlesKey struct{}
+
+// WithExcludedBundles returns a context that makes resolvers skip the named bundles, for
+// example bundles that failed to roll out. When a candidate bundle is excluded, resolvers
+// don't fall back to versions older than the installed bundle, so that the ClusterExtension
+// stays on the installed bundle rather than being downgraded.
+func WithExcludedBundles(ctx context.Context, names ...string) context.Context {
+	if len(names) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, excludedBundlesKey{}, sets.New(names...))
+}
+
+// ExcludedBundles returns the names of the bundles excluded from resolution by
+// WithExcludedBundles.
+func ExcludedBundles(ctx context.Context) sets.Set[string] {
+	excluded, _ := ctx.Value(excludedBundlesKey{}).(sets.Set[string])
+	return excluded
+}
+
+// CatalogResolution describes how a bundle was resolved from ClusterCatalogs.
+type CatalogResolution struct {
+	// Catalog is the name of the ClusterCatalog that provided the resolved bundle.
+	Catalog string
+	// IncompatibleBundles summarizes the bundles that were excluded from resolution
+	// because they can't run on the cluster, sorted by bundle and catalog name.
+	// Long lists are truncated.
+	IncompatibleBundles []string
+	// DeprecatedChannels are the requested channels that are deprecated in favor of
+	// a successor channel. They are only reported with the Migrate and Block channel
+	// deprecation policies, and the Migrate policy resolves from the successors.
+	DeprecatedChannels []DeprecatedChannel
+}
+
+// CatalogSourceResolver is implemented by resolvers that can report how the
+// resolved bundle was found, including the name of the ClusterCatalog that
+// provided it. installedCatalog is the ClusterCatalog that provided the
+// installed bundle, if known. The CatalogResolution is returned along with
+// resolution errors when bundles were excluded.
+type CatalogSourceResolver interface {
+	ResolveWithCatalog(ctx context.Context, ext *ocv1.ClusterExtension, installedBundle *ocv1.BundleMetadata, installedCatalog string) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, *CatalogResolution, error)
+}
+
+// SourceResolver resolves bundles with the resolver matching the sourceType of a
+// ClusterExtension. It implements CatalogSourceResolver by delegating to the
+// Catalog resolver when it implements it.
+type SourceResolver struct {
+	Catalog Resolver
+	// Helm resolves ClusterExtensions with the Helm sourceType. When nil, the Helm
+	// sourceType is rejected.
+	Helm Resolver
+}
+
+func (r *SourceResolver) Resolve(ctx context.Context, ext *ocv1.ClusterExtension, installedBundle *ocv1.BundleMetadata) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, error) {
+	resolvedBundle, resolvedBundleVersion, deprecation, _, err := r.ResolveWithCatalog(ctx, ext, installedBundle, "")
+	return resolvedBundle, resolvedBundleVersion, deprecation, err
+}
+
+func (r *SourceResolver) ResolveWithCatalog(ctx context.Context, ext *ocv1.ClusterExtension, installedBundle *ocv1.BundleMetadata, installedCatalog string) (*declcfg.Bundle, *bundle.VersionRelease, *declcfg.Deprecation, *CatalogResolution, error) {
+	if ext.Spec.Source.SourceType == ocv1.SourceTypeHelm {
+		if r.Helm == nil {
+			return nil, nil, nil, nil, reconcile.TerminalError(errors.New("the Helm sourceType is not enabled"))
+		}
+		resolvedBundle, resolvedBundleVersion, deprecation, err := r.Helm.Resolve(ctx, ext, installedBundle)
+		return resolvedBundle, resolvedBundleVersion, deprecation, nil, err
+	}
+	if cr, ok := r.Catalog.(CatalogSourceResolver); ok {
+		return cr.ResolveWithCatalog(ctx, ext, installedBundle, installedCatalog)
+	}
+	resolvedBundle, resolvedBundleVersion, deprecation, err := r.Catalog.Resolve(ctx, ext, installedBundle)
+	return resolvedBundle, resolvedBundleVersion, deprecation, nil, err
+}
@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/extensionpolicy"
+)
+
+// ClusterExtension wraps the external v1.ClusterExtension type and implements admission.Validator
+// to reject ClusterExtensions that violate an ExtensionPolicy.
+type ClusterExtension struct {
+	PolicyChecker *extensionpolicy.Checker
+}
+
+// ValidateCreate rejects new ClusterExtensions that violate an ExtensionPolicy.
+func (r *ClusterExtension) ValidateCreate(ctx context.Context, obj *ocv1.ClusterExtension) (admission.Warnings, error) {
+	return nil, r.validate(ctx, obj)
+}
+
+// ValidateUpdate rejects updates of the spec of a ClusterExtension that leave it in violation of
+// an ExtensionPolicy.
+func (r *ClusterExtension) ValidateUpdate(ctx context.Context, oldObj, newObj *ocv1.ClusterExtension) (admission.Warnings, error) {
+	// Updates to a ClusterExtension that is being deleted must always be allowed so
+	// that finalizers can be removed.
+	if newObj.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+	// Updates that don't change the spec, such as updates of labels, annotations and
+	// finalizers, are allowed even for ClusterExtensions created before a policy they violate.
+	if equality.Semantic.DeepEqual(oldObj.Spec, newObj.Spec) {
+		return nil, nil
+	}
+	return nil, r.validate(ctx, newObj)
+}
+
+// ValidateDelete always allows deletion.
+func (r *ClusterExtension) ValidateDelete(_ context.Context, _ *ocv1.ClusterExtension) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (r *ClusterExtension) validate(ctx context.Context, obj *ocv1.ClusterExtension) error {
+	err := r.PolicyChecker.Check(ctx, obj)
+	var violation *extensionpolicy.ViolationError
+	if errors.As(err, &violation) {
+		log.FromContext(ctx).Info("rejecting ClusterExtension", "name", obj.GetName(), "violations", violation.Violations)
+	}
+	return err
+}
+
+// SetupWebhookWithManager sets up the webhook with the manager
+func (r *ClusterExtension) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &ocv1.ClusterExtension{}).
+		WithValidator(r).
+		Complete()
+}
@@ -0,0 +1,114 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ocv1 "github.com/operator-framework/operator-controller/api/v1"
+	"github.com/operator-framework/operator-controller/internal/operator-controller/extensionpolicy"
+)
+
+func TestClusterExtensionValidation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, ocv1.AddToScheme(scheme))
+
+	policy := &ocv1.ExtensionPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "allowed-packages"},
+		Spec: ocv1.ExtensionPolicySpec{
+			AllowedPackages: []string{"allowed"},
+		},
+	}
+	validator := &ClusterExtension{
+		PolicyChecker: &extensionpolicy.Checker{
+			Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).Build(),
+		},
+	}
+
+	newExtension := func(packageName string) *ocv1.ClusterExtension {
+		return &ocv1.ClusterExtension{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ext"},
+			Spec: ocv1.ClusterExtensionSpec{
+				Namespace:      "test-ns",
+				ServiceAccount: ocv1.ServiceAccountReference{Name: "test-sa"},
+				Source: ocv1.SourceConfig{
+					SourceType: ocv1.SourceTypeCatalog,
+					Catalog:    &ocv1.CatalogFilter{PackageName: packageName},
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		validate    func(context.Context) error
+		expectError bool
+	}{
+		"create with allowed package is admitted": {
+			validate: func(ctx context.Context) error {
+				_, err := validator.ValidateCreate(ctx, newExtension("allowed"))
+				return err
+			},
+		},
+		"create with disallowed package is rejected": {
+			validate: func(ctx context.Context) error {
+				_, err := validator.ValidateCreate(ctx, newExtension("disallowed"))
+				return err
+			},
+			expectError: true,
+		},
+		"update with disallowed package is rejected": {
+			validate: func(ctx context.Context) error {
+				_, err := validator.ValidateUpdate(ctx, newExtension("allowed"), newExtension("disallowed"))
+				return err
+			},
+			expectError: true,
+		},
+		"update of the spec of a violating extension is rejected": {
+			validate: func(ctx context.Context) error {
+				updated := newExtension("disallowed")
+				updated.Spec.Namespace = "other-ns"
+				_, err := validator.ValidateUpdate(ctx, newExtension("disallowed"), updated)
+				return err
+			},
+			expectError: true,
+		},
+		"update of the metadata of a violating extension is admitted": {
+			validate: func(ctx context.Context) error {
+				updated := newExtension("disallowed")
+				updated.Labels = map[string]string{"example.com/team": "a"}
+				updated.Finalizers = nil
+				_, err := validator.ValidateUpdate(ctx, newExtension("disallowed"), updated)
+				return err
+			},
+		},
+		"update of a deleting extension is admitted": {
+			validate: func(ctx context.Context) error {
+				ext := newExtension("disallowed")
+				ext.DeletionTimestamp = &metav1.Time{}
+				_, err := validator.ValidateUpdate(ctx, ext, ext)
+				return err
+			},
+		},
+		"delete is admitted": {
+			validate: func(ctx context.Context) error {
+				_, err := validator.ValidateDelete(ctx, newExtension("disallowed"))
+				return err
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.validate(context.Background())
+			if tc.expectError {
+				require.ErrorContains(t, err, `policy "allowed-packages"`)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.podman.io/image/v5/docker"
+	"go.podman.io/image/v5/docker/reference"
+	"helm.sh/helm/v3/pkg/registry"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ListTags returns the tags of the repository referenced by ref, for example
+// "ghcr.io/example/charts/mychart".
+func (p *ContainersImagePuller) ListTags(ctx context.Context, ref string) ([]string, error) {
+	srcCtx, err := p.SourceCtxFunc(ctx)
+	if err != nil {
+		return nil, err
+	}
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return nil, reconcile.TerminalError(fmt.Errorf("error parsing repository reference %q: %w", ref, err))
+	}
+	imgRef, err := docker.NewReference(reference.TagNameOnly(named))
+	if err != nil {
+		return nil, reconcile.TerminalError(fmt.Errorf("error creating reference: %w", err))
+	}
+	return docker.GetRepositoryTags(ctx, srcCtx, imgRef)
+}
+
+// HTTPChartReference returns the reference of the chart archive at chartURL for an
+// HTTPChartPuller. When dgst is not empty, it is appended to the URL after an "@".
+func HTTPChartReference(chartURL string, dgst digest.Digest) string {
+	if dgst == "" {
+		return chartURL
+	}
+	return chartURL + "@" + dgst.String()
+}
+
+func parseHTTPChartReference(ref string) (string, digest.Digest) {
+	if i := strings.LastIndex(ref, "@"); i >= 0 {
+		if dgst, err := digest.Parse(ref[i+1:]); err == nil {
+			return ref[:i], dgst
+		}
+	}
+	return ref, ""
+}
+
+// HTTPChartPuller pulls Helm chart archives from HTTP chart repositories. References are
+// chart archive URLs as returned by HTTPChartReference. When a reference includes the
+// digest of the archive, a chart already unpacked in the cache is used without
+// downloading it again, and the downloaded archive is verified against the digest.
+type HTTPChartPuller struct {
+	HTTPClientFunc func() (*http.Client, error)
+}
+
+func (p *HTTPChartPuller) Pull(ctx context.Context, ownerID string, ref string, cache Cache) (fs.FS, reference.Canonical, time.Time, error) {
+	chartURL, expectedDigest := parseHTTPChartReference(ref)
+	u, err := url.Parse(chartURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, nil, time.Time{}, reconcile.TerminalError(fmt.Errorf("invalid chart URL %q", chartURL))
+	}
+	name := chartReferenceName(u)
+
+	l := log.FromContext(ctx, "ref", chartURL)
+	ctx = log.IntoContext(ctx, l)
+
+	if expectedDigest != "" {
+		canonicalRef, err := reference.WithDigest(name, expectedDigest)
+		if err != nil {
+			return nil, nil, time.Time{}, reconcile.TerminalError(fmt.Errorf("error creating canonical reference: %w", err))
+		}
+		fsys, modTime, err := cache.Fetch(ctx, ownerID, canonicalRef)
+		if err != nil {
+			return nil, nil, time.Time{}, err
+		}
+		if fsys != nil {
+			return fsys, canonicalRef, modTime, nil
+		}
+	}
+
+	data, err := p.download(ctx, chartURL)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	actualDigest := digest.FromBytes(data)
+	if expectedDigest != "" && actualDigest != expectedDigest {
+		return nil, nil, time.Time{}, fmt.Errorf("chart archive %q has digest %s, expected %s", chartURL, actualDigest, expectedDigest)
+	}
+	canonicalRef, err := reference.WithDigest(name, actualDigest)
+	if err != nil {
+		return nil, nil, time.Time{}, reconcile.TerminalError(fmt.Errorf("error creating canonical reference: %w", err))
+	}
+	if expectedDigest == "" {
+		fsys, modTime, err := cache.Fetch(ctx, ownerID, canonicalRef)
+		if err != nil {
+			return nil, nil, time.Time{}, err
+		}
+		if fsys != nil {
+			return fsys, canonicalRef, modTime, nil
+		}
+	}
+
+	layers := iter.Seq[LayerData](func(yield func(LayerData) bool) {
+		yield(LayerData{MediaType: registry.ChartLayerMediaType, Reader: bytes.NewReader(data)})
+	})
+	fsys, modTime, err := cache.Store(ctx, ownerID, name, canonicalRef, ocispecv1.Image{}, layers)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	return fsys, canonicalRef, modTime, nil
+}
+
+func (p *HTTPChartPuller) download(ctx context.Context, chartURL string) ([]byte, error) {
+	httpClient, err := p.HTTPClientFunc()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, chartURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading chart archive %q: %w", chartURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error downloading chart archive %q: unexpected status %q", chartURL, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading chart archive %q: %w", chartURL, err)
+	}
+	return data, nil
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// chartReferenceName returns the name under which a chart downloaded from u is
+// cached. The cache only keys content by digest, so the name is informational.
+func chartReferenceName(u *url.URL) reference.Named {
+	base := strings.TrimSuffix(path.Base(u.Path), ".tgz")
+	base = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(base), "-"), "-")
+	if named, err := reference.ParseNormalizedNamed(path.Join(strings.ToLower(u.Host), base)); err == nil {
+		return named
+	}
+	named, _ := reference.ParseNormalizedNamed("helm-chart")
+	return named
+}
+
+// SchemePuller pulls references with an http:// or https:// scheme with HTTP, and all
+// other references with Image. It allows Helm charts from HTTP chart repositories to be
+// pulled alongside bundle images and OCI charts.
+type SchemePuller struct {
+	Image Puller
+	HTTP  Puller
+}
+
+func (p *SchemePuller) Pull(ctx context.Context, ownerID string, ref string, cache Cache) (fs.FS, reference.Canonical, time.Time, error) {
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		if p.HTTP == nil {
+			return nil, nil, time.Time{}, reconcile.TerminalError(errors.New("pulling from HTTP chart repositories is not enabled"))
+		}
+		return p.HTTP.Pull(ctx, ownerID, ref, cache)
+	}
+	return p.Image.Pull(ctx, ownerID, ref, cache)
+}
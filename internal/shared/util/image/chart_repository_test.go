@@ -0,0 +1,142 @@
+package image
+
+import (
+	"context"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.podman.io/image/v5/docker/reference"
+)
+
+func TestHTTPChartPuller_Pull(t *testing.T) {
+	testChart := mockHelmChartTgz(t,
+		[]fileContent{
+			{
+				name:    "testchart/Chart.yaml",
+				content: []byte("apiVersion: v2\nname: testchart\nversion: 0.1.0"),
+			},
+			{
+				name:    "testchart/templates/deployment.yaml",
+				content: []byte("kind: Deployment\napiVersion: apps/v1"),
+			},
+		},
+	)
+	chartDigest := digest.FromBytes(testChart)
+
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/charts/testchart-0.1.0.tgz" {
+			http.NotFound(w, r)
+			return
+		}
+		downloads++
+		_, _ = w.Write(testChart)
+	}))
+	defer server.Close()
+	chartURL := server.URL + "/charts/testchart-0.1.0.tgz"
+
+	puller := &HTTPChartPuller{HTTPClientFunc: func() (*http.Client, error) { return server.Client(), nil }}
+
+	tests := []struct {
+		name              string
+		ref               string
+		expectedDownloads int
+		expectedErr       string
+	}{
+		{
+			name:              "downloads the chart once when the digest is known",
+			ref:               HTTPChartReference(chartURL, chartDigest),
+			expectedDownloads: 1,
+		},
+		{
+			name:              "downloads the chart on every pull when the digest is unknown",
+			ref:               chartURL,
+			expectedDownloads: 2,
+		},
+		{
+			name:        "rejects a chart that does not match the digest",
+			ref:         HTTPChartReference(chartURL, digest.FromString("other")),
+			expectedErr: "expected " + digest.FromString("other").String(),
+		},
+		{
+			name:        "reports missing charts",
+			ref:         server.URL + "/charts/missing-0.1.0.tgz",
+			expectedErr: "unexpected status",
+		},
+	}
+
+	ctx := context.Background()
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			downloads = 0
+			cache := BundleCache(t.TempDir())
+			defer func() {
+				require.NoError(t, cache.Delete(ctx, "myOwner"))
+			}()
+
+			for range 2 {
+				fsys, canonicalRef, _, err := puller.Pull(ctx, "myOwner", tc.ref, cache)
+				if tc.expectedErr != "" {
+					require.ErrorContains(t, err, tc.expectedErr)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, chartDigest, canonicalRef.Digest())
+
+				actualChartData, err := fs.ReadFile(fsys, "testchart-0.1.0.tgz")
+				require.NoError(t, err)
+				assert.Equal(t, testChart, actualChartData)
+			}
+			assert.Equal(t, tc.expectedDownloads, downloads)
+		})
+	}
+}
+
+func TestContainersImagePuller_ListTags(t *testing.T) {
+	chartTagRef, _, cleanup := setupChartRegistry(t,
+		mockHelmChartTgz(t,
+			[]fileContent{
+				{
+					name:    "testchart/Chart.yaml",
+					content: []byte("apiVersion: v2\nname: testchart\nversion: 0.1.0"),
+				},
+			},
+		),
+	)
+	defer cleanup()
+
+	puller := &ContainersImagePuller{SourceCtxFunc: buildSourceContextFunc(t, chartTagRef)}
+	tags, err := puller.ListTags(context.Background(), reference.TrimNamed(chartTagRef).String())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0.1.0"}, tags)
+}
+
+type fakePuller struct {
+	pulled []string
+}
+
+func (p *fakePuller) Pull(_ context.Context, _, ref string, _ Cache) (fs.FS, reference.Canonical, time.Time, error) {
+	p.pulled = append(p.pulled, ref)
+	return nil, nil, time.Time{}, nil
+}
+
+func TestSchemePuller_Pull(t *testing.T) {
+	imagePuller, httpPuller := &fakePuller{}, &fakePuller{}
+	puller := &SchemePuller{Image: imagePuller, HTTP: httpPuller}
+
+	for _, ref := range []string{"quay.io/example/bundle:v1.0.0", "https://charts.example.com/testchart-0.1.0.tgz", "http://charts.example.com/testchart-0.1.0.tgz"} {
+		_, _, _, err := puller.Pull(context.Background(), "myOwner", ref, nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"quay.io/example/bundle:v1.0.0"}, imagePuller.pulled)
+	assert.Equal(t, []string{"https://charts.example.com/testchart-0.1.0.tgz", "http://charts.example.com/testchart-0.1.0.tgz"}, httpPuller.pulled)
+
+	_, _, _, err := (&SchemePuller{Image: imagePuller}).Pull(context.Background(), "myOwner", "https://charts.example.com/testchart-0.1.0.tgz", nil)
+	require.ErrorContains(t, err, "not enabled")
+}
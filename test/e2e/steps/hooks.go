@@ -71,6 +71,21 @@ var (
 		features.WebhookProviderOpenshiftServiceCA: false,
 		features.HelmChartSupport:                  false,
 		features.BoxcutterRuntime:                  false,
+		features.ExtensionPolicy:                   false,
+		features.ConfigSourceReferences:            false,
+		features.HelmReleaseAdoption:               false,
+		features.ReleaseRollback:                   false,
+		features.UpgradePreview:                    false,
+		features.ManifestPatches:                   false,
+		features.InstallNamespaceCreation:          false,
+		features.ObjectProbes:                      false,
+		features.CanaryRollout:                     false,
+		features.RevisionHistory:                   false,
+		features.CollisionProtectionPolicy:         false,
+		features.OLMv0Migration:                    false,
+		features.UninstallPolicy:                   false,
+		features.PauseReconciliation:               false,
+		features.DriftPolicy:                       false,
 	}
 	logger logr.Logger
 )